@@ -201,7 +201,7 @@ func (d *DefaultDispatcher) getLink(ctx context.Context) (*transport.Link, *tran
 	if connID, ok := ratelimit.EnsureConnIDFromContext(ctx); ok && connID != 0 {
 		// Оборачиваем именно outboundLink: outbound handler использует и Reader, и Writer,
 		// значит мы режем и uplink, и downlink в одном месте.
-		outboundLink = ratelimit.WrapLinkWithConnID(connID, outboundLink)
+		outboundLink = ratelimit.WrapLinkWithConnID(ctx, connID, sessionInbound.Tag, outboundLink)
 	}
 
 	return inboundLink, outboundLink
@@ -247,7 +247,7 @@ func (d *DefaultDispatcher) WrapLink(ctx context.Context, link *transport.Link)
 
 	// custom
 	if connID, ok := ratelimit.EnsureConnIDFromContext(ctx); ok && connID != 0 {
-		link = ratelimit.WrapLinkWithConnID(connID, link)
+		link = ratelimit.WrapLinkWithConnID(ctx, connID, sessionInbound.Tag, link)
 	}
 
 	return link
@@ -470,11 +470,37 @@ func (d *DefaultDispatcher) routedDispatch(ctx context.Context, link *transport.
 	ob := outbounds[len(outbounds)-1]
 
 	var handler outbound.Handler
+	var failover *routing.Failover
 
 	routingLink := routing_session.AsRoutingContext(ctx)
 	inTag := routingLink.GetInboundTag()
 	isPickRoute := 0
 
+	// custom
+	//	users over their device limit or traffic quota (block/close action) don't get new connections
+	if uuid, ok := ratelimit.UUIDFromContext(ctx); ok {
+		err := ratelimit.CheckDeviceAllowed(ctx)
+		if err != nil {
+			name := "user>>>" + uuid + ">>>devices>>>rejected"
+			if c, _ := stats.GetOrRegisterCounter(d.stats, name); c != nil {
+				c.Add(1)
+			}
+		} else {
+			err = ratelimit.CheckUserAllowed(uuid)
+		}
+		if err != nil {
+			errors.LogInfo(ctx, err, ": rejecting [", destination, "] for user ", uuid)
+			if accessMessage := log.AccessMessageFromContext(ctx); accessMessage != nil {
+				accessMessage.Status = log.AccessRejected
+				accessMessage.Reason = err
+				log.Record(accessMessage)
+			}
+			common.Close(link.Writer)
+			common.Interrupt(link.Reader)
+			return
+		}
+	}
+
 	// custom
 	//	choose outbound per deviceEntry lifetime (uuid + optional srcIP)
 	if session.GetForcedOutboundTagFromContext(ctx) == "" {
@@ -519,8 +545,12 @@ func (d *DefaultDispatcher) routedDispatch(ctx context.Context, link *transport.
 					errors.LogInfo(ctx, "taking detour [", outTag, "] for [", destination, "]")
 				} else {
 					errors.LogInfo(ctx, "Hit route rule: [", route.GetRuleTag(), "] so taking detour [", outTag, "] for [", destination, "]")
+					link = d.countRule(route.GetRuleTag(), link)
 				}
 				handler = h
+				if fr, ok := route.(routing.FailoverRoute); ok {
+					failover = fr.GetFailover()
+				}
 			} else {
 				errors.LogWarning(ctx, "non existing outTag: ", outTag)
 				common.Close(link.Writer)
@@ -559,5 +589,9 @@ func (d *DefaultDispatcher) routedDispatch(ctx context.Context, link *transport.
 		log.Record(accessMessage)
 	}
 
+	if failover != nil {
+		d.dispatchWithFailover(ctx, link, handler, failover)
+		return
+	}
 	handler.Dispatch(ctx, link)
 }
@@ -0,0 +1,421 @@
+package dispatcher
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/xtls/xray-core/common"
+	"github.com/xtls/xray-core/common/buf"
+	"github.com/xtls/xray-core/common/errors"
+	"github.com/xtls/xray-core/common/session"
+	"github.com/xtls/xray-core/features/outbound"
+	"github.com/xtls/xray-core/features/routing"
+	"github.com/xtls/xray-core/transport"
+)
+
+// failoverReplayLimit is how much client data is kept for the next outbound;
+// past it the connection stays with the outbound it has.
+const failoverReplayLimit = 64 * 1024
+
+// dispatchWithFailover dispatches link to handler and, while the client has
+// not got any data back, to the outbounds of failover in turn when the
+// previous one fails.
+func (d *DefaultDispatcher) dispatchWithFailover(ctx context.Context, link *transport.Link, handler outbound.Handler, failover *routing.Failover) {
+	outbounds := session.OutboundsFromContext(ctx)
+	ob := outbounds[len(outbounds)-1]
+	// handlers may resolve the target in place
+	target := ob.Target
+
+	attempts := failover.Attempts
+	if attempts <= 0 {
+		attempts = 1 + len(failover.OutboundTags)
+	}
+	next := failover.OutboundTags
+	uplink := newReplayReader(link.Reader)
+
+	for i := 1; ; i++ {
+		a := newFailoverAttempt(ctx, uplink, link.Writer, failover.Timeout)
+		ob.Target = target
+		ob.Conn = nil
+		ob.Tag = handler.Tag()
+		handler.Dispatch(a.ctx, &transport.Link{Reader: &attemptReader{attempt: a}, Writer: &attemptWriter{attempt: a}})
+
+		result := a.wait()
+		if result != attemptFailed {
+			return
+		}
+		// the handler tells the observatory itself
+		err := a.failure()
+
+		var nextHandler outbound.Handler
+		for nextHandler == nil && len(next) > 0 && i < attempts {
+			nextHandler = d.ohm.GetHandler(next[0])
+			next = next[1:]
+		}
+		if nextHandler == nil || uplink.overflowed() || ctx.Err() != nil {
+			a.finish()
+			return
+		}
+		errors.LogInfoInner(ctx, err, "failover from [", handler.Tag(), "] to [", nextHandler.Tag(), "]")
+		a.abandon()
+		handler = nextHandler
+	}
+}
+
+// replayReader reads the uplink of a connection and keeps the data until it
+// is clear which outbound takes the connection, so that another outbound can
+// be given the same data.
+type replayReader struct {
+	reader buf.Reader
+
+	// one read of reader at a time
+	readMu sync.Mutex
+
+	mu        sync.Mutex
+	log       []buf.MultiBuffer
+	size      int32
+	recording bool
+	overflow  bool
+	err       error
+}
+
+func newReplayReader(reader buf.Reader) *replayReader {
+	return &replayReader{reader: reader, recording: true}
+}
+
+// replayed returns a copy of entry pos of the log, if there is one.
+func (r *replayReader) replayed(pos int) (buf.MultiBuffer, error, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if pos < len(r.log) {
+		return copyMultiBuffer(r.log[pos]), nil, true
+	}
+	if r.err != nil {
+		return nil, r.err, true
+	}
+	return nil, nil, false
+}
+
+// read returns the data at pos of the log, or reads more.
+func (r *replayReader) read(pos int, timeout time.Duration) (buf.MultiBuffer, int, error) {
+	if mb, err, ok := r.replayed(pos); ok {
+		if err != nil {
+			return nil, pos, err
+		}
+		return mb, pos + 1, nil
+	}
+
+	r.readMu.Lock()
+	defer r.readMu.Unlock()
+	// another attempt may have read meanwhile
+	if mb, err, ok := r.replayed(pos); ok {
+		if err != nil {
+			return nil, pos, err
+		}
+		return mb, pos + 1, nil
+	}
+
+	var mb buf.MultiBuffer
+	var err error
+	if tr, ok := r.reader.(buf.TimeoutReader); ok && timeout > 0 {
+		mb, err = tr.ReadMultiBufferTimeout(timeout)
+	} else {
+		mb, err = r.reader.ReadMultiBuffer()
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err != nil {
+		if err != buf.ErrReadTimeout && r.recording {
+			r.err = err
+		}
+		return nil, pos, err
+	}
+	if !r.recording {
+		return mb, pos, nil
+	}
+	if r.size+mb.Len() > failoverReplayLimit {
+		r.stopLocked(true)
+		return mb, pos, nil
+	}
+	r.log = append(r.log, copyMultiBuffer(mb))
+	r.size += mb.Len()
+	return mb, len(r.log), nil
+}
+
+// stop ends recording; the log stays for an attempt still replaying it.
+func (r *replayReader) stop() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.stopLocked(false)
+}
+
+func (r *replayReader) stopLocked(overflow bool) {
+	r.recording = false
+	r.overflow = r.overflow || overflow
+}
+
+func (r *replayReader) overflowed() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.overflow
+}
+
+func (r *replayReader) release() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, mb := range r.log {
+		buf.ReleaseMulti(mb)
+	}
+	r.log = nil
+}
+
+func copyMultiBuffer(mb buf.MultiBuffer) buf.MultiBuffer {
+	result := make(buf.MultiBuffer, 0, len(mb))
+	for _, b := range mb {
+		nb := buf.New()
+		nb.Write(b.Bytes())
+		nb.UDP = b.UDP
+		result = append(result, nb)
+	}
+	return result
+}
+
+type attemptResult int
+
+const (
+	attemptPending attemptResult = iota
+	// the client got data, the connection stays with this outbound
+	attemptCommitted
+	attemptClosed
+	attemptFailed
+	// failed and another outbound takes over
+	attemptAbandoned
+)
+
+// failoverAttempt is one outbound trying a connection. Until the client gets
+// data, an interrupt of the link is held back so that the next outbound can
+// take over.
+type failoverAttempt struct {
+	ctx      context.Context
+	cancel   context.CancelFunc
+	uplink   *replayReader
+	downlink buf.Writer
+	timer    *time.Timer
+
+	mu                sync.Mutex
+	result            attemptResult
+	connected         bool
+	expired           bool
+	readerInterrupted bool
+	err               error
+	done              chan struct{}
+}
+
+func newFailoverAttempt(ctx context.Context, uplink *replayReader, downlink buf.Writer, timeout time.Duration) *failoverAttempt {
+	a := &failoverAttempt{
+		uplink:   uplink,
+		downlink: downlink,
+		done:     make(chan struct{}),
+	}
+	a.ctx, a.cancel = context.WithCancel(ctx)
+	a.ctx = session.TrackedConnectionError(a.ctx, &attemptErrorFeedback{attempt: a, parent: ctx})
+	a.ctx = session.TrackedOutboundDial(a.ctx, a)
+	if timeout > 0 {
+		a.timer = time.AfterFunc(timeout, func() {
+			a.mu.Lock()
+			expired := !a.connected && a.result == attemptPending
+			if expired {
+				a.expired = true
+				if a.err == nil {
+					a.err = errors.New("failed to connect in ", timeout)
+				}
+			}
+			a.mu.Unlock()
+			if expired {
+				a.cancel()
+			}
+		})
+	}
+	return a
+}
+
+// SubmitDial implements session.TrackedDialFeedback.
+func (a *failoverAttempt) SubmitDial(err error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if err == nil {
+		a.connected = true
+	} else if a.err == nil {
+		a.err = err
+	}
+}
+
+type attemptErrorFeedback struct {
+	attempt *failoverAttempt
+	parent  context.Context
+}
+
+// SubmitError implements session.TrackedRequestErrorFeedback.
+func (f *attemptErrorFeedback) SubmitError(err error) {
+	f.attempt.mu.Lock()
+	if f.attempt.err == nil {
+		f.attempt.err = err
+	}
+	f.attempt.mu.Unlock()
+	session.SubmitOutboundErrorToOriginator(f.parent, err)
+}
+
+func (a *failoverAttempt) settleLocked(result attemptResult) {
+	if a.result != attemptPending {
+		return
+	}
+	a.result = result
+	if a.timer != nil {
+		a.timer.Stop()
+	}
+	close(a.done)
+}
+
+func (a *failoverAttempt) wait() attemptResult {
+	<-a.done
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.result
+}
+
+func (a *failoverAttempt) failure() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.err
+}
+
+// finish lets a failed attempt end the connection.
+func (a *failoverAttempt) finish() {
+	a.mu.Lock()
+	interrupted := a.readerInterrupted
+	a.mu.Unlock()
+	common.Interrupt(a.downlink)
+	if interrupted {
+		common.Interrupt(a.uplink.reader)
+	}
+	a.uplink.release()
+}
+
+// abandon cuts a failed attempt off the link for the next one.
+func (a *failoverAttempt) abandon() {
+	a.mu.Lock()
+	a.result = attemptAbandoned
+	a.mu.Unlock()
+	a.cancel()
+}
+
+type attemptReader struct {
+	attempt *failoverAttempt
+	pos     int
+}
+
+func (r *attemptReader) read(timeout time.Duration) (buf.MultiBuffer, error) {
+	a := r.attempt
+	a.mu.Lock()
+	abandoned := a.result == attemptAbandoned
+	a.mu.Unlock()
+	if abandoned {
+		return nil, io.ErrClosedPipe
+	}
+	mb, pos, err := a.uplink.read(r.pos, timeout)
+	r.pos = pos
+	return mb, err
+}
+
+// ReadMultiBuffer implements buf.Reader.
+func (r *attemptReader) ReadMultiBuffer() (buf.MultiBuffer, error) {
+	return r.read(0)
+}
+
+// ReadMultiBufferTimeout implements buf.TimeoutReader.
+func (r *attemptReader) ReadMultiBufferTimeout(timeout time.Duration) (buf.MultiBuffer, error) {
+	return r.read(timeout)
+}
+
+// Interrupt implements common.Interruptible.
+func (r *attemptReader) Interrupt() {
+	a := r.attempt
+	a.mu.Lock()
+	switch a.result {
+	case attemptPending, attemptFailed:
+		// up to the dispatcher
+		a.readerInterrupted = true
+		a.mu.Unlock()
+		return
+	case attemptAbandoned:
+		a.mu.Unlock()
+		return
+	}
+	a.mu.Unlock()
+	common.Interrupt(a.uplink.reader)
+	a.uplink.release()
+}
+
+type attemptWriter struct {
+	attempt *failoverAttempt
+}
+
+// WriteMultiBuffer implements buf.Writer.
+func (w *attemptWriter) WriteMultiBuffer(mb buf.MultiBuffer) error {
+	a := w.attempt
+	a.mu.Lock()
+	switch a.result {
+	case attemptAbandoned, attemptFailed:
+		a.mu.Unlock()
+		buf.ReleaseMulti(mb)
+		return io.ErrClosedPipe
+	case attemptPending:
+		if !mb.IsEmpty() {
+			a.settleLocked(attemptCommitted)
+			a.uplink.stop()
+		}
+	}
+	a.mu.Unlock()
+	return a.downlink.WriteMultiBuffer(mb)
+}
+
+// Close implements common.Closable.
+func (w *attemptWriter) Close() error {
+	a := w.attempt
+	a.mu.Lock()
+	if a.result == attemptAbandoned || a.result == attemptFailed {
+		a.mu.Unlock()
+		return nil
+	}
+	if a.expired && a.result == attemptPending {
+		// the proxy took the cancel for a normal end
+		a.settleLocked(attemptFailed)
+		a.mu.Unlock()
+		return nil
+	}
+	// a clean close without data is not a failure
+	a.settleLocked(attemptClosed)
+	a.uplink.stop()
+	a.mu.Unlock()
+	return common.Close(a.downlink)
+}
+
+// Interrupt implements common.Interruptible.
+func (w *attemptWriter) Interrupt() {
+	a := w.attempt
+	a.mu.Lock()
+	switch a.result {
+	case attemptPending:
+		a.settleLocked(attemptFailed)
+		a.mu.Unlock()
+		return
+	case attemptAbandoned, attemptFailed:
+		a.mu.Unlock()
+		return
+	}
+	a.mu.Unlock()
+	common.Interrupt(a.downlink)
+}
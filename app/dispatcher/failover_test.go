@@ -0,0 +1,96 @@
+package dispatcher
+
+import (
+	"context"
+	"testing"
+
+	"github.com/xtls/xray-core/common"
+	"github.com/xtls/xray-core/common/buf"
+	"github.com/xtls/xray-core/common/errors"
+	"github.com/xtls/xray-core/common/net"
+	"github.com/xtls/xray-core/common/session"
+	"github.com/xtls/xray-core/features/outbound"
+	"github.com/xtls/xray-core/features/routing"
+	"github.com/xtls/xray-core/transport"
+	"github.com/xtls/xray-core/transport/pipe"
+)
+
+type testHandler struct {
+	outbound.Handler
+	tag      string
+	fail     bool
+	received []string
+}
+
+func (h *testHandler) Tag() string {
+	return h.tag
+}
+
+func (h *testHandler) Dispatch(ctx context.Context, link *transport.Link) {
+	mb, err := link.Reader.ReadMultiBuffer()
+	if err == nil {
+		h.received = append(h.received, mb.String())
+		buf.ReleaseMulti(mb)
+	}
+	if h.fail {
+		session.SubmitOutboundDialToOriginator(ctx, errors.New("refused by ", h.tag))
+		common.Interrupt(link.Writer)
+	} else {
+		common.Must(link.Writer.WriteMultiBuffer(buf.MergeBytes(nil, []byte("reply from "+h.tag))))
+		common.Close(link.Writer)
+	}
+	common.Interrupt(link.Reader)
+}
+
+type testManager struct {
+	outbound.Manager
+	handlers map[string]outbound.Handler
+}
+
+func (m *testManager) GetHandler(tag string) outbound.Handler {
+	return m.handlers[tag]
+}
+
+func TestDispatchWithFailover(t *testing.T) {
+	primary := &testHandler{tag: "primary", fail: true}
+	broken := &testHandler{tag: "broken", fail: true}
+	backup := &testHandler{tag: "backup"}
+	d := &DefaultDispatcher{
+		ohm: &testManager{handlers: map[string]outbound.Handler{
+			"broken": broken,
+			"backup": backup,
+		}},
+	}
+
+	dispatch := func(failover *routing.Failover) (string, error) {
+		ob := &session.Outbound{Target: net.TCPDestination(net.DomainAddress("example.com"), 443)}
+		ctx := session.ContextWithOutbounds(context.Background(), []*session.Outbound{ob})
+		uplinkReader, uplinkWriter := pipe.New()
+		downlinkReader, downlinkWriter := pipe.New()
+		common.Must(uplinkWriter.WriteMultiBuffer(buf.MergeBytes(nil, []byte("hello"))))
+
+		d.dispatchWithFailover(ctx, &transport.Link{Reader: uplinkReader, Writer: downlinkWriter}, primary, failover)
+		mb, err := downlinkReader.ReadMultiBuffer()
+		defer buf.ReleaseMulti(mb)
+		return mb.String(), err
+	}
+
+	reply, err := dispatch(&routing.Failover{OutboundTags: []string{"missing", "broken", "backup"}})
+	common.Must(err)
+	if reply != "reply from backup" {
+		t.Fatalf("unexpected reply %q", reply)
+	}
+	for _, h := range []*testHandler{primary, broken, backup} {
+		if len(h.received) != 1 || h.received[0] != "hello" {
+			t.Errorf("%s got %q, want the replayed request", h.tag, h.received)
+		}
+	}
+
+	// two attempts end with broken
+	if _, err := dispatch(&routing.Failover{OutboundTags: []string{"broken", "backup"}, Attempts: 2}); err == nil {
+		t.Fatal("expected the connection to fail")
+	}
+	if len(backup.received) != 1 {
+		t.Error("unexpected attempt with backup")
+	}
+}
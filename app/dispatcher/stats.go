@@ -1,9 +1,12 @@
 package dispatcher
 
 import (
+	"time"
+
 	"github.com/xtls/xray-core/common"
 	"github.com/xtls/xray-core/common/buf"
 	"github.com/xtls/xray-core/features/stats"
+	"github.com/xtls/xray-core/transport"
 )
 
 type SizeStatWriter struct {
@@ -23,3 +26,67 @@ func (w *SizeStatWriter) Close() error {
 func (w *SizeStatWriter) Interrupt() {
 	common.Interrupt(w.Writer)
 }
+
+// SizeStatReader counts the bytes read from Reader.
+type SizeStatReader struct {
+	Counter stats.Counter
+	Reader  buf.Reader
+}
+
+// NewSizeStatReader wraps r, keeping it a buf.TimeoutReader if it is one.
+func NewSizeStatReader(c stats.Counter, r buf.Reader) buf.Reader {
+	sr := &SizeStatReader{Counter: c, Reader: r}
+	if tr, ok := r.(buf.TimeoutReader); ok {
+		return &sizeStatTimeoutReader{SizeStatReader: sr, timeoutReader: tr}
+	}
+	return sr
+}
+
+func (r *SizeStatReader) ReadMultiBuffer() (buf.MultiBuffer, error) {
+	mb, err := r.Reader.ReadMultiBuffer()
+	r.Counter.Add(int64(mb.Len()))
+	return mb, err
+}
+
+func (r *SizeStatReader) Interrupt() {
+	common.Interrupt(r.Reader)
+}
+
+func (r *SizeStatReader) Close() error {
+	return common.Close(r.Reader)
+}
+
+type sizeStatTimeoutReader struct {
+	*SizeStatReader
+	timeoutReader buf.TimeoutReader
+}
+
+func (r *sizeStatTimeoutReader) ReadMultiBufferTimeout(timeout time.Duration) (buf.MultiBuffer, error) {
+	mb, err := r.timeoutReader.ReadMultiBufferTimeout(timeout)
+	r.Counter.Add(int64(mb.Len()))
+	return mb, err
+}
+
+// countRule counts the connection and its traffic into the counters of the
+// routing rule it matched. The router registers them only for tagged rules
+// with rule stats enabled, so usually there is nothing to do.
+func (d *DefaultDispatcher) countRule(ruleTag string, link *transport.Link) *transport.Link {
+	if d.stats == nil {
+		return link
+	}
+	prefix := "rule>>>" + ruleTag + ">>>"
+	hits := d.stats.GetCounter(prefix + "hits")
+	if hits == nil {
+		return link
+	}
+	hits.Add(1)
+
+	counted := &transport.Link{Reader: link.Reader, Writer: link.Writer}
+	if c := d.stats.GetCounter(prefix + "traffic>>>uplink"); c != nil {
+		counted.Reader = NewSizeStatReader(c, counted.Reader)
+	}
+	if c := d.stats.GetCounter(prefix + "traffic>>>downlink"); c != nil {
+		counted.Writer = &SizeStatWriter{Counter: c, Writer: counted.Writer}
+	}
+	return counted
+}
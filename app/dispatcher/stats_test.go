@@ -1,11 +1,14 @@
 package dispatcher_test
 
 import (
+	"bytes"
 	"testing"
+	"time"
 
 	. "github.com/xtls/xray-core/app/dispatcher"
 	"github.com/xtls/xray-core/common"
 	"github.com/xtls/xray-core/common/buf"
+	"github.com/xtls/xray-core/transport/pipe"
 )
 
 type TestCounter int64
@@ -42,3 +45,30 @@ func TestStatsWriter(t *testing.T) {
 		t.Fatal("unexpected counter value. want 7, but got ", c.Value())
 	}
 }
+
+func TestStatsReader(t *testing.T) {
+	var c TestCounter
+	pReader, pWriter := pipe.New()
+	reader := NewSizeStatReader(&c, pReader)
+	if _, ok := reader.(buf.TimeoutReader); !ok {
+		t.Fatal("expected the wrapper of a timeout reader to be a timeout reader")
+	}
+
+	common.Must(pWriter.WriteMultiBuffer(buf.MergeBytes(nil, []byte("abcd"))))
+	mb, err := reader.ReadMultiBuffer()
+	common.Must(err)
+	buf.ReleaseMulti(mb)
+
+	common.Must(pWriter.WriteMultiBuffer(buf.MergeBytes(nil, []byte("efg"))))
+	mb, err = reader.(buf.TimeoutReader).ReadMultiBufferTimeout(time.Second)
+	common.Must(err)
+	buf.ReleaseMulti(mb)
+
+	if c.Value() != 7 {
+		t.Fatal("unexpected counter value. want 7, but got ", c.Value())
+	}
+
+	if _, ok := NewSizeStatReader(&c, buf.NewReader(bytes.NewReader(nil))).(buf.TimeoutReader); ok {
+		t.Fatal("expected a plain reader to stay plain")
+	}
+}
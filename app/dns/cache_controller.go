@@ -4,6 +4,7 @@ import (
 	"context"
 	go_errors "errors"
 	"runtime"
+	"strings"
 	"sync"
 	"time"
 
@@ -32,12 +33,17 @@ type CacheController struct {
 
 	ips      map[string]*record
 	dirtyips map[string]*record
+	// migration is bumped when a migration is dropped
+	migration uint64
 
 	sync.RWMutex
 	pub           *pubsub.Service
 	cacheCleanup  *task.Periodic
 	highWatermark int
 	requestGroup  singleflight.Group
+
+	// closed once the nameserver is removed, the cleanup isn't started again
+	closed bool
 }
 
 func NewCacheController(name string, disableCache bool, serveStale bool, serveExpiredTTL uint32) *CacheController {
@@ -187,6 +193,7 @@ func (c *CacheController) migrate() {
 
 	c.RLock()
 	dirtyips := c.dirtyips
+	migration := c.migration
 	c.RUnlock()
 
 	// double check to prevent upper call multiple cleanup tasks
@@ -201,27 +208,40 @@ func (c *CacheController) migrate() {
 		batch = append(batch, migrationEntry{domain, recD})
 
 		if len(batch) >= migrationBatchSize {
-			c.flush(batch)
+			if !c.flush(batch, migration) {
+				return
+			}
 			batch = batch[:0]
 			runtime.Gosched()
 		}
 	}
-	if len(batch) > 0 {
-		c.flush(batch)
+	if len(batch) > 0 && !c.flush(batch, migration) {
+		return
 	}
 
 	c.Lock()
-	c.dirtyips = nil
+	if c.migration == migration {
+		c.dirtyips = nil
+	}
 	c.Unlock()
 
 	errors.LogDebug(context.Background(), c.name, " cache migration completed")
 }
 
-func (c *CacheController) flush(batch []migrationEntry) {
+// flush moves batch into the cache, unless the migration was dropped.
+// Records flushed meanwhile are skipped.
+func (c *CacheController) flush(batch []migrationEntry, migration uint64) bool {
 	c.Lock()
 	defer c.Unlock()
 
+	if c.migration != migration {
+		return false
+	}
+
 	for _, dirty := range batch {
+		if c.dirtyips[dirty.key] != dirty.value {
+			continue
+		}
 		if cur := c.ips[dirty.key]; cur != nil {
 			merge := &record{}
 			if cur.A == nil {
@@ -239,6 +259,7 @@ func (c *CacheController) flush(batch []migrationEntry) {
 			c.ips[dirty.key] = dirty.value
 		}
 	}
+	return true
 }
 
 func (c *CacheController) updateRecord(req *dnsRequest, rep *IPRecord) {
@@ -291,6 +312,7 @@ func (c *CacheController) updateRecord(req *dnsRequest, rep *IPRecord) {
 	}
 
 	c.ips[req.domain] = newRec
+	closed := c.closed
 	c.Unlock()
 
 	if pubRecord != nil {
@@ -302,11 +324,19 @@ func (c *CacheController) updateRecord(req *dnsRequest, rep *IPRecord) {
 
 	errors.LogInfo(context.Background(), c.name, " got answer: ", req.domain, " ", req.reqType, " -> ", rep.IP, ", rtt: ", rtt, ", lock: ", lockWait)
 
-	if !c.serveStale || c.serveExpiredTTL != 0 {
+	if !closed && (!c.serveStale || c.serveExpiredTTL != 0) {
 		common.Must(c.cacheCleanup.Start())
 	}
 }
 
+// Close stops the cache cleanup of a removed nameserver.
+func (c *CacheController) Close() error {
+	c.Lock()
+	c.closed = true
+	c.Unlock()
+	return c.cacheCleanup.Close()
+}
+
 func (c *CacheController) findRecords(domain string) *record {
 	c.RLock()
 	defer c.RUnlock()
@@ -337,3 +367,92 @@ func closeSubscribers(sub4 *pubsub.Subscriber, sub6 *pubsub.Subscriber) {
 		sub6.Close()
 	}
 }
+
+// listRecords returns the records of domain, or all if empty. Domains are
+// matched case-insensitively.
+func (c *CacheController) listRecords(domain string) []*CacheEntry {
+	c.RLock()
+	defer c.RUnlock()
+
+	now := time.Now()
+	var entries []*CacheEntry
+	add := func(name string, rec *record) {
+		if domain != "" && !strings.EqualFold(name, domain) {
+			return
+		}
+		for _, r := range []struct {
+			typ string
+			rec *IPRecord
+		}{{"A", rec.A}, {"AAAA", rec.AAAA}} {
+			if r.rec == nil {
+				continue
+			}
+			entry := &CacheEntry{
+				Domain: name,
+				Type:   r.typ,
+				RCode:  uint16(r.rec.RCode),
+				TTL:    int32(r.rec.Expire.Sub(now).Seconds()),
+			}
+			for _, ip := range r.rec.IP {
+				entry.IP = append(entry.IP, ip.String())
+			}
+			entries = append(entries, entry)
+		}
+	}
+	for name, rec := range c.ips {
+		add(name, rec)
+	}
+	for name, rec := range c.dirtyips {
+		if _, found := c.ips[name]; !found {
+			add(name, rec)
+		}
+	}
+	return entries
+}
+
+// flushRecords removes the records of domain, or all if empty, and returns
+// how many domains were removed. Domains are matched case-insensitively.
+func (c *CacheController) flushRecords(domain string) int {
+	c.Lock()
+	defer c.Unlock()
+
+	matches := func(name string) bool {
+		return domain == "" || strings.EqualFold(name, domain)
+	}
+	removed := 0
+	for name := range c.dirtyips {
+		if _, found := c.ips[name]; !found && matches(name) {
+			removed++
+		}
+	}
+	if c.dirtyips != nil {
+		if domain == "" {
+			c.dirtyips = nil
+			c.migration++
+		} else {
+			// the migration is ranging over the map, so the records are
+			// left out of a copy of it, which flush checks against
+			dirtyips := make(map[string]*record, len(c.dirtyips))
+			for name, rec := range c.dirtyips {
+				if !matches(name) {
+					dirtyips[name] = rec
+				}
+			}
+			c.dirtyips = dirtyips
+		}
+	}
+
+	if domain == "" {
+		removed += len(c.ips)
+		c.ips = make(map[string]*record)
+		c.highWatermark = 0
+		return removed
+	}
+	for name := range c.ips {
+		if matches(name) {
+			delete(c.ips, name)
+			removed++
+		}
+	}
+	return removed
+}
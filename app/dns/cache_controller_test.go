@@ -0,0 +1,45 @@
+package dns
+
+import (
+	"testing"
+	"time"
+
+	"github.com/xtls/xray-core/common/net"
+)
+
+func TestFlushRecordsDuringMigration(t *testing.T) {
+	c := NewCacheController("test", false, false, 0)
+	newRecord := func(ip byte) *record {
+		return &record{A: &IPRecord{IP: []net.IP{{10, 0, 0, ip}}, Expire: time.Now().Add(time.Hour)}}
+	}
+	c.dirtyips = map[string]*record{
+		"a.example.com.": newRecord(1),
+		"b.example.com.": newRecord(2),
+		"c.example.com.": newRecord(3),
+	}
+	c.ips["c.example.com."] = newRecord(4)
+
+	if removed := c.flushRecords("A.example.com."); removed != 1 {
+		t.Fatalf("flushed %d domains", removed)
+	}
+	if c.dirtyips == nil || c.migration != 0 {
+		t.Fatal("a targeted flush dropped the migration")
+	}
+	if c.findRecords("a.example.com.") != nil {
+		t.Fatal("flushed record still found")
+	}
+	if c.findRecords("b.example.com.") == nil {
+		t.Fatal("record of another domain dropped")
+	}
+
+	c.migrate()
+	if c.dirtyips != nil {
+		t.Fatal("migration not completed")
+	}
+	if len(c.ips) != 2 || c.ips["a.example.com."] != nil || c.ips["b.example.com."] == nil {
+		t.Fatalf("unexpected cache after migration %v", c.ips)
+	}
+	if ip := c.ips["c.example.com."].A.IP[0]; !ip.Equal(net.IP{10, 0, 0, 4}) {
+		t.Fatalf("migration overwrote the newer record with %v", ip)
+	}
+}
@@ -0,0 +1,153 @@
+package command
+
+import (
+	"context"
+	"time"
+
+	"github.com/xtls/xray-core/app/dns"
+	"github.com/xtls/xray-core/common"
+	"github.com/xtls/xray-core/common/errors"
+	"github.com/xtls/xray-core/core"
+	dns_feature "github.com/xtls/xray-core/features/dns"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// dnsServer is an implementation of DNSService.
+type dnsServer struct {
+	client dns_feature.Client
+}
+
+func NewDNSServer(client dns_feature.Client) DNSServiceServer {
+	return &dnsServer{client: client}
+}
+
+func (s *dnsServer) dns() (*dns.DNS, error) {
+	d, ok := s.client.(*dns.DNS)
+	if !ok {
+		return nil, errors.New("unsupported DNS implementation")
+	}
+	return d, nil
+}
+
+func (s *dnsServer) AddHosts(ctx context.Context, request *AddHostsRequest) (*AddHostsResponse, error) {
+	d, err := s.dns()
+	if err != nil {
+		return nil, err
+	}
+	return &AddHostsResponse{}, d.AddHosts(request.Hosts)
+}
+
+func (s *dnsServer) RemoveHosts(ctx context.Context, request *RemoveHostsRequest) (*RemoveHostsResponse, error) {
+	d, err := s.dns()
+	if err != nil {
+		return nil, err
+	}
+	removed, err := d.RemoveHosts(request.Hosts)
+	if err != nil {
+		return nil, err
+	}
+	return &RemoveHostsResponse{Removed: uint32(removed)}, nil
+}
+
+func (s *dnsServer) AddNameServer(ctx context.Context, request *AddNameServerRequest) (*AddNameServerResponse, error) {
+	d, err := s.dns()
+	if err != nil {
+		return nil, err
+	}
+	if err := d.AddNameServer(request.NameServer...); err != nil {
+		return nil, err
+	}
+	return &AddNameServerResponse{}, nil
+}
+
+func (s *dnsServer) RemoveNameServer(ctx context.Context, request *RemoveNameServerRequest) (*RemoveNameServerResponse, error) {
+	d, err := s.dns()
+	if err != nil {
+		return nil, err
+	}
+	removed := d.RemoveNameServer(request.Name)
+	if removed == 0 {
+		return nil, status.Error(codes.NotFound, request.Name+" not found.")
+	}
+	return &RemoveNameServerResponse{Removed: uint32(removed)}, nil
+}
+
+func (s *dnsServer) ListCache(ctx context.Context, request *ListCacheRequest) (*ListCacheResponse, error) {
+	d, err := s.dns()
+	if err != nil {
+		return nil, err
+	}
+	response := &ListCacheResponse{}
+	for _, e := range d.ListCache(request.Server, request.Domain) {
+		response.Entry = append(response.Entry, &CacheEntry{
+			Server: e.Server,
+			Domain: e.Domain,
+			Type:   e.Type,
+			Ip:     e.IP,
+			Rcode:  uint32(e.RCode),
+			Ttl:    e.TTL,
+		})
+	}
+	return response, nil
+}
+
+func (s *dnsServer) FlushCache(ctx context.Context, request *FlushCacheRequest) (*FlushCacheResponse, error) {
+	d, err := s.dns()
+	if err != nil {
+		return nil, err
+	}
+	return &FlushCacheResponse{Removed: uint32(d.FlushCache(request.Server, request.Domain))}, nil
+}
+
+func (s *dnsServer) Resolve(ctx context.Context, request *ResolveRequest) (*ResolveResponse, error) {
+	if request.Domain == "" {
+		return nil, status.Error(codes.InvalidArgument, "empty domain")
+	}
+	option := dns_feature.IPOption{IPv4Enable: true, IPv6Enable: true}
+	switch request.QueryStrategy {
+	case dns.QueryStrategy_USE_IP4:
+		option.IPv6Enable = false
+	case dns.QueryStrategy_USE_IP6:
+		option.IPv4Enable = false
+	}
+
+	response := &ResolveResponse{}
+	if d, ok := s.client.(*dns.DNS); ok {
+		response.Server = d.QueryOrder(request.Domain)
+	}
+	start := time.Now()
+	ips, ttl, err := s.client.LookupIP(request.Domain, option)
+	response.ElapsedMs = time.Since(start).Milliseconds()
+	if err != nil {
+		response.Error = err.Error()
+	}
+	for _, ip := range ips {
+		response.Ip = append(response.Ip, ip.String())
+	}
+	response.Ttl = ttl
+	return response, nil
+}
+
+func (s *dnsServer) mustEmbedUnimplementedDNSServiceServer() {}
+
+type service struct {
+	client dns_feature.Client
+}
+
+func (s *service) Register(server *grpc.Server) {
+	RegisterDNSServiceServer(server, NewDNSServer(s.client))
+}
+
+func init() {
+	common.Must(common.RegisterConfig((*Config)(nil), func(ctx context.Context, cfg interface{}) (interface{}, error) {
+		s := new(service)
+
+		core.RequireFeatures(ctx, func(client dns_feature.Client) {
+			s.client = client
+		})
+
+		return s, nil
+	}))
+}
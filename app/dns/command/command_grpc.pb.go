@@ -0,0 +1,349 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v5.28.2
+// source: app/dns/command/command.proto
+
+package command
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	DNSService_AddHosts_FullMethodName         = "/xray.app.dns.command.DNSService/AddHosts"
+	DNSService_RemoveHosts_FullMethodName      = "/xray.app.dns.command.DNSService/RemoveHosts"
+	DNSService_AddNameServer_FullMethodName    = "/xray.app.dns.command.DNSService/AddNameServer"
+	DNSService_RemoveNameServer_FullMethodName = "/xray.app.dns.command.DNSService/RemoveNameServer"
+	DNSService_ListCache_FullMethodName        = "/xray.app.dns.command.DNSService/ListCache"
+	DNSService_FlushCache_FullMethodName       = "/xray.app.dns.command.DNSService/FlushCache"
+	DNSService_Resolve_FullMethodName          = "/xray.app.dns.command.DNSService/Resolve"
+)
+
+// DNSServiceClient is the client API for DNSService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type DNSServiceClient interface {
+	AddHosts(ctx context.Context, in *AddHostsRequest, opts ...grpc.CallOption) (*AddHostsResponse, error)
+	RemoveHosts(ctx context.Context, in *RemoveHostsRequest, opts ...grpc.CallOption) (*RemoveHostsResponse, error)
+	AddNameServer(ctx context.Context, in *AddNameServerRequest, opts ...grpc.CallOption) (*AddNameServerResponse, error)
+	RemoveNameServer(ctx context.Context, in *RemoveNameServerRequest, opts ...grpc.CallOption) (*RemoveNameServerResponse, error)
+	ListCache(ctx context.Context, in *ListCacheRequest, opts ...grpc.CallOption) (*ListCacheResponse, error)
+	FlushCache(ctx context.Context, in *FlushCacheRequest, opts ...grpc.CallOption) (*FlushCacheResponse, error)
+	Resolve(ctx context.Context, in *ResolveRequest, opts ...grpc.CallOption) (*ResolveResponse, error)
+}
+
+type dNSServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewDNSServiceClient(cc grpc.ClientConnInterface) DNSServiceClient {
+	return &dNSServiceClient{cc}
+}
+
+func (c *dNSServiceClient) AddHosts(ctx context.Context, in *AddHostsRequest, opts ...grpc.CallOption) (*AddHostsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AddHostsResponse)
+	err := c.cc.Invoke(ctx, DNSService_AddHosts_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dNSServiceClient) RemoveHosts(ctx context.Context, in *RemoveHostsRequest, opts ...grpc.CallOption) (*RemoveHostsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RemoveHostsResponse)
+	err := c.cc.Invoke(ctx, DNSService_RemoveHosts_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dNSServiceClient) AddNameServer(ctx context.Context, in *AddNameServerRequest, opts ...grpc.CallOption) (*AddNameServerResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AddNameServerResponse)
+	err := c.cc.Invoke(ctx, DNSService_AddNameServer_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dNSServiceClient) RemoveNameServer(ctx context.Context, in *RemoveNameServerRequest, opts ...grpc.CallOption) (*RemoveNameServerResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RemoveNameServerResponse)
+	err := c.cc.Invoke(ctx, DNSService_RemoveNameServer_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dNSServiceClient) ListCache(ctx context.Context, in *ListCacheRequest, opts ...grpc.CallOption) (*ListCacheResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListCacheResponse)
+	err := c.cc.Invoke(ctx, DNSService_ListCache_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dNSServiceClient) FlushCache(ctx context.Context, in *FlushCacheRequest, opts ...grpc.CallOption) (*FlushCacheResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(FlushCacheResponse)
+	err := c.cc.Invoke(ctx, DNSService_FlushCache_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dNSServiceClient) Resolve(ctx context.Context, in *ResolveRequest, opts ...grpc.CallOption) (*ResolveResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ResolveResponse)
+	err := c.cc.Invoke(ctx, DNSService_Resolve_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// DNSServiceServer is the server API for DNSService service.
+// All implementations must embed UnimplementedDNSServiceServer
+// for forward compatibility.
+type DNSServiceServer interface {
+	AddHosts(context.Context, *AddHostsRequest) (*AddHostsResponse, error)
+	RemoveHosts(context.Context, *RemoveHostsRequest) (*RemoveHostsResponse, error)
+	AddNameServer(context.Context, *AddNameServerRequest) (*AddNameServerResponse, error)
+	RemoveNameServer(context.Context, *RemoveNameServerRequest) (*RemoveNameServerResponse, error)
+	ListCache(context.Context, *ListCacheRequest) (*ListCacheResponse, error)
+	FlushCache(context.Context, *FlushCacheRequest) (*FlushCacheResponse, error)
+	Resolve(context.Context, *ResolveRequest) (*ResolveResponse, error)
+	mustEmbedUnimplementedDNSServiceServer()
+}
+
+// UnimplementedDNSServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedDNSServiceServer struct{}
+
+func (UnimplementedDNSServiceServer) AddHosts(context.Context, *AddHostsRequest) (*AddHostsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddHosts not implemented")
+}
+func (UnimplementedDNSServiceServer) RemoveHosts(context.Context, *RemoveHostsRequest) (*RemoveHostsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveHosts not implemented")
+}
+func (UnimplementedDNSServiceServer) AddNameServer(context.Context, *AddNameServerRequest) (*AddNameServerResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddNameServer not implemented")
+}
+func (UnimplementedDNSServiceServer) RemoveNameServer(context.Context, *RemoveNameServerRequest) (*RemoveNameServerResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveNameServer not implemented")
+}
+func (UnimplementedDNSServiceServer) ListCache(context.Context, *ListCacheRequest) (*ListCacheResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListCache not implemented")
+}
+func (UnimplementedDNSServiceServer) FlushCache(context.Context, *FlushCacheRequest) (*FlushCacheResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FlushCache not implemented")
+}
+func (UnimplementedDNSServiceServer) Resolve(context.Context, *ResolveRequest) (*ResolveResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Resolve not implemented")
+}
+func (UnimplementedDNSServiceServer) mustEmbedUnimplementedDNSServiceServer() {}
+func (UnimplementedDNSServiceServer) testEmbeddedByValue()                    {}
+
+// UnsafeDNSServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to DNSServiceServer will
+// result in compilation errors.
+type UnsafeDNSServiceServer interface {
+	mustEmbedUnimplementedDNSServiceServer()
+}
+
+func RegisterDNSServiceServer(s grpc.ServiceRegistrar, srv DNSServiceServer) {
+	// If the following call pancis, it indicates UnimplementedDNSServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&DNSService_ServiceDesc, srv)
+}
+
+func _DNSService_AddHosts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddHostsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DNSServiceServer).AddHosts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DNSService_AddHosts_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DNSServiceServer).AddHosts(ctx, req.(*AddHostsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DNSService_RemoveHosts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveHostsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DNSServiceServer).RemoveHosts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DNSService_RemoveHosts_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DNSServiceServer).RemoveHosts(ctx, req.(*RemoveHostsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DNSService_AddNameServer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddNameServerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DNSServiceServer).AddNameServer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DNSService_AddNameServer_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DNSServiceServer).AddNameServer(ctx, req.(*AddNameServerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DNSService_RemoveNameServer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveNameServerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DNSServiceServer).RemoveNameServer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DNSService_RemoveNameServer_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DNSServiceServer).RemoveNameServer(ctx, req.(*RemoveNameServerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DNSService_ListCache_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListCacheRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DNSServiceServer).ListCache(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DNSService_ListCache_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DNSServiceServer).ListCache(ctx, req.(*ListCacheRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DNSService_FlushCache_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FlushCacheRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DNSServiceServer).FlushCache(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DNSService_FlushCache_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DNSServiceServer).FlushCache(ctx, req.(*FlushCacheRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DNSService_Resolve_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResolveRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DNSServiceServer).Resolve(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DNSService_Resolve_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DNSServiceServer).Resolve(ctx, req.(*ResolveRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// DNSService_ServiceDesc is the grpc.ServiceDesc for DNSService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var DNSService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "xray.app.dns.command.DNSService",
+	HandlerType: (*DNSServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "AddHosts",
+			Handler:    _DNSService_AddHosts_Handler,
+		},
+		{
+			MethodName: "RemoveHosts",
+			Handler:    _DNSService_RemoveHosts_Handler,
+		},
+		{
+			MethodName: "AddNameServer",
+			Handler:    _DNSService_AddNameServer_Handler,
+		},
+		{
+			MethodName: "RemoveNameServer",
+			Handler:    _DNSService_RemoveNameServer_Handler,
+		},
+		{
+			MethodName: "ListCache",
+			Handler:    _DNSService_ListCache_Handler,
+		},
+		{
+			MethodName: "FlushCache",
+			Handler:    _DNSService_FlushCache_Handler,
+		},
+		{
+			MethodName: "Resolve",
+			Handler:    _DNSService_Resolve_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "app/dns/command/command.proto",
+}
@@ -0,0 +1,51 @@
+package command_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/xtls/xray-core/app/dns"
+	. "github.com/xtls/xray-core/app/dns/command"
+	"github.com/xtls/xray-core/common"
+	"github.com/xtls/xray-core/common/net"
+	feature_dns "github.com/xtls/xray-core/features/dns"
+)
+
+type staticClient struct {
+	feature_dns.Client
+	option feature_dns.IPOption
+}
+
+func (c *staticClient) LookupIP(domain string, option feature_dns.IPOption) ([]net.IP, uint32, error) {
+	c.option = option
+	if domain != "example.com" {
+		return nil, 0, feature_dns.ErrEmptyResponse
+	}
+	return []net.IP{{192, 0, 2, 1}}, 60, nil
+}
+
+func TestResolve(t *testing.T) {
+	client := &staticClient{}
+	s := NewDNSServer(client)
+
+	resp, err := s.Resolve(context.Background(), &ResolveRequest{Domain: "example.com", QueryStrategy: dns.QueryStrategy_USE_IP4})
+	common.Must(err)
+	if r := cmp.Diff(resp.Ip, []string{"192.0.2.1"}); r != "" || resp.Ttl != 60 || resp.Error != "" {
+		t.Fatalf("unexpected response %v", resp)
+	}
+	if client.option.IPv6Enable || !client.option.IPv4Enable {
+		t.Errorf("unexpected option %+v", client.option)
+	}
+
+	resp, err = s.Resolve(context.Background(), &ResolveRequest{Domain: "example.org"})
+	common.Must(err)
+	if resp.Error == "" || len(resp.Ip) != 0 {
+		t.Fatalf("expected the failure in the response, got %v", resp)
+	}
+
+	// only the DNS app can be managed
+	if _, err := s.FlushCache(context.Background(), &FlushCacheRequest{}); err == nil {
+		t.Error("expected an error for an unsupported DNS client")
+	}
+}
@@ -27,12 +27,25 @@ type DNS struct {
 	disableFallbackIfMatch bool
 	enableParallelQuery    bool
 	ipOption               *dns.IPOption
-	hosts                  *StaticHosts
-	clients                []*Client
 	ctx                    context.Context
-	domainMatcher          strmatcher.IndexMatcher
-	matcherInfos           []*DomainMatcherInfo
 	checkSystem            bool
+
+	// what nameservers added at runtime are created with
+	config     *Config
+	clientIP   net.IP
+	defaultTag string
+	// nil if DNSSEC validation is off
+	trustAnchors TrustAnchors
+	// the localhost client added when the config has no nameservers, nil
+	// otherwise; nameservers added at runtime replace it
+	implicitLocal *Client
+
+	// the fields below may be replaced at runtime, under the lock
+	hosts         *StaticHosts
+	hostMappings  []*Config_HostMapping
+	clients       []*Client
+	domainMatcher strmatcher.IndexMatcher
+	matcherInfos  []*DomainMatcherInfo
 }
 
 // DomainMatcherInfo contains information attached to index returned by Server.domainMatcher
@@ -88,14 +101,35 @@ func New(ctx context.Context, config *Config) (*DNS, error) {
 		return nil, errors.New("failed to create hosts").Base(err)
 	}
 
-	var clients []*Client
-	domainRuleCount := 0
-
 	var defaultTag = config.Tag
 	if len(config.Tag) == 0 {
 		defaultTag = generateRandomTag()
 	}
 
+	var trustAnchors TrustAnchors
+	if config.Dnssec != nil {
+		trustAnchors, err = ParseTrustAnchors(config.Dnssec.TrustAnchor)
+		if err != nil {
+			return nil, errors.New("failed to parse DNSSEC trust anchors").Base(err)
+		}
+	}
+
+	s := &DNS{
+		hosts:                  hosts,
+		hostMappings:           config.StaticHosts,
+		ipOption:               &ipOption,
+		ctx:                    ctx,
+		config:                 config,
+		clientIP:               clientIP,
+		defaultTag:             defaultTag,
+		trustAnchors:           trustAnchors,
+		disableFallback:        config.DisableFallback,
+		disableFallbackIfMatch: config.DisableFallbackIfMatch,
+		enableParallelQuery:    config.EnableParallelQuery,
+		checkSystem:            checkSystem,
+	}
+
+	domainRuleCount := 0
 	for _, ns := range config.NameServer {
 		domainRuleCount += len(ns.PrioritizedDomain)
 	}
@@ -104,6 +138,7 @@ func New(ctx context.Context, config *Config) (*DNS, error) {
 	matcherInfos := make([]*DomainMatcherInfo, domainRuleCount+1)
 	domainMatcher := &strmatcher.MatcherGroup{}
 
+	var clients []*Client
 	for _, ns := range config.NameServer {
 		clientIdx := len(clients)
 		updateDomain := func(domainRule strmatcher.Matcher, originalRuleIdx int, matcherInfos []*DomainMatcherInfo) error {
@@ -115,60 +150,68 @@ func New(ctx context.Context, config *Config) (*DNS, error) {
 			return nil
 		}
 
-		myClientIP := clientIP
-		switch len(ns.ClientIp) {
-		case net.IPv4len, net.IPv6len:
-			myClientIP = net.IP(ns.ClientIp)
+		client, err := s.newClient(ns, &matcherInfos, updateDomain)
+		if err != nil {
+			return nil, err
 		}
+		clients = append(clients, client)
+	}
 
-		disableCache := config.DisableCache
-		if ns.DisableCache != nil {
-			disableCache = *ns.DisableCache
-		}
+	// If there is no DNS client in config, add a `localhost` DNS client
+	if len(clients) == 0 {
+		s.implicitLocal = NewLocalDNSClient(ipOption)
+		clients = append(clients, s.implicitLocal)
+	}
 
-		serveStale := config.ServeStale
-		if ns.ServeStale != nil {
-			serveStale = *ns.ServeStale
-		}
+	s.clients = clients
+	s.domainMatcher = domainMatcher
+	s.matcherInfos = matcherInfos
+	return s, nil
+}
 
-		serveExpiredTTL := config.ServeExpiredTTL
-		if ns.ServeExpiredTTL != nil {
-			serveExpiredTTL = *ns.ServeExpiredTTL
-		}
+// newClient creates the client of nameserver ns, with the options it
+// doesn't set taken from the config.
+func (s *DNS) newClient(ns *NameServer, matcherInfos *[]*DomainMatcherInfo, updateDomain func(strmatcher.Matcher, int, []*DomainMatcherInfo) error) (*Client, error) {
+	myClientIP := s.clientIP
+	switch len(ns.ClientIp) {
+	case net.IPv4len, net.IPv6len:
+		myClientIP = net.IP(ns.ClientIp)
+	}
 
-		var tag = defaultTag
-		if len(ns.Tag) > 0 {
-			tag = ns.Tag
-		}
-		clientIPOption := ResolveIpOptionOverride(ns.QueryStrategy, ipOption)
-		if !clientIPOption.IPv4Enable && !clientIPOption.IPv6Enable {
-			return nil, errors.New("no QueryStrategy available for ", ns.Address)
-		}
+	disableCache := s.config.DisableCache
+	if ns.DisableCache != nil {
+		disableCache = *ns.DisableCache
+	}
 
-		client, err := NewClient(ctx, ns, myClientIP, disableCache, serveStale, serveExpiredTTL, tag, clientIPOption, &matcherInfos, updateDomain)
-		if err != nil {
-			return nil, errors.New("failed to create client").Base(err)
-		}
-		clients = append(clients, client)
+	serveStale := s.config.ServeStale
+	if ns.ServeStale != nil {
+		serveStale = *ns.ServeStale
 	}
 
-	// If there is no DNS client in config, add a `localhost` DNS client
-	if len(clients) == 0 {
-		clients = append(clients, NewLocalDNSClient(ipOption))
+	serveExpiredTTL := s.config.ServeExpiredTTL
+	if ns.ServeExpiredTTL != nil {
+		serveExpiredTTL = *ns.ServeExpiredTTL
 	}
 
-	return &DNS{
-		hosts:                  hosts,
-		ipOption:               &ipOption,
-		clients:                clients,
-		ctx:                    ctx,
-		domainMatcher:          domainMatcher,
-		matcherInfos:           matcherInfos,
-		disableFallback:        config.DisableFallback,
-		disableFallbackIfMatch: config.DisableFallbackIfMatch,
-		enableParallelQuery:    config.EnableParallelQuery,
-		checkSystem:            checkSystem,
-	}, nil
+	var tag = s.defaultTag
+	if len(ns.Tag) > 0 {
+		tag = ns.Tag
+	}
+	clientIPOption := ResolveIpOptionOverride(ns.QueryStrategy, *s.ipOption)
+	if !clientIPOption.IPv4Enable && !clientIPOption.IPv6Enable {
+		return nil, errors.New("no QueryStrategy available for ", ns.Address)
+	}
+
+	var trustAnchors TrustAnchors
+	if !ns.SkipDnssec {
+		trustAnchors = s.trustAnchors
+	}
+
+	client, err := NewClient(s.ctx, ns, myClientIP, disableCache, serveStale, serveExpiredTTL, tag, clientIPOption, trustAnchors, matcherInfos, updateDomain)
+	if err != nil {
+		return nil, errors.New("failed to create client").Base(err)
+	}
+	return client, nil
 }
 
 // Type implements common.HasType.
@@ -192,7 +235,8 @@ func (s *DNS) IsOwnLink(ctx context.Context) bool {
 	if inbound == nil {
 		return false
 	}
-	for _, client := range s.clients {
+	clients, _, _ := s.nameServers()
+	for _, client := range clients {
 		if client.tag == inbound.Tag {
 			return true
 		}
@@ -222,7 +266,10 @@ func (s *DNS) LookupIP(domain string, option dns.IPOption) ([]net.IP, uint32, er
 	}
 
 	// Static host lookup
-	switch addrs, err := s.hosts.Lookup(domain, option); {
+	s.Lock()
+	hosts := s.hosts
+	s.Unlock()
+	switch addrs, err := hosts.Lookup(domain, option); {
 	case err != nil:
 		if go_errors.Is(err, dns.ErrEmptyResponse) {
 			return nil, 0, dns.ErrEmptyResponse
@@ -253,20 +300,21 @@ func (s *DNS) LookupIP(domain string, option dns.IPOption) ([]net.IP, uint32, er
 }
 
 func (s *DNS) sortClients(domain string) []*Client {
-	clients := make([]*Client, 0, len(s.clients))
-	clientUsed := make([]bool, len(s.clients))
-	clientNames := make([]string, 0, len(s.clients))
+	allClients, domainMatcher, matcherInfos := s.nameServers()
+	clients := make([]*Client, 0, len(allClients))
+	clientUsed := make([]bool, len(allClients))
+	clientNames := make([]string, 0, len(allClients))
 	domainRules := []string{}
 
 	// Priority domain matching
 	hasMatch := false
-	MatchSlice := s.domainMatcher.Match(domain)
+	MatchSlice := domainMatcher.Match(domain)
 	sort.Slice(MatchSlice, func(i, j int) bool {
 		return MatchSlice[i] < MatchSlice[j]
 	})
 	for _, match := range MatchSlice {
-		info := s.matcherInfos[match]
-		client := s.clients[info.clientIdx]
+		info := matcherInfos[match]
+		client := allClients[info.clientIdx]
 		domainRule := client.domains[info.domainRuleIdx]
 		domainRules = append(domainRules, fmt.Sprintf("%s(DNS idx:%d)", domainRule, info.clientIdx))
 		if clientUsed[info.clientIdx] {
@@ -283,7 +331,7 @@ func (s *DNS) sortClients(domain string) []*Client {
 
 	if !(s.disableFallback || s.disableFallbackIfMatch && hasMatch) {
 		// Default round-robin query
-		for idx, client := range s.clients {
+		for idx, client := range allClients {
 			if clientUsed[idx] || client.skipFallback {
 				continue
 			}
@@ -304,9 +352,9 @@ func (s *DNS) sortClients(domain string) []*Client {
 	}
 
 	if len(clients) == 0 {
-		if len(s.clients) > 0 {
-			clients = append(clients, s.clients[0])
-			clientNames = append(clientNames, s.clients[0].Name())
+		if len(allClients) > 0 {
+			clients = append(clients, allClients[0])
+			clientNames = append(clientNames, allClients[0].Name())
 			errors.LogWarning(s.ctx, "domain ", domain, " will use the first DNS: ", clientNames)
 		} else {
 			errors.LogError(s.ctx, "no DNS clients available for domain ", domain, " and no default clients configured")
@@ -367,9 +415,12 @@ func (s *DNS) parallelQuery(domain string, option dns.IPOption) ([]net.IP, uint3
 	var errs []error
 	clients := s.sortClients(domain)
 
-	resultsChan := asyncQueryAll(domain, option, clients, s.ctx)
-
 	groups, groupOf := makeGroups( /*s.ctx,*/ clients)
+	held := preferFaster(clients, groups, groupOf)
+	defer held.release()
+
+	resultsChan := asyncQueryAll(domain, option, clients, held.wait, s.ctx)
+
 	results := make([]*queryResult, len(clients))
 	pending := make([]int, len(groups))
 	for gi, g := range groups {
@@ -383,6 +434,10 @@ func (s *DNS) parallelQuery(domain string, option dns.IPOption) ([]net.IP, uint3
 
 		gi := groupOf[result.index]
 		pending[gi]--
+		if result.err != nil || len(result.ips) == 0 {
+			// no use waiting for the failed server, query the others now
+			held.hurry(gi)
+		}
 
 		for nextGroup < len(groups) {
 			g := groups[nextGroup]
@@ -424,7 +479,10 @@ type queryResult struct {
 	index int
 }
 
-func asyncQueryAll(domain string, option dns.IPOption, clients []*Client, ctx context.Context) chan queryResult {
+// asyncQueryAll queries all clients at once. wait, if not nil, is called
+// before querying clients[i] and returns false if the query isn't needed
+// anymore.
+func asyncQueryAll(domain string, option dns.IPOption, clients []*Client, wait func(i int) bool, ctx context.Context) chan queryResult {
 	if len(clients) == 0 {
 		ch := make(chan queryResult)
 		close(ch)
@@ -440,6 +498,10 @@ func asyncQueryAll(domain string, option dns.IPOption, clients []*Client, ctx co
 		}
 
 		go func(i int, c *Client) {
+			if wait != nil && !wait(i) {
+				ch <- queryResult{err: context.Canceled, index: i}
+				return
+			}
 			qctx := ctx
 			if !c.server.IsDisableCache() {
 				nctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), c.timeoutMs*2)
@@ -512,6 +574,88 @@ func makeGroups( /*ctx context.Context,*/ clients []*Client) ([]group, []int) {
 	return groups, groupOf
 }
 
+// laggardFactor is how many times slower than the fastest server of its
+// group a server must be on average to be held back.
+const laggardFactor = 2
+
+// preferFaster orders the clients of each group by their average latency.
+// Servers not queried yet go first to get measured. A server much slower
+// than the fastest of its group is held back until the fastest had time to
+// answer, so that its answer is taken even if the slower server happens to
+// reply first.
+func preferFaster(clients []*Client, groups []group, groupOf []int) *heldQueries {
+	h := &heldQueries{
+		delays:  make([]time.Duration, len(clients)),
+		groupOf: groupOf,
+		hurried: make([]chan struct{}, len(groups)),
+		done:    make(chan struct{}),
+	}
+	for gi, g := range groups {
+		h.hurried[gi] = make(chan struct{})
+		members := clients[g.start : g.end+1]
+		sort.SliceStable(members, func(i, j int) bool {
+			return members[i].stats.averageLatency() < members[j].stats.averageLatency()
+		})
+
+		var best time.Duration
+		for i, c := range members {
+			latency := c.stats.averageLatency()
+			if latency == 0 {
+				continue
+			}
+			if best == 0 {
+				best = latency
+			} else if latency > laggardFactor*best {
+				h.delays[g.start+i] = laggardFactor * best
+			}
+		}
+	}
+	return h
+}
+
+// heldQueries holds back the queries to slow servers of a parallel query.
+type heldQueries struct {
+	// how long to hold back each client, 0 to query it at once
+	delays  []time.Duration
+	groupOf []int
+	// closed to query the held clients of a group at once
+	hurried []chan struct{}
+	// closed once the parallel query returned
+	done chan struct{}
+}
+
+// wait holds back clients[i] as long as needed and returns whether it's
+// still to be queried.
+func (h *heldQueries) wait(i int) bool {
+	if h.delays[i] == 0 {
+		return true
+	}
+	timer := time.NewTimer(h.delays[i])
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-h.hurried[h.groupOf[i]]:
+		return true
+	case <-h.done:
+		return false
+	}
+}
+
+// hurry queries the held clients of group gi at once.
+func (h *heldQueries) hurry(gi int) {
+	select {
+	case <-h.hurried[gi]:
+	default:
+		close(h.hurried[gi])
+	}
+}
+
+// release drops the clients still held back.
+func (h *heldQueries) release() {
+	close(h.done)
+}
+
 func init() {
 	common.Must(common.RegisterConfig((*Config)(nil), func(ctx context.Context, config interface{}) (interface{}, error) {
 		return New(ctx, config.(*Config))
@@ -3,6 +3,7 @@ package dns
 import (
 	"context"
 	"encoding/binary"
+	"io"
 	"math"
 	"strings"
 	"time"
@@ -261,3 +262,26 @@ func toDnsContext(ctx context.Context, addr string) context.Context {
 	})
 	return dnsCtx
 }
+
+// writeStreamMessage writes msg prefixed with its length, as DNS over TCP
+// does.
+func writeStreamMessage(w io.Writer, msg []byte) error {
+	b := make([]byte, 2+len(msg))
+	binary.BigEndian.PutUint16(b, uint16(len(msg)))
+	copy(b[2:], msg)
+	_, err := w.Write(b)
+	return err
+}
+
+// readStreamMessage reads a message prefixed with its length.
+func readStreamMessage(r io.Reader) ([]byte, error) {
+	var length uint16
+	if err := binary.Read(r, binary.BigEndian, &length); err != nil {
+		return nil, errors.New("failed to read response length").Base(err)
+	}
+	msg := make([]byte, length)
+	if _, err := io.ReadFull(r, msg); err != nil {
+		return nil, errors.New("failed to read response").Base(err)
+	}
+	return msg, nil
+}
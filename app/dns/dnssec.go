@@ -0,0 +1,743 @@
+package dns
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	mdns "github.com/miekg/dns"
+	"github.com/xtls/xray-core/common/errors"
+	"golang.org/x/sync/singleflight"
+)
+
+// rootTrustAnchor is the DS of the KSK-2017 of the root zone, the trust
+// anchor if the config has none.
+const rootTrustAnchor = ". IN DS 20326 8 2 E06D44B80B8F1D39A95C0B0D7C65D08458E880409BBC683457104237C7F8EC8D"
+
+const (
+	// maxKeyCacheTTL caps how long the keys of a zone are kept.
+	maxKeyCacheTTL = time.Hour
+	// maxNSEC3Iterations is the most NSEC3 hash iterations taken as proof,
+	// as RFC 9276 suggests.
+	maxNSEC3Iterations = 150
+)
+
+// errBogus is the cause of the errors of answers that fail validation.
+var errBogus = errors.New("bogus DNSSEC answer")
+
+// TrustAnchors are the DS records of the zones validation starts from, by
+// zone.
+type TrustAnchors map[string][]*mdns.DS
+
+// ParseTrustAnchors parses DS records in presentation format. The root
+// zone's KSK is used if there are none.
+func ParseTrustAnchors(anchors []string) (TrustAnchors, error) {
+	if len(anchors) == 0 {
+		anchors = []string{rootTrustAnchor}
+	}
+	ta := make(TrustAnchors)
+	for _, anchor := range anchors {
+		rr, err := mdns.NewRR(anchor)
+		if err != nil {
+			return nil, errors.New("invalid trust anchor ", anchor).Base(err)
+		}
+		ds, ok := rr.(*mdns.DS)
+		if !ok {
+			return nil, errors.New("trust anchor ", anchor, " is not a DS record")
+		}
+		zone := mdns.CanonicalName(ds.Hdr.Name)
+		ta[zone] = append(ta[zone], ds)
+	}
+	return ta, nil
+}
+
+// covers returns whether zone is at or below a trust anchor.
+func (ta TrustAnchors) covers(zone string) bool {
+	for anchor := range ta {
+		if mdns.IsSubDomain(anchor, zone) {
+			return true
+		}
+	}
+	return false
+}
+
+// dnssecValidator validates answers from the trust anchors, fetching the
+// DNSKEY and DS records of the chain through exchange.
+type dnssecValidator struct {
+	anchors  TrustAnchors
+	exchange func(ctx context.Context, query []byte) ([]byte, error)
+
+	access sync.Mutex
+	zones  map[string]*zoneKeys
+	group  singleflight.Group
+}
+
+// zoneKeys are the validated keys of a zone, none if it is insecure.
+type zoneKeys struct {
+	keys   []*mdns.DNSKEY
+	expire time.Time
+}
+
+func newDNSSECValidator(anchors TrustAnchors, exchange func(ctx context.Context, query []byte) ([]byte, error)) *dnssecValidator {
+	return &dnssecValidator{
+		anchors:  anchors,
+		exchange: exchange,
+		zones:    make(map[string]*zoneKeys),
+	}
+}
+
+// rrSet is the records of a name and type with their signatures.
+type rrSet struct {
+	name  string
+	rtype uint16
+	rrs   []mdns.RR
+	sigs  []*mdns.RRSIG
+}
+
+func groupRRSets(rrs []mdns.RR) []*rrSet {
+	var sets []*rrSet
+	find := func(name string, rtype uint16) *rrSet {
+		for _, set := range sets {
+			if set.name == name && set.rtype == rtype {
+				return set
+			}
+		}
+		set := &rrSet{name: name, rtype: rtype}
+		sets = append(sets, set)
+		return set
+	}
+	for _, rr := range rrs {
+		name := mdns.CanonicalName(rr.Header().Name)
+		if sig, ok := rr.(*mdns.RRSIG); ok {
+			set := find(name, sig.TypeCovered)
+			set.sigs = append(set.sigs, sig)
+			continue
+		}
+		set := find(name, rr.Header().Rrtype)
+		set.rrs = append(set.rrs, rr)
+	}
+	return sets
+}
+
+func findRRSet(sets []*rrSet, name string, rtype uint16) *rrSet {
+	for _, set := range sets {
+		if set.name == name && set.rtype == rtype && len(set.rrs) > 0 {
+			return set
+		}
+	}
+	return nil
+}
+
+// denialProof is the validated NSEC and NSEC3 records of an answer.
+type denialProof struct {
+	signed bool
+	nsec   []*mdns.NSEC
+	nsec3  []*mdns.NSEC3
+}
+
+// validate validates the answer in payload. It returns an error with cause
+// errBogus if the answer is bogus, another one if the validation couldn't be
+// done, nil if it is secure or provably insecure.
+func (v *dnssecValidator) validate(ctx context.Context, payload []byte) error {
+	msg := new(mdns.Msg)
+	if err := msg.Unpack(payload); err != nil {
+		return errors.New("failed to parse DNS response").Base(err)
+	}
+	if len(msg.Question) != 1 {
+		return errors.New("response has ", len(msg.Question), " questions").Base(errBogus)
+	}
+	if msg.Rcode != mdns.RcodeSuccess && msg.Rcode != mdns.RcodeNameError {
+		// a failure anyway
+		return nil
+	}
+	q := msg.Question[0]
+
+	proof := &denialProof{}
+	for _, set := range groupRRSets(msg.Ns) {
+		if len(set.sigs) == 0 || len(set.rrs) == 0 {
+			continue
+		}
+		secure, err := v.verifyRRSet(ctx, set, nil)
+		if err != nil {
+			return err
+		}
+		if !secure {
+			continue
+		}
+		proof.signed = true
+		for _, rr := range set.rrs {
+			switch rr := rr.(type) {
+			case *mdns.NSEC:
+				proof.nsec = append(proof.nsec, rr)
+			case *mdns.NSEC3:
+				proof.nsec3 = append(proof.nsec3, rr)
+			}
+		}
+	}
+
+	answers := groupRRSets(msg.Answer)
+	for _, set := range answers {
+		if len(set.rrs) == 0 {
+			continue
+		}
+		if len(set.sigs) == 0 && set.rtype == mdns.TypeCNAME && synthesizedFromDNAME(answers, set) {
+			continue
+		}
+		if _, err := v.verifyRRSet(ctx, set, proof); err != nil {
+			return err
+		}
+	}
+
+	target := mdns.CanonicalName(q.Name)
+	for i := 0; i < len(answers); i++ {
+		if findRRSet(answers, target, q.Qtype) != nil {
+			return nil
+		}
+		cname := findRRSet(answers, target, mdns.TypeCNAME)
+		if cname == nil {
+			break
+		}
+		target = mdns.CanonicalName(cname.rrs[0].(*mdns.CNAME).Target)
+	}
+	if findRRSet(answers, target, q.Qtype) != nil {
+		return nil
+	}
+
+	if !proof.signed {
+		keys, err := v.zoneKeys(ctx, target)
+		if err != nil {
+			return err
+		}
+		if keys != nil {
+			return errors.New("unsigned negative answer for ", target, " in a signed zone").Base(errBogus)
+		}
+		return nil
+	}
+	if !proof.denies(target, q.Qtype, msg.Rcode == mdns.RcodeNameError) {
+		return errors.New("no proof that ", target, " ", mdns.TypeToString[q.Qtype], " doesn't exist").Base(errBogus)
+	}
+	return nil
+}
+
+// synthesizedFromDNAME returns whether the CNAME set is the one a DNAME in
+// answers makes, which isn't signed.
+func synthesizedFromDNAME(answers []*rrSet, set *rrSet) bool {
+	target := mdns.CanonicalName(set.rrs[0].(*mdns.CNAME).Target)
+	for _, dname := range answers {
+		if dname.rtype != mdns.TypeDNAME || len(dname.rrs) == 0 || len(dname.sigs) == 0 {
+			continue
+		}
+		if dname.name == set.name || !mdns.IsSubDomain(dname.name, set.name) {
+			continue
+		}
+		synthesized := strings.TrimSuffix(set.name, dname.name) + mdns.CanonicalName(dname.rrs[0].(*mdns.DNAME).Target)
+		if synthesized == target {
+			return true
+		}
+	}
+	return false
+}
+
+// verifyRRSet checks the signatures of set. It returns false if the zone it
+// is in is insecure. A set expanded from a wildcard needs proof that there is
+// no closer match.
+func (v *dnssecValidator) verifyRRSet(ctx context.Context, set *rrSet, proof *denialProof) (bool, error) {
+	if len(set.sigs) == 0 {
+		keys, err := v.zoneKeys(ctx, set.name)
+		if err != nil {
+			return false, err
+		}
+		if keys != nil {
+			return false, errors.New("missing signature of ", set.name, " ", mdns.TypeToString[set.rtype]).Base(errBogus)
+		}
+		return false, nil
+	}
+
+	var lastErr error
+	for _, sig := range set.sigs {
+		signer := mdns.CanonicalName(sig.SignerName)
+		if !mdns.IsSubDomain(signer, set.name) {
+			lastErr = errors.New(set.name, " is signed by ", signer, " out of its zone").Base(errBogus)
+			continue
+		}
+		keys, err := v.zoneKeys(ctx, signer)
+		if err != nil {
+			return false, err
+		}
+		if keys == nil {
+			return false, nil
+		}
+		if err := verifySignature(sig, keys.keys, set.rrs); err != nil {
+			lastErr = err
+			continue
+		}
+		if labels := int(sig.Labels); labels < mdns.CountLabel(set.name) && !proof.coversWildcard(set.name, labels) {
+			lastErr = errors.New("no proof that wildcard answer ", set.name, " has no closer match").Base(errBogus)
+			continue
+		}
+		return true, nil
+	}
+	return false, lastErr
+}
+
+// verifySignature checks that sig of rrs is valid now and made by one of
+// keys.
+func verifySignature(sig *mdns.RRSIG, keys []*mdns.DNSKEY, rrs []mdns.RR) error {
+	name := rrs[0].Header().Name
+	if !sig.ValidityPeriod(time.Now()) {
+		return errors.New("signature of ", name, " ", mdns.TypeToString[sig.TypeCovered], " is expired or not yet valid").Base(errBogus)
+	}
+	for _, key := range keys {
+		if key.KeyTag() != sig.KeyTag || key.Algorithm != sig.Algorithm {
+			continue
+		}
+		if err := sig.Verify(key, rrs); err == nil {
+			return nil
+		}
+	}
+	return errors.New("invalid signature of ", name, " ", mdns.TypeToString[sig.TypeCovered]).Base(errBogus)
+}
+
+// zoneKeys returns the validated keys of the zone name is in, nil if it is
+// insecure.
+func (v *dnssecValidator) zoneKeys(ctx context.Context, name string) (*zoneKeys, error) {
+	name = mdns.CanonicalName(name)
+	v.access.Lock()
+	keys := v.zones[name]
+	v.access.Unlock()
+	if keys != nil && time.Now().Before(keys.expire) {
+		if keys.keys == nil {
+			return nil, nil
+		}
+		return keys, nil
+	}
+
+	r, err, _ := v.group.Do(name, func() (any, error) {
+		keys, err := v.fetchZoneKeys(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		v.access.Lock()
+		defer v.access.Unlock()
+		if len(v.zones) >= 1024 {
+			now := time.Now()
+			for zone, keys := range v.zones {
+				if now.After(keys.expire) {
+					delete(v.zones, zone)
+				}
+			}
+		}
+		v.zones[name] = keys
+		return keys, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	keys = r.(*zoneKeys)
+	if keys.keys == nil {
+		return nil, nil
+	}
+	return keys, nil
+}
+
+// fetchZoneKeys follows the chain of trust down to name: its DS records are
+// validated with the keys of the parent zone, and its DNSKEY records with
+// those. If name has no DS records, it is either an insecure delegation or
+// not a zone at all, so it has the keys of its parent.
+func (v *dnssecValidator) fetchZoneKeys(ctx context.Context, name string) (*zoneKeys, error) {
+	if ds, ok := v.anchors[name]; ok {
+		return v.fetchDNSKEY(ctx, name, ds)
+	}
+	if name == "." || !v.anchors.covers(name) {
+		return &zoneKeys{expire: time.Now().Add(maxKeyCacheTTL)}, nil
+	}
+
+	resp, err := v.query(ctx, name, mdns.TypeDS)
+	if err != nil {
+		return nil, err
+	}
+	if set := findRRSet(groupRRSets(resp.Answer), name, mdns.TypeDS); set != nil {
+		if len(set.sigs) == 0 {
+			parentKeys, err := v.zoneKeys(ctx, parentName(name))
+			if err != nil {
+				return nil, err
+			}
+			if parentKeys != nil {
+				return nil, errors.New("missing signature of ", name, " DS").Base(errBogus)
+			}
+			return &zoneKeys{expire: expireOf(set.rrs)}, nil
+		}
+		if !signedAbove(set, name) {
+			return nil, errors.New("DS of ", name, " is not signed by a parent zone").Base(errBogus)
+		}
+		secure, err := v.verifyRRSet(ctx, set, nil)
+		if err != nil {
+			return nil, err
+		}
+		if !secure {
+			return &zoneKeys{expire: expireOf(set.rrs)}, nil
+		}
+		var ds []*mdns.DS
+		for _, rr := range set.rrs {
+			ds = append(ds, rr.(*mdns.DS))
+		}
+		return v.fetchDNSKEY(ctx, name, ds)
+	}
+
+	// the SOA is of the zone that answered, which has name in it
+	parent := parentName(name)
+	for _, rr := range resp.Ns {
+		if soa, ok := rr.(*mdns.SOA); ok {
+			if zone := mdns.CanonicalName(soa.Hdr.Name); zone != name && mdns.IsSubDomain(zone, name) {
+				parent = zone
+			}
+		}
+	}
+	parentKeys, err := v.zoneKeys(ctx, parent)
+	if err != nil {
+		return nil, err
+	}
+	if parentKeys == nil {
+		return &zoneKeys{expire: expireOf(resp.Ns)}, nil
+	}
+
+	proof := &denialProof{}
+	for _, set := range groupRRSets(resp.Ns) {
+		if set.rtype != mdns.TypeNSEC && set.rtype != mdns.TypeNSEC3 || len(set.rrs) == 0 {
+			continue
+		}
+		if !signedAbove(set, name) {
+			return nil, errors.New("denial of ", name, " DS is not signed by a parent zone").Base(errBogus)
+		}
+		secure, err := v.verifyRRSet(ctx, set, nil)
+		if err != nil {
+			return nil, err
+		}
+		if !secure {
+			return &zoneKeys{expire: expireOf(resp.Ns)}, nil
+		}
+		for _, rr := range set.rrs {
+			switch rr := rr.(type) {
+			case *mdns.NSEC:
+				proof.nsec = append(proof.nsec, rr)
+			case *mdns.NSEC3:
+				proof.nsec3 = append(proof.nsec3, rr)
+			}
+		}
+	}
+
+	notZone := &zoneKeys{keys: parentKeys.keys, expire: parentKeys.expire}
+	if expire := expireOf(resp.Ns); expire.Before(notZone.expire) {
+		notZone.expire = expire
+	}
+	insecure := &zoneKeys{expire: notZone.expire}
+	for _, nsec := range proof.nsec {
+		if mdns.CanonicalName(nsec.Hdr.Name) == name {
+			switch {
+			case hasType(nsec.TypeBitMap, mdns.TypeDS):
+				return nil, errors.New("NSEC of ", name, " has DS").Base(errBogus)
+			case hasType(nsec.TypeBitMap, mdns.TypeNS) && !hasType(nsec.TypeBitMap, mdns.TypeSOA):
+				return insecure, nil
+			default:
+				return notZone, nil
+			}
+		}
+	}
+	for _, nsec3 := range proof.nsec3 {
+		if nsec3.Iterations <= maxNSEC3Iterations && nsec3.Match(name) {
+			switch {
+			case hasType(nsec3.TypeBitMap, mdns.TypeDS):
+				return nil, errors.New("NSEC3 of ", name, " has DS").Base(errBogus)
+			case hasType(nsec3.TypeBitMap, mdns.TypeNS) && !hasType(nsec3.TypeBitMap, mdns.TypeSOA):
+				return insecure, nil
+			default:
+				return notZone, nil
+			}
+		}
+	}
+	if proof.coversName(name) {
+		return notZone, nil
+	}
+	if _, cover := proof.closestEncloser(name); cover != nil {
+		if cover.Flags&1 != 0 {
+			// opt-out: unsigned delegations may be in the span
+			return insecure, nil
+		}
+		return notZone, nil
+	}
+	return nil, errors.New("no proof that ", name, " has no DS").Base(errBogus)
+}
+
+// signedAbove returns whether set is signed, only by zones above name. The
+// chain of trust to name can't depend on itself.
+func signedAbove(set *rrSet, name string) bool {
+	for _, sig := range set.sigs {
+		signer := mdns.CanonicalName(sig.SignerName)
+		if signer == name || !mdns.IsSubDomain(signer, name) {
+			return false
+		}
+	}
+	return len(set.sigs) > 0
+}
+
+// fetchDNSKEY returns the DNSKEY records of zone if one of them matches ds
+// and signs them.
+func (v *dnssecValidator) fetchDNSKEY(ctx context.Context, zone string, ds []*mdns.DS) (*zoneKeys, error) {
+	resp, err := v.query(ctx, zone, mdns.TypeDNSKEY)
+	if err != nil {
+		return nil, err
+	}
+	set := findRRSet(groupRRSets(resp.Answer), zone, mdns.TypeDNSKEY)
+	if set == nil {
+		return nil, errors.New("no DNSKEY of ", zone).Base(errBogus)
+	}
+
+	var keys, trusted []*mdns.DNSKEY
+	for _, rr := range set.rrs {
+		key := rr.(*mdns.DNSKEY)
+		keys = append(keys, key)
+		for _, d := range ds {
+			if key.KeyTag() != d.KeyTag || key.Algorithm != d.Algorithm {
+				continue
+			}
+			if digest := key.ToDS(d.DigestType); digest != nil && strings.EqualFold(digest.Digest, d.Digest) {
+				trusted = append(trusted, key)
+				break
+			}
+		}
+	}
+	if len(trusted) == 0 {
+		return nil, errors.New("no DNSKEY of ", zone, " matches its DS").Base(errBogus)
+	}
+
+	var lastErr error
+	for _, sig := range set.sigs {
+		if lastErr = verifySignature(sig, trusted, set.rrs); lastErr == nil {
+			expire := expireOf(set.rrs)
+			if sigExpire := time.Unix(int64(sig.Expiration), 0); sigExpire.Before(expire) {
+				expire = sigExpire
+			}
+			return &zoneKeys{keys: keys, expire: expire}, nil
+		}
+	}
+	if lastErr == nil {
+		lastErr = errors.New("DNSKEY of ", zone, " is not signed").Base(errBogus)
+	}
+	return nil, lastErr
+}
+
+// query sends a query for the DNSSEC records of name.
+func (v *dnssecValidator) query(ctx context.Context, name string, qtype uint16) (*mdns.Msg, error) {
+	m := new(mdns.Msg)
+	m.SetQuestion(name, qtype)
+	m.CheckingDisabled = true
+	m.SetEdns0(dnssecUDPSize, true)
+	query, err := m.Pack()
+	if err != nil {
+		return nil, err
+	}
+
+	payload, err := v.exchange(ctx, query)
+	if err != nil {
+		return nil, errors.New("failed to query ", name, " ", mdns.TypeToString[qtype]).Base(err)
+	}
+	resp := new(mdns.Msg)
+	if err := resp.Unpack(payload); err != nil {
+		return nil, errors.New("failed to parse response of ", name, " ", mdns.TypeToString[qtype]).Base(err)
+	}
+	if len(resp.Question) != 1 || mdns.CanonicalName(resp.Question[0].Name) != name || resp.Question[0].Qtype != qtype {
+		return nil, errors.New("mismatched response of ", name, " ", mdns.TypeToString[qtype])
+	}
+	if resp.Truncated {
+		return nil, errors.New("truncated response of ", name, " ", mdns.TypeToString[qtype])
+	}
+	if resp.Rcode != mdns.RcodeSuccess && resp.Rcode != mdns.RcodeNameError {
+		return nil, errors.New("failed to query ", name, " ", mdns.TypeToString[qtype], ": ", mdns.RcodeToString[resp.Rcode])
+	}
+	return resp, nil
+}
+
+// denies returns whether the proof shows that name has no qtype records, or
+// doesn't exist at all if nxdomain.
+func (p *denialProof) denies(name string, qtype uint16, nxdomain bool) bool {
+	noType := func(bitmap []uint16) bool {
+		return !hasType(bitmap, qtype) && !hasType(bitmap, mdns.TypeCNAME)
+	}
+
+	if !nxdomain {
+		for _, nsec := range p.nsec {
+			if mdns.CanonicalName(nsec.Hdr.Name) == name && noType(nsec.TypeBitMap) {
+				return true
+			}
+		}
+		for _, nsec3 := range p.nsec3 {
+			if nsec3.Iterations <= maxNSEC3Iterations && nsec3.Match(name) && noType(nsec3.TypeBitMap) {
+				return true
+			}
+		}
+	}
+
+	// name doesn't exist, nor a wildcard that would match it, or it is an
+	// empty non-terminal
+	if cover := p.coveringNSEC(name); cover != nil {
+		next := mdns.CanonicalName(cover.NextDomain)
+		if !nxdomain && next != name && mdns.IsSubDomain(name, next) {
+			return true
+		}
+		encloser := commonAncestor(name, mdns.CanonicalName(cover.Hdr.Name))
+		if other := commonAncestor(name, next); mdns.CountLabel(other) > mdns.CountLabel(encloser) {
+			encloser = other
+		}
+		wildcard := "*." + encloser
+		if nxdomain {
+			return p.coversName(wildcard)
+		}
+		for _, nsec := range p.nsec {
+			if mdns.CanonicalName(nsec.Hdr.Name) == wildcard && noType(nsec.TypeBitMap) {
+				return true
+			}
+		}
+		return false
+	}
+
+	encloser, cover := p.closestEncloser(name)
+	if cover == nil {
+		return false
+	}
+	if cover.Flags&1 != 0 {
+		// opt-out: the name may be an unsigned delegation
+		return true
+	}
+	wildcard := "*." + encloser
+	for _, nsec3 := range p.nsec3 {
+		if nsec3.Iterations > maxNSEC3Iterations {
+			continue
+		}
+		if nxdomain && nsec3.Cover(wildcard) || !nxdomain && nsec3.Match(wildcard) && noType(nsec3.TypeBitMap) {
+			return true
+		}
+	}
+	return false
+}
+
+// coversWildcard returns whether the proof shows that name, answered from a
+// wildcard with labels labels, has no closer match.
+func (p *denialProof) coversWildcard(name string, labels int) bool {
+	if p == nil {
+		return false
+	}
+	if p.coversName(name) {
+		return true
+	}
+	indexes := mdns.Split(name)
+	nextCloser := name[indexes[len(indexes)-labels-1]:]
+	for _, nsec3 := range p.nsec3 {
+		if nsec3.Iterations <= maxNSEC3Iterations && nsec3.Cover(nextCloser) {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *denialProof) coversName(name string) bool {
+	return p.coveringNSEC(name) != nil
+}
+
+// coveringNSEC returns the NSEC record name falls between the owner and next
+// name of.
+func (p *denialProof) coveringNSEC(name string) *mdns.NSEC {
+	for _, nsec := range p.nsec {
+		owner, next := mdns.CanonicalName(nsec.Hdr.Name), mdns.CanonicalName(nsec.NextDomain)
+		if !mdns.IsSubDomain(commonAncestor(owner, next), name) {
+			continue
+		}
+		afterOwner := canonicalCompare(owner, name) < 0
+		beforeNext := canonicalCompare(name, next) < 0
+		if canonicalCompare(owner, next) < 0 {
+			if afterOwner && beforeNext {
+				return nsec
+			}
+		} else if afterOwner || beforeNext {
+			// the last NSEC of the zone
+			return nsec
+		}
+	}
+	return nil
+}
+
+// closestEncloser finds the closest ancestor of name with an NSEC3 record,
+// and returns it with the NSEC3 record covering the next closer name.
+func (p *denialProof) closestEncloser(name string) (string, *mdns.NSEC3) {
+	indexes := mdns.Split(name)
+	for i := 1; i < len(indexes); i++ {
+		encloser, nextCloser := name[indexes[i]:], name[indexes[i-1]:]
+		matched := false
+		for _, nsec3 := range p.nsec3 {
+			if nsec3.Iterations <= maxNSEC3Iterations && nsec3.Match(encloser) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			continue
+		}
+		for _, nsec3 := range p.nsec3 {
+			if nsec3.Iterations <= maxNSEC3Iterations && nsec3.Cover(nextCloser) {
+				return encloser, nsec3
+			}
+		}
+		return "", nil
+	}
+	return "", nil
+}
+
+func hasType(bitmap []uint16, rtype uint16) bool {
+	for _, t := range bitmap {
+		if t == rtype {
+			return true
+		}
+	}
+	return false
+}
+
+// expireOf returns when the first of rrs expires, at most maxKeyCacheTTL
+// from now.
+func expireOf(rrs []mdns.RR) time.Time {
+	ttl := maxKeyCacheTTL
+	for _, rr := range rrs {
+		if t := time.Duration(rr.Header().Ttl) * time.Second; t < ttl {
+			ttl = t
+		}
+	}
+	return time.Now().Add(ttl)
+}
+
+func parentName(name string) string {
+	if off, end := mdns.NextLabel(name, 0); !end {
+		return name[off:]
+	}
+	return "."
+}
+
+func commonAncestor(a, b string) string {
+	n := mdns.CompareDomainName(a, b)
+	if n == 0 {
+		return "."
+	}
+	indexes := mdns.Split(a)
+	return a[indexes[len(indexes)-n]:]
+}
+
+// canonicalCompare orders names as RFC 4034 section 6.1 does, by their
+// labels from the right.
+func canonicalCompare(a, b string) int {
+	la, lb := mdns.SplitDomainName(a), mdns.SplitDomainName(b)
+	for i, j := len(la)-1, len(lb)-1; i >= 0 && j >= 0; i, j = i-1, j-1 {
+		if c := bytes.Compare([]byte(strings.ToLower(la[i])), []byte(strings.ToLower(lb[j]))); c != 0 {
+			return c
+		}
+	}
+	return len(la) - len(lb)
+}
@@ -0,0 +1,288 @@
+package dns
+
+import (
+	"context"
+	"crypto"
+	go_errors "errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	mdns "github.com/miekg/dns"
+	"github.com/xtls/xray-core/app/dispatcher"
+	"github.com/xtls/xray-core/app/policy"
+	"github.com/xtls/xray-core/app/proxyman"
+	_ "github.com/xtls/xray-core/app/proxyman/outbound"
+	"github.com/xtls/xray-core/common"
+	"github.com/xtls/xray-core/common/net"
+	"github.com/xtls/xray-core/common/serial"
+	"github.com/xtls/xray-core/core"
+	feature_dns "github.com/xtls/xray-core/features/dns"
+	"github.com/xtls/xray-core/proxy/freedom"
+	"github.com/xtls/xray-core/testing/servers/udp"
+)
+
+// testZones answers from a signed root zone and a signed zone example.,
+// which delegates insecure.example. without DS.
+type testZones struct {
+	anchor  string
+	answers map[string]*mdns.Msg
+}
+
+func newTestZones() *testZones {
+	newKey := func(zone string) (*mdns.DNSKEY, crypto.Signer) {
+		key := &mdns.DNSKEY{
+			Hdr:       mdns.RR_Header{Name: zone, Rrtype: mdns.TypeDNSKEY, Class: mdns.ClassINET, Ttl: 3600},
+			Flags:     257,
+			Protocol:  3,
+			Algorithm: mdns.ECDSAP256SHA256,
+		}
+		priv, err := key.Generate(256)
+		common.Must(err)
+		return key, priv.(crypto.Signer)
+	}
+	rr := func(s string) mdns.RR {
+		r, err := mdns.NewRR(s)
+		common.Must(err)
+		return r
+	}
+	now := time.Now()
+	signAt := func(inception, expiration time.Time, key *mdns.DNSKEY, priv crypto.Signer, rrs ...mdns.RR) *mdns.RRSIG {
+		sig := &mdns.RRSIG{
+			Hdr:        mdns.RR_Header{Name: rrs[0].Header().Name, Rrtype: mdns.TypeRRSIG, Class: mdns.ClassINET, Ttl: rrs[0].Header().Ttl},
+			KeyTag:     key.KeyTag(),
+			SignerName: key.Hdr.Name,
+			Algorithm:  key.Algorithm,
+			Inception:  uint32(inception.Unix()),
+			Expiration: uint32(expiration.Unix()),
+		}
+		common.Must(sig.Sign(priv, rrs))
+		return sig
+	}
+	sign := func(key *mdns.DNSKEY, priv crypto.Signer, rrs ...mdns.RR) []mdns.RR {
+		return append(rrs, signAt(now.Add(-time.Hour), now.Add(time.Hour), key, priv, rrs...))
+	}
+
+	rootKey, rootPriv := newKey(".")
+	exKey, exPriv := newKey("example.")
+	soa := rr("example. 3600 IN SOA ns.example. admin.example. 1 3600 600 86400 300")
+
+	z := &testZones{
+		anchor:  rootKey.ToDS(mdns.SHA256).String(),
+		answers: make(map[string]*mdns.Msg),
+	}
+	add := func(name string, qtype uint16, rcode int, answer []mdns.RR, ns ...[]mdns.RR) {
+		m := &mdns.Msg{Answer: answer}
+		m.Rcode = rcode
+		for _, rrs := range ns {
+			m.Ns = append(m.Ns, rrs...)
+		}
+		z.answers[name+" "+mdns.TypeToString[qtype]] = m
+	}
+
+	add(".", mdns.TypeDNSKEY, mdns.RcodeSuccess, sign(rootKey, rootPriv, rootKey))
+	add("example.", mdns.TypeDS, mdns.RcodeSuccess, sign(rootKey, rootPriv, exKey.ToDS(mdns.SHA256)))
+	add("example.", mdns.TypeDNSKEY, mdns.RcodeSuccess, sign(exKey, exPriv, exKey))
+
+	add("www.example.", mdns.TypeA, mdns.RcodeSuccess, sign(exKey, exPriv, rr("www.example. 300 IN A 192.0.2.1")))
+	// signed for another address
+	add("bad.example.", mdns.TypeA, mdns.RcodeSuccess, []mdns.RR{
+		rr("bad.example. 300 IN A 192.0.2.66"),
+		signAt(now.Add(-time.Hour), now.Add(time.Hour), exKey, exPriv, rr("bad.example. 300 IN A 192.0.2.2")),
+	})
+	add("old.example.", mdns.TypeA, mdns.RcodeSuccess, []mdns.RR{
+		rr("old.example. 300 IN A 192.0.2.4"),
+		signAt(now.Add(-2*time.Hour), now.Add(-time.Hour), exKey, exPriv, rr("old.example. 300 IN A 192.0.2.4")),
+	})
+	add("nx.example.", mdns.TypeA, mdns.RcodeNameError, nil,
+		sign(exKey, exPriv, soa),
+		sign(exKey, exPriv, rr("insecure.example. 300 IN NSEC old.example. NS RRSIG NSEC")),
+		sign(exKey, exPriv, rr("example. 300 IN NSEC bad.example. NS SOA RRSIG NSEC DNSKEY")),
+	)
+	add("forged.example.", mdns.TypeA, mdns.RcodeNameError, nil, sign(exKey, exPriv, soa))
+	add("www.example.", mdns.TypeAAAA, mdns.RcodeSuccess, nil,
+		sign(exKey, exPriv, soa),
+		sign(exKey, exPriv, rr("www.example. 300 IN NSEC example. A RRSIG NSEC")),
+	)
+
+	add("insecure.example.", mdns.TypeDS, mdns.RcodeSuccess, nil,
+		sign(exKey, exPriv, soa),
+		sign(exKey, exPriv, rr("insecure.example. 300 IN NSEC old.example. NS RRSIG NSEC")),
+	)
+	add("host.insecure.example.", mdns.TypeDS, mdns.RcodeSuccess, nil,
+		[]mdns.RR{rr("insecure.example. 3600 IN SOA ns.insecure.example. admin.insecure.example. 1 3600 600 86400 300")},
+	)
+	add("host.insecure.example.", mdns.TypeA, mdns.RcodeSuccess, []mdns.RR{rr("host.insecure.example. 300 IN A 192.0.2.3")})
+
+	return z
+}
+
+func (z *testZones) reply(r *mdns.Msg) *mdns.Msg {
+	m := new(mdns.Msg)
+	m.SetReply(r)
+	q := r.Question[0]
+	if answer, ok := z.answers[strings.ToLower(q.Name)+" "+mdns.TypeToString[q.Qtype]]; ok {
+		m.Answer = answer.Answer
+		m.Ns = answer.Ns
+		m.Rcode = answer.Rcode
+	} else {
+		m.Rcode = mdns.RcodeServerFailure
+	}
+	if opt := r.IsEdns0(); opt != nil {
+		m.SetEdns0(4096, opt.Do())
+	}
+	return m
+}
+
+func (z *testZones) ServeDNS(w mdns.ResponseWriter, r *mdns.Msg) {
+	w.WriteMsg(z.reply(r))
+}
+
+func (z *testZones) exchange(ctx context.Context, query []byte) ([]byte, error) {
+	r := new(mdns.Msg)
+	if err := r.Unpack(query); err != nil {
+		return nil, err
+	}
+	return z.reply(r).Pack()
+}
+
+func TestDNSSECValidate(t *testing.T) {
+	z := newTestZones()
+	anchors, err := ParseTrustAnchors([]string{z.anchor})
+	common.Must(err)
+	v := newDNSSECValidator(anchors, z.exchange)
+
+	for _, test := range []struct {
+		name  string
+		qtype uint16
+		bogus bool
+	}{
+		{"www.example.", mdns.TypeA, false},
+		{"WWW.Example.", mdns.TypeA, false},
+		{"www.example.", mdns.TypeAAAA, false},
+		{"bad.example.", mdns.TypeA, true},
+		{"old.example.", mdns.TypeA, true},
+		{"nx.example.", mdns.TypeA, false},
+		{"forged.example.", mdns.TypeA, true},
+		{"host.insecure.example.", mdns.TypeA, false},
+	} {
+		q := new(mdns.Msg)
+		q.SetQuestion(test.name, test.qtype)
+		q.SetEdns0(dnssecUDPSize, true)
+		payload, err := z.reply(q).Pack()
+		common.Must(err)
+
+		err = v.validate(context.Background(), payload)
+		if test.bogus {
+			if !go_errors.Is(err, errBogus) {
+				t.Errorf("%s %s: expected bogus answer, got %v", test.name, mdns.TypeToString[test.qtype], err)
+			}
+		} else if err != nil {
+			t.Errorf("%s %s: %v", test.name, mdns.TypeToString[test.qtype], err)
+		}
+	}
+
+	// a trust anchor of another key
+	key := &mdns.DNSKEY{
+		Hdr:       mdns.RR_Header{Name: ".", Rrtype: mdns.TypeDNSKEY, Class: mdns.ClassINET},
+		Flags:     257,
+		Protocol:  3,
+		Algorithm: mdns.ECDSAP256SHA256,
+	}
+	_, err = key.Generate(256)
+	common.Must(err)
+	anchors, err = ParseTrustAnchors([]string{key.ToDS(mdns.SHA256).String()})
+	common.Must(err)
+	v = newDNSSECValidator(anchors, z.exchange)
+	q := new(mdns.Msg)
+	q.SetQuestion("www.example.", mdns.TypeA)
+	payload, err := z.reply(q).Pack()
+	common.Must(err)
+	if err := v.validate(context.Background(), payload); !go_errors.Is(err, errBogus) {
+		t.Errorf("expected bogus answer with wrong trust anchor, got %v", err)
+	}
+}
+
+func TestDNSSECFallback(t *testing.T) {
+	z := newTestZones()
+	port := udp.PickPort()
+	dnsServer := mdns.Server{
+		Addr:    "127.0.0.1:" + port.String(),
+		Net:     "udp",
+		Handler: z,
+		UDPSize: 4096,
+	}
+	go dnsServer.ListenAndServe()
+	defer dnsServer.Shutdown()
+	time.Sleep(time.Second)
+
+	nameServer := func(tag string, skipDNSSEC bool) *NameServer {
+		return &NameServer{
+			Address: &net.Endpoint{
+				Network: net.Network_UDP,
+				Address: &net.IPOrDomain{
+					Address: &net.IPOrDomain_Ip{
+						Ip: []byte{127, 0, 0, 1},
+					},
+				},
+				Port: uint32(port),
+			},
+			Tag:        tag,
+			SkipDnssec: skipDNSSEC,
+		}
+	}
+
+	config := &core.Config{
+		App: []*serial.TypedMessage{
+			serial.ToTypedMessage(&Config{
+				NameServer: []*NameServer{
+					nameServer("validating", false),
+					nameServer("plain", true),
+				},
+				Dnssec: &Config_DNSSEC{
+					TrustAnchor: []string{z.anchor},
+				},
+			}),
+			serial.ToTypedMessage(&dispatcher.Config{}),
+			serial.ToTypedMessage(&proxyman.OutboundConfig{}),
+			serial.ToTypedMessage(&policy.Config{}),
+		},
+		Outbound: []*core.OutboundHandlerConfig{
+			{
+				ProxySettings: serial.ToTypedMessage(&freedom.Config{}),
+			},
+		},
+	}
+
+	v, err := core.New(config)
+	common.Must(err)
+	d := v.GetFeature(feature_dns.ClientType()).(*DNS)
+	option := feature_dns.IPOption{IPv4Enable: true}
+
+	ips, _, err := d.LookupIP("www.example", option)
+	common.Must(err)
+	if r := cmp.Diff(ips, []net.IP{{192, 0, 2, 1}}); r != "" {
+		t.Fatal(r)
+	}
+	if entries := d.ListCache("plain", ""); len(entries) != 0 {
+		t.Fatalf("secure answer fell back to the next nameserver: %+v", entries)
+	}
+
+	// the bogus answer is only taken from the nameserver that skips DNSSEC
+	ips, _, err = d.LookupIP("bad.example", option)
+	common.Must(err)
+	if r := cmp.Diff(ips, []net.IP{{192, 0, 2, 66}}); r != "" {
+		t.Fatal(r)
+	}
+	entries := d.ListCache("validating", "bad.example")
+	if len(entries) != 1 || entries[0].RCode != uint16(mdns.RcodeServerFailure) || len(entries[0].IP) != 0 {
+		t.Fatalf("unexpected cache entries of the validating nameserver %+v", entries)
+	}
+
+	d.RemoveNameServer("plain")
+	d.FlushCache("", "")
+	if ips, _, err := d.LookupIP("bad.example", option); err == nil {
+		t.Fatalf("bogus answer returned: %v", ips)
+	}
+}
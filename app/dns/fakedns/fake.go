@@ -5,12 +5,14 @@ import (
 	"math"
 	"math/big"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/xtls/xray-core/common"
 	"github.com/xtls/xray-core/common/cache"
 	"github.com/xtls/xray-core/common/errors"
 	"github.com/xtls/xray-core/common/net"
+	"github.com/xtls/xray-core/common/task"
 	"github.com/xtls/xray-core/features/dns"
 )
 
@@ -20,6 +22,11 @@ type Holder struct {
 	mu         *sync.Mutex
 
 	config *FakeDnsPool
+
+	// saves the mapping to config.PersistPath, if set
+	saver  *task.Periodic
+	dirty  atomic.Bool
+	saveMu sync.Mutex
 }
 
 func (fkdns *Holder) IsIPInIPPool(ip net.Address) bool {
@@ -43,15 +50,50 @@ func (*Holder) Type() interface{} {
 
 func (fkdns *Holder) Start() error {
 	if fkdns.config != nil && fkdns.config.IpPool != "" && fkdns.config.LruSize != 0 {
-		return fkdns.initializeFromConfig()
+		if err := fkdns.initializeFromConfig(); err != nil {
+			return err
+		}
+		if fkdns.config.PersistPath != "" {
+			return fkdns.startPersistence()
+		}
+		return nil
 	}
 	return errors.New("invalid fakeDNS setting")
 }
 
+func (fkdns *Holder) startPersistence() error {
+	if err := fkdns.load(); err != nil {
+		errors.LogWarningInner(context.Background(), err, "starting with an empty fake DNS pool")
+	}
+	interval := time.Minute
+	if fkdns.config.PersistInterval > 0 {
+		interval = time.Duration(fkdns.config.PersistInterval) * time.Second
+	}
+	fkdns.saver = &task.Periodic{
+		Interval: interval,
+		Execute: func() error {
+			if err := fkdns.save(); err != nil {
+				errors.LogWarningInner(context.Background(), err, "failed to save fake DNS pool")
+			}
+			return nil
+		},
+	}
+	return fkdns.saver.Start()
+}
+
 func (fkdns *Holder) Close() error {
+	if fkdns.saver != nil {
+		fkdns.saver.Close()
+		fkdns.saver = nil
+		if err := fkdns.save(); err != nil {
+			errors.LogWarningInner(context.Background(), err, "failed to save fake DNS pool")
+		}
+	}
+	fkdns.saveMu.Lock()
 	fkdns.domainToIP = nil
 	fkdns.ipRange = nil
 	fkdns.mu = nil
+	fkdns.saveMu.Unlock()
 	return nil
 }
 
@@ -70,7 +112,7 @@ func NewFakeDNSHolder() (*Holder, error) {
 }
 
 func NewFakeDNSHolderConfigOnly(conf *FakeDnsPool) (*Holder, error) {
-	return &Holder{nil, nil, nil, conf}, nil
+	return &Holder{config: conf}, nil
 }
 
 func (fkdns *Holder) initializeFromConfig() error {
@@ -126,6 +168,7 @@ func (fkdns *Holder) GetFakeIPForDomain(domain string) []net.Address {
 		}
 	}
 	fkdns.domainToIP.Put(domain, ip)
+	fkdns.dirty.Store(true)
 	return []net.Address{ip}
 }
 
@@ -1,6 +1,7 @@
 package fakedns
 
 import (
+	"path/filepath"
 	"strconv"
 	"testing"
 
@@ -203,3 +204,49 @@ func TestFakeDNSMulti(t *testing.T) {
 		})
 	})
 }
+
+func TestFakeDNSPersistence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "fakedns.json")
+	newHolder := func(pool string, size int64) *Holder {
+		fkdns, err := NewFakeDNSHolderConfigOnly(&FakeDnsPool{
+			IpPool:      pool,
+			LruSize:     size,
+			PersistPath: path,
+		})
+		common.Must(err)
+		common.Must(fkdns.Start())
+		return fkdns
+	}
+
+	fkdns := newHolder("240.0.0.0/12", 256)
+	var addrs []net.Address
+	for i := 0; i < 10; i++ {
+		addrs = append(addrs, fkdns.GetFakeIPForDomain("fakednstest" + strconv.Itoa(i) + ".example.com")[0])
+	}
+	common.Must(fkdns.Close())
+
+	// a restarted holder maps the old fake IPs back, and doesn't reuse them
+	fkdns = newHolder("240.0.0.0/12", 256)
+	for i, addr := range addrs {
+		assert.Equal(t, "fakednstest"+strconv.Itoa(i)+".example.com", fkdns.GetDomainFromFakeDNS(addr))
+	}
+	assert.Equal(t, addrs[3], fkdns.GetFakeIPForDomain("fakednstest3.example.com")[0])
+	assert.NotContains(t, addrs, fkdns.GetFakeIPForDomain("fakednstest-new.example.com")[0])
+	common.Must(fkdns.Close())
+
+	// a smaller pool keeps the most recently used domains
+	fkdns = newHolder("240.0.0.0/12", 2)
+	assert.Equal(t, "fakednstest3.example.com", fkdns.GetDomainFromFakeDNS(addrs[3]))
+	assert.Equal(t, "", fkdns.GetDomainFromFakeDNS(addrs[9]))
+	common.Must(fkdns.Close())
+
+	// the mapping of another pool is dropped
+	fkdns = newHolder("fddd:c5b4:ff5f:f4f0::/64", 256)
+	assert.Equal(t, "", fkdns.GetDomainFromFakeDNS(addrs[3]))
+	v6 := fkdns.GetFakeIPForDomain("fakednstest3.example.com")[0]
+	common.Must(fkdns.Close())
+
+	fkdns = newHolder("fddd:c5b4:ff5f:f4f0::/64", 256)
+	assert.Equal(t, "fakednstest3.example.com", fkdns.GetDomainFromFakeDNS(v6))
+	common.Must(fkdns.Close())
+}
@@ -0,0 +1,128 @@
+package fakedns
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	"github.com/xtls/xray-core/common/errors"
+	"github.com/xtls/xray-core/common/net"
+)
+
+const poolFileVersion = 1
+
+// poolFile is the mapping of a pool as saved on disk.
+type poolFile struct {
+	Version int    `json:"version"`
+	IPPool  string `json:"ipPool"`
+	LruSize int64  `json:"lruSize"`
+	// Mappings are from the least recently used, so that putting them in
+	// order restores the LRU.
+	Mappings []poolMapping `json:"mappings"`
+}
+
+type poolMapping struct {
+	Domain string `json:"domain"`
+	IP     string `json:"ip"`
+}
+
+// load restores the mapping saved in the persist file. A mapping saved for
+// another pool is dropped, one saved with a bigger size keeps its most
+// recently used domains.
+func (fkdns *Holder) load() error {
+	path := fkdns.config.PersistPath
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return errors.New("failed to read fake DNS pool file ", path).Base(err)
+	}
+
+	var f poolFile
+	if err := json.Unmarshal(data, &f); err != nil {
+		return errors.New("failed to parse fake DNS pool file ", path).Base(err)
+	}
+	if f.Version != poolFileVersion {
+		errors.LogWarning(context.Background(), "fake DNS pool file ", path, " has unknown version ", f.Version, ", ignoring it")
+		return nil
+	}
+	if _, ipRange, err := net.ParseCIDR(f.IPPool); err != nil || ipRange.String() != fkdns.ipRange.String() {
+		errors.LogWarning(context.Background(), "fake DNS pool changed from ", f.IPPool, " to ", fkdns.ipRange, ", dropping the saved mapping")
+		return nil
+	}
+	mappings := f.Mappings
+	if f.LruSize != fkdns.config.LruSize {
+		errors.LogInfo(context.Background(), "fake DNS pool size changed from ", f.LruSize, " to ", fkdns.config.LruSize)
+		if n := int(fkdns.config.LruSize); len(mappings) > n {
+			mappings = mappings[len(mappings)-n:]
+		}
+	}
+
+	fkdns.mu.Lock()
+	defer fkdns.mu.Unlock()
+	loaded := 0
+	for _, m := range mappings {
+		ip := net.ParseAddress(m.IP)
+		if m.Domain == "" || !ip.Family().IsIP() || !fkdns.ipRange.Contains(ip.IP()) {
+			continue
+		}
+		if _, ok := fkdns.domainToIP.PeekKeyFromValue(ip); ok {
+			continue
+		}
+		fkdns.domainToIP.Put(m.Domain, ip)
+		loaded++
+	}
+	errors.LogInfo(context.Background(), "loaded ", loaded, " fake DNS mappings of ", fkdns.ipRange, " from ", path)
+	return nil
+}
+
+// save writes the mapping to the persist file if it changed. The file is
+// replaced at once, so a crash doesn't leave half of it.
+func (fkdns *Holder) save() error {
+	fkdns.saveMu.Lock()
+	defer fkdns.saveMu.Unlock()
+	if fkdns.domainToIP == nil || !fkdns.dirty.Swap(false) {
+		return nil
+	}
+
+	f := poolFile{
+		Version: poolFileVersion,
+		IPPool:  fkdns.ipRange.String(),
+		LruSize: fkdns.config.LruSize,
+	}
+	fkdns.domainToIP.Range(func(key, value interface{}) bool {
+		f.Mappings = append(f.Mappings, poolMapping{
+			Domain: key.(string),
+			IP:     value.(net.Address).IP().String(),
+		})
+		return true
+	})
+	data, err := json.Marshal(&f)
+	if err != nil {
+		return err
+	}
+
+	path := fkdns.config.PersistPath
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp*")
+	if err != nil {
+		fkdns.dirty.Store(true)
+		return errors.New("failed to save fake DNS pool file ", path).Base(err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err = tmp.Write(data); err == nil {
+		err = tmp.Sync()
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), path)
+	}
+	if err != nil {
+		fkdns.dirty.Store(true)
+		return errors.New("failed to save fake DNS pool file ", path).Base(err)
+	}
+	return nil
+}
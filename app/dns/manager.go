@@ -0,0 +1,258 @@
+package dns
+
+import (
+	"strings"
+
+	"github.com/xtls/xray-core/common"
+	"github.com/xtls/xray-core/common/errors"
+	"github.com/xtls/xray-core/common/strmatcher"
+	"google.golang.org/protobuf/proto"
+)
+
+// nameServers returns the clients in use and the matcher of their domain
+// rules.
+func (s *DNS) nameServers() ([]*Client, strmatcher.IndexMatcher, []*DomainMatcherInfo) {
+	s.Lock()
+	defer s.Unlock()
+	return s.clients, s.domainMatcher, s.matcherInfos
+}
+
+// buildDomainMatcher matches the domain rules of clients, in their order.
+func buildDomainMatcher(clients []*Client) (*strmatcher.MatcherGroup, []*DomainMatcherInfo) {
+	g := &strmatcher.MatcherGroup{}
+	// matcher's index starts from 1
+	infos := []*DomainMatcherInfo{nil}
+	for idx, client := range clients {
+		for _, rule := range client.domainRules {
+			g.Add(rule.matcher)
+			infos = append(infos, &DomainMatcherInfo{
+				clientIdx:     uint16(idx),
+				domainRuleIdx: uint16(rule.ruleIdx),
+			})
+		}
+	}
+	return g, infos
+}
+
+// setClients replaces the clients in use. Callers hold the lock.
+func (s *DNS) setClients(clients []*Client) {
+	s.domainMatcher, s.matcherInfos = buildDomainMatcher(clients)
+	s.clients = clients
+}
+
+// AddHosts adds static host mappings, after the existing ones.
+func (s *DNS) AddHosts(mappings []*Config_HostMapping) error {
+	s.Lock()
+	defer s.Unlock()
+
+	newMappings := make([]*Config_HostMapping, 0, len(s.hostMappings)+len(mappings))
+	newMappings = append(newMappings, s.hostMappings...)
+	newMappings = append(newMappings, mappings...)
+	hosts, err := NewStaticHosts(newMappings)
+	if err != nil {
+		return errors.New("failed to create hosts").Base(err)
+	}
+	s.hosts = hosts
+	s.hostMappings = newMappings
+	return nil
+}
+
+// RemoveHosts removes the static host mappings with the type and domain of
+// one in mappings, and returns how many there were.
+func (s *DNS) RemoveHosts(mappings []*Config_HostMapping) (int, error) {
+	type key struct {
+		t      DomainMatchingType
+		domain string
+	}
+	remove := make(map[key]bool, len(mappings))
+	for _, mapping := range mappings {
+		remove[key{mapping.Type, mapping.Domain}] = true
+	}
+
+	s.Lock()
+	defer s.Unlock()
+
+	newMappings := make([]*Config_HostMapping, 0, len(s.hostMappings))
+	for _, mapping := range s.hostMappings {
+		if !remove[key{mapping.Type, mapping.Domain}] {
+			newMappings = append(newMappings, mapping)
+		}
+	}
+	removed := len(s.hostMappings) - len(newMappings)
+	if removed == 0 {
+		return 0, nil
+	}
+	hosts, err := NewStaticHosts(newMappings)
+	if err != nil {
+		return 0, errors.New("failed to create hosts").Base(err)
+	}
+	s.hosts = hosts
+	s.hostMappings = newMappings
+	return removed, nil
+}
+
+// AddNameServer adds nameservers after the existing ones, all of them or
+// none if one fails. Options they don't set are taken from the config. Each
+// is queried apart from the others in parallel mode. The localhost client
+// used when the config has no nameservers gives way to them.
+func (s *DNS) AddNameServer(nss ...*NameServer) error {
+	added := make([]*Client, 0, len(nss))
+	rules := 0
+	for _, ns := range nss {
+		// building the client changes the rules of localhost, so it works on
+		// a copy to leave the request as it is
+		ns = proto.Clone(ns).(*NameServer)
+
+		// the domain rules are kept in the client, these are built anew below
+		var matcherInfos []*DomainMatcherInfo
+		updateDomain := func(strmatcher.Matcher, int, []*DomainMatcherInfo) error { return nil }
+		client, err := s.newClient(ns, &matcherInfos, updateDomain)
+		if err == nil && client.server == nil {
+			err = errors.New("features of nameserver ", ns.Address, " are not ready")
+		}
+		if err != nil {
+			for _, c := range added {
+				common.Close(c.server)
+			}
+			return err
+		}
+		added = append(added, client)
+		rules += len(client.domainRules)
+	}
+	if len(added) == 0 {
+		return nil
+	}
+
+	s.Lock()
+	defer s.Unlock()
+	clients := make([]*Client, 0, len(s.clients)+len(added))
+	for _, c := range s.clients {
+		if c != s.implicitLocal {
+			clients = append(clients, c)
+		}
+	}
+	if len(clients)+len(added) > 0xffff || len(s.matcherInfos)+rules > 0xffff {
+		return errors.New("too many nameservers or domain rules")
+	}
+	var next uint32
+	for _, c := range clients {
+		if c.policyID >= next {
+			next = c.policyID + 1
+		}
+	}
+	for _, c := range added {
+		if c.policyID < next {
+			c.policyID = next
+		}
+		next = c.policyID + 1
+		errors.LogInfo(s.ctx, "DNS: added nameserver ", c.label)
+	}
+	s.setClients(append(clients, added...))
+	return nil
+}
+
+// RemoveNameServer removes the nameservers with tag, or server name for those
+// without one, and returns how many there were.
+func (s *DNS) RemoveNameServer(label string) int {
+	s.Lock()
+	clients := make([]*Client, 0, len(s.clients))
+	var removed []*Client
+	for _, client := range s.clients {
+		if client.label != label {
+			clients = append(clients, client)
+		} else {
+			removed = append(removed, client)
+		}
+	}
+	if len(removed) > 0 {
+		if len(clients) == 0 && s.implicitLocal != nil {
+			// back to the localhost client of a config without nameservers
+			clients = append(clients, s.implicitLocal)
+		}
+		s.setClients(clients)
+	}
+	s.Unlock()
+
+	// queries in flight may still use them, they reconnect if they must
+	for _, client := range removed {
+		if err := common.Close(client.server); err != nil {
+			errors.LogInfoInner(s.ctx, err, "DNS: failed to close nameserver ", label)
+		}
+	}
+	if len(removed) > 0 {
+		errors.LogInfo(s.ctx, "DNS: removed nameserver ", label)
+	}
+	return len(removed)
+}
+
+// CacheEntry is a cached answer of a nameserver.
+type CacheEntry struct {
+	// Server is the tag of the nameserver, or its server name.
+	Server string
+	Domain string
+	// Type is "A" or "AAAA".
+	Type  string
+	IP    []string
+	RCode uint16
+	// TTL is the seconds left, negative once expired.
+	TTL int32
+}
+
+// cacheControllers returns the caches of the nameservers with label, or of
+// all if label is empty.
+func (s *DNS) cacheControllers(label string) ([]*Client, []*CacheController) {
+	allClients, _, _ := s.nameServers()
+	var clients []*Client
+	var caches []*CacheController
+	for _, client := range allClients {
+		if label != "" && client.label != label {
+			continue
+		}
+		if cs, ok := client.server.(CachedNameserver); ok {
+			clients = append(clients, client)
+			caches = append(caches, cs.getCacheController())
+		}
+	}
+	return clients, caches
+}
+
+// ListCache returns the cached answers for domain, or all if empty, of the
+// nameservers with label, or of all if empty.
+func (s *DNS) ListCache(label, domain string) []*CacheEntry {
+	if domain != "" {
+		domain = Fqdn(domain)
+	}
+	var entries []*CacheEntry
+	clients, caches := s.cacheControllers(label)
+	for i, cache := range caches {
+		for _, entry := range cache.listRecords(domain) {
+			entry.Server = clients[i].label
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+// FlushCache removes the cached answers for domain, or all if empty, of the
+// nameservers with label, or of all if empty. It returns how many domains
+// were removed.
+func (s *DNS) FlushCache(label, domain string) int {
+	if domain != "" {
+		domain = Fqdn(domain)
+	}
+	removed := 0
+	_, caches := s.cacheControllers(label)
+	for _, cache := range caches {
+		removed += cache.flushRecords(domain)
+	}
+	return removed
+}
+
+// QueryOrder returns the nameservers a query for domain goes to, in order.
+func (s *DNS) QueryOrder(domain string) []string {
+	var labels []string
+	for _, client := range s.sortClients(strings.TrimSuffix(domain, ".")) {
+		labels = append(labels, client.label)
+	}
+	return labels
+}
@@ -0,0 +1,208 @@
+package dns_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/miekg/dns"
+	"github.com/xtls/xray-core/app/dispatcher"
+	. "github.com/xtls/xray-core/app/dns"
+	"github.com/xtls/xray-core/app/policy"
+	"github.com/xtls/xray-core/app/proxyman"
+	"github.com/xtls/xray-core/common"
+	"github.com/xtls/xray-core/common/net"
+	"github.com/xtls/xray-core/common/serial"
+	"github.com/xtls/xray-core/core"
+	feature_dns "github.com/xtls/xray-core/features/dns"
+	"github.com/xtls/xray-core/proxy/freedom"
+	"github.com/xtls/xray-core/testing/servers/udp"
+)
+
+func TestRuntimeManagement(t *testing.T) {
+	port := udp.PickPort()
+
+	dnsServer := dns.Server{
+		Addr:    "127.0.0.1:" + port.String(),
+		Net:     "udp",
+		Handler: &staticHandler{},
+		UDPSize: 1200,
+	}
+
+	go dnsServer.ListenAndServe()
+	time.Sleep(time.Second)
+
+	nameServer := func(tag string, domains ...string) *NameServer {
+		ns := &NameServer{
+			Address: &net.Endpoint{
+				Network: net.Network_UDP,
+				Address: &net.IPOrDomain{
+					Address: &net.IPOrDomain_Ip{
+						Ip: []byte{127, 0, 0, 1},
+					},
+				},
+				Port: uint32(port),
+			},
+			Tag: tag,
+		}
+		for _, domain := range domains {
+			ns.PrioritizedDomain = append(ns.PrioritizedDomain, &NameServer_PriorityDomain{Type: DomainMatchingType_Full, Domain: domain})
+		}
+		return ns
+	}
+
+	config := &core.Config{
+		App: []*serial.TypedMessage{
+			serial.ToTypedMessage(&Config{
+				NameServer: []*NameServer{nameServer("primary")},
+			}),
+			serial.ToTypedMessage(&dispatcher.Config{}),
+			serial.ToTypedMessage(&proxyman.OutboundConfig{}),
+			serial.ToTypedMessage(&policy.Config{}),
+		},
+		Outbound: []*core.OutboundHandlerConfig{
+			{
+				ProxySettings: serial.ToTypedMessage(&freedom.Config{}),
+			},
+		},
+	}
+
+	v, err := core.New(config)
+	common.Must(err)
+
+	d := v.GetFeature(feature_dns.ClientType()).(*DNS)
+	lookup := func(domain string) []net.IP {
+		t.Helper()
+		ips, _, err := d.LookupIP(domain, feature_dns.IPOption{IPv4Enable: true})
+		common.Must(err)
+		return ips
+	}
+
+	if r := cmp.Diff(lookup("google.com"), []net.IP{{8, 8, 8, 8}}); r != "" {
+		t.Fatal(r)
+	}
+	entries := d.ListCache("", "Google.com")
+	if len(entries) != 1 || entries[0].Server != "primary" || entries[0].Type != "A" || entries[0].IP[0] != "8.8.8.8" {
+		t.Fatalf("unexpected cache entries %+v", entries)
+	}
+
+	// split-horizon entry
+	mapping := &Config_HostMapping{Type: DomainMatchingType_Full, Domain: "google.com", Ip: [][]byte{{10, 0, 0, 1}}}
+	common.Must(d.AddHosts([]*Config_HostMapping{mapping}))
+	if r := cmp.Diff(lookup("google.com"), []net.IP{{10, 0, 0, 1}}); r != "" {
+		t.Fatal(r)
+	}
+	if removed, err := d.RemoveHosts([]*Config_HostMapping{{Type: DomainMatchingType_Full, Domain: "google.com"}}); err != nil || removed != 1 {
+		t.Fatalf("unexpected removal of hosts: %d, %v", removed, err)
+	}
+	if r := cmp.Diff(lookup("google.com"), []net.IP{{8, 8, 8, 8}}); r != "" {
+		t.Fatal(r)
+	}
+
+	if removed := d.FlushCache("other", ""); removed != 0 {
+		t.Fatalf("flushed %d domains of a nameserver not configured", removed)
+	}
+	if removed := d.FlushCache("primary", "google.com"); removed != 1 {
+		t.Fatalf("flushed %d domains", removed)
+	}
+	if entries := d.ListCache("", ""); len(entries) != 0 {
+		t.Fatalf("unexpected cache entries after flush %+v", entries)
+	}
+
+	split := nameServer("split", "api.google.com")
+	common.Must(d.AddNameServer(split))
+	if split.PolicyID != 0 {
+		t.Fatalf("the request was changed to policy %d", split.PolicyID)
+	}
+	if r := cmp.Diff(d.QueryOrder("api.google.com"), []string{"split", "primary"}); r != "" {
+		t.Fatal(r)
+	}
+	if r := cmp.Diff(lookup("api.google.com"), []net.IP{{8, 8, 7, 7}}); r != "" {
+		t.Fatal(r)
+	}
+	if removed := d.RemoveNameServer("split"); removed != 1 {
+		t.Fatalf("removed %d nameservers", removed)
+	}
+	if r := cmp.Diff(d.QueryOrder("api.google.com"), []string{"primary"}); r != "" {
+		t.Fatal(r)
+	}
+}
+
+func TestAddNameServerWithoutConfiguredOnes(t *testing.T) {
+	port := udp.PickPort()
+
+	dnsServer := dns.Server{
+		Addr:    "127.0.0.1:" + port.String(),
+		Net:     "udp",
+		Handler: &staticHandler{},
+		UDPSize: 1200,
+	}
+
+	go dnsServer.ListenAndServe()
+	time.Sleep(time.Second)
+
+	nameServer := func(tag string) *NameServer {
+		return &NameServer{
+			Address: &net.Endpoint{
+				Network: net.Network_UDP,
+				Address: &net.IPOrDomain{
+					Address: &net.IPOrDomain_Ip{
+						Ip: []byte{127, 0, 0, 1},
+					},
+				},
+				Port: uint32(port),
+			},
+			Tag: tag,
+		}
+	}
+
+	config := &core.Config{
+		App: []*serial.TypedMessage{
+			serial.ToTypedMessage(&Config{}),
+			serial.ToTypedMessage(&dispatcher.Config{}),
+			serial.ToTypedMessage(&proxyman.OutboundConfig{}),
+			serial.ToTypedMessage(&policy.Config{}),
+		},
+		Outbound: []*core.OutboundHandlerConfig{
+			{
+				ProxySettings: serial.ToTypedMessage(&freedom.Config{}),
+			},
+		},
+	}
+
+	v, err := core.New(config)
+	common.Must(err)
+
+	d := v.GetFeature(feature_dns.ClientType()).(*DNS)
+	if r := cmp.Diff(d.QueryOrder("google.com"), []string{"localhost"}); r != "" {
+		t.Fatal(r)
+	}
+
+	// the localhost client of a config without nameservers gives way
+	common.Must(d.AddNameServer(nameServer("primary")))
+	if r := cmp.Diff(d.QueryOrder("google.com"), []string{"primary"}); r != "" {
+		t.Fatal(r)
+	}
+	ips, _, err := d.LookupIP("google.com", feature_dns.IPOption{IPv4Enable: true})
+	common.Must(err)
+	if r := cmp.Diff(ips, []net.IP{{8, 8, 8, 8}}); r != "" {
+		t.Fatal(r)
+	}
+
+	// nothing is added if one of the nameservers fails
+	bad := nameServer("bad")
+	bad.PrioritizedDomain = []*NameServer_PriorityDomain{{Type: DomainMatchingType_Regex, Domain: "("}}
+	if err := d.AddNameServer(nameServer("secondary"), bad); err == nil {
+		t.Fatal("expected an invalid nameserver to be rejected")
+	}
+	if r := cmp.Diff(d.QueryOrder("google.com"), []string{"primary"}); r != "" {
+		t.Fatal(r)
+	}
+
+	if removed := d.RemoveNameServer("primary"); removed != 1 {
+		t.Fatalf("removed %d nameservers", removed)
+	}
+	if r := cmp.Diff(d.QueryOrder("google.com"), []string{"localhost"}); r != "" {
+		t.Fatal(r)
+	}
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/xtls/xray-core/core"
 	"github.com/xtls/xray-core/features/dns"
 	"github.com/xtls/xray-core/features/routing"
+	"github.com/xtls/xray-core/features/stats"
 )
 
 // Server is the interface for Name Server.
@@ -42,6 +43,16 @@ type Client struct {
 	ipOption      *dns.IPOption
 	checkSystem   bool
 	policyID      uint32
+	stats         *clientStats
+	// tag of the nameserver, or the server name if it has none
+	label string
+	// domain rules, to rebuild the matcher when nameservers change
+	domainRules []clientDomainRule
+}
+
+type clientDomainRule struct {
+	matcher strmatcher.Matcher
+	ruleIdx int
 }
 
 // NewServer creates a name server object according to the network destination url.
@@ -68,6 +79,10 @@ func NewServer(ctx context.Context, dest net.Destination, dispatcher routing.Dis
 			return NewTCPNameServer(u, dispatcher, disableCache, serveStale, serveExpiredTTL, clientIP)
 		case strings.EqualFold(u.Scheme, "tcp+local"): // DNS-over-TCP Local mode
 			return NewTCPLocalNameServer(u, disableCache, serveStale, serveExpiredTTL, clientIP)
+		case strings.EqualFold(u.Scheme, "tls"): // DNS-over-TLS Remote mode
+			return NewTLSNameServer(u, dispatcher, disableCache, serveStale, serveExpiredTTL, clientIP)
+		case strings.EqualFold(u.Scheme, "tls+local"): // DNS-over-TLS Local mode
+			return NewTLSLocalNameServer(u, disableCache, serveStale, serveExpiredTTL, clientIP)
 		case strings.EqualFold(u.String(), "fakedns"):
 			var fd dns.FakeDNSEngine
 			err = core.RequireFeatures(ctx, func(fdns dns.FakeDNSEngine) {
@@ -96,18 +111,30 @@ func NewClient(
 	disableCache bool, serveStale bool, serveExpiredTTL uint32,
 	tag string,
 	ipOption dns.IPOption,
+	trustAnchors TrustAnchors,
 	matcherInfos *[]*DomainMatcherInfo,
 	updateDomainRule func(strmatcher.Matcher, int, []*DomainMatcherInfo) error,
 ) (*Client, error) {
 	client := &Client{}
 
-	err := core.RequireFeatures(ctx, func(dispatcher routing.Dispatcher) error {
+	err := core.RequireFeatures(ctx, func(dispatcher routing.Dispatcher, sm stats.Manager) error {
 		// Create a new server for each client for now
 		server, err := NewServer(ctx, ns.Address.AsDestination(), dispatcher, disableCache, serveStale, serveExpiredTTL, clientIP)
 		if err != nil {
 			return errors.New("failed to create nameserver").Base(err).AtWarning()
 		}
 
+		// Validate answers with DNSSEC, except those of FakeDNS which aren't real
+		if trustAnchors != nil {
+			switch s := server.(type) {
+			case rawExchanger:
+				server = NewDNSSECNameServer(s, trustAnchors, clientIP)
+			case *FakeDNSServer:
+			default:
+				return errors.New("nameserver ", server.Name(), " doesn't support DNSSEC validation, set skipDnssec for it").AtWarning()
+			}
+		}
+
 		// Prioritize local domains with specific TLDs or those without any dot for the local DNS
 		if _, isLocalDNS := server.(*LocalNameServer); isLocalDNS {
 			ns.PrioritizedDomain = append(ns.PrioritizedDomain, localTLDsAndDotlessDomains...)
@@ -155,6 +182,7 @@ func NewClient(
 			if err != nil {
 				return errors.New("failed to create prioritized domain").Base(err).AtWarning()
 			}
+			client.domainRules = append(client.domainRules, clientDomainRule{domainRule, originalRuleIdx})
 		}
 
 		// Establish expected IPs
@@ -204,6 +232,12 @@ func NewClient(
 		client.ipOption = &ipOption
 		client.checkSystem = checkSystem
 		client.policyID = ns.PolicyID
+		client.label = ns.Tag
+		if client.label == "" {
+			client.label = server.Name()
+		}
+		// nameservers sharing a tag share their counters
+		client.stats = newClientStats(sm, client.label)
 		return nil
 	})
 	return client, err
@@ -231,15 +265,18 @@ func (c *Client) QueryIP(ctx context.Context, domain string, option dns.IPOption
 
 	ctx, cancel := context.WithTimeout(ctx, c.timeoutMs)
 	ctx = session.ContextWithInbound(ctx, &session.Inbound{Tag: c.tag})
+	ctx, record := contextWithQueryRecord(ctx)
+	start := time.Now()
 	ips, ttl, err := c.server.QueryIP(ctx, domain, option)
 	cancel()
 
-	if err != nil {
-		return nil, 0, err
+	if err == nil && len(ips) == 0 {
+		err = dns.ErrEmptyResponse
 	}
+	c.stats.record(time.Since(start), record.cacheHit, err, c.timeoutMs)
 
-	if len(ips) == 0 {
-		return nil, 0, dns.ErrEmptyResponse
+	if err != nil {
+		return nil, 0, err
 	}
 
 	if c.expectedIPs != nil && !c.actPrior {
@@ -0,0 +1,132 @@
+package dns
+
+import (
+	"context"
+	go_errors "errors"
+	"time"
+
+	"github.com/xtls/xray-core/common"
+	"github.com/xtls/xray-core/common/errors"
+	"github.com/xtls/xray-core/common/net"
+	dns_feature "github.com/xtls/xray-core/features/dns"
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// dnssecUDPSize is the UDP payload size advertised in queries for DNSSEC
+// records, which don't fit in the classic 512 bytes.
+const dnssecUDPSize = 1232
+
+// bogusTTL is how long a bogus answer is cached as a failure.
+const bogusTTL = 30 * time.Second
+
+// rawExchanger is a nameserver that can send packed queries, for DNSSEC
+// validation to get the records it needs.
+type rawExchanger interface {
+	Server
+	CachedNameserver
+
+	// exchangeRaw sends query and returns the packed response. The message
+	// id of query may be changed.
+	exchangeRaw(ctx context.Context, query []byte) ([]byte, error)
+}
+
+// DNSSECNameServer validates the answers of a nameserver with DNSSEC. Bogus
+// answers are cached as server failures, so that the next nameserver is
+// queried.
+type DNSSECNameServer struct {
+	rawExchanger
+	validator *dnssecValidator
+	clientIP  net.IP
+}
+
+// NewDNSSECNameServer creates a validating nameserver on top of server.
+func NewDNSSECNameServer(server rawExchanger, anchors TrustAnchors, clientIP net.IP) *DNSSECNameServer {
+	errors.LogInfo(context.Background(), "DNS: enabled DNSSEC validation for ", server.Name())
+	return &DNSSECNameServer{
+		rawExchanger: server,
+		validator:    newDNSSECValidator(anchors, server.exchangeRaw),
+		clientIP:     clientIP,
+	}
+}
+
+// Close implements common.Closable. It closes the validated nameserver.
+func (s *DNSSECNameServer) Close() error {
+	return common.Close(s.rawExchanger)
+}
+
+// sendQuery implements CachedNameserver.
+func (s *DNSSECNameServer) sendQuery(ctx context.Context, noResponseErrCh chan<- error, fqdn string, option dns_feature.IPOption) {
+	errors.LogInfo(ctx, s.Name(), " querying DNS with DNSSEC for: ", fqdn)
+
+	opt := genEDNS0Options(s.clientIP, 0)
+	if opt == nil {
+		opt = &dnsmessage.Resource{Body: &dnsmessage.OPTResource{}}
+	}
+	common.Must(opt.Header.SetEDNS0(dnssecUDPSize, 0xfe00, true))
+	reqs := buildReqMsgs(fqdn, option, func() uint16 { return 0 }, opt)
+
+	var deadline time.Time
+	if d, ok := ctx.Deadline(); ok {
+		deadline = d
+	} else {
+		deadline = time.Now().Add(time.Second * 5)
+	}
+
+	for _, req := range reqs {
+		go func(r *dnsRequest) {
+			dnsCtx, cancel := context.WithDeadline(ctx, deadline)
+			defer cancel()
+
+			r.msg.CheckingDisabled = true
+			query, err := r.msg.Pack()
+			if err != nil {
+				errors.LogErrorInner(ctx, err, "failed to pack dns query")
+				if noResponseErrCh != nil {
+					noResponseErrCh <- err
+				}
+				return
+			}
+
+			resp, err := s.exchangeRaw(dnsCtx, query)
+			if err != nil {
+				errors.LogErrorInner(ctx, err, "failed to query ", s.Name())
+				if noResponseErrCh != nil {
+					noResponseErrCh <- err
+				}
+				return
+			}
+
+			rec, err := parseResponse(resp)
+			if err != nil {
+				errors.LogErrorInner(ctx, err, "failed to parse response of ", s.Name())
+				if noResponseErrCh != nil {
+					noResponseErrCh <- err
+				}
+				return
+			}
+
+			if err := s.validator.validate(dnsCtx, resp); err != nil {
+				if !go_errors.Is(err, errBogus) {
+					errors.LogWarningInner(ctx, err, "failed to validate answer of ", s.Name(), " for ", fqdn)
+					if noResponseErrCh != nil {
+						noResponseErrCh <- err
+					}
+					return
+				}
+				errors.LogWarningInner(ctx, err, s.Name(), " answered bogus ", r.reqType, " for ", fqdn)
+				rec = &IPRecord{
+					ReqID:     rec.ReqID,
+					RCode:     dnsmessage.RCodeServerFailure,
+					Expire:    time.Now().Add(bogusTTL),
+					RawHeader: rec.RawHeader,
+				}
+			}
+			s.getCacheController().updateRecord(r, rec)
+		}(req)
+	}
+}
+
+// QueryIP implements Server.
+func (s *DNSSECNameServer) QueryIP(ctx context.Context, domain string, option dns_feature.IPOption) ([]net.IP, uint32, error) {
+	return queryIP(ctx, s, domain, option)
+}
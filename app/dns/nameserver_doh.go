@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"crypto/tls"
+	"encoding/binary"
 	"fmt"
 	"io"
 	"net/http"
@@ -125,6 +126,13 @@ func (s *DoHNameServer) newReqID() uint16 {
 	return 0
 }
 
+// Close implements common.Closable. It closes the idle connections and stops
+// the cache cleanup.
+func (s *DoHNameServer) Close() error {
+	s.httpClient.CloseIdleConnections()
+	return s.cacheController.Close()
+}
+
 // getCacheController implements CachedNameserver.
 func (s *DoHNameServer) getCacheController() *CacheController {
 	return s.cacheController
@@ -233,6 +241,16 @@ func (s *DoHNameServer) dohHTTPSContext(ctx context.Context, b []byte) ([]byte,
 	return io.ReadAll(resp.Body)
 }
 
+// exchangeRaw implements rawExchanger.
+func (s *DoHNameServer) exchangeRaw(ctx context.Context, query []byte) ([]byte, error) {
+	ctx = session.ContextWithContent(ctx, &session.Content{
+		Protocol:       "https",
+		SkipDNSResolve: true,
+	})
+	binary.BigEndian.PutUint16(query, s.newReqID())
+	return s.dohHTTPSContext(ctx, query)
+}
+
 // QueryIP implements Server.
 func (s *DoHNameServer) QueryIP(ctx context.Context, domain string, option dns_feature.IPOption) ([]net.IP, uint32, error) {
 	return queryIP(ctx, s, domain, option)
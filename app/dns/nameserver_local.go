@@ -50,5 +50,6 @@ func NewLocalNameServer() *LocalNameServer {
 
 // NewLocalDNSClient creates localdns client object for directly lookup in system DNS.
 func NewLocalDNSClient(ipOption dns.IPOption) *Client {
-	return &Client{server: NewLocalNameServer(), ipOption: &ipOption}
+	server := NewLocalNameServer()
+	return &Client{server: server, ipOption: &ipOption, label: server.Name()}
 }
@@ -74,6 +74,18 @@ func (s *QUICNameServer) newReqID() uint16 {
 // getCacheController implements CachedNameServer.
 func (s *QUICNameServer) getCacheController() *CacheController { return s.cacheController }
 
+// Close implements common.Closable. It closes the connection and stops the
+// cache cleanup.
+func (s *QUICNameServer) Close() error {
+	s.Lock()
+	if s.connection != nil {
+		_ = s.connection.CloseWithError(0, "")
+		s.connection = nil
+	}
+	s.Unlock()
+	return s.cacheController.Close()
+}
+
 // sendQuery implements CachedNameServer.
 func (s *QUICNameServer) sendQuery(ctx context.Context, noResponseErrCh chan<- error, fqdn string, option dns_feature.IPOption) {
 	errors.LogInfo(ctx, s.Name(), " querying: ", fqdn)
@@ -197,6 +209,26 @@ func (s *QUICNameServer) sendQuery(ctx context.Context, noResponseErrCh chan<- e
 	}
 }
 
+// exchangeRaw implements rawExchanger.
+func (s *QUICNameServer) exchangeRaw(ctx context.Context, query []byte) ([]byte, error) {
+	ctx = session.ContextWithContent(ctx, &session.Content{
+		Protocol:       "quic",
+		SkipDNSResolve: true,
+	})
+	// the message id is 0 over QUIC
+	binary.BigEndian.PutUint16(query, 0)
+
+	conn, err := s.openStream(ctx)
+	if err != nil {
+		return nil, errors.New("failed to open quic connection").Base(err)
+	}
+	if err := writeStreamMessage(conn, query); err != nil {
+		return nil, errors.New("failed to send query").Base(err)
+	}
+	_ = conn.Close()
+	return readStreamMessage(conn)
+}
+
 // QueryIP implements Server.
 func (s *QUICNameServer) QueryIP(ctx context.Context, domain string, option dns_feature.IPOption) ([]net.IP, uint32, error) {
 	return queryIP(ctx, s, domain, option)
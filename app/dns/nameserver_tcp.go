@@ -104,6 +104,11 @@ func (s *TCPNameServer) newReqID() uint16 {
 	return uint16(atomic.AddUint32(&s.reqID, 1))
 }
 
+// Close implements common.Closable. It stops the cache cleanup.
+func (s *TCPNameServer) Close() error {
+	return s.cacheController.Close()
+}
+
 // getCacheController implements CachedNameserver.
 func (s *TCPNameServer) getCacheController() *CacheController {
 	return s.cacheController
@@ -229,6 +234,27 @@ func (s *TCPNameServer) sendQuery(ctx context.Context, noResponseErrCh chan<- er
 	}
 }
 
+// exchangeRaw implements rawExchanger.
+func (s *TCPNameServer) exchangeRaw(ctx context.Context, query []byte) ([]byte, error) {
+	ctx = session.ContextWithContent(ctx, &session.Content{
+		Protocol:       "dns",
+		SkipDNSResolve: true,
+	})
+	conn, err := s.dial(ctx)
+	if err != nil {
+		return nil, errors.New("failed to dial namesever").Base(err)
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	if err := writeStreamMessage(conn, query); err != nil {
+		return nil, errors.New("failed to send query").Base(err)
+	}
+	return readStreamMessage(conn)
+}
+
 // QueryIP implements Server.
 func (s *TCPNameServer) QueryIP(ctx context.Context, domain string, option dns_feature.IPOption) ([]net.IP, uint32, error) {
 	return queryIP(ctx, s, domain, option)
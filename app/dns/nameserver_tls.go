@@ -0,0 +1,399 @@
+package dns
+
+import (
+	"context"
+	gotls "crypto/tls"
+	"encoding/binary"
+	"io"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/xtls/xray-core/common/errors"
+	"github.com/xtls/xray-core/common/log"
+	"github.com/xtls/xray-core/common/net"
+	"github.com/xtls/xray-core/common/net/cnc"
+	"github.com/xtls/xray-core/common/protocol/dns"
+	"github.com/xtls/xray-core/common/session"
+	dns_feature "github.com/xtls/xray-core/features/dns"
+	"github.com/xtls/xray-core/features/routing"
+	"github.com/xtls/xray-core/transport/internet"
+	"github.com/xtls/xray-core/transport/internet/tls"
+)
+
+// NextProtoDoT is the ALPN token of DNS over TLS.
+const NextProtoDoT = "dot"
+
+// dotIdleTimeout is how long a connection without queries in flight is kept.
+const dotIdleTimeout = 30 * time.Second
+
+// TLSNameServer implemented DNS over TLS (RFC7858). Queries share one
+// connection and are pipelined, responses are matched by message ID.
+type TLSNameServer struct {
+	cacheController *CacheController
+	destination     *net.Destination
+	reqID           uint32
+	dial            func(context.Context) (net.Conn, error)
+	tlsConfig       *gotls.Config
+	clientIP        net.IP
+
+	access sync.Mutex
+	conn   *dotConn
+}
+
+// NewTLSNameServer creates DNS over TLS server object for remote resolving.
+func NewTLSNameServer(
+	url *url.URL,
+	dispatcher routing.Dispatcher,
+	disableCache bool, serveStale bool, serveExpiredTTL uint32,
+	clientIP net.IP,
+) (*TLSNameServer, error) {
+	s, err := baseTLSNameServer(url, "DOT", disableCache, serveStale, serveExpiredTTL, clientIP)
+	if err != nil {
+		return nil, err
+	}
+
+	s.dial = func(ctx context.Context) (net.Conn, error) {
+		link, err := dispatcher.Dispatch(toDnsContext(ctx, s.destination.String()), *s.destination)
+		if err != nil {
+			return nil, err
+		}
+
+		return cnc.NewConnection(
+			cnc.ConnectionInputMulti(link.Writer),
+			cnc.ConnectionOutputMulti(link.Reader),
+		), nil
+	}
+
+	errors.LogInfo(context.Background(), "DNS: created DNS-over-TLS client initialized for ", url.String())
+	return s, nil
+}
+
+// NewTLSLocalNameServer creates DNS over TLS client object for local resolving
+func NewTLSLocalNameServer(url *url.URL, disableCache bool, serveStale bool, serveExpiredTTL uint32, clientIP net.IP) (*TLSNameServer, error) {
+	s, err := baseTLSNameServer(url, "DOTL", disableCache, serveStale, serveExpiredTTL, clientIP)
+	if err != nil {
+		return nil, err
+	}
+
+	s.dial = func(ctx context.Context) (net.Conn, error) {
+		log.Record(&log.AccessMessage{
+			From:   "DNS",
+			To:     s.destination,
+			Status: log.AccessAccepted,
+			Detour: "local",
+		})
+		return internet.DialSystem(ctx, *s.destination, nil)
+	}
+
+	errors.LogInfo(context.Background(), "DNS: created Local DNS-over-TLS client initialized for ", url.String())
+	return s, nil
+}
+
+func baseTLSNameServer(url *url.URL, prefix string, disableCache bool, serveStale bool, serveExpiredTTL uint32, clientIP net.IP) (*TLSNameServer, error) {
+	port := net.Port(853)
+	if url.Port() != "" {
+		var err error
+		if port, err = net.PortFromString(url.Port()); err != nil {
+			return nil, err
+		}
+	}
+	dest := net.TCPDestination(net.ParseAddress(url.Hostname()), port)
+
+	tlsConfig := &tls.Config{ServerName: url.Hostname()}
+	s := &TLSNameServer{
+		cacheController: NewCacheController(prefix+"//"+dest.NetAddr(), disableCache, serveStale, serveExpiredTTL),
+		destination:     &dest,
+		tlsConfig:       tlsConfig.GetTLSConfig(tls.WithNextProto(NextProtoDoT)),
+		clientIP:        clientIP,
+	}
+
+	return s, nil
+}
+
+// Name implements Server.
+func (s *TLSNameServer) Name() string {
+	return s.cacheController.name
+}
+
+// IsDisableCache implements Server.
+func (s *TLSNameServer) IsDisableCache() bool {
+	return s.cacheController.disableCache
+}
+
+func (s *TLSNameServer) newReqID() uint16 {
+	return uint16(atomic.AddUint32(&s.reqID, 1))
+}
+
+// Close implements common.Closable. It closes the connection and stops the
+// cache cleanup.
+func (s *TLSNameServer) Close() error {
+	s.access.Lock()
+	if s.conn != nil {
+		s.conn.close(errors.New("nameserver removed"))
+	}
+	s.access.Unlock()
+	return s.cacheController.Close()
+}
+
+// getCacheController implements CachedNameserver.
+func (s *TLSNameServer) getCacheController() *CacheController {
+	return s.cacheController
+}
+
+// sendQuery implements CachedNameserver.
+func (s *TLSNameServer) sendQuery(ctx context.Context, noResponseErrCh chan<- error, fqdn string, option dns_feature.IPOption) {
+	errors.LogInfo(ctx, s.Name(), " querying DNS for: ", fqdn)
+
+	reqs := buildReqMsgs(fqdn, option, s.newReqID, genEDNS0Options(s.clientIP, 0))
+
+	var deadline time.Time
+	if d, ok := ctx.Deadline(); ok {
+		deadline = d
+	} else {
+		deadline = time.Now().Add(time.Second * 5)
+	}
+
+	for _, req := range reqs {
+		go func(r *dnsRequest) {
+			dnsCtx := ctx
+
+			if inbound := session.InboundFromContext(ctx); inbound != nil {
+				dnsCtx = session.ContextWithInbound(dnsCtx, inbound)
+			}
+
+			dnsCtx = session.ContextWithContent(dnsCtx, &session.Content{
+				Protocol:       "tls",
+				SkipDNSResolve: true,
+			})
+
+			var cancel context.CancelFunc
+			dnsCtx, cancel = context.WithDeadline(dnsCtx, deadline)
+			defer cancel()
+
+			b, err := dns.PackMessage(r.msg)
+			if err != nil {
+				errors.LogErrorInner(ctx, err, "failed to pack dns query")
+				if noResponseErrCh != nil {
+					noResponseErrCh <- err
+				}
+				return
+			}
+			query := make([]byte, 2+b.Len())
+			binary.BigEndian.PutUint16(query, uint16(b.Len()))
+			copy(query[2:], b.Bytes())
+			b.Release()
+
+			resp, err := s.exchange(dnsCtx, r.msg.ID, query)
+			if err != nil {
+				errors.LogErrorInner(ctx, err, "failed to query DNS over TLS")
+				if noResponseErrCh != nil {
+					noResponseErrCh <- err
+				}
+				return
+			}
+
+			rec, err := parseResponse(resp)
+			if err != nil {
+				errors.LogErrorInner(ctx, err, "failed to parse DNS over TLS response")
+				if noResponseErrCh != nil {
+					noResponseErrCh <- err
+				}
+				return
+			}
+
+			s.cacheController.updateRecord(r, rec)
+		}(req)
+	}
+}
+
+// exchange sends query on the shared connection and waits for the response
+// with the same id. A connection the server closed meanwhile is replaced
+// once.
+func (s *TLSNameServer) exchange(ctx context.Context, id uint16, query []byte) ([]byte, error) {
+	var err error
+	for i := 0; i < 2; i++ {
+		var c *dotConn
+		if c, err = s.getConn(ctx); err != nil {
+			return nil, err
+		}
+		var resp []byte
+		resp, err = c.exchange(ctx, id, query)
+		if err == nil || ctx.Err() != nil {
+			return resp, err
+		}
+	}
+	return nil, err
+}
+
+// exchangeRaw implements rawExchanger.
+func (s *TLSNameServer) exchangeRaw(ctx context.Context, query []byte) ([]byte, error) {
+	ctx = session.ContextWithContent(ctx, &session.Content{
+		Protocol:       "tls",
+		SkipDNSResolve: true,
+	})
+	id := s.newReqID()
+	framed := make([]byte, 2+len(query))
+	binary.BigEndian.PutUint16(framed, uint16(len(query)))
+	copy(framed[2:], query)
+	binary.BigEndian.PutUint16(framed[2:], id)
+	return s.exchange(ctx, id, framed)
+}
+
+func (s *TLSNameServer) getConn(ctx context.Context) (*dotConn, error) {
+	s.access.Lock()
+	defer s.access.Unlock()
+	if s.conn != nil && !s.conn.isClosed() {
+		return s.conn, nil
+	}
+
+	// the connection outlives the query opening it, and the dispatcher ends
+	// the link once the context of Dispatch is done, so only the deadline of
+	// the query is kept, for the handshake
+	connCtx := context.WithoutCancel(ctx)
+	conn, err := s.dial(connCtx)
+	if err != nil {
+		return nil, errors.New("failed to dial ", s.destination).Base(err)
+	}
+	handshakeCtx := connCtx
+	if deadline, ok := ctx.Deadline(); ok {
+		var cancel context.CancelFunc
+		handshakeCtx, cancel = context.WithDeadline(connCtx, deadline)
+		defer cancel()
+	}
+	tlsConn := gotls.Client(conn, s.tlsConfig)
+	if err := tlsConn.HandshakeContext(handshakeCtx); err != nil {
+		conn.Close()
+		return nil, errors.New("failed to handshake with ", s.destination).Base(err)
+	}
+	s.conn = newDoTConn(tlsConn)
+	return s.conn, nil
+}
+
+// dotConn is a connection to a DoT server with queries in flight.
+type dotConn struct {
+	conn net.Conn
+
+	writeAccess sync.Mutex
+
+	access  sync.Mutex
+	pending map[uint16]chan []byte
+	idle    *time.Timer
+	err     error
+	closed  chan struct{}
+}
+
+func newDoTConn(conn net.Conn) *dotConn {
+	c := &dotConn{
+		conn:    conn,
+		pending: make(map[uint16]chan []byte),
+		closed:  make(chan struct{}),
+	}
+	c.idle = time.AfterFunc(dotIdleTimeout, func() {
+		c.close(errors.New("idle"))
+	})
+	go c.readLoop()
+	return c
+}
+
+func (c *dotConn) isClosed() bool {
+	select {
+	case <-c.closed:
+		return true
+	default:
+		return false
+	}
+}
+
+func (c *dotConn) close(err error) {
+	c.access.Lock()
+	defer c.access.Unlock()
+	if c.isClosed() {
+		return
+	}
+	c.err = err
+	c.idle.Stop()
+	close(c.closed)
+	c.conn.Close()
+}
+
+func (c *dotConn) exchange(ctx context.Context, id uint16, query []byte) ([]byte, error) {
+	ch := make(chan []byte, 1)
+	c.access.Lock()
+	if c.isClosed() {
+		c.access.Unlock()
+		return nil, c.err
+	}
+	if _, found := c.pending[id]; found {
+		c.access.Unlock()
+		return nil, errors.New("duplicate query ID ", id)
+	}
+	c.pending[id] = ch
+	c.idle.Stop()
+	c.access.Unlock()
+	defer c.done(id)
+
+	c.writeAccess.Lock()
+	if deadline, ok := ctx.Deadline(); ok {
+		c.conn.SetWriteDeadline(deadline)
+	}
+	_, err := c.conn.Write(query)
+	c.writeAccess.Unlock()
+	if err != nil {
+		c.close(err)
+		return nil, err
+	}
+
+	select {
+	case resp := <-ch:
+		return resp, nil
+	case <-c.closed:
+		return nil, errors.New("connection closed").Base(c.err)
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// done forgets the query id, the connection is idle after the last one.
+func (c *dotConn) done(id uint16) {
+	c.access.Lock()
+	defer c.access.Unlock()
+	delete(c.pending, id)
+	if len(c.pending) == 0 && !c.isClosed() {
+		c.idle.Reset(dotIdleTimeout)
+	}
+}
+
+func (c *dotConn) readLoop() {
+	var length [2]byte
+	for {
+		if _, err := io.ReadFull(c.conn, length[:]); err != nil {
+			c.close(err)
+			return
+		}
+		resp := make([]byte, binary.BigEndian.Uint16(length[:]))
+		if _, err := io.ReadFull(c.conn, resp); err != nil {
+			c.close(err)
+			return
+		}
+		if len(resp) < 2 {
+			continue
+		}
+		c.access.Lock()
+		ch, found := c.pending[binary.BigEndian.Uint16(resp)]
+		c.access.Unlock()
+		if found {
+			// buffered, and only one response per query is waited for
+			select {
+			case ch <- resp:
+			default:
+			}
+		}
+	}
+}
+
+// QueryIP implements Server.
+func (s *TLSNameServer) QueryIP(ctx context.Context, domain string, option dns_feature.IPOption) ([]net.IP, uint32, error) {
+	return queryIP(ctx, s, domain, option)
+}
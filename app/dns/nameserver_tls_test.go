@@ -0,0 +1,235 @@
+package dns
+
+import (
+	"context"
+	gotls "crypto/tls"
+	"crypto/x509"
+	"encoding/binary"
+	"io"
+	gonet "net"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/miekg/dns"
+	"github.com/xtls/xray-core/common"
+	"github.com/xtls/xray-core/common/buf"
+	"github.com/xtls/xray-core/common/net"
+	"github.com/xtls/xray-core/common/protocol/tls/cert"
+	"github.com/xtls/xray-core/core"
+	dns_feature "github.com/xtls/xray-core/features/dns"
+	"github.com/xtls/xray-core/features/routing"
+	"github.com/xtls/xray-core/transport"
+	"github.com/xtls/xray-core/transport/pipe"
+)
+
+// dotStandIn answers queries in pairs and in reverse order, so a client
+// only gets answers if it pipelines and matches them by ID.
+type dotStandIn struct {
+	listener gonet.Listener
+
+	access sync.Mutex
+	conns  []gonet.Conn
+}
+
+func newDoTStandIn(t *testing.T) (*dotStandIn, *x509.CertPool) {
+	ca := cert.MustGenerate(nil, cert.Authority(true), cert.DNSNames("dns.example"), cert.KeyUsage(x509.KeyUsageCertSign|x509.KeyUsageDigitalSignature))
+	certPEM, keyPEM := ca.ToPEM()
+	certificate, err := gotls.X509KeyPair(certPEM, keyPEM)
+	common.Must(err)
+	roots := x509.NewCertPool()
+	roots.AppendCertsFromPEM(certPEM)
+
+	listener, err := gotls.Listen("tcp", "127.0.0.1:0", &gotls.Config{
+		Certificates: []gotls.Certificate{certificate},
+		NextProtos:   []string{NextProtoDoT},
+	})
+	common.Must(err)
+	s := &dotStandIn{listener: listener}
+	t.Cleanup(func() {
+		listener.Close()
+		s.closeConns()
+	})
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			s.access.Lock()
+			s.conns = append(s.conns, conn)
+			s.access.Unlock()
+			go s.serve(conn)
+		}
+	}()
+	return s, roots
+}
+
+func (s *dotStandIn) connCount() int {
+	s.access.Lock()
+	defer s.access.Unlock()
+	return len(s.conns)
+}
+
+func (s *dotStandIn) closeConns() {
+	s.access.Lock()
+	defer s.access.Unlock()
+	for _, conn := range s.conns {
+		conn.Close()
+	}
+}
+
+func (s *dotStandIn) serve(conn gonet.Conn) {
+	for {
+		var queries []*dns.Msg
+		for len(queries) < 2 {
+			var length [2]byte
+			if _, err := io.ReadFull(conn, length[:]); err != nil {
+				return
+			}
+			b := make([]byte, binary.BigEndian.Uint16(length[:]))
+			if _, err := io.ReadFull(conn, b); err != nil {
+				return
+			}
+			q := new(dns.Msg)
+			common.Must(q.Unpack(b))
+			queries = append(queries, q)
+		}
+		for i := len(queries) - 1; i >= 0; i-- {
+			q := queries[i]
+			ans := new(dns.Msg)
+			ans.SetReply(q)
+			switch q.Question[0].Qtype {
+			case dns.TypeA:
+				rr, _ := dns.NewRR(q.Question[0].Name + " IN A 192.0.2.1")
+				ans.Answer = append(ans.Answer, rr)
+			case dns.TypeAAAA:
+				rr, _ := dns.NewRR(q.Question[0].Name + " IN AAAA 2001:db8::1")
+				ans.Answer = append(ans.Answer, rr)
+			}
+			b, err := ans.Pack()
+			common.Must(err)
+			frame := binary.BigEndian.AppendUint16(nil, uint16(len(b)))
+			if _, err := conn.Write(append(frame, b...)); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func TestTLSLocalNameServer(t *testing.T) {
+	standIn, roots := newDoTStandIn(t)
+	u, err := url.Parse("tls+local://" + standIn.listener.Addr().String())
+	common.Must(err)
+	s, err := NewTLSLocalNameServer(u, true, false, 0, net.IP(nil))
+	common.Must(err)
+	s.tlsConfig.ServerName = "dns.example"
+	s.tlsConfig.RootCAs = roots
+
+	query := func(domain string) {
+		t.Helper()
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+		defer cancel()
+		ips, _, err := s.QueryIP(ctx, domain, dns_feature.IPOption{
+			IPv4Enable: true,
+			IPv6Enable: true,
+		})
+		common.Must(err)
+		if len(ips) != 2 {
+			t.Fatalf("expected an IPv4 and an IPv6 address, got %v", ips)
+		}
+	}
+
+	query("example.com")
+	query("example.org")
+	if n := standIn.connCount(); n != 1 {
+		t.Fatalf("expected queries to share one connection, got %d", n)
+	}
+
+	// the server may close a connection at any time
+	standIn.closeConns()
+	query("example.net")
+	if n := standIn.connCount(); n != 2 {
+		t.Fatalf("expected a new connection, got %d", n)
+	}
+
+	// a removed nameserver doesn't keep its connection
+	conn := s.conn
+	common.Must(s.Close())
+	if !conn.isClosed() {
+		t.Fatal("connection kept open after close")
+	}
+}
+
+// standInDispatcher routes to the stand-in like an outbound would: the link
+// ends once the context of Dispatch is done.
+type standInDispatcher struct {
+	routing.Dispatcher
+	addr string
+}
+
+func (d *standInDispatcher) Dispatch(ctx context.Context, dest net.Destination) (*transport.Link, error) {
+	conn, err := gonet.Dial("tcp", d.addr)
+	if err != nil {
+		return nil, err
+	}
+	upReader, upWriter := pipe.New(pipe.WithoutSizeLimit())
+	downReader, downWriter := pipe.New(pipe.WithoutSizeLimit())
+	go func() {
+		<-ctx.Done()
+		conn.Close()
+	}()
+	go func() {
+		buf.Copy(upReader, buf.NewWriter(conn))
+		conn.Close()
+	}()
+	go func() {
+		buf.Copy(buf.NewReader(conn), downWriter)
+		downWriter.Close()
+	}()
+	return &transport.Link{Reader: downReader, Writer: upWriter}, nil
+}
+
+func TestTLSNameServerSharesDispatchedConnection(t *testing.T) {
+	standIn, roots := newDoTStandIn(t)
+	u, err := url.Parse("tls://127.0.0.1:853")
+	common.Must(err)
+	s, err := NewTLSNameServer(u, &standInDispatcher{addr: standIn.listener.Addr().String()}, true, false, 0, net.IP(nil))
+	common.Must(err)
+	s.tlsConfig.ServerName = "dns.example"
+	s.tlsConfig.RootCAs = roots
+	t.Cleanup(func() { s.Close() })
+
+	// the dial context is derived from the one of the query, with the instance
+	instanceCtx := context.WithValue(context.Background(), core.XrayKey(1), &core.Instance{})
+	for _, domain := range []string{"example.com", "example.org"} {
+		ctx, cancel := context.WithTimeout(instanceCtx, time.Second*5)
+		ips, _, err := s.QueryIP(ctx, domain, dns_feature.IPOption{
+			IPv4Enable: true,
+			IPv6Enable: true,
+		})
+		cancel()
+		common.Must(err)
+		if len(ips) != 2 {
+			t.Fatalf("expected an IPv4 and an IPv6 address, got %v", ips)
+		}
+	}
+	if n := standIn.connCount(); n != 1 {
+		t.Fatalf("expected queries to share one dispatched connection, got %d", n)
+	}
+}
+
+func TestTLSLocalNameServerUntrusted(t *testing.T) {
+	standIn, _ := newDoTStandIn(t)
+	u, err := url.Parse("tls+local://" + standIn.listener.Addr().String())
+	common.Must(err)
+	s, err := NewTLSLocalNameServer(u, true, false, 0, net.IP(nil))
+	common.Must(err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+	if _, _, err := s.QueryIP(ctx, "example.com", dns_feature.IPOption{IPv4Enable: true}); err == nil {
+		t.Fatal("expected a server with an untrusted certificate to fail")
+	}
+}
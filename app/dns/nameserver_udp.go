@@ -2,12 +2,14 @@ package dns
 
 import (
 	"context"
+	"encoding/binary"
 	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
 
 	"github.com/xtls/xray-core/common"
+	"github.com/xtls/xray-core/common/buf"
 	"github.com/xtls/xray-core/common/errors"
 	"github.com/xtls/xray-core/common/net"
 	"github.com/xtls/xray-core/common/protocol/dns"
@@ -34,6 +36,8 @@ type ClassicNameServer struct {
 type udpDnsRequest struct {
 	dnsRequest
 	ctx context.Context
+	// raw gets the response of exchangeRaw
+	raw chan<- []byte
 }
 
 // NewClassicNameServer creates udp server object for remote resolving.
@@ -95,8 +99,8 @@ func (s *ClassicNameServer) RequestsCleanup() error {
 // HandleResponse handles udp response packet from remote DNS server.
 func (s *ClassicNameServer) HandleResponse(ctx context.Context, packet *udp_proto.Packet) {
 	payload := packet.Payload
+	defer payload.Release()
 	ipRec, err := parseResponse(payload.Bytes())
-	payload.Release()
 	if err != nil {
 		errors.LogErrorInner(ctx, err, s.Name(), " fail to parse responded DNS udp")
 		return
@@ -115,6 +119,11 @@ func (s *ClassicNameServer) HandleResponse(ctx context.Context, packet *udp_prot
 		return
 	}
 
+	if req.raw != nil {
+		req.raw <- append([]byte(nil), payload.Bytes()...)
+		return
+	}
+
 	// if truncated, retry with EDNS0 option(udp payload size: 1350)
 	if ipRec.RawHeader.Truncated {
 		// if already has EDNS0 option, no need to retry
@@ -154,6 +163,11 @@ func (s *ClassicNameServer) addPendingRequest(req *udpDnsRequest) {
 	common.Must(s.requestsCleanup.Start())
 }
 
+// Close implements common.Closable. It stops the cleanup tasks.
+func (s *ClassicNameServer) Close() error {
+	return errors.Combine(s.requestsCleanup.Close(), s.cacheController.Close())
+}
+
 // getCacheController implements CachedNameserver.
 func (s *ClassicNameServer) getCacheController() *CacheController {
 	return s.cacheController
@@ -178,6 +192,37 @@ func (s *ClassicNameServer) sendQuery(ctx context.Context, _ chan<- error, fqdn
 	}
 }
 
+// exchangeRaw implements rawExchanger.
+func (s *ClassicNameServer) exchangeRaw(ctx context.Context, query []byte) ([]byte, error) {
+	id := s.newReqID()
+	binary.BigEndian.PutUint16(query, id)
+	raw := make(chan []byte, 1)
+	s.addPendingRequest(&udpDnsRequest{
+		dnsRequest: dnsRequest{msg: &dnsmessage.Message{Header: dnsmessage.Header{ID: id}}},
+		ctx:        ctx,
+		raw:        raw,
+	})
+
+	b := buf.New()
+	if _, err := b.Write(query); err != nil {
+		b.Release()
+		return nil, err
+	}
+	copyDest := net.UDPDestination(s.address.Address, s.address.Port)
+	b.UDP = &copyDest
+	s.udpServer.Dispatch(toDnsContext(ctx, s.address.String()), *s.address, b)
+
+	select {
+	case resp := <-raw:
+		return resp, nil
+	case <-ctx.Done():
+		s.Lock()
+		delete(s.requests, id)
+		s.Unlock()
+		return nil, ctx.Err()
+	}
+}
+
 // QueryIP implements Server.
 func (s *ClassicNameServer) QueryIP(ctx context.Context, domain string, option dns_feature.IPOption) ([]net.IP, uint32, error) {
 	return queryIP(ctx, s, domain, option)
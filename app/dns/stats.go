@@ -0,0 +1,167 @@
+package dns
+
+import (
+	"context"
+	go_errors "errors"
+	"sync/atomic"
+	"time"
+
+	"github.com/xtls/xray-core/features/dns"
+	"github.com/xtls/xray-core/features/stats"
+)
+
+// latencyBuckets are the upper bounds of the latency histogram of a
+// nameserver. Like a Prometheus histogram the buckets are cumulative: a query
+// counts into every bucket whose bound it doesn't exceed, and always into
+// "le_inf".
+var latencyBuckets = []time.Duration{
+	10 * time.Millisecond,
+	25 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	250 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+	2500 * time.Millisecond,
+}
+
+// statsCounterNames are the stats counters of a nameserver. The latency
+// counters follow in the order of latencyBuckets, then "le_inf", then the
+// sum in milliseconds.
+func statsCounterNames(name string) []string {
+	prefix := "dns>>>" + name + ">>>"
+	names := []string{
+		prefix + "queries",
+		prefix + "errors",
+		prefix + "cache_hits",
+	}
+	for _, bound := range latencyBuckets {
+		names = append(names, prefix+"latency>>>le_"+bound.String())
+	}
+	return append(names,
+		prefix+"latency>>>le_inf",
+		prefix+"latency>>>sum_ms",
+	)
+}
+
+// clientStats counts the queries of a Client. Counters are kept in stats if
+// the config has it, the latency average is kept anyway for parallel
+// queries.
+type clientStats struct {
+	queries   stats.Counter
+	errors    stats.Counter
+	cacheHits stats.Counter
+	// one per latency bucket and le_inf
+	buckets   []stats.Counter
+	latencyMs stats.Counter
+
+	// latency is the moving average of queries sent to the server, in
+	// nanoseconds, 0 until the first one.
+	latency atomic.Int64
+}
+
+func newClientStats(sm stats.Manager, name string) *clientStats {
+	s := &clientStats{}
+	if sm == nil {
+		return s
+	}
+	var counters []stats.Counter
+	for _, n := range statsCounterNames(name) {
+		c, err := stats.GetOrRegisterCounter(sm, n)
+		if err != nil {
+			// no "stats" in the config
+			return s
+		}
+		counters = append(counters, c)
+	}
+	s.queries, s.errors, s.cacheHits = counters[0], counters[1], counters[2]
+	s.buckets = counters[3 : len(counters)-1]
+	s.latencyMs = counters[len(counters)-1]
+	return s
+}
+
+// record counts a query that took elapsed. A failed query counts into the
+// latency average with timeout, so that a server which fails fast isn't
+// preferred.
+func (s *clientStats) record(elapsed time.Duration, cacheHit bool, err error, timeout time.Duration) {
+	if s == nil {
+		return
+	}
+	addCounter(s.queries, 1)
+	if err != nil {
+		addCounter(s.errors, 1)
+	}
+	if cacheHit {
+		addCounter(s.cacheHits, 1)
+		return
+	}
+
+	if s.buckets != nil {
+		for i, bound := range latencyBuckets {
+			if elapsed <= bound {
+				s.buckets[i].Add(1)
+			}
+		}
+		s.buckets[len(latencyBuckets)].Add(1)
+		s.latencyMs.Add(elapsed.Milliseconds())
+	}
+
+	sample := elapsed
+	if !isAnswer(err) && sample < timeout {
+		sample = timeout
+	}
+	for {
+		old := s.latency.Load()
+		avg := int64(sample)
+		if old != 0 {
+			// moves a quarter of the way to the new sample
+			avg = old + (int64(sample)-old)/4
+		}
+		if s.latency.CompareAndSwap(old, avg) {
+			return
+		}
+	}
+}
+
+// averageLatency returns the moving average latency of the server, 0 if it
+// wasn't queried yet.
+func (s *clientStats) averageLatency() time.Duration {
+	if s == nil {
+		return 0
+	}
+	return time.Duration(s.latency.Load())
+}
+
+func addCounter(c stats.Counter, delta int64) {
+	if c != nil {
+		c.Add(delta)
+	}
+}
+
+// isAnswer returns whether err still means the server answered.
+func isAnswer(err error) bool {
+	var rcode dns.RCodeError
+	return err == nil ||
+		go_errors.Is(err, dns.ErrEmptyResponse) ||
+		go_errors.Is(err, errRecordNotFound) ||
+		go_errors.As(err, &rcode)
+}
+
+type queryRecordKey struct{}
+
+// queryRecord carries back from a Server whether a query was answered from
+// its cache.
+type queryRecord struct {
+	cacheHit bool
+}
+
+func contextWithQueryRecord(ctx context.Context) (context.Context, *queryRecord) {
+	r := &queryRecord{}
+	return context.WithValue(ctx, queryRecordKey{}, r), r
+}
+
+func markCacheHit(ctx context.Context) {
+	if r, ok := ctx.Value(queryRecordKey{}).(*queryRecord); ok {
+		r.cacheHit = true
+	}
+}
@@ -0,0 +1,133 @@
+package dns
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/xtls/xray-core/app/stats"
+	"github.com/xtls/xray-core/common"
+	"github.com/xtls/xray-core/common/errors"
+	"github.com/xtls/xray-core/common/net"
+	"github.com/xtls/xray-core/common/strmatcher"
+	"github.com/xtls/xray-core/features/dns"
+)
+
+func TestClientStats(t *testing.T) {
+	m, err := stats.NewManager(context.Background(), &stats.Config{})
+	common.Must(err)
+	s := newClientStats(m, "ns")
+	value := func(name string) int64 {
+		t.Helper()
+		c := m.GetCounter("dns>>>ns>>>" + name)
+		if c == nil {
+			t.Fatalf("counter %s not registered", name)
+		}
+		return c.Value()
+	}
+
+	s.record(20*time.Millisecond, false, nil, time.Second)
+	s.record(0, true, nil, time.Second)
+	s.record(3*time.Second, false, dns.ErrEmptyResponse, 4*time.Second)
+	if v := value("queries"); v != 3 {
+		t.Errorf("queries = %d", v)
+	}
+	if v := value("errors"); v != 1 {
+		t.Errorf("errors = %d", v)
+	}
+	if v := value("cache_hits"); v != 1 {
+		t.Errorf("cache hits = %d", v)
+	}
+	if value("latency>>>le_10ms") != 0 || value("latency>>>le_25ms") != 1 || value("latency>>>le_2.5s") != 1 || value("latency>>>le_inf") != 2 {
+		t.Error("unexpected latency buckets")
+	}
+	if v := value("latency>>>sum_ms"); v != 3020 {
+		t.Errorf("latency sum = %d", v)
+	}
+	// 20ms, then a quarter of the way to 3s
+	if d := s.averageLatency(); d != 765*time.Millisecond {
+		t.Errorf("average latency = %v", d)
+	}
+
+	// no stats in the config: only the average is kept
+	s = newClientStats(nil, "ns")
+	s.record(10*time.Millisecond, false, errors.New("refused"), time.Second)
+	if d := s.averageLatency(); d != time.Second {
+		t.Errorf("a failure should count as the timeout, got %v", d)
+	}
+}
+
+func TestPreferFaster(t *testing.T) {
+	newClient := func(policyID uint32, latency time.Duration) *Client {
+		c := &Client{policyID: policyID, stats: &clientStats{}}
+		c.stats.latency.Store(int64(latency))
+		return c
+	}
+	slow := newClient(1, 300*time.Millisecond)
+	fast := newClient(1, 30*time.Millisecond)
+	unknown := newClient(1, 0)
+	other := newClient(2, time.Millisecond)
+
+	clients := []*Client{slow, fast, unknown, other}
+	groups, groupOf := makeGroups(clients)
+	held := preferFaster(clients, groups, groupOf)
+	defer held.release()
+	for i, expected := range []*Client{unknown, fast, slow, other} {
+		if clients[i] != expected {
+			t.Fatalf("unexpected order at %d", i)
+		}
+	}
+	for i, expected := range []time.Duration{0, 0, 60 * time.Millisecond, 0} {
+		if held.delays[i] != expected {
+			t.Errorf("client %d held back for %v", i, held.delays[i])
+		}
+	}
+}
+
+// answeringServer answers every query with ip after delay.
+type answeringServer struct {
+	name  string
+	ip    net.IP
+	delay time.Duration
+}
+
+func (s *answeringServer) Name() string { return s.name }
+
+func (s *answeringServer) IsDisableCache() bool { return true }
+
+func (s *answeringServer) QueryIP(ctx context.Context, domain string, option dns.IPOption) ([]net.IP, uint32, error) {
+	select {
+	case <-time.After(s.delay):
+		return []net.IP{s.ip}, 600, nil
+	case <-ctx.Done():
+		return nil, 0, ctx.Err()
+	}
+}
+
+func TestParallelQueryPrefersFaster(t *testing.T) {
+	newClient := func(server *answeringServer, latency time.Duration) *Client {
+		c := &Client{
+			server:    server,
+			timeoutMs: 4 * time.Second,
+			ipOption:  &dns.IPOption{IPv4Enable: true},
+			policyID:  1,
+			stats:     &clientStats{},
+		}
+		c.stats.latency.Store(int64(latency))
+		return c
+	}
+	// the slow server happens to answer first this time
+	slow := newClient(&answeringServer{name: "slow", ip: net.IP{10, 0, 0, 2}}, 500*time.Millisecond)
+	fast := newClient(&answeringServer{name: "fast", ip: net.IP{10, 0, 0, 1}, delay: 20 * time.Millisecond}, 50*time.Millisecond)
+
+	s := &DNS{
+		ctx:           context.Background(),
+		clients:       []*Client{slow, fast},
+		domainMatcher: &strmatcher.MatcherGroup{},
+	}
+	ips, _, err := s.parallelQuery("example.com", dns.IPOption{IPv4Enable: true})
+	common.Must(err)
+	if len(ips) != 1 || !ips[0].Equal(net.IP{10, 0, 0, 1}) {
+		t.Fatalf("expected the answer of the fast server, got %v", ips)
+	}
+}
@@ -26,18 +26,30 @@ type MetricsHandler struct {
 	tag          string
 	listen       string
 	tcpListener  net.Listener
+
+	ctx             context.Context
+	maxUserLabels   uint32
+	maxDeviceLabels uint32
 }
 
 // NewMetricsHandler creates a new MetricsHandler based on the given config.
 func NewMetricsHandler(ctx context.Context, config *Config) (*MetricsHandler, error) {
 	c := &MetricsHandler{
-		tag:    config.Tag,
-		listen: config.Listen,
+		tag:             config.Tag,
+		listen:          config.Listen,
+		ctx:             ctx,
+		maxUserLabels:   config.MaxUserLabels,
+		maxDeviceLabels: config.MaxDeviceLabels,
 	}
 	common.Must(core.RequireFeatures(ctx, func(om outbound.Manager, sm feature_stats.Manager) {
 		c.statsManager = sm
 		c.ohm = om
 	}))
+	// the observatory may be created after the metrics handler, or not be
+	// configured at all; either way it is known before the server starts
+	common.Must(core.OptionalFeatures(ctx, func(o extension.Observatory) {
+		c.observatory = o
+	}))
 	expvar.Publish("stats", expvar.Func(func() interface{} {
 		manager, ok := c.statsManager.(*stats.Manager)
 		if !ok {
@@ -64,13 +76,7 @@ func NewMetricsHandler(ctx context.Context, config *Config) (*MetricsHandler, er
 	}))
 	expvar.Publish("observatory", expvar.Func(func() interface{} {
 		if c.observatory == nil {
-			common.Must(core.RequireFeatures(ctx, func(observatory extension.Observatory) error {
-				c.observatory = observatory
-				return nil
-			}))
-			if c.observatory == nil {
-				return nil
-			}
+			return nil
 		}
 		resp := map[string]*observatory.OutboundStatus{}
 		if o, err := c.observatory.GetObservation(context.Background()); err != nil {
@@ -82,6 +88,7 @@ func NewMetricsHandler(ctx context.Context, config *Config) (*MetricsHandler, er
 		}
 		return resp
 	}))
+	registerPrometheus(c)
 	return c, nil
 }
 
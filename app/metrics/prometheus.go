@@ -0,0 +1,320 @@
+package metrics
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/xtls/xray-core/app/observatory"
+	"github.com/xtls/xray-core/app/ratelimit"
+	"github.com/xtls/xray-core/app/stats"
+	feature_stats "github.com/xtls/xray-core/features/stats"
+)
+
+// otherLabel replaces label values over a cardinality cap.
+const otherLabel = "_other"
+
+// promContentType is the Prometheus text exposition format.
+const promContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+var (
+	promHandler  atomic.Pointer[MetricsHandler]
+	registerProm sync.Once
+)
+
+// registerPrometheus serves /metrics on http.DefaultServeMux for the most
+// recently created MetricsHandler. The mux panics on duplicate patterns, so
+// the route is registered once and looks up the handler on every scrape.
+func registerPrometheus(p *MetricsHandler) {
+	promHandler.Store(p)
+	registerProm.Do(func() {
+		http.HandleFunc("/metrics", func(w http.ResponseWriter, r *http.Request) {
+			h := promHandler.Load()
+			if h == nil {
+				http.NotFound(w, r)
+				return
+			}
+			w.Header().Set("Content-Type", promContentType)
+			bw := bufio.NewWriter(w)
+			h.writePrometheus(bw)
+			bw.Flush()
+		})
+	})
+}
+
+// labelCap keeps the first max distinct values of a label and maps the rest
+// to otherLabel.
+type labelCap struct {
+	max  int
+	seen map[string]struct{}
+}
+
+func newLabelCap(max uint32) *labelCap {
+	return &labelCap{max: int(max), seen: make(map[string]struct{})}
+}
+
+func (c *labelCap) value(v string) string {
+	if c.max <= 0 {
+		return v
+	}
+	if _, ok := c.seen[v]; ok {
+		return v
+	}
+	if len(c.seen) >= c.max {
+		return otherLabel
+	}
+	c.seen[v] = struct{}{}
+	return v
+}
+
+// promFamily is one metric family; samples with equal labels are summed,
+// which is how capped label values are folded into otherLabel.
+type promFamily struct {
+	name, help, typ string
+
+	order   []string
+	samples map[string]float64
+}
+
+func newPromFamily(name, typ, help string) *promFamily {
+	return &promFamily{name: name, help: help, typ: typ, samples: make(map[string]float64)}
+}
+
+// add sums v into the sample with labels given as name/value pairs.
+func (f *promFamily) add(v float64, labels ...string) {
+	var sb strings.Builder
+	for i := 0; i+1 < len(labels); i += 2 {
+		if i > 0 {
+			sb.WriteByte(',')
+		}
+		sb.WriteString(labels[i])
+		sb.WriteString(`="`)
+		sb.WriteString(escapeLabelValue(labels[i+1]))
+		sb.WriteByte('"')
+	}
+	key := sb.String()
+	if _, ok := f.samples[key]; !ok {
+		f.order = append(f.order, key)
+	}
+	f.samples[key] += v
+}
+
+func (f *promFamily) write(w *bufio.Writer) {
+	if len(f.order) == 0 {
+		return
+	}
+	w.WriteString("# HELP " + f.name + " " + f.help + "\n")
+	w.WriteString("# TYPE " + f.name + " " + f.typ + "\n")
+	for _, key := range f.order {
+		w.WriteString(f.name)
+		if key != "" {
+			w.WriteString("{" + key + "}")
+		}
+		w.WriteByte(' ')
+		w.WriteString(strconv.FormatFloat(f.samples[key], 'g', -1, 64))
+		w.WriteByte('\n')
+	}
+}
+
+var labelValueEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escapeLabelValue(v string) string {
+	return labelValueEscaper.Replace(v)
+}
+
+func boolToFloat(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// writePrometheus renders stats counters, observatory results and ratelimit
+// data in the Prometheus text format.
+func (p *MetricsHandler) writePrometheus(w *bufio.Writer) {
+	users := newLabelCap(p.maxUserLabels)
+
+	var families []*promFamily
+	families = append(families, p.collectStats(users)...)
+	families = append(families, p.collectObservatory()...)
+	families = append(families, collectRateLimit(users, newLabelCap(p.maxDeviceLabels))...)
+
+	for _, f := range families {
+		f.write(w)
+	}
+}
+
+func (p *MetricsHandler) collectStats(users *labelCap) []*promFamily {
+	manager, ok := p.statsManager.(*stats.Manager)
+	if !ok {
+		return nil
+	}
+
+	var (
+		inbound  = newPromFamily("xray_inbound_traffic_bytes_total", "counter", "Traffic of an inbound.")
+		outbound = newPromFamily("xray_outbound_traffic_bytes_total", "counter", "Traffic of an outbound.")
+		user     = newPromFamily("xray_user_traffic_bytes_total", "counter", "Traffic of a user.")
+		rule     = newPromFamily("xray_rule_traffic_bytes_total", "counter", "Traffic routed by a rule.")
+		ruleHits = newPromFamily("xray_rule_hits_total", "counter", "Connections routed by a rule.")
+		other    = newPromFamily("xray_stats_counter", "counter", "Any other stats counter, by name.")
+	)
+
+	type counter struct {
+		name  string
+		value int64
+	}
+	var counters []counter
+	manager.VisitCounters(func(name string, c feature_stats.Counter) bool {
+		counters = append(counters, counter{name, c.Value()})
+		return true
+	})
+	// stable order keeps the same users under the cap between scrapes
+	sort.Slice(counters, func(i, j int) bool { return counters[i].name < counters[j].name })
+
+	for _, c := range counters {
+		parts := strings.Split(c.name, ">>>")
+		v := float64(c.value)
+		if len(parts) == 4 && parts[2] == "traffic" {
+			switch parts[0] {
+			case "inbound":
+				inbound.add(v, "inbound", parts[1], "direction", parts[3])
+				continue
+			case "outbound":
+				outbound.add(v, "outbound", parts[1], "direction", parts[3])
+				continue
+			case "user":
+				user.add(v, "user", users.value(parts[1]), "direction", parts[3])
+				continue
+			case "rule":
+				rule.add(v, "rule", parts[1], "direction", parts[3])
+				continue
+			}
+		}
+		if len(parts) == 3 && parts[0] == "rule" && parts[2] == "hits" {
+			ruleHits.add(v, "rule", parts[1])
+			continue
+		}
+		if len(parts) > 2 && parts[0] == "user" {
+			parts[1] = users.value(parts[1])
+			other.add(v, "name", strings.Join(parts, ">>>"))
+			continue
+		}
+		other.add(v, "name", c.name)
+	}
+
+	return []*promFamily{inbound, outbound, user, rule, ruleHits, other}
+}
+
+func (p *MetricsHandler) collectObservatory() []*promFamily {
+	o := p.observatory
+	if o == nil {
+		return nil
+	}
+	result, err := o.GetObservation(context.Background())
+	if err != nil {
+		return nil
+	}
+	res, ok := result.(*observatory.ObservationResult)
+	if !ok {
+		return nil
+	}
+
+	var (
+		alive    = newPromFamily("xray_observatory_alive", "gauge", "Whether the outbound passed the last probe.")
+		delay    = newPromFamily("xray_observatory_delay_milliseconds", "gauge", "Delay of the last probe of the outbound.")
+		lastSeen = newPromFamily("xray_observatory_last_seen_timestamp_seconds", "gauge", "Time the outbound was last seen alive.")
+		lastTry  = newPromFamily("xray_observatory_last_try_timestamp_seconds", "gauge", "Time the outbound was last probed.")
+	)
+	status := res.GetStatus()
+	sort.Slice(status, func(i, j int) bool { return status[i].OutboundTag < status[j].OutboundTag })
+	for _, s := range status {
+		alive.add(boolToFloat(s.Alive), "outbound", s.OutboundTag)
+		delay.add(float64(s.Delay), "outbound", s.OutboundTag)
+		lastSeen.add(float64(s.LastSeenTime), "outbound", s.OutboundTag)
+		lastTry.add(float64(s.LastTryTime), "outbound", s.OutboundTag)
+	}
+	return []*promFamily{alive, delay, lastSeen, lastTry}
+}
+
+func collectRateLimit(users, devices *labelCap) []*promFamily {
+	var (
+		activeDevices = newPromFamily("xray_ratelimit_active_devices", "gauge", "Devices of a user with at least one open connection.")
+		connections   = newPromFamily("xray_ratelimit_connections", "gauge", "Registered ratelimit connections of a user.")
+		limit         = newPromFamily("xray_ratelimit_limit_bits_per_second", "gauge", "Applied limit; 0 means unlimited.")
+		throttled     = newPromFamily("xray_ratelimit_throttled_seconds_total", "counter", "Time connections spent waiting for a limit.")
+		quotaUsed     = newPromFamily("xray_ratelimit_quota_used_bytes", "gauge", "Traffic counted against the quota in the current period.")
+		quotaLimit    = newPromFamily("xray_ratelimit_quota_limit_bytes", "gauge", "Traffic quota of a user.")
+		quotaExceeded = newPromFamily("xray_ratelimit_quota_exceeded", "gauge", "Whether the user has exhausted the quota.")
+		deviceLimit   = newPromFamily("xray_ratelimit_device_limit_events_total", "counter", "Times the device limit of a user fired, by outcome.")
+		deviceBytes   = newPromFamily("xray_ratelimit_device_traffic_bytes", "gauge", "Traffic of a device since its counters were last reset, by the outbound it is bound to.")
+	)
+
+	addRate := func(r *ratelimit.RateBps, labels ...string) {
+		if r == nil {
+			return
+		}
+		limit.add(float64(r.Up), append(labels, "direction", "uplink")...)
+		limit.add(float64(r.Down), append(labels, "direction", "downlink")...)
+	}
+
+	for _, um := range ratelimit.CollectUserMetrics() {
+		u := users.value(um.UUID)
+		activeDevices.add(float64(um.ActiveDevices), "user", u)
+		connections.add(float64(um.Connections), "user", u)
+		// a sum of limits means nothing, so capped users only count in totals
+		if u != otherLabel {
+			addRate(um.PerConn, "scope", "per_conn", "user", u, "inbound", "")
+			addRate(um.Total, "scope", "user_total", "user", u, "inbound", "")
+		}
+		throttled.add(um.ThrottledUp.Seconds(), "scope", "user", "user", u, "inbound", "", "direction", "uplink")
+		throttled.add(um.ThrottledDown.Seconds(), "scope", "user", "user", u, "inbound", "", "direction", "downlink")
+
+		if q := um.Quota; q != nil {
+			var used uint64
+			switch q.Quota.Direction {
+			case ratelimit.QuotaUp:
+				used = q.UpBytes
+			case ratelimit.QuotaDown:
+				used = q.DownBytes
+			default:
+				used = q.UpBytes + q.DownBytes
+			}
+			quotaUsed.add(float64(used), "user", u)
+			quotaLimit.add(float64(q.Quota.LimitBytes), "user", u)
+			quotaExceeded.add(boolToFloat(q.Exceeded), "user", u)
+		}
+
+		c := um.DeviceLimit
+		deviceLimit.add(float64(c.Rejected), "user", u, "outcome", "rejected")
+		deviceLimit.add(float64(c.Evicted), "user", u, "outcome", "evicted")
+		deviceLimit.add(float64(c.Exceeded), "user", u, "outcome", "exceeded")
+	}
+
+	for _, im := range ratelimit.CollectInboundMetrics() {
+		addRate(im.Total, "scope", "inbound_total", "user", "", "inbound", im.Tag)
+		throttled.add(im.ThrottledUp.Seconds(), "scope", "inbound", "user", "", "inbound", im.Tag, "direction", "uplink")
+		throttled.add(im.ThrottledDown.Seconds(), "scope", "inbound", "user", "", "inbound", im.Tag, "direction", "downlink")
+	}
+	if g, ok := ratelimit.Limits.GetGlobalTotal(); ok {
+		addRate(&g, "scope", "global_total", "user", "", "inbound", "")
+	}
+
+	list := ratelimit.ListDevicesAll()
+	sort.Slice(list, func(i, j int) bool { return list[i].DeviceKey < list[j].DeviceKey })
+	for _, d := range list {
+		u := users.value(d.UUID)
+		dev := devices.value(d.DeviceKey)
+		if u == otherLabel {
+			dev = otherLabel
+		}
+		deviceBytes.add(float64(d.RxBytes), "user", u, "device", dev, "outbound", d.EgressTag, "direction", "uplink")
+		deviceBytes.add(float64(d.TxBytes), "user", u, "device", dev, "outbound", d.EgressTag, "direction", "downlink")
+	}
+
+	return []*promFamily{activeDevices, connections, limit, throttled, quotaUsed, quotaLimit, quotaExceeded, deviceLimit, deviceBytes}
+}
@@ -0,0 +1,131 @@
+package metrics
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/xtls/xray-core/app/observatory"
+	"github.com/xtls/xray-core/app/ratelimit"
+	"github.com/xtls/xray-core/app/stats"
+	"github.com/xtls/xray-core/common"
+	"github.com/xtls/xray-core/features/extension"
+	"google.golang.org/protobuf/proto"
+)
+
+type staticObservatory struct {
+	result *observatory.ObservationResult
+}
+
+func (o *staticObservatory) Type() interface{} { return extension.ObservatoryType() }
+func (o *staticObservatory) Start() error      { return nil }
+func (o *staticObservatory) Close() error      { return nil }
+
+func (o *staticObservatory) GetObservation(ctx context.Context) (proto.Message, error) {
+	return o.result, nil
+}
+
+func TestPromFamilyFormat(t *testing.T) {
+	f := newPromFamily("xray_test_total", "counter", "Test counter.")
+	f.add(1, "user", `a"b\c`, "direction", "uplink")
+	f.add(2, "user", `a"b\c`, "direction", "uplink")
+	f.add(0.5, "user", "d", "direction", "downlink")
+
+	var out bytes.Buffer
+	w := bufio.NewWriter(&out)
+	f.write(w)
+	w.Flush()
+
+	expected := `# HELP xray_test_total Test counter.
+# TYPE xray_test_total counter
+xray_test_total{user="a\"b\\c",direction="uplink"} 3
+xray_test_total{user="d",direction="downlink"} 0.5
+`
+	if out.String() != expected {
+		t.Fatalf("unexpected output:\n%s", out.String())
+	}
+}
+
+func TestLabelCap(t *testing.T) {
+	c := newLabelCap(2)
+	for _, v := range []string{"a", "b", "a", "c", "b", "d"} {
+		got := c.value(v)
+		if (v == "c" || v == "d") != (got == otherLabel) {
+			t.Fatalf("value(%q) = %q", v, got)
+		}
+	}
+
+	if got := newLabelCap(0).value("x"); got != "x" {
+		t.Fatalf("expected no cap, got %q", got)
+	}
+}
+
+func TestWritePrometheusCapsUsers(t *testing.T) {
+	sm, err := stats.NewManager(nil, nil)
+	common.Must(err)
+	for _, name := range []string{
+		"user>>>u1>>>traffic>>>uplink",
+		"user>>>u2>>>traffic>>>uplink",
+		"user>>>u3>>>traffic>>>uplink",
+		"inbound>>>in>>>traffic>>>downlink",
+		"rule>>>r1>>>hits",
+		"rule>>>r1>>>traffic>>>uplink",
+	} {
+		c, err := sm.RegisterCounter(name)
+		common.Must(err)
+		c.Add(10)
+	}
+
+	ratelimit.Limits.SetUserDefault("prom-user", 8000, 4000)
+	t.Cleanup(func() { ratelimit.Limits.ClearUserDefault("prom-user") })
+
+	obs := &staticObservatory{result: &observatory.ObservationResult{
+		Status: []*observatory.OutboundStatus{{OutboundTag: "proxy", Alive: true, Delay: 42}},
+	}}
+	p := &MetricsHandler{statsManager: sm, observatory: obs, maxUserLabels: 2}
+	var out bytes.Buffer
+	w := bufio.NewWriter(&out)
+	p.writePrometheus(w)
+	w.Flush()
+	text := out.String()
+
+	for _, line := range []string{
+		`xray_user_traffic_bytes_total{user="u1",direction="uplink"} 10`,
+		`xray_user_traffic_bytes_total{user="u2",direction="uplink"} 10`,
+		`xray_user_traffic_bytes_total{user="_other",direction="uplink"} 10`,
+		`xray_inbound_traffic_bytes_total{inbound="in",direction="downlink"} 10`,
+		`xray_rule_hits_total{rule="r1"} 10`,
+		`xray_rule_traffic_bytes_total{rule="r1",direction="uplink"} 10`,
+		`xray_observatory_alive{outbound="proxy"} 1`,
+		`xray_observatory_delay_milliseconds{outbound="proxy"} 42`,
+	} {
+		if !strings.Contains(text, line+"\n") {
+			t.Fatalf("missing %q in:\n%s", line, text)
+		}
+	}
+	// limits of users over the cap are not summed
+	if strings.Contains(text, `xray_ratelimit_limit_bits_per_second{scope="per_conn",user="prom-user"`) {
+		t.Fatalf("expected capped user to be left out of limit gauges:\n%s", text)
+	}
+}
+
+func TestCollectRateLimitDeviceOutbound(t *testing.T) {
+	deviceKey := "prom-device|198.51.100.30"
+	ratelimit.DeviceStart(deviceKey, "prom-device")
+	ratelimit.DeviceSetEgress(deviceKey, "proxy")
+	t.Cleanup(func() { ratelimit.DeviceEnd(deviceKey) })
+
+	var out bytes.Buffer
+	w := bufio.NewWriter(&out)
+	for _, f := range collectRateLimit(newLabelCap(0), newLabelCap(0)) {
+		f.write(w)
+	}
+	w.Flush()
+
+	line := `xray_ratelimit_device_traffic_bytes{user="prom-device",device="prom-device|198.51.100.30",outbound="proxy",direction="uplink"} 0`
+	if !strings.Contains(out.String(), line+"\n") {
+		t.Fatalf("missing %q in:\n%s", line, out.String())
+	}
+}
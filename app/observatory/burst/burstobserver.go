@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"sync"
+	"time"
 
 	"github.com/xtls/xray-core/app/observatory"
 	"github.com/xtls/xray-core/common"
@@ -25,7 +26,8 @@ type Observer struct {
 
 	finished *done.Instance
 
-	ohm outbound.Manager
+	ohm     outbound.Manager
+	passive *observatory.PassiveTracker
 }
 
 func (o *Observer) GetObservation(ctx context.Context) (proto.Message, error) {
@@ -87,6 +89,28 @@ func (o *Observer) Close() error {
 	return nil
 }
 
+// ReportFailure implements extension.HealthReporter.
+func (o *Observer) ReportFailure(outbound string, err error) {
+	if !o.passive.Observes(outbound) {
+		return
+	}
+	errors.LogDebugInner(o.ctx, err, "the outbound ", outbound, " failed a connection")
+	if o.passive.Failure(outbound) {
+		o.hp.PutPassiveResult(outbound, rttFailed)
+	}
+}
+
+// ReportSuccess implements extension.HealthReporter.
+func (o *Observer) ReportSuccess(outbound string, delay time.Duration) {
+	if !o.passive.Observes(outbound) {
+		return
+	}
+	o.passive.Success(outbound)
+	if delay > 0 {
+		o.hp.PutPassiveResult(outbound, delay)
+	}
+}
+
 func New(ctx context.Context, config *Config) (*Observer, error) {
 	var outboundManager outbound.Manager
 	var dispatcher routing.Dispatcher
@@ -98,11 +122,18 @@ func New(ctx context.Context, config *Config) (*Observer, error) {
 		return nil, errors.New("Cannot get depended features").Base(err)
 	}
 	hp := NewHealthPing(ctx, dispatcher, config.PingConfig)
+	passive := observatory.NewPassiveTracker(config.SubjectSelector, config.Passive)
+	if passive != nil {
+		hp.skipProbe = func(tag string) bool {
+			return passive.SkipProbe(tag, hp.Settings.Interval)
+		}
+	}
 	return &Observer{
-		config: config,
-		ctx:    ctx,
-		ohm:    outboundManager,
-		hp:     hp,
+		config:  config,
+		ctx:     ctx,
+		ohm:     outboundManager,
+		hp:      hp,
+		passive: passive,
 	}, nil
 }
 
@@ -32,6 +32,11 @@ type HealthPing struct {
 
 	Settings *HealthPingSettings
 	Results  map[string]*HealthPingRTTS
+
+	// skipProbe, if set, leaves out pings of outbounds known to be working
+	skipProbe func(tag string) bool
+	// guarded by access
+	passiveAt map[string]time.Time
 }
 
 // NewHealthPing creates a new HealthPing with settings
@@ -173,6 +178,14 @@ func (h *HealthPing) doCheck(tags []string, duration time.Duration, rounds int)
 				delay = time.Duration(dice.RollInt63n(int64(duration)))
 			}
 			time.AfterFunc(delay, func() {
+				if h.skipProbe != nil && h.skipProbe(handler) {
+					errors.LogDebug(h.ctx, "skip checking ", handler, " with recent traffic")
+					ch <- &rtt{
+						handler: handler,
+						value:   0,
+					}
+					return
+				}
 				errors.LogDebug(h.ctx, "checking ", handler)
 				delay, err := client.MeasureDelay(h.Settings.HttpMethod)
 				if err == nil {
@@ -232,6 +245,24 @@ func (h *HealthPing) PutResult(tag string, rtt time.Duration) {
 	r.Put(rtt)
 }
 
+// PutPassiveResult puts the rtt of a real connection, rttFailed if it
+// failed. They count like pings but at most one per interval, so that
+// busy outbounds keep the results of pings too.
+func (h *HealthPing) PutPassiveResult(tag string, rtt time.Duration) {
+	h.access.Lock()
+	if h.passiveAt == nil {
+		h.passiveAt = make(map[string]time.Time)
+	}
+	now := time.Now()
+	if now.Sub(h.passiveAt[tag]) < h.Settings.Interval {
+		h.access.Unlock()
+		return
+	}
+	h.passiveAt[tag] = now
+	h.access.Unlock()
+	h.PutResult(tag, rtt)
+}
+
 // Cleanup removes results of removed handlers,
 // tags should be all valid tags of the Balancer now
 func (h *HealthPing) Cleanup(tags []string) {
@@ -247,6 +278,7 @@ func (h *HealthPing) Cleanup(tags []string) {
 		}
 		if !found {
 			delete(h.Results, tag)
+			delete(h.passiveAt, tag)
 		}
 	}
 }
@@ -34,10 +34,18 @@ type Observer struct {
 
 	ohm        outbound.Manager
 	dispatcher routing.Dispatcher
+	passive    *PassiveTracker
 }
 
 func (o *Observer) GetObservation(ctx context.Context) (proto.Message, error) {
-	return &ObservationResult{Status: o.status}, nil
+	// passive results update the status while balancers read it
+	o.statusLock.Lock()
+	defer o.statusLock.Unlock()
+	status := make([]*OutboundStatus, len(o.status))
+	for i, s := range o.status {
+		status[i] = proto.Clone(s).(*OutboundStatus)
+	}
+	return &ObservationResult{Status: status}, nil
 }
 
 func (o *Observer) Type() interface{} {
@@ -79,6 +87,10 @@ func (o *Observer) background() {
 		if !o.config.EnableConcurrency {
 			sort.Strings(outbounds)
 			for _, v := range outbounds {
+				if o.passive.SkipProbe(v, sleepTime*time.Duration(len(outbounds))) {
+					time.Sleep(sleepTime)
+					continue
+				}
 				result := o.probe(v)
 				o.updateStatusForResult(v, &result)
 				if o.finished.Done() {
@@ -92,6 +104,10 @@ func (o *Observer) background() {
 		ch := make(chan struct{}, len(outbounds))
 
 		for _, v := range outbounds {
+			if o.passive.SkipProbe(v, sleepTime) {
+				ch <- struct{}{}
+				continue
+			}
 			go func(v string) {
 				result := o.probe(v)
 				o.updateStatusForResult(v, &result)
@@ -230,6 +246,7 @@ func New(ctx context.Context, config *Config) (*Observer, error) {
 		ctx:        ctx,
 		ohm:        outboundManager,
 		dispatcher: dispatcher,
+		passive:    NewPassiveTracker(config.SubjectSelector, config.Passive),
 	}, nil
 }
 
@@ -238,3 +255,48 @@ func init() {
 		return New(ctx, config.(*Config))
 	}))
 }
+
+// ReportFailure implements extension.HealthReporter.
+func (o *Observer) ReportFailure(outbound string, err error) {
+	if !o.passive.Observes(outbound) {
+		return
+	}
+	errors.LogDebugInner(o.ctx, err, "the outbound ", outbound, " failed a connection")
+	if o.passive.Failure(outbound) {
+		errors.LogInfoInner(o.ctx, err, "the outbound ", outbound, " is dead: connections failed in a row")
+		o.updateStatusForResult(outbound, &ProbeResult{Alive: false, LastErrorReason: err.Error()})
+	}
+}
+
+// ReportSuccess implements extension.HealthReporter.
+func (o *Observer) ReportSuccess(outbound string, delay time.Duration) {
+	if !o.passive.Observes(outbound) {
+		return
+	}
+	o.passive.Success(outbound)
+	o.updateStatusForPassive(outbound, delay)
+}
+
+// updateStatusForPassive marks outbound alive. The first byte of a real
+// connection comes later than the answer to a probe, so the delay only
+// moves the known one a quarter of the way.
+func (o *Observer) updateStatusForPassive(outbound string, delay time.Duration) {
+	o.statusLock.Lock()
+	defer o.statusLock.Unlock()
+	var status *OutboundStatus
+	if location := o.findStatusLocationLockHolderOnly(outbound); location != -1 {
+		status = o.status[location]
+	} else {
+		status = &OutboundStatus{OutboundTag: outbound}
+		o.status = append(o.status, status)
+	}
+
+	if status.Alive && status.Delay > 0 {
+		status.Delay += (delay.Milliseconds() - status.Delay) / 4
+	} else {
+		status.Delay = delay.Milliseconds()
+	}
+	status.Alive = true
+	status.LastSeenTime = time.Now().Unix()
+	status.LastErrorReason = ""
+}
@@ -0,0 +1,96 @@
+package observatory
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+const defaultPassiveFailureThreshold = 3
+
+// PassiveTracker keeps what real connections tell about the observed
+// outbounds. One failed connection may be the fault of its destination, so
+// an outbound only counts as dead after several failures in a row.
+type PassiveTracker struct {
+	selectors []string
+	threshold int
+	skipProbe bool
+
+	access    sync.Mutex
+	outbounds map[string]*passiveState
+}
+
+type passiveState struct {
+	failures int
+	lastSeen time.Time
+}
+
+// NewPassiveTracker returns nil if config does not enable passive results.
+func NewPassiveTracker(selectors []string, config *PassiveConfig) *PassiveTracker {
+	if config == nil {
+		return nil
+	}
+	t := &PassiveTracker{
+		selectors: selectors,
+		threshold: int(config.FailureThreshold),
+		skipProbe: config.SkipProbe,
+		outbounds: make(map[string]*passiveState),
+	}
+	if t.threshold == 0 {
+		t.threshold = defaultPassiveFailureThreshold
+	}
+	return t
+}
+
+// Observes returns whether tag is matched by the subject selectors.
+func (t *PassiveTracker) Observes(tag string) bool {
+	if t == nil || tag == "" {
+		return false
+	}
+	for _, s := range t.selectors {
+		if strings.HasPrefix(tag, s) {
+			return true
+		}
+	}
+	return false
+}
+
+func (t *PassiveTracker) state(tag string) *passiveState {
+	s, found := t.outbounds[tag]
+	if !found {
+		s = new(passiveState)
+		t.outbounds[tag] = s
+	}
+	return s
+}
+
+// Failure records a failed connection and returns whether the outbound is
+// now to be marked dead.
+func (t *PassiveTracker) Failure(tag string) bool {
+	t.access.Lock()
+	defer t.access.Unlock()
+	s := t.state(tag)
+	s.failures++
+	return s.failures >= t.threshold
+}
+
+// Success records a connection that got data back.
+func (t *PassiveTracker) Success(tag string) {
+	t.access.Lock()
+	defer t.access.Unlock()
+	s := t.state(tag)
+	s.failures = 0
+	s.lastSeen = time.Now()
+}
+
+// SkipProbe returns whether the probe of tag can be left out because real
+// traffic got through it within the last period.
+func (t *PassiveTracker) SkipProbe(tag string, period time.Duration) bool {
+	if t == nil || !t.skipProbe {
+		return false
+	}
+	t.access.Lock()
+	defer t.access.Unlock()
+	s, found := t.outbounds[tag]
+	return found && time.Since(s.lastSeen) < period
+}
@@ -0,0 +1,51 @@
+package observatory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/xtls/xray-core/common/errors"
+)
+
+func TestObserverPassive(t *testing.T) {
+	o := &Observer{
+		ctx:     context.Background(),
+		passive: NewPassiveTracker([]string{"proxy-"}, &PassiveConfig{FailureThreshold: 2, SkipProbe: true}),
+	}
+	status := func(tag string) *OutboundStatus {
+		if location := o.findStatusLocationLockHolderOnly(tag); location != -1 {
+			return o.status[location]
+		}
+		return nil
+	}
+
+	o.ReportSuccess("direct", time.Second)
+	if status("direct") != nil {
+		t.Fatal("unexpected status of an outbound not observed")
+	}
+
+	o.updateStatusForResult("proxy-a", &ProbeResult{Alive: true, Delay: 100})
+	o.ReportSuccess("proxy-a", 500*time.Millisecond)
+	if s := status("proxy-a"); !s.Alive || s.Delay != 200 {
+		t.Fatalf("unexpected status after success: %+v", s)
+	}
+	if !o.passive.SkipProbe("proxy-a", time.Minute) || o.passive.SkipProbe("proxy-b", time.Minute) {
+		t.Fatal("expected only the outbound with traffic to skip its probe")
+	}
+
+	o.ReportFailure("proxy-a", errors.New("refused"))
+	if !status("proxy-a").Alive {
+		t.Fatal("one failure must not mark the outbound dead")
+	}
+	o.ReportFailure("proxy-a", errors.New("refused"))
+	if s := status("proxy-a"); s.Alive || s.LastErrorReason == "" {
+		t.Fatalf("unexpected status after failures: %+v", s)
+	}
+
+	o.ReportSuccess("proxy-a", 300*time.Millisecond)
+	o.ReportFailure("proxy-a", errors.New("refused"))
+	if s := status("proxy-a"); !s.Alive || s.Delay != 300 {
+		t.Fatalf("unexpected status after recovery: %+v", s)
+	}
+}
@@ -20,6 +20,7 @@ import (
 	"github.com/xtls/xray-core/common/serial"
 	"github.com/xtls/xray-core/common/session"
 	"github.com/xtls/xray-core/core"
+	"github.com/xtls/xray-core/features/extension"
 	"github.com/xtls/xray-core/features/outbound"
 	"github.com/xtls/xray-core/features/policy"
 	"github.com/xtls/xray-core/features/stats"
@@ -70,6 +71,7 @@ type Handler struct {
 	udp443          string
 	uplinkCounter   stats.Counter
 	downlinkCounter stats.Counter
+	health          extension.HealthReporter
 }
 
 // NewHandler creates a new Handler based on the given configuration.
@@ -82,6 +84,11 @@ func NewHandler(ctx context.Context, config *core.OutboundHandlerConfig) (outbou
 		uplinkCounter:   uplinkCounter,
 		downlinkCounter: downlinkCounter,
 	}
+	core.OptionalFeatures(ctx, func(o extension.Observatory) {
+		if r, ok := o.(extension.HealthReporter); ok {
+			h.health = r
+		}
+	})
 
 	if config.SenderSettings != nil {
 		senderSettings, err := config.SenderSettings.GetInstance()
@@ -239,6 +246,7 @@ func (h *Handler) Dispatch(ctx context.Context, link *transport.Link) {
 		}
 	}
 out:
+	link, health := h.monitorHealth(link, ob.Target.Network)
 	err := h.proxy.Process(ctx, link, h)
 	var errC error
 	if err != nil {
@@ -247,6 +255,7 @@ out:
 			err = nil
 		}
 	}
+	health.done(err)
 	if err != nil {
 		// Ensure outbound ray is properly closed.
 		err := errors.New("failed to process outbound traffic").Base(err)
@@ -314,6 +323,7 @@ func (h *Handler) Dial(ctx context.Context, dest net.Destination) (stat.Connecti
 	}
 
 	conn, err := internet.Dial(ctx, dest, h.streamSettings)
+	session.SubmitOutboundDialToOriginator(ctx, err)
 	conn = h.getStatCouterConnection(conn)
 	outbounds := session.OutboundsFromContext(ctx)
 	if outbounds != nil {
@@ -174,3 +174,39 @@ func TestTagsCache(t *testing.T) {
 	stop_get = true
 	wg_get.Wait()
 }
+
+func TestHandlerChangeNotification(t *testing.T) {
+	ohm, err := New(context.Background(), &proxyman.OutboundConfig{})
+	if err != nil {
+		t.Fatal("failed to create outbound handler manager")
+	}
+	v, _ := core.New(&core.Config{})
+	v.AddFeature(ohm)
+	ctx := context.WithValue(context.Background(), xrayKey, v)
+
+	var events []string
+	ohm.OnHandlerChange(func(tag string, added bool) {
+		// listeners run without the manager lock
+		events = append(events, fmt.Sprintf("%s %v %v", tag, added, ohm.Select([]string{"node"})))
+	})
+
+	for _, tag := range []string{"node1", "node2", ""} {
+		h, err := NewHandler(ctx, &core.OutboundHandlerConfig{
+			Tag:           tag,
+			ProxySettings: serial.ToTypedMessage(&freedom.Config{}),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := ohm.AddHandler(ctx, h); err != nil {
+			t.Fatal(err)
+		}
+	}
+	ohm.RemoveHandler(ctx, "node1")
+	ohm.RemoveHandler(ctx, "missing")
+
+	expected := []string{"node1 true [node1]", "node2 true [node1 node2]", "node1 false [node2]"}
+	if fmt.Sprint(events) != fmt.Sprint(expected) {
+		t.Fatalf("unexpected events: %v", events)
+	}
+}
@@ -0,0 +1,117 @@
+package outbound
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/xtls/xray-core/common"
+	"github.com/xtls/xray-core/common/buf"
+	"github.com/xtls/xray-core/common/errors"
+	"github.com/xtls/xray-core/common/net"
+	"github.com/xtls/xray-core/features/extension"
+	"github.com/xtls/xray-core/transport"
+)
+
+// healthMonitor tells the observatory how a connection through the handler
+// went: the delay to the first data back, or why none came.
+type healthMonitor struct {
+	reporter extension.HealthReporter
+	tag      string
+	network  net.Network
+	start    time.Time
+
+	sent     atomic.Bool
+	received atomic.Bool
+}
+
+func (h *Handler) monitorHealth(link *transport.Link, network net.Network) (*transport.Link, *healthMonitor) {
+	if h.health == nil || h.tag == "" {
+		return link, nil
+	}
+	m := &healthMonitor{
+		reporter: h.health,
+		tag:      h.tag,
+		network:  network,
+		start:    time.Now(),
+	}
+	hr := &healthReader{Reader: link.Reader, monitor: m}
+	var reader buf.Reader = hr
+	if tr, ok := link.Reader.(buf.TimeoutReader); ok {
+		reader = &healthTimeoutReader{healthReader: hr, timeoutReader: tr}
+	}
+	return &transport.Link{
+		Reader: reader,
+		Writer: &healthWriter{Writer: link.Writer, monitor: m},
+	}, m
+}
+
+// done reports a connection that got nothing back: a dial or handshake
+// failure if err is set, otherwise an empty response to a request.
+func (m *healthMonitor) done(err error) {
+	if m == nil || m.received.Load() {
+		return
+	}
+	if err == nil {
+		// silence is normal for UDP, and for TCP the client sent nothing
+		if m.network == net.Network_UDP || !m.sent.Load() {
+			return
+		}
+		err = errors.New("empty response")
+	}
+	m.reporter.ReportFailure(m.tag, err)
+}
+
+type healthReader struct {
+	Reader  buf.Reader
+	monitor *healthMonitor
+}
+
+func (r *healthReader) ReadMultiBuffer() (buf.MultiBuffer, error) {
+	mb, err := r.Reader.ReadMultiBuffer()
+	if !mb.IsEmpty() {
+		r.monitor.sent.Store(true)
+	}
+	return mb, err
+}
+
+func (r *healthReader) Interrupt() {
+	common.Interrupt(r.Reader)
+}
+
+func (r *healthReader) Close() error {
+	return common.Close(r.Reader)
+}
+
+type healthTimeoutReader struct {
+	*healthReader
+	timeoutReader buf.TimeoutReader
+}
+
+func (r *healthTimeoutReader) ReadMultiBufferTimeout(timeout time.Duration) (buf.MultiBuffer, error) {
+	mb, err := r.timeoutReader.ReadMultiBufferTimeout(timeout)
+	if !mb.IsEmpty() {
+		r.monitor.sent.Store(true)
+	}
+	return mb, err
+}
+
+type healthWriter struct {
+	Writer  buf.Writer
+	monitor *healthMonitor
+}
+
+func (w *healthWriter) WriteMultiBuffer(mb buf.MultiBuffer) error {
+	m := w.monitor
+	if !mb.IsEmpty() && m.received.CompareAndSwap(false, true) {
+		m.reporter.ReportSuccess(m.tag, time.Since(m.start))
+	}
+	return w.Writer.WriteMultiBuffer(mb)
+}
+
+func (w *healthWriter) Close() error {
+	return common.Close(w.Writer)
+}
+
+func (w *healthWriter) Interrupt() {
+	common.Interrupt(w.Writer)
+}
@@ -5,6 +5,7 @@ import (
 	"sort"
 	"strings"
 	"sync"
+	"sync/atomic"
 
 	"github.com/xtls/xray-core/app/proxyman"
 	"github.com/xtls/xray-core/common"
@@ -20,15 +21,16 @@ type Manager struct {
 	taggedHandler    map[string]outbound.Handler
 	untaggedHandlers []outbound.Handler
 	running          bool
-	tagsCache        *sync.Map
+	tagsCache        atomic.Pointer[sync.Map]
+	changeListeners  []func(tag string, added bool)
 }
 
 // New creates a new Manager.
 func New(ctx context.Context, config *proxyman.OutboundConfig) (*Manager, error) {
 	m := &Manager{
 		taggedHandler: make(map[string]outbound.Handler),
-		tagsCache:     &sync.Map{},
 	}
+	m.tagsCache.Store(&sync.Map{})
 	return m, nil
 }
 
@@ -101,10 +103,20 @@ func (m *Manager) GetHandler(tag string) outbound.Handler {
 
 // AddHandler implements outbound.Manager.
 func (m *Manager) AddHandler(ctx context.Context, handler outbound.Handler) error {
+	if err := m.addHandler(handler); err != nil {
+		return err
+	}
+	if tag := handler.Tag(); len(tag) > 0 {
+		m.notifyHandlerChange(tag, true)
+	}
+	return nil
+}
+
+func (m *Manager) addHandler(handler outbound.Handler) error {
 	m.access.Lock()
 	defer m.access.Unlock()
 
-	m.tagsCache = &sync.Map{}
+	m.tagsCache.Store(&sync.Map{})
 
 	if m.defaultHandler == nil {
 		m.defaultHandler = handler
@@ -133,18 +145,41 @@ func (m *Manager) RemoveHandler(ctx context.Context, tag string) error {
 		return common.ErrNoClue
 	}
 	m.access.Lock()
-	defer m.access.Unlock()
 
-	m.tagsCache = &sync.Map{}
+	m.tagsCache.Store(&sync.Map{})
 
+	_, found := m.taggedHandler[tag]
 	delete(m.taggedHandler, tag)
 	if m.defaultHandler != nil && m.defaultHandler.Tag() == tag {
 		m.defaultHandler = nil
 	}
+	m.access.Unlock()
 
+	if found {
+		m.notifyHandlerChange(tag, false)
+	}
 	return nil
 }
 
+// OnHandlerChange implements outbound.HandlerChangeNotifier.
+func (m *Manager) OnHandlerChange(f func(tag string, added bool)) {
+	m.access.Lock()
+	defer m.access.Unlock()
+	m.changeListeners = append(m.changeListeners, f)
+}
+
+// notifyHandlerChange runs the listeners without the lock, so they may call
+// back into the Manager, e.g. Select.
+func (m *Manager) notifyHandlerChange(tag string, added bool) {
+	m.access.RLock()
+	listeners := m.changeListeners
+	m.access.RUnlock()
+
+	for _, f := range listeners {
+		f(tag, added)
+	}
+}
+
 // ListHandlers implements outbound.Manager.
 func (m *Manager) ListHandlers(ctx context.Context) []outbound.Handler {
 	m.access.RLock()
@@ -164,7 +199,7 @@ func (m *Manager) ListHandlers(ctx context.Context) []outbound.Handler {
 func (m *Manager) Select(selectors []string) []string {
 
 	key := strings.Join(selectors, ",")
-	if cache, ok := m.tagsCache.Load(key); ok {
+	if cache, ok := m.tagsCache.Load().Load(key); ok {
 		return cache.([]string)
 	}
 
@@ -183,7 +218,7 @@ func (m *Manager) Select(selectors []string) []string {
 	}
 
 	sort.Strings(tags)
-	m.tagsCache.Store(key, tags)
+	m.tagsCache.Load().Store(key, tags)
 
 	return tags
 }
@@ -67,6 +67,368 @@ func (SetKeyModeRequest_Mode) EnumDescriptor() ([]byte, []int) {
 	return file_app_ratelimit_api_ratelimit_proto_rawDescGZIP(), []int{14, 0}
 }
 
+type Quota_Period int32
+
+const (
+	Quota_ABSOLUTE Quota_Period = 0
+	Quota_DAILY    Quota_Period = 1
+	Quota_MONTHLY  Quota_Period = 2
+)
+
+// Enum value maps for Quota_Period.
+var (
+	Quota_Period_name = map[int32]string{
+		0: "ABSOLUTE",
+		1: "DAILY",
+		2: "MONTHLY",
+	}
+	Quota_Period_value = map[string]int32{
+		"ABSOLUTE": 0,
+		"DAILY":    1,
+		"MONTHLY":  2,
+	}
+)
+
+func (x Quota_Period) Enum() *Quota_Period {
+	p := new(Quota_Period)
+	*p = x
+	return p
+}
+
+func (x Quota_Period) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Quota_Period) Descriptor() protoreflect.EnumDescriptor {
+	return file_app_ratelimit_api_ratelimit_proto_enumTypes[1].Descriptor()
+}
+
+func (Quota_Period) Type() protoreflect.EnumType {
+	return &file_app_ratelimit_api_ratelimit_proto_enumTypes[1]
+}
+
+func (x Quota_Period) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Quota_Period.Descriptor instead.
+func (Quota_Period) EnumDescriptor() ([]byte, []int) {
+	return file_app_ratelimit_api_ratelimit_proto_rawDescGZIP(), []int{53, 0}
+}
+
+type Quota_Direction int32
+
+const (
+	Quota_TOTAL Quota_Direction = 0
+	Quota_UP    Quota_Direction = 1
+	Quota_DOWN  Quota_Direction = 2
+)
+
+// Enum value maps for Quota_Direction.
+var (
+	Quota_Direction_name = map[int32]string{
+		0: "TOTAL",
+		1: "UP",
+		2: "DOWN",
+	}
+	Quota_Direction_value = map[string]int32{
+		"TOTAL": 0,
+		"UP":    1,
+		"DOWN":  2,
+	}
+)
+
+func (x Quota_Direction) Enum() *Quota_Direction {
+	p := new(Quota_Direction)
+	*p = x
+	return p
+}
+
+func (x Quota_Direction) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Quota_Direction) Descriptor() protoreflect.EnumDescriptor {
+	return file_app_ratelimit_api_ratelimit_proto_enumTypes[2].Descriptor()
+}
+
+func (Quota_Direction) Type() protoreflect.EnumType {
+	return &file_app_ratelimit_api_ratelimit_proto_enumTypes[2]
+}
+
+func (x Quota_Direction) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Quota_Direction.Descriptor instead.
+func (Quota_Direction) EnumDescriptor() ([]byte, []int) {
+	return file_app_ratelimit_api_ratelimit_proto_rawDescGZIP(), []int{53, 1}
+}
+
+// BLOCK: новые соединения отклоняются; CLOSE: ещё и активные закрываются;
+// PENALTY: пользователь режется до penalty_*_bps.
+type Quota_Action int32
+
+const (
+	Quota_BLOCK   Quota_Action = 0
+	Quota_CLOSE   Quota_Action = 1
+	Quota_PENALTY Quota_Action = 2
+)
+
+// Enum value maps for Quota_Action.
+var (
+	Quota_Action_name = map[int32]string{
+		0: "BLOCK",
+		1: "CLOSE",
+		2: "PENALTY",
+	}
+	Quota_Action_value = map[string]int32{
+		"BLOCK":   0,
+		"CLOSE":   1,
+		"PENALTY": 2,
+	}
+)
+
+func (x Quota_Action) Enum() *Quota_Action {
+	p := new(Quota_Action)
+	*p = x
+	return p
+}
+
+func (x Quota_Action) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Quota_Action) Descriptor() protoreflect.EnumDescriptor {
+	return file_app_ratelimit_api_ratelimit_proto_enumTypes[3].Descriptor()
+}
+
+func (Quota_Action) Type() protoreflect.EnumType {
+	return &file_app_ratelimit_api_ratelimit_proto_enumTypes[3]
+}
+
+func (x Quota_Action) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Quota_Action.Descriptor instead.
+func (Quota_Action) EnumDescriptor() ([]byte, []int) {
+	return file_app_ratelimit_api_ratelimit_proto_rawDescGZIP(), []int{53, 2}
+}
+
+// REJECT_NEWEST: новое устройство не пускается; EVICT_OLDEST: закрываются
+// соединения самого старого устройства; LOG_ONLY: только лог и счётчик.
+type MaxDevices_Policy int32
+
+const (
+	MaxDevices_REJECT_NEWEST MaxDevices_Policy = 0
+	MaxDevices_EVICT_OLDEST  MaxDevices_Policy = 1
+	MaxDevices_LOG_ONLY      MaxDevices_Policy = 2
+)
+
+// Enum value maps for MaxDevices_Policy.
+var (
+	MaxDevices_Policy_name = map[int32]string{
+		0: "REJECT_NEWEST",
+		1: "EVICT_OLDEST",
+		2: "LOG_ONLY",
+	}
+	MaxDevices_Policy_value = map[string]int32{
+		"REJECT_NEWEST": 0,
+		"EVICT_OLDEST":  1,
+		"LOG_ONLY":      2,
+	}
+)
+
+func (x MaxDevices_Policy) Enum() *MaxDevices_Policy {
+	p := new(MaxDevices_Policy)
+	*p = x
+	return p
+}
+
+func (x MaxDevices_Policy) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (MaxDevices_Policy) Descriptor() protoreflect.EnumDescriptor {
+	return file_app_ratelimit_api_ratelimit_proto_enumTypes[4].Descriptor()
+}
+
+func (MaxDevices_Policy) Type() protoreflect.EnumType {
+	return &file_app_ratelimit_api_ratelimit_proto_enumTypes[4]
+}
+
+func (x MaxDevices_Policy) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use MaxDevices_Policy.Descriptor instead.
+func (MaxDevices_Policy) EnumDescriptor() ([]byte, []int) {
+	return file_app_ratelimit_api_ratelimit_proto_rawDescGZIP(), []int{62, 0}
+}
+
+type DeviceEvent_Type int32
+
+const (
+	DeviceEvent_STARTED        DeviceEvent_Type = 0 // новое устройство
+	DeviceEvent_GRACE          DeviceEvent_Type = 1 // закрылось последнее соединение, устройство в grace
+	DeviceEvent_RESUMED        DeviceEvent_Type = 2 // вернулось из grace
+	DeviceEvent_DESTROYED      DeviceEvent_Type = 3 // удалено
+	DeviceEvent_EVICTED        DeviceEvent_Type = 4 // выкинуто лимитом устройств
+	DeviceEvent_LIMIT_CHANGED  DeviceEvent_Type = 5
+	DeviceEvent_EGRESS_CHANGED DeviceEvent_Type = 6
+)
+
+// Enum value maps for DeviceEvent_Type.
+var (
+	DeviceEvent_Type_name = map[int32]string{
+		0: "STARTED",
+		1: "GRACE",
+		2: "RESUMED",
+		3: "DESTROYED",
+		4: "EVICTED",
+		5: "LIMIT_CHANGED",
+		6: "EGRESS_CHANGED",
+	}
+	DeviceEvent_Type_value = map[string]int32{
+		"STARTED":        0,
+		"GRACE":          1,
+		"RESUMED":        2,
+		"DESTROYED":      3,
+		"EVICTED":        4,
+		"LIMIT_CHANGED":  5,
+		"EGRESS_CHANGED": 6,
+	}
+)
+
+func (x DeviceEvent_Type) Enum() *DeviceEvent_Type {
+	p := new(DeviceEvent_Type)
+	*p = x
+	return p
+}
+
+func (x DeviceEvent_Type) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (DeviceEvent_Type) Descriptor() protoreflect.EnumDescriptor {
+	return file_app_ratelimit_api_ratelimit_proto_enumTypes[5].Descriptor()
+}
+
+func (DeviceEvent_Type) Type() protoreflect.EnumType {
+	return &file_app_ratelimit_api_ratelimit_proto_enumTypes[5]
+}
+
+func (x DeviceEvent_Type) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use DeviceEvent_Type.Descriptor instead.
+func (DeviceEvent_Type) EnumDescriptor() ([]byte, []int) {
+	return file_app_ratelimit_api_ratelimit_proto_rawDescGZIP(), []int{70, 0}
+}
+
+type DeviceEvent_LimitScope int32
+
+const (
+	DeviceEvent_DEVICE       DeviceEvent_LimitScope = 0
+	DeviceEvent_USER_DEFAULT DeviceEvent_LimitScope = 1 // device_key пуст
+	DeviceEvent_USER_TOTAL   DeviceEvent_LimitScope = 2 // device_key пуст
+)
+
+// Enum value maps for DeviceEvent_LimitScope.
+var (
+	DeviceEvent_LimitScope_name = map[int32]string{
+		0: "DEVICE",
+		1: "USER_DEFAULT",
+		2: "USER_TOTAL",
+	}
+	DeviceEvent_LimitScope_value = map[string]int32{
+		"DEVICE":       0,
+		"USER_DEFAULT": 1,
+		"USER_TOTAL":   2,
+	}
+)
+
+func (x DeviceEvent_LimitScope) Enum() *DeviceEvent_LimitScope {
+	p := new(DeviceEvent_LimitScope)
+	*p = x
+	return p
+}
+
+func (x DeviceEvent_LimitScope) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (DeviceEvent_LimitScope) Descriptor() protoreflect.EnumDescriptor {
+	return file_app_ratelimit_api_ratelimit_proto_enumTypes[6].Descriptor()
+}
+
+func (DeviceEvent_LimitScope) Type() protoreflect.EnumType {
+	return &file_app_ratelimit_api_ratelimit_proto_enumTypes[6]
+}
+
+func (x DeviceEvent_LimitScope) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use DeviceEvent_LimitScope.Descriptor instead.
+func (DeviceEvent_LimitScope) EnumDescriptor() ([]byte, []int) {
+	return file_app_ratelimit_api_ratelimit_proto_rawDescGZIP(), []int{70, 1}
+}
+
+type ListEgressBindingsResponse_Strategy int32
+
+const (
+	ListEgressBindingsResponse_MAP_ONLY      ListEgressBindingsResponse_Strategy = 0
+	ListEgressBindingsResponse_HASH          ListEgressBindingsResponse_Strategy = 1
+	ListEgressBindingsResponse_LEAST_DEVICES ListEgressBindingsResponse_Strategy = 2
+	ListEgressBindingsResponse_RANDOM        ListEgressBindingsResponse_Strategy = 3
+)
+
+// Enum value maps for ListEgressBindingsResponse_Strategy.
+var (
+	ListEgressBindingsResponse_Strategy_name = map[int32]string{
+		0: "MAP_ONLY",
+		1: "HASH",
+		2: "LEAST_DEVICES",
+		3: "RANDOM",
+	}
+	ListEgressBindingsResponse_Strategy_value = map[string]int32{
+		"MAP_ONLY":      0,
+		"HASH":          1,
+		"LEAST_DEVICES": 2,
+		"RANDOM":        3,
+	}
+)
+
+func (x ListEgressBindingsResponse_Strategy) Enum() *ListEgressBindingsResponse_Strategy {
+	p := new(ListEgressBindingsResponse_Strategy)
+	*p = x
+	return p
+}
+
+func (x ListEgressBindingsResponse_Strategy) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ListEgressBindingsResponse_Strategy) Descriptor() protoreflect.EnumDescriptor {
+	return file_app_ratelimit_api_ratelimit_proto_enumTypes[7].Descriptor()
+}
+
+func (ListEgressBindingsResponse_Strategy) Type() protoreflect.EnumType {
+	return &file_app_ratelimit_api_ratelimit_proto_enumTypes[7]
+}
+
+func (x ListEgressBindingsResponse_Strategy) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ListEgressBindingsResponse_Strategy.Descriptor instead.
+func (ListEgressBindingsResponse_Strategy) EnumDescriptor() ([]byte, []int) {
+	return file_app_ratelimit_api_ratelimit_proto_rawDescGZIP(), []int{95, 0}
+}
+
 type ClearAllRateLimitsRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	unknownFields protoimpl.UnknownFields
@@ -1016,12 +1378,21 @@ func (x *SetUserTotalLimitRequest) GetUpBps() uint64 {
 }
 
 type SetUserTotalLimitResponse struct {
-	state            protoimpl.MessageState `protogen:"open.v1"`
-	DeviceCount      uint32                 `protobuf:"varint,1,opt,name=device_count,json=deviceCount,proto3" json:"device_count,omitempty"` // сколько устройств было найдено
-	PerDeviceDownBps uint64                 `protobuf:"varint,2,opt,name=per_device_down_bps,json=perDeviceDownBps,proto3" json:"per_device_down_bps,omitempty"`
-	PerDeviceUpBps   uint64                 `protobuf:"varint,3,opt,name=per_device_up_bps,json=perDeviceUpBps,proto3" json:"per_device_up_bps,omitempty"`
-	unknownFields    protoimpl.UnknownFields
-	sizeCache        protoimpl.SizeCache
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	DeviceCount uint32                 `protobuf:"varint,1,opt,name=device_count,json=deviceCount,proto3" json:"device_count,omitempty"` // сколько устройств было найдено
+	// справедливая доля на устройство, если все они активны одновременно (справочно).
+	// Устарело: устройства делят total_down_bps/total_up_bps.
+	//
+	// Deprecated: Marked as deprecated in app/ratelimit/api/ratelimit.proto.
+	PerDeviceDownBps uint64 `protobuf:"varint,2,opt,name=per_device_down_bps,json=perDeviceDownBps,proto3" json:"per_device_down_bps,omitempty"`
+	// Deprecated: Marked as deprecated in app/ratelimit/api/ratelimit.proto.
+	PerDeviceUpBps uint64 `protobuf:"varint,3,opt,name=per_device_up_bps,json=perDeviceUpBps,proto3" json:"per_device_up_bps,omitempty"`
+	// действующий общий лимит, который делят все устройства пользователя
+	// (расписание может его перекрывать)
+	TotalDownBps  uint64 `protobuf:"varint,4,opt,name=total_down_bps,json=totalDownBps,proto3" json:"total_down_bps,omitempty"`
+	TotalUpBps    uint64 `protobuf:"varint,5,opt,name=total_up_bps,json=totalUpBps,proto3" json:"total_up_bps,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *SetUserTotalLimitResponse) Reset() {
@@ -1061,6 +1432,7 @@ func (x *SetUserTotalLimitResponse) GetDeviceCount() uint32 {
 	return 0
 }
 
+// Deprecated: Marked as deprecated in app/ratelimit/api/ratelimit.proto.
 func (x *SetUserTotalLimitResponse) GetPerDeviceDownBps() uint64 {
 	if x != nil {
 		return x.PerDeviceDownBps
@@ -1068,6 +1440,7 @@ func (x *SetUserTotalLimitResponse) GetPerDeviceDownBps() uint64 {
 	return 0
 }
 
+// Deprecated: Marked as deprecated in app/ratelimit/api/ratelimit.proto.
 func (x *SetUserTotalLimitResponse) GetPerDeviceUpBps() uint64 {
 	if x != nil {
 		return x.PerDeviceUpBps
@@ -1075,31 +1448,41 @@ func (x *SetUserTotalLimitResponse) GetPerDeviceUpBps() uint64 {
 	return 0
 }
 
-type ConnectionInfo struct {
+func (x *SetUserTotalLimitResponse) GetTotalDownBps() uint64 {
+	if x != nil {
+		return x.TotalDownBps
+	}
+	return 0
+}
+
+func (x *SetUserTotalLimitResponse) GetTotalUpBps() uint64 {
+	if x != nil {
+		return x.TotalUpBps
+	}
+	return 0
+}
+
+type ClearUserTotalLimitRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	ConnId        uint64                 `protobuf:"varint,1,opt,name=conn_id,json=connId,proto3" json:"conn_id,omitempty"`
-	StartedAtUnix uint64                 `protobuf:"varint,2,opt,name=started_at_unix,json=startedAtUnix,proto3" json:"started_at_unix,omitempty"`
-	LastSeenUnix  uint64                 `protobuf:"varint,3,opt,name=last_seen_unix,json=lastSeenUnix,proto3" json:"last_seen_unix,omitempty"`
-	RxBytes       uint64                 `protobuf:"varint,4,opt,name=rx_bytes,json=rxBytes,proto3" json:"rx_bytes,omitempty"`
-	TxBytes       uint64                 `protobuf:"varint,5,opt,name=tx_bytes,json=txBytes,proto3" json:"tx_bytes,omitempty"`
+	Uuid          string                 `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ConnectionInfo) Reset() {
-	*x = ConnectionInfo{}
+func (x *ClearUserTotalLimitRequest) Reset() {
+	*x = ClearUserTotalLimitRequest{}
 	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[22]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ConnectionInfo) String() string {
+func (x *ClearUserTotalLimitRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ConnectionInfo) ProtoMessage() {}
+func (*ClearUserTotalLimitRequest) ProtoMessage() {}
 
-func (x *ConnectionInfo) ProtoReflect() protoreflect.Message {
+func (x *ClearUserTotalLimitRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[22]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1111,69 +1494,39 @@ func (x *ConnectionInfo) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ConnectionInfo.ProtoReflect.Descriptor instead.
-func (*ConnectionInfo) Descriptor() ([]byte, []int) {
+// Deprecated: Use ClearUserTotalLimitRequest.ProtoReflect.Descriptor instead.
+func (*ClearUserTotalLimitRequest) Descriptor() ([]byte, []int) {
 	return file_app_ratelimit_api_ratelimit_proto_rawDescGZIP(), []int{22}
 }
 
-func (x *ConnectionInfo) GetConnId() uint64 {
-	if x != nil {
-		return x.ConnId
-	}
-	return 0
-}
-
-func (x *ConnectionInfo) GetStartedAtUnix() uint64 {
-	if x != nil {
-		return x.StartedAtUnix
-	}
-	return 0
-}
-
-func (x *ConnectionInfo) GetLastSeenUnix() uint64 {
-	if x != nil {
-		return x.LastSeenUnix
-	}
-	return 0
-}
-
-func (x *ConnectionInfo) GetRxBytes() uint64 {
-	if x != nil {
-		return x.RxBytes
-	}
-	return 0
-}
-
-func (x *ConnectionInfo) GetTxBytes() uint64 {
+func (x *ClearUserTotalLimitRequest) GetUuid() string {
 	if x != nil {
-		return x.TxBytes
+		return x.Uuid
 	}
-	return 0
+	return ""
 }
 
-type SetUserDefaultPerConnLimitRequest struct {
+type ClearUserTotalLimitResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Uuid          string                 `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"`
-	DownBps       uint64                 `protobuf:"varint,2,opt,name=down_bps,json=downBps,proto3" json:"down_bps,omitempty"`
-	UpBps         uint64                 `protobuf:"varint,3,opt,name=up_bps,json=upBps,proto3" json:"up_bps,omitempty"`
+	Cleared       bool                   `protobuf:"varint,1,opt,name=cleared,proto3" json:"cleared,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *SetUserDefaultPerConnLimitRequest) Reset() {
-	*x = SetUserDefaultPerConnLimitRequest{}
+func (x *ClearUserTotalLimitResponse) Reset() {
+	*x = ClearUserTotalLimitResponse{}
 	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[23]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *SetUserDefaultPerConnLimitRequest) String() string {
+func (x *ClearUserTotalLimitResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SetUserDefaultPerConnLimitRequest) ProtoMessage() {}
+func (*ClearUserTotalLimitResponse) ProtoMessage() {}
 
-func (x *SetUserDefaultPerConnLimitRequest) ProtoReflect() protoreflect.Message {
+func (x *ClearUserTotalLimitResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[23]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1185,53 +1538,2729 @@ func (x *SetUserDefaultPerConnLimitRequest) ProtoReflect() protoreflect.Message
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SetUserDefaultPerConnLimitRequest.ProtoReflect.Descriptor instead.
-func (*SetUserDefaultPerConnLimitRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use ClearUserTotalLimitResponse.ProtoReflect.Descriptor instead.
+func (*ClearUserTotalLimitResponse) Descriptor() ([]byte, []int) {
 	return file_app_ratelimit_api_ratelimit_proto_rawDescGZIP(), []int{23}
 }
 
-func (x *SetUserDefaultPerConnLimitRequest) GetUuid() string {
+func (x *ClearUserTotalLimitResponse) GetCleared() bool {
 	if x != nil {
-		return x.Uuid
+		return x.Cleared
 	}
-	return ""
+	return false
 }
 
-func (x *SetUserDefaultPerConnLimitRequest) GetDownBps() uint64 {
-	if x != nil {
-		return x.DownBps
-	}
+type SetInboundTotalLimitRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	InboundTag    string                 `protobuf:"bytes,1,opt,name=inbound_tag,json=inboundTag,proto3" json:"inbound_tag,omitempty"`
+	DownBps       uint64                 `protobuf:"varint,2,opt,name=down_bps,json=downBps,proto3" json:"down_bps,omitempty"`
+	UpBps         uint64                 `protobuf:"varint,3,opt,name=up_bps,json=upBps,proto3" json:"up_bps,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetInboundTotalLimitRequest) Reset() {
+	*x = SetInboundTotalLimitRequest{}
+	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetInboundTotalLimitRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetInboundTotalLimitRequest) ProtoMessage() {}
+
+func (x *SetInboundTotalLimitRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetInboundTotalLimitRequest.ProtoReflect.Descriptor instead.
+func (*SetInboundTotalLimitRequest) Descriptor() ([]byte, []int) {
+	return file_app_ratelimit_api_ratelimit_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *SetInboundTotalLimitRequest) GetInboundTag() string {
+	if x != nil {
+		return x.InboundTag
+	}
+	return ""
+}
+
+func (x *SetInboundTotalLimitRequest) GetDownBps() uint64 {
+	if x != nil {
+		return x.DownBps
+	}
+	return 0
+}
+
+func (x *SetInboundTotalLimitRequest) GetUpBps() uint64 {
+	if x != nil {
+		return x.UpBps
+	}
+	return 0
+}
+
+type SetInboundTotalLimitResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetInboundTotalLimitResponse) Reset() {
+	*x = SetInboundTotalLimitResponse{}
+	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetInboundTotalLimitResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetInboundTotalLimitResponse) ProtoMessage() {}
+
+func (x *SetInboundTotalLimitResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetInboundTotalLimitResponse.ProtoReflect.Descriptor instead.
+func (*SetInboundTotalLimitResponse) Descriptor() ([]byte, []int) {
+	return file_app_ratelimit_api_ratelimit_proto_rawDescGZIP(), []int{25}
+}
+
+type ClearInboundTotalLimitRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	InboundTag    string                 `protobuf:"bytes,1,opt,name=inbound_tag,json=inboundTag,proto3" json:"inbound_tag,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ClearInboundTotalLimitRequest) Reset() {
+	*x = ClearInboundTotalLimitRequest{}
+	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ClearInboundTotalLimitRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ClearInboundTotalLimitRequest) ProtoMessage() {}
+
+func (x *ClearInboundTotalLimitRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ClearInboundTotalLimitRequest.ProtoReflect.Descriptor instead.
+func (*ClearInboundTotalLimitRequest) Descriptor() ([]byte, []int) {
+	return file_app_ratelimit_api_ratelimit_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *ClearInboundTotalLimitRequest) GetInboundTag() string {
+	if x != nil {
+		return x.InboundTag
+	}
+	return ""
+}
+
+type ClearInboundTotalLimitResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Cleared       bool                   `protobuf:"varint,1,opt,name=cleared,proto3" json:"cleared,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ClearInboundTotalLimitResponse) Reset() {
+	*x = ClearInboundTotalLimitResponse{}
+	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ClearInboundTotalLimitResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ClearInboundTotalLimitResponse) ProtoMessage() {}
+
+func (x *ClearInboundTotalLimitResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ClearInboundTotalLimitResponse.ProtoReflect.Descriptor instead.
+func (*ClearInboundTotalLimitResponse) Descriptor() ([]byte, []int) {
+	return file_app_ratelimit_api_ratelimit_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *ClearInboundTotalLimitResponse) GetCleared() bool {
+	if x != nil {
+		return x.Cleared
+	}
+	return false
+}
+
+type SetGlobalTotalLimitRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DownBps       uint64                 `protobuf:"varint,1,opt,name=down_bps,json=downBps,proto3" json:"down_bps,omitempty"`
+	UpBps         uint64                 `protobuf:"varint,2,opt,name=up_bps,json=upBps,proto3" json:"up_bps,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetGlobalTotalLimitRequest) Reset() {
+	*x = SetGlobalTotalLimitRequest{}
+	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetGlobalTotalLimitRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetGlobalTotalLimitRequest) ProtoMessage() {}
+
+func (x *SetGlobalTotalLimitRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetGlobalTotalLimitRequest.ProtoReflect.Descriptor instead.
+func (*SetGlobalTotalLimitRequest) Descriptor() ([]byte, []int) {
+	return file_app_ratelimit_api_ratelimit_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *SetGlobalTotalLimitRequest) GetDownBps() uint64 {
+	if x != nil {
+		return x.DownBps
+	}
+	return 0
+}
+
+func (x *SetGlobalTotalLimitRequest) GetUpBps() uint64 {
+	if x != nil {
+		return x.UpBps
+	}
+	return 0
+}
+
+type SetGlobalTotalLimitResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetGlobalTotalLimitResponse) Reset() {
+	*x = SetGlobalTotalLimitResponse{}
+	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetGlobalTotalLimitResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetGlobalTotalLimitResponse) ProtoMessage() {}
+
+func (x *SetGlobalTotalLimitResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetGlobalTotalLimitResponse.ProtoReflect.Descriptor instead.
+func (*SetGlobalTotalLimitResponse) Descriptor() ([]byte, []int) {
+	return file_app_ratelimit_api_ratelimit_proto_rawDescGZIP(), []int{29}
+}
+
+type ClearGlobalTotalLimitRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ClearGlobalTotalLimitRequest) Reset() {
+	*x = ClearGlobalTotalLimitRequest{}
+	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ClearGlobalTotalLimitRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ClearGlobalTotalLimitRequest) ProtoMessage() {}
+
+func (x *ClearGlobalTotalLimitRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ClearGlobalTotalLimitRequest.ProtoReflect.Descriptor instead.
+func (*ClearGlobalTotalLimitRequest) Descriptor() ([]byte, []int) {
+	return file_app_ratelimit_api_ratelimit_proto_rawDescGZIP(), []int{30}
+}
+
+type ClearGlobalTotalLimitResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Cleared       bool                   `protobuf:"varint,1,opt,name=cleared,proto3" json:"cleared,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ClearGlobalTotalLimitResponse) Reset() {
+	*x = ClearGlobalTotalLimitResponse{}
+	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ClearGlobalTotalLimitResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ClearGlobalTotalLimitResponse) ProtoMessage() {}
+
+func (x *ClearGlobalTotalLimitResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ClearGlobalTotalLimitResponse.ProtoReflect.Descriptor instead.
+func (*ClearGlobalTotalLimitResponse) Descriptor() ([]byte, []int) {
+	return file_app_ratelimit_api_ratelimit_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *ClearGlobalTotalLimitResponse) GetCleared() bool {
+	if x != nil {
+		return x.Cleared
+	}
+	return false
+}
+
+type ConnectionInfo struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ConnId        uint64                 `protobuf:"varint,1,opt,name=conn_id,json=connId,proto3" json:"conn_id,omitempty"`
+	StartedAtUnix uint64                 `protobuf:"varint,2,opt,name=started_at_unix,json=startedAtUnix,proto3" json:"started_at_unix,omitempty"`
+	LastSeenUnix  uint64                 `protobuf:"varint,3,opt,name=last_seen_unix,json=lastSeenUnix,proto3" json:"last_seen_unix,omitempty"`
+	RxBytes       uint64                 `protobuf:"varint,4,opt,name=rx_bytes,json=rxBytes,proto3" json:"rx_bytes,omitempty"`
+	TxBytes       uint64                 `protobuf:"varint,5,opt,name=tx_bytes,json=txBytes,proto3" json:"tx_bytes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ConnectionInfo) Reset() {
+	*x = ConnectionInfo{}
+	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ConnectionInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConnectionInfo) ProtoMessage() {}
+
+func (x *ConnectionInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConnectionInfo.ProtoReflect.Descriptor instead.
+func (*ConnectionInfo) Descriptor() ([]byte, []int) {
+	return file_app_ratelimit_api_ratelimit_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *ConnectionInfo) GetConnId() uint64 {
+	if x != nil {
+		return x.ConnId
+	}
+	return 0
+}
+
+func (x *ConnectionInfo) GetStartedAtUnix() uint64 {
+	if x != nil {
+		return x.StartedAtUnix
+	}
+	return 0
+}
+
+func (x *ConnectionInfo) GetLastSeenUnix() uint64 {
+	if x != nil {
+		return x.LastSeenUnix
+	}
+	return 0
+}
+
+func (x *ConnectionInfo) GetRxBytes() uint64 {
+	if x != nil {
+		return x.RxBytes
+	}
+	return 0
+}
+
+func (x *ConnectionInfo) GetTxBytes() uint64 {
+	if x != nil {
+		return x.TxBytes
+	}
+	return 0
+}
+
+type SetUserDefaultPerConnLimitRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Uuid          string                 `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"`
+	DownBps       uint64                 `protobuf:"varint,2,opt,name=down_bps,json=downBps,proto3" json:"down_bps,omitempty"`
+	UpBps         uint64                 `protobuf:"varint,3,opt,name=up_bps,json=upBps,proto3" json:"up_bps,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetUserDefaultPerConnLimitRequest) Reset() {
+	*x = SetUserDefaultPerConnLimitRequest{}
+	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetUserDefaultPerConnLimitRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetUserDefaultPerConnLimitRequest) ProtoMessage() {}
+
+func (x *SetUserDefaultPerConnLimitRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetUserDefaultPerConnLimitRequest.ProtoReflect.Descriptor instead.
+func (*SetUserDefaultPerConnLimitRequest) Descriptor() ([]byte, []int) {
+	return file_app_ratelimit_api_ratelimit_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *SetUserDefaultPerConnLimitRequest) GetUuid() string {
+	if x != nil {
+		return x.Uuid
+	}
+	return ""
+}
+
+func (x *SetUserDefaultPerConnLimitRequest) GetDownBps() uint64 {
+	if x != nil {
+		return x.DownBps
+	}
+	return 0
+}
+
+func (x *SetUserDefaultPerConnLimitRequest) GetUpBps() uint64 {
+	if x != nil {
+		return x.UpBps
+	}
+	return 0
+}
+
+type SetUserDefaultPerConnLimitResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetUserDefaultPerConnLimitResponse) Reset() {
+	*x = SetUserDefaultPerConnLimitResponse{}
+	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetUserDefaultPerConnLimitResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetUserDefaultPerConnLimitResponse) ProtoMessage() {}
+
+func (x *SetUserDefaultPerConnLimitResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetUserDefaultPerConnLimitResponse.ProtoReflect.Descriptor instead.
+func (*SetUserDefaultPerConnLimitResponse) Descriptor() ([]byte, []int) {
+	return file_app_ratelimit_api_ratelimit_proto_rawDescGZIP(), []int{34}
+}
+
+type UserDefaultPerConnLimit struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Uuid          string                 `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"`
+	DownBps       uint64                 `protobuf:"varint,2,opt,name=down_bps,json=downBps,proto3" json:"down_bps,omitempty"`
+	UpBps         uint64                 `protobuf:"varint,3,opt,name=up_bps,json=upBps,proto3" json:"up_bps,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UserDefaultPerConnLimit) Reset() {
+	*x = UserDefaultPerConnLimit{}
+	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UserDefaultPerConnLimit) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UserDefaultPerConnLimit) ProtoMessage() {}
+
+func (x *UserDefaultPerConnLimit) ProtoReflect() protoreflect.Message {
+	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UserDefaultPerConnLimit.ProtoReflect.Descriptor instead.
+func (*UserDefaultPerConnLimit) Descriptor() ([]byte, []int) {
+	return file_app_ratelimit_api_ratelimit_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *UserDefaultPerConnLimit) GetUuid() string {
+	if x != nil {
+		return x.Uuid
+	}
+	return ""
+}
+
+func (x *UserDefaultPerConnLimit) GetDownBps() uint64 {
+	if x != nil {
+		return x.DownBps
+	}
+	return 0
+}
+
+func (x *UserDefaultPerConnLimit) GetUpBps() uint64 {
+	if x != nil {
+		return x.UpBps
+	}
+	return 0
+}
+
+type SetUserDefaultPerConnLimitsRequest struct {
+	state         protoimpl.MessageState     `protogen:"open.v1"`
+	Limits        []*UserDefaultPerConnLimit `protobuf:"bytes,1,rep,name=limits,proto3" json:"limits,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetUserDefaultPerConnLimitsRequest) Reset() {
+	*x = SetUserDefaultPerConnLimitsRequest{}
+	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetUserDefaultPerConnLimitsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetUserDefaultPerConnLimitsRequest) ProtoMessage() {}
+
+func (x *SetUserDefaultPerConnLimitsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetUserDefaultPerConnLimitsRequest.ProtoReflect.Descriptor instead.
+func (*SetUserDefaultPerConnLimitsRequest) Descriptor() ([]byte, []int) {
+	return file_app_ratelimit_api_ratelimit_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *SetUserDefaultPerConnLimitsRequest) GetLimits() []*UserDefaultPerConnLimit {
+	if x != nil {
+		return x.Limits
+	}
+	return nil
+}
+
+type SetUserDefaultPerConnLimitsResponse struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Updated          uint32                 `protobuf:"varint,1,opt,name=updated,proto3" json:"updated,omitempty"`
+	ClearedOverrides uint32                 `protobuf:"varint,2,opt,name=cleared_overrides,json=clearedOverrides,proto3" json:"cleared_overrides,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *SetUserDefaultPerConnLimitsResponse) Reset() {
+	*x = SetUserDefaultPerConnLimitsResponse{}
+	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetUserDefaultPerConnLimitsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetUserDefaultPerConnLimitsResponse) ProtoMessage() {}
+
+func (x *SetUserDefaultPerConnLimitsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetUserDefaultPerConnLimitsResponse.ProtoReflect.Descriptor instead.
+func (*SetUserDefaultPerConnLimitsResponse) Descriptor() ([]byte, []int) {
+	return file_app_ratelimit_api_ratelimit_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *SetUserDefaultPerConnLimitsResponse) GetUpdated() uint32 {
+	if x != nil {
+		return x.Updated
+	}
+	return 0
+}
+
+func (x *SetUserDefaultPerConnLimitsResponse) GetClearedOverrides() uint32 {
+	if x != nil {
+		return x.ClearedOverrides
+	}
+	return 0
+}
+
+type ListUserConnectionsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Uuid          string                 `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListUserConnectionsRequest) Reset() {
+	*x = ListUserConnectionsRequest{}
+	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListUserConnectionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListUserConnectionsRequest) ProtoMessage() {}
+
+func (x *ListUserConnectionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListUserConnectionsRequest.ProtoReflect.Descriptor instead.
+func (*ListUserConnectionsRequest) Descriptor() ([]byte, []int) {
+	return file_app_ratelimit_api_ratelimit_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *ListUserConnectionsRequest) GetUuid() string {
+	if x != nil {
+		return x.Uuid
+	}
+	return ""
+}
+
+type ListUserConnectionsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Connections   []*ConnectionInfo      `protobuf:"bytes,1,rep,name=connections,proto3" json:"connections,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListUserConnectionsResponse) Reset() {
+	*x = ListUserConnectionsResponse{}
+	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListUserConnectionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListUserConnectionsResponse) ProtoMessage() {}
+
+func (x *ListUserConnectionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListUserConnectionsResponse.ProtoReflect.Descriptor instead.
+func (*ListUserConnectionsResponse) Descriptor() ([]byte, []int) {
+	return file_app_ratelimit_api_ratelimit_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *ListUserConnectionsResponse) GetConnections() []*ConnectionInfo {
+	if x != nil {
+		return x.Connections
+	}
+	return nil
+}
+
+type SetConnectionLimitRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ConnId        uint64                 `protobuf:"varint,1,opt,name=conn_id,json=connId,proto3" json:"conn_id,omitempty"`
+	DownBps       uint64                 `protobuf:"varint,2,opt,name=down_bps,json=downBps,proto3" json:"down_bps,omitempty"`
+	UpBps         uint64                 `protobuf:"varint,3,opt,name=up_bps,json=upBps,proto3" json:"up_bps,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetConnectionLimitRequest) Reset() {
+	*x = SetConnectionLimitRequest{}
+	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetConnectionLimitRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetConnectionLimitRequest) ProtoMessage() {}
+
+func (x *SetConnectionLimitRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetConnectionLimitRequest.ProtoReflect.Descriptor instead.
+func (*SetConnectionLimitRequest) Descriptor() ([]byte, []int) {
+	return file_app_ratelimit_api_ratelimit_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *SetConnectionLimitRequest) GetConnId() uint64 {
+	if x != nil {
+		return x.ConnId
+	}
+	return 0
+}
+
+func (x *SetConnectionLimitRequest) GetDownBps() uint64 {
+	if x != nil {
+		return x.DownBps
+	}
+	return 0
+}
+
+func (x *SetConnectionLimitRequest) GetUpBps() uint64 {
+	if x != nil {
+		return x.UpBps
+	}
+	return 0
+}
+
+type SetConnectionLimitResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetConnectionLimitResponse) Reset() {
+	*x = SetConnectionLimitResponse{}
+	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[41]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetConnectionLimitResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetConnectionLimitResponse) ProtoMessage() {}
+
+func (x *SetConnectionLimitResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[41]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetConnectionLimitResponse.ProtoReflect.Descriptor instead.
+func (*SetConnectionLimitResponse) Descriptor() ([]byte, []int) {
+	return file_app_ratelimit_api_ratelimit_proto_rawDescGZIP(), []int{41}
+}
+
+type ClearConnectionLimitRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ConnId        uint64                 `protobuf:"varint,1,opt,name=conn_id,json=connId,proto3" json:"conn_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ClearConnectionLimitRequest) Reset() {
+	*x = ClearConnectionLimitRequest{}
+	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[42]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ClearConnectionLimitRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ClearConnectionLimitRequest) ProtoMessage() {}
+
+func (x *ClearConnectionLimitRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[42]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ClearConnectionLimitRequest.ProtoReflect.Descriptor instead.
+func (*ClearConnectionLimitRequest) Descriptor() ([]byte, []int) {
+	return file_app_ratelimit_api_ratelimit_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *ClearConnectionLimitRequest) GetConnId() uint64 {
+	if x != nil {
+		return x.ConnId
+	}
+	return 0
+}
+
+type ClearConnectionLimitResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ClearConnectionLimitResponse) Reset() {
+	*x = ClearConnectionLimitResponse{}
+	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[43]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ClearConnectionLimitResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ClearConnectionLimitResponse) ProtoMessage() {}
+
+func (x *ClearConnectionLimitResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[43]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ClearConnectionLimitResponse.ProtoReflect.Descriptor instead.
+func (*ClearConnectionLimitResponse) Descriptor() ([]byte, []int) {
+	return file_app_ratelimit_api_ratelimit_proto_rawDescGZIP(), []int{43}
+}
+
+type DeviceInfo struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Uuid  string                 `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"`
+	// IP адрес, который сервер видит как Source IP клиента (без порта)
+	SrcIp string `protobuf:"bytes,2,opt,name=src_ip,json=srcIp,proto3" json:"src_ip,omitempty"`
+	// Уникальный ключ "устройства" на сервере: uuid + "|" + src_ip
+	DeviceKey string `protobuf:"bytes,3,opt,name=device_key,json=deviceKey,proto3" json:"device_key,omitempty"`
+	// Внутренний ConnID, к которому привязан лимитер (может меняться между рестартами)
+	ConnId uint64 `protobuf:"varint,4,opt,name=conn_id,json=connId,proto3" json:"conn_id,omitempty"`
+	// Сколько inbound TCP соединений сейчас у этого устройства (refcount)
+	RefCount uint32 `protobuf:"varint,5,opt,name=ref_count,json=refCount,proto3" json:"ref_count,omitempty"`
+	// Момент, когда устройство было создано (первый старт) и последнее событие
+	StartedAtUnix uint64 `protobuf:"varint,6,opt,name=started_at_unix,json=startedAtUnix,proto3" json:"started_at_unix,omitempty"`
+	LastSeenUnix  uint64 `protobuf:"varint,7,opt,name=last_seen_unix,json=lastSeenUnix,proto3" json:"last_seen_unix,omitempty"`
+	// Статистика трафика
+	RxBytes       uint64 `protobuf:"varint,8,opt,name=rx_bytes,json=rxBytes,proto3" json:"rx_bytes,omitempty"`
+	TxBytes       uint64 `protobuf:"varint,9,opt,name=tx_bytes,json=txBytes,proto3" json:"tx_bytes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeviceInfo) Reset() {
+	*x = DeviceInfo{}
+	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[44]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeviceInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeviceInfo) ProtoMessage() {}
+
+func (x *DeviceInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[44]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeviceInfo.ProtoReflect.Descriptor instead.
+func (*DeviceInfo) Descriptor() ([]byte, []int) {
+	return file_app_ratelimit_api_ratelimit_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *DeviceInfo) GetUuid() string {
+	if x != nil {
+		return x.Uuid
+	}
+	return ""
+}
+
+func (x *DeviceInfo) GetSrcIp() string {
+	if x != nil {
+		return x.SrcIp
+	}
+	return ""
+}
+
+func (x *DeviceInfo) GetDeviceKey() string {
+	if x != nil {
+		return x.DeviceKey
+	}
+	return ""
+}
+
+func (x *DeviceInfo) GetConnId() uint64 {
+	if x != nil {
+		return x.ConnId
+	}
+	return 0
+}
+
+func (x *DeviceInfo) GetRefCount() uint32 {
+	if x != nil {
+		return x.RefCount
+	}
+	return 0
+}
+
+func (x *DeviceInfo) GetStartedAtUnix() uint64 {
+	if x != nil {
+		return x.StartedAtUnix
+	}
+	return 0
+}
+
+func (x *DeviceInfo) GetLastSeenUnix() uint64 {
+	if x != nil {
+		return x.LastSeenUnix
+	}
+	return 0
+}
+
+func (x *DeviceInfo) GetRxBytes() uint64 {
+	if x != nil {
+		return x.RxBytes
+	}
+	return 0
+}
+
+func (x *DeviceInfo) GetTxBytes() uint64 {
+	if x != nil {
+		return x.TxBytes
+	}
+	return 0
+}
+
+type GetActiveDevicesSnapshotRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetActiveDevicesSnapshotRequest) Reset() {
+	*x = GetActiveDevicesSnapshotRequest{}
+	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[45]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetActiveDevicesSnapshotRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetActiveDevicesSnapshotRequest) ProtoMessage() {}
+
+func (x *GetActiveDevicesSnapshotRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[45]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetActiveDevicesSnapshotRequest.ProtoReflect.Descriptor instead.
+func (*GetActiveDevicesSnapshotRequest) Descriptor() ([]byte, []int) {
+	return file_app_ratelimit_api_ratelimit_proto_rawDescGZIP(), []int{45}
+}
+
+type GetActiveDevicesSnapshotResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Devices       []*DeviceInfo          `protobuf:"bytes,1,rep,name=devices,proto3" json:"devices,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetActiveDevicesSnapshotResponse) Reset() {
+	*x = GetActiveDevicesSnapshotResponse{}
+	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[46]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetActiveDevicesSnapshotResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetActiveDevicesSnapshotResponse) ProtoMessage() {}
+
+func (x *GetActiveDevicesSnapshotResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[46]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetActiveDevicesSnapshotResponse.ProtoReflect.Descriptor instead.
+func (*GetActiveDevicesSnapshotResponse) Descriptor() ([]byte, []int) {
+	return file_app_ratelimit_api_ratelimit_proto_rawDescGZIP(), []int{46}
+}
+
+func (x *GetActiveDevicesSnapshotResponse) GetDevices() []*DeviceInfo {
+	if x != nil {
+		return x.Devices
+	}
+	return nil
+}
+
+type ListUserDevicesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Uuid          string                 `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListUserDevicesRequest) Reset() {
+	*x = ListUserDevicesRequest{}
+	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[47]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListUserDevicesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListUserDevicesRequest) ProtoMessage() {}
+
+func (x *ListUserDevicesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[47]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListUserDevicesRequest.ProtoReflect.Descriptor instead.
+func (*ListUserDevicesRequest) Descriptor() ([]byte, []int) {
+	return file_app_ratelimit_api_ratelimit_proto_rawDescGZIP(), []int{47}
+}
+
+func (x *ListUserDevicesRequest) GetUuid() string {
+	if x != nil {
+		return x.Uuid
+	}
+	return ""
+}
+
+type ListUserDevicesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Devices       []*DeviceInfo          `protobuf:"bytes,1,rep,name=devices,proto3" json:"devices,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListUserDevicesResponse) Reset() {
+	*x = ListUserDevicesResponse{}
+	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[48]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListUserDevicesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListUserDevicesResponse) ProtoMessage() {}
+
+func (x *ListUserDevicesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[48]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListUserDevicesResponse.ProtoReflect.Descriptor instead.
+func (*ListUserDevicesResponse) Descriptor() ([]byte, []int) {
+	return file_app_ratelimit_api_ratelimit_proto_rawDescGZIP(), []int{48}
+}
+
+func (x *ListUserDevicesResponse) GetDevices() []*DeviceInfo {
+	if x != nil {
+		return x.Devices
+	}
+	return nil
+}
+
+type SetDeviceLimitRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DeviceKey     string                 `protobuf:"bytes,1,opt,name=device_key,json=deviceKey,proto3" json:"device_key,omitempty"`
+	DownBps       uint64                 `protobuf:"varint,2,opt,name=down_bps,json=downBps,proto3" json:"down_bps,omitempty"`
+	UpBps         uint64                 `protobuf:"varint,3,opt,name=up_bps,json=upBps,proto3" json:"up_bps,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetDeviceLimitRequest) Reset() {
+	*x = SetDeviceLimitRequest{}
+	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[49]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetDeviceLimitRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetDeviceLimitRequest) ProtoMessage() {}
+
+func (x *SetDeviceLimitRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[49]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetDeviceLimitRequest.ProtoReflect.Descriptor instead.
+func (*SetDeviceLimitRequest) Descriptor() ([]byte, []int) {
+	return file_app_ratelimit_api_ratelimit_proto_rawDescGZIP(), []int{49}
+}
+
+func (x *SetDeviceLimitRequest) GetDeviceKey() string {
+	if x != nil {
+		return x.DeviceKey
+	}
+	return ""
+}
+
+func (x *SetDeviceLimitRequest) GetDownBps() uint64 {
+	if x != nil {
+		return x.DownBps
+	}
+	return 0
+}
+
+func (x *SetDeviceLimitRequest) GetUpBps() uint64 {
+	if x != nil {
+		return x.UpBps
+	}
+	return 0
+}
+
+type SetDeviceLimitResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetDeviceLimitResponse) Reset() {
+	*x = SetDeviceLimitResponse{}
+	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[50]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetDeviceLimitResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetDeviceLimitResponse) ProtoMessage() {}
+
+func (x *SetDeviceLimitResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[50]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetDeviceLimitResponse.ProtoReflect.Descriptor instead.
+func (*SetDeviceLimitResponse) Descriptor() ([]byte, []int) {
+	return file_app_ratelimit_api_ratelimit_proto_rawDescGZIP(), []int{50}
+}
+
+type ClearDeviceLimitRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DeviceKey     string                 `protobuf:"bytes,1,opt,name=device_key,json=deviceKey,proto3" json:"device_key,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ClearDeviceLimitRequest) Reset() {
+	*x = ClearDeviceLimitRequest{}
+	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[51]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ClearDeviceLimitRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ClearDeviceLimitRequest) ProtoMessage() {}
+
+func (x *ClearDeviceLimitRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[51]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ClearDeviceLimitRequest.ProtoReflect.Descriptor instead.
+func (*ClearDeviceLimitRequest) Descriptor() ([]byte, []int) {
+	return file_app_ratelimit_api_ratelimit_proto_rawDescGZIP(), []int{51}
+}
+
+func (x *ClearDeviceLimitRequest) GetDeviceKey() string {
+	if x != nil {
+		return x.DeviceKey
+	}
+	return ""
+}
+
+type ClearDeviceLimitResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ClearDeviceLimitResponse) Reset() {
+	*x = ClearDeviceLimitResponse{}
+	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[52]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ClearDeviceLimitResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ClearDeviceLimitResponse) ProtoMessage() {}
+
+func (x *ClearDeviceLimitResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[52]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ClearDeviceLimitResponse.ProtoReflect.Descriptor instead.
+func (*ClearDeviceLimitResponse) Descriptor() ([]byte, []int) {
+	return file_app_ratelimit_api_ratelimit_proto_rawDescGZIP(), []int{52}
+}
+
+type Quota struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Period         Quota_Period           `protobuf:"varint,1,opt,name=period,proto3,enum=ratelimit.v1.Quota_Period" json:"period,omitempty"`
+	Direction      Quota_Direction        `protobuf:"varint,2,opt,name=direction,proto3,enum=ratelimit.v1.Quota_Direction" json:"direction,omitempty"`
+	LimitBytes     uint64                 `protobuf:"varint,3,opt,name=limit_bytes,json=limitBytes,proto3" json:"limit_bytes,omitempty"`
+	Action         Quota_Action           `protobuf:"varint,4,opt,name=action,proto3,enum=ratelimit.v1.Quota_Action" json:"action,omitempty"`
+	PenaltyDownBps uint64                 `protobuf:"varint,5,opt,name=penalty_down_bps,json=penaltyDownBps,proto3" json:"penalty_down_bps,omitempty"`
+	PenaltyUpBps   uint64                 `protobuf:"varint,6,opt,name=penalty_up_bps,json=penaltyUpBps,proto3" json:"penalty_up_bps,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *Quota) Reset() {
+	*x = Quota{}
+	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[53]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Quota) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Quota) ProtoMessage() {}
+
+func (x *Quota) ProtoReflect() protoreflect.Message {
+	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[53]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Quota.ProtoReflect.Descriptor instead.
+func (*Quota) Descriptor() ([]byte, []int) {
+	return file_app_ratelimit_api_ratelimit_proto_rawDescGZIP(), []int{53}
+}
+
+func (x *Quota) GetPeriod() Quota_Period {
+	if x != nil {
+		return x.Period
+	}
+	return Quota_ABSOLUTE
+}
+
+func (x *Quota) GetDirection() Quota_Direction {
+	if x != nil {
+		return x.Direction
+	}
+	return Quota_TOTAL
+}
+
+func (x *Quota) GetLimitBytes() uint64 {
+	if x != nil {
+		return x.LimitBytes
+	}
+	return 0
+}
+
+func (x *Quota) GetAction() Quota_Action {
+	if x != nil {
+		return x.Action
+	}
+	return Quota_BLOCK
+}
+
+func (x *Quota) GetPenaltyDownBps() uint64 {
+	if x != nil {
+		return x.PenaltyDownBps
+	}
+	return 0
+}
+
+func (x *Quota) GetPenaltyUpBps() uint64 {
+	if x != nil {
+		return x.PenaltyUpBps
+	}
+	return 0
+}
+
+type SetUserQuotaRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Uuid          string                 `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"`
+	Quota         *Quota                 `protobuf:"bytes,2,opt,name=quota,proto3" json:"quota,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetUserQuotaRequest) Reset() {
+	*x = SetUserQuotaRequest{}
+	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[54]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetUserQuotaRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetUserQuotaRequest) ProtoMessage() {}
+
+func (x *SetUserQuotaRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[54]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetUserQuotaRequest.ProtoReflect.Descriptor instead.
+func (*SetUserQuotaRequest) Descriptor() ([]byte, []int) {
+	return file_app_ratelimit_api_ratelimit_proto_rawDescGZIP(), []int{54}
+}
+
+func (x *SetUserQuotaRequest) GetUuid() string {
+	if x != nil {
+		return x.Uuid
+	}
+	return ""
+}
+
+func (x *SetUserQuotaRequest) GetQuota() *Quota {
+	if x != nil {
+		return x.Quota
+	}
+	return nil
+}
+
+type SetUserQuotaResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetUserQuotaResponse) Reset() {
+	*x = SetUserQuotaResponse{}
+	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[55]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetUserQuotaResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetUserQuotaResponse) ProtoMessage() {}
+
+func (x *SetUserQuotaResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[55]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetUserQuotaResponse.ProtoReflect.Descriptor instead.
+func (*SetUserQuotaResponse) Descriptor() ([]byte, []int) {
+	return file_app_ratelimit_api_ratelimit_proto_rawDescGZIP(), []int{55}
+}
+
+type ClearUserQuotaRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Uuid          string                 `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ClearUserQuotaRequest) Reset() {
+	*x = ClearUserQuotaRequest{}
+	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[56]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ClearUserQuotaRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ClearUserQuotaRequest) ProtoMessage() {}
+
+func (x *ClearUserQuotaRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[56]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ClearUserQuotaRequest.ProtoReflect.Descriptor instead.
+func (*ClearUserQuotaRequest) Descriptor() ([]byte, []int) {
+	return file_app_ratelimit_api_ratelimit_proto_rawDescGZIP(), []int{56}
+}
+
+func (x *ClearUserQuotaRequest) GetUuid() string {
+	if x != nil {
+		return x.Uuid
+	}
+	return ""
+}
+
+type ClearUserQuotaResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Cleared       bool                   `protobuf:"varint,1,opt,name=cleared,proto3" json:"cleared,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ClearUserQuotaResponse) Reset() {
+	*x = ClearUserQuotaResponse{}
+	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[57]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ClearUserQuotaResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ClearUserQuotaResponse) ProtoMessage() {}
+
+func (x *ClearUserQuotaResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[57]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ClearUserQuotaResponse.ProtoReflect.Descriptor instead.
+func (*ClearUserQuotaResponse) Descriptor() ([]byte, []int) {
+	return file_app_ratelimit_api_ratelimit_proto_rawDescGZIP(), []int{57}
+}
+
+func (x *ClearUserQuotaResponse) GetCleared() bool {
+	if x != nil {
+		return x.Cleared
+	}
+	return false
+}
+
+type GetUserQuotaRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Uuid          string                 `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetUserQuotaRequest) Reset() {
+	*x = GetUserQuotaRequest{}
+	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[58]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetUserQuotaRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUserQuotaRequest) ProtoMessage() {}
+
+func (x *GetUserQuotaRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[58]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUserQuotaRequest.ProtoReflect.Descriptor instead.
+func (*GetUserQuotaRequest) Descriptor() ([]byte, []int) {
+	return file_app_ratelimit_api_ratelimit_proto_rawDescGZIP(), []int{58}
+}
+
+func (x *GetUserQuotaRequest) GetUuid() string {
+	if x != nil {
+		return x.Uuid
+	}
+	return ""
+}
+
+type GetUserQuotaResponse struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Uuid            string                 `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"`
+	Quota           *Quota                 `protobuf:"bytes,2,opt,name=quota,proto3" json:"quota,omitempty"`
+	UpBytes         uint64                 `protobuf:"varint,3,opt,name=up_bytes,json=upBytes,proto3" json:"up_bytes,omitempty"`
+	DownBytes       uint64                 `protobuf:"varint,4,opt,name=down_bytes,json=downBytes,proto3" json:"down_bytes,omitempty"`
+	PeriodStartUnix uint64                 `protobuf:"varint,5,opt,name=period_start_unix,json=periodStartUnix,proto3" json:"period_start_unix,omitempty"` // 0 для ABSOLUTE
+	Exceeded        bool                   `protobuf:"varint,6,opt,name=exceeded,proto3" json:"exceeded,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *GetUserQuotaResponse) Reset() {
+	*x = GetUserQuotaResponse{}
+	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[59]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetUserQuotaResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUserQuotaResponse) ProtoMessage() {}
+
+func (x *GetUserQuotaResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[59]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUserQuotaResponse.ProtoReflect.Descriptor instead.
+func (*GetUserQuotaResponse) Descriptor() ([]byte, []int) {
+	return file_app_ratelimit_api_ratelimit_proto_rawDescGZIP(), []int{59}
+}
+
+func (x *GetUserQuotaResponse) GetUuid() string {
+	if x != nil {
+		return x.Uuid
+	}
+	return ""
+}
+
+func (x *GetUserQuotaResponse) GetQuota() *Quota {
+	if x != nil {
+		return x.Quota
+	}
+	return nil
+}
+
+func (x *GetUserQuotaResponse) GetUpBytes() uint64 {
+	if x != nil {
+		return x.UpBytes
+	}
+	return 0
+}
+
+func (x *GetUserQuotaResponse) GetDownBytes() uint64 {
+	if x != nil {
+		return x.DownBytes
+	}
+	return 0
+}
+
+func (x *GetUserQuotaResponse) GetPeriodStartUnix() uint64 {
+	if x != nil {
+		return x.PeriodStartUnix
+	}
+	return 0
+}
+
+func (x *GetUserQuotaResponse) GetExceeded() bool {
+	if x != nil {
+		return x.Exceeded
+	}
+	return false
+}
+
+type ResetUserQuotaUsageRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Uuid          string                 `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResetUserQuotaUsageRequest) Reset() {
+	*x = ResetUserQuotaUsageRequest{}
+	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[60]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResetUserQuotaUsageRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResetUserQuotaUsageRequest) ProtoMessage() {}
+
+func (x *ResetUserQuotaUsageRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[60]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResetUserQuotaUsageRequest.ProtoReflect.Descriptor instead.
+func (*ResetUserQuotaUsageRequest) Descriptor() ([]byte, []int) {
+	return file_app_ratelimit_api_ratelimit_proto_rawDescGZIP(), []int{60}
+}
+
+func (x *ResetUserQuotaUsageRequest) GetUuid() string {
+	if x != nil {
+		return x.Uuid
+	}
+	return ""
+}
+
+type ResetUserQuotaUsageResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Found         bool                   `protobuf:"varint,1,opt,name=found,proto3" json:"found,omitempty"` // false, если у uuid нет квоты
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResetUserQuotaUsageResponse) Reset() {
+	*x = ResetUserQuotaUsageResponse{}
+	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[61]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResetUserQuotaUsageResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResetUserQuotaUsageResponse) ProtoMessage() {}
+
+func (x *ResetUserQuotaUsageResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[61]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResetUserQuotaUsageResponse.ProtoReflect.Descriptor instead.
+func (*ResetUserQuotaUsageResponse) Descriptor() ([]byte, []int) {
+	return file_app_ratelimit_api_ratelimit_proto_rawDescGZIP(), []int{61}
+}
+
+func (x *ResetUserQuotaUsageResponse) GetFound() bool {
+	if x != nil {
+		return x.Found
+	}
+	return false
+}
+
+type MaxDevices struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Max           uint32                 `protobuf:"varint,1,opt,name=max,proto3" json:"max,omitempty"` // 0 — без ограничения
+	Policy        MaxDevices_Policy      `protobuf:"varint,2,opt,name=policy,proto3,enum=ratelimit.v1.MaxDevices_Policy" json:"policy,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MaxDevices) Reset() {
+	*x = MaxDevices{}
+	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[62]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MaxDevices) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MaxDevices) ProtoMessage() {}
+
+func (x *MaxDevices) ProtoReflect() protoreflect.Message {
+	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[62]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MaxDevices.ProtoReflect.Descriptor instead.
+func (*MaxDevices) Descriptor() ([]byte, []int) {
+	return file_app_ratelimit_api_ratelimit_proto_rawDescGZIP(), []int{62}
+}
+
+func (x *MaxDevices) GetMax() uint32 {
+	if x != nil {
+		return x.Max
+	}
+	return 0
+}
+
+func (x *MaxDevices) GetPolicy() MaxDevices_Policy {
+	if x != nil {
+		return x.Policy
+	}
+	return MaxDevices_REJECT_NEWEST
+}
+
+type SetMaxDevicesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Uuid          string                 `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"`
+	Limit         *MaxDevices            `protobuf:"bytes,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetMaxDevicesRequest) Reset() {
+	*x = SetMaxDevicesRequest{}
+	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[63]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetMaxDevicesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetMaxDevicesRequest) ProtoMessage() {}
+
+func (x *SetMaxDevicesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[63]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetMaxDevicesRequest.ProtoReflect.Descriptor instead.
+func (*SetMaxDevicesRequest) Descriptor() ([]byte, []int) {
+	return file_app_ratelimit_api_ratelimit_proto_rawDescGZIP(), []int{63}
+}
+
+func (x *SetMaxDevicesRequest) GetUuid() string {
+	if x != nil {
+		return x.Uuid
+	}
+	return ""
+}
+
+func (x *SetMaxDevicesRequest) GetLimit() *MaxDevices {
+	if x != nil {
+		return x.Limit
+	}
+	return nil
+}
+
+type SetMaxDevicesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetMaxDevicesResponse) Reset() {
+	*x = SetMaxDevicesResponse{}
+	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[64]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetMaxDevicesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetMaxDevicesResponse) ProtoMessage() {}
+
+func (x *SetMaxDevicesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[64]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetMaxDevicesResponse.ProtoReflect.Descriptor instead.
+func (*SetMaxDevicesResponse) Descriptor() ([]byte, []int) {
+	return file_app_ratelimit_api_ratelimit_proto_rawDescGZIP(), []int{64}
+}
+
+type ClearMaxDevicesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Uuid          string                 `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ClearMaxDevicesRequest) Reset() {
+	*x = ClearMaxDevicesRequest{}
+	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[65]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ClearMaxDevicesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ClearMaxDevicesRequest) ProtoMessage() {}
+
+func (x *ClearMaxDevicesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[65]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ClearMaxDevicesRequest.ProtoReflect.Descriptor instead.
+func (*ClearMaxDevicesRequest) Descriptor() ([]byte, []int) {
+	return file_app_ratelimit_api_ratelimit_proto_rawDescGZIP(), []int{65}
+}
+
+func (x *ClearMaxDevicesRequest) GetUuid() string {
+	if x != nil {
+		return x.Uuid
+	}
+	return ""
+}
+
+type ClearMaxDevicesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Cleared       bool                   `protobuf:"varint,1,opt,name=cleared,proto3" json:"cleared,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ClearMaxDevicesResponse) Reset() {
+	*x = ClearMaxDevicesResponse{}
+	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[66]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ClearMaxDevicesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ClearMaxDevicesResponse) ProtoMessage() {}
+
+func (x *ClearMaxDevicesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[66]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ClearMaxDevicesResponse.ProtoReflect.Descriptor instead.
+func (*ClearMaxDevicesResponse) Descriptor() ([]byte, []int) {
+	return file_app_ratelimit_api_ratelimit_proto_rawDescGZIP(), []int{66}
+}
+
+func (x *ClearMaxDevicesResponse) GetCleared() bool {
+	if x != nil {
+		return x.Cleared
+	}
+	return false
+}
+
+type GetMaxDevicesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Uuid          string                 `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetMaxDevicesRequest) Reset() {
+	*x = GetMaxDevicesRequest{}
+	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[67]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetMaxDevicesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetMaxDevicesRequest) ProtoMessage() {}
+
+func (x *GetMaxDevicesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[67]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetMaxDevicesRequest.ProtoReflect.Descriptor instead.
+func (*GetMaxDevicesRequest) Descriptor() ([]byte, []int) {
+	return file_app_ratelimit_api_ratelimit_proto_rawDescGZIP(), []int{67}
+}
+
+func (x *GetMaxDevicesRequest) GetUuid() string {
+	if x != nil {
+		return x.Uuid
+	}
+	return ""
+}
+
+type GetMaxDevicesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Uuid          string                 `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"`
+	Limit         *MaxDevices            `protobuf:"bytes,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	Found         bool                   `protobuf:"varint,3,opt,name=found,proto3" json:"found,omitempty"`                    // false, если лимита нет ни у uuid, ни общего
+	PerUser       bool                   `protobuf:"varint,4,opt,name=per_user,json=perUser,proto3" json:"per_user,omitempty"` // лимит задан именно для uuid
+	ActiveDevices uint32                 `protobuf:"varint,5,opt,name=active_devices,json=activeDevices,proto3" json:"active_devices,omitempty"`
+	Rejected      uint64                 `protobuf:"varint,6,opt,name=rejected,proto3" json:"rejected,omitempty"`
+	Evicted       uint64                 `protobuf:"varint,7,opt,name=evicted,proto3" json:"evicted,omitempty"`
+	Exceeded      uint64                 `protobuf:"varint,8,opt,name=exceeded,proto3" json:"exceeded,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetMaxDevicesResponse) Reset() {
+	*x = GetMaxDevicesResponse{}
+	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[68]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetMaxDevicesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetMaxDevicesResponse) ProtoMessage() {}
+
+func (x *GetMaxDevicesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[68]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetMaxDevicesResponse.ProtoReflect.Descriptor instead.
+func (*GetMaxDevicesResponse) Descriptor() ([]byte, []int) {
+	return file_app_ratelimit_api_ratelimit_proto_rawDescGZIP(), []int{68}
+}
+
+func (x *GetMaxDevicesResponse) GetUuid() string {
+	if x != nil {
+		return x.Uuid
+	}
+	return ""
+}
+
+func (x *GetMaxDevicesResponse) GetLimit() *MaxDevices {
+	if x != nil {
+		return x.Limit
+	}
+	return nil
+}
+
+func (x *GetMaxDevicesResponse) GetFound() bool {
+	if x != nil {
+		return x.Found
+	}
+	return false
+}
+
+func (x *GetMaxDevicesResponse) GetPerUser() bool {
+	if x != nil {
+		return x.PerUser
+	}
+	return false
+}
+
+func (x *GetMaxDevicesResponse) GetActiveDevices() uint32 {
+	if x != nil {
+		return x.ActiveDevices
+	}
+	return 0
+}
+
+func (x *GetMaxDevicesResponse) GetRejected() uint64 {
+	if x != nil {
+		return x.Rejected
+	}
+	return 0
+}
+
+func (x *GetMaxDevicesResponse) GetEvicted() uint64 {
+	if x != nil {
+		return x.Evicted
+	}
+	return 0
+}
+
+func (x *GetMaxDevicesResponse) GetExceeded() uint64 {
+	if x != nil {
+		return x.Exceeded
+	}
+	return 0
+}
+
+type SubscribeDeviceEventsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Uuid          string                 `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"` // пусто — события всех пользователей
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SubscribeDeviceEventsRequest) Reset() {
+	*x = SubscribeDeviceEventsRequest{}
+	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[69]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubscribeDeviceEventsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubscribeDeviceEventsRequest) ProtoMessage() {}
+
+func (x *SubscribeDeviceEventsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[69]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubscribeDeviceEventsRequest.ProtoReflect.Descriptor instead.
+func (*SubscribeDeviceEventsRequest) Descriptor() ([]byte, []int) {
+	return file_app_ratelimit_api_ratelimit_proto_rawDescGZIP(), []int{69}
+}
+
+func (x *SubscribeDeviceEventsRequest) GetUuid() string {
+	if x != nil {
+		return x.Uuid
+	}
+	return ""
+}
+
+type DeviceEvent struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	Type       DeviceEvent_Type       `protobuf:"varint,1,opt,name=type,proto3,enum=ratelimit.v1.DeviceEvent_Type" json:"type,omitempty"`
+	TimeUnixMs uint64                 `protobuf:"varint,2,opt,name=time_unix_ms,json=timeUnixMs,proto3" json:"time_unix_ms,omitempty"`
+	Uuid       string                 `protobuf:"bytes,3,opt,name=uuid,proto3" json:"uuid,omitempty"`
+	DeviceKey  string                 `protobuf:"bytes,4,opt,name=device_key,json=deviceKey,proto3" json:"device_key,omitempty"`
+	ConnId     uint64                 `protobuf:"varint,5,opt,name=conn_id,json=connId,proto3" json:"conn_id,omitempty"`
+	// LIMIT_CHANGED
+	LimitScope   DeviceEvent_LimitScope `protobuf:"varint,6,opt,name=limit_scope,json=limitScope,proto3,enum=ratelimit.v1.DeviceEvent_LimitScope" json:"limit_scope,omitempty"`
+	DownBps      uint64                 `protobuf:"varint,7,opt,name=down_bps,json=downBps,proto3" json:"down_bps,omitempty"`
+	UpBps        uint64                 `protobuf:"varint,8,opt,name=up_bps,json=upBps,proto3" json:"up_bps,omitempty"`
+	LimitCleared bool                   `protobuf:"varint,9,opt,name=limit_cleared,json=limitCleared,proto3" json:"limit_cleared,omitempty"`
+	// EGRESS_CHANGED (пусто — привязка снята), STARTED (восстановленная привязка)
+	EgressTag     string `protobuf:"bytes,10,opt,name=egress_tag,json=egressTag,proto3" json:"egress_tag,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeviceEvent) Reset() {
+	*x = DeviceEvent{}
+	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[70]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeviceEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeviceEvent) ProtoMessage() {}
+
+func (x *DeviceEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[70]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeviceEvent.ProtoReflect.Descriptor instead.
+func (*DeviceEvent) Descriptor() ([]byte, []int) {
+	return file_app_ratelimit_api_ratelimit_proto_rawDescGZIP(), []int{70}
+}
+
+func (x *DeviceEvent) GetType() DeviceEvent_Type {
+	if x != nil {
+		return x.Type
+	}
+	return DeviceEvent_STARTED
+}
+
+func (x *DeviceEvent) GetTimeUnixMs() uint64 {
+	if x != nil {
+		return x.TimeUnixMs
+	}
+	return 0
+}
+
+func (x *DeviceEvent) GetUuid() string {
+	if x != nil {
+		return x.Uuid
+	}
+	return ""
+}
+
+func (x *DeviceEvent) GetDeviceKey() string {
+	if x != nil {
+		return x.DeviceKey
+	}
+	return ""
+}
+
+func (x *DeviceEvent) GetConnId() uint64 {
+	if x != nil {
+		return x.ConnId
+	}
+	return 0
+}
+
+func (x *DeviceEvent) GetLimitScope() DeviceEvent_LimitScope {
+	if x != nil {
+		return x.LimitScope
+	}
+	return DeviceEvent_DEVICE
+}
+
+func (x *DeviceEvent) GetDownBps() uint64 {
+	if x != nil {
+		return x.DownBps
+	}
+	return 0
+}
+
+func (x *DeviceEvent) GetUpBps() uint64 {
+	if x != nil {
+		return x.UpBps
+	}
+	return 0
+}
+
+func (x *DeviceEvent) GetLimitCleared() bool {
+	if x != nil {
+		return x.LimitCleared
+	}
+	return false
+}
+
+func (x *DeviceEvent) GetEgressTag() string {
+	if x != nil {
+		return x.EgressTag
+	}
+	return ""
+}
+
+type Rate struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DownBps       uint64                 `protobuf:"varint,1,opt,name=down_bps,json=downBps,proto3" json:"down_bps,omitempty"`
+	UpBps         uint64                 `protobuf:"varint,2,opt,name=up_bps,json=upBps,proto3" json:"up_bps,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Rate) Reset() {
+	*x = Rate{}
+	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[71]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Rate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Rate) ProtoMessage() {}
+
+func (x *Rate) ProtoReflect() protoreflect.Message {
+	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[71]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Rate.ProtoReflect.Descriptor instead.
+func (*Rate) Descriptor() ([]byte, []int) {
+	return file_app_ratelimit_api_ratelimit_proto_rawDescGZIP(), []int{71}
+}
+
+func (x *Rate) GetDownBps() uint64 {
+	if x != nil {
+		return x.DownBps
+	}
+	return 0
+}
+
+func (x *Rate) GetUpBps() uint64 {
+	if x != nil {
+		return x.UpBps
+	}
+	return 0
+}
+
+type ScheduleWindow struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Days          []uint32               `protobuf:"varint,1,rep,packed,name=days,proto3" json:"days,omitempty"`                           // 0 — воскресенье ... 6 — суббота; пусто — каждый день
+	StartMinute   uint32                 `protobuf:"varint,2,opt,name=start_minute,json=startMinute,proto3" json:"start_minute,omitempty"` // минуты от полуночи
+	EndMinute     uint32                 `protobuf:"varint,3,opt,name=end_minute,json=endMinute,proto3" json:"end_minute,omitempty"`       // <= start_minute — окно через полночь
+	PerConn       *Rate                  `protobuf:"bytes,4,opt,name=per_conn,json=perConn,proto3" json:"per_conn,omitempty"`              // не задан — окно не трогает per-conn лимит; 0/0 — без ограничения
+	Total         *Rate                  `protobuf:"bytes,5,opt,name=total,proto3" json:"total,omitempty"`                                 // общий лимит пользователя
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ScheduleWindow) Reset() {
+	*x = ScheduleWindow{}
+	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[72]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ScheduleWindow) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScheduleWindow) ProtoMessage() {}
+
+func (x *ScheduleWindow) ProtoReflect() protoreflect.Message {
+	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[72]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScheduleWindow.ProtoReflect.Descriptor instead.
+func (*ScheduleWindow) Descriptor() ([]byte, []int) {
+	return file_app_ratelimit_api_ratelimit_proto_rawDescGZIP(), []int{72}
+}
+
+func (x *ScheduleWindow) GetDays() []uint32 {
+	if x != nil {
+		return x.Days
+	}
+	return nil
+}
+
+func (x *ScheduleWindow) GetStartMinute() uint32 {
+	if x != nil {
+		return x.StartMinute
+	}
+	return 0
+}
+
+func (x *ScheduleWindow) GetEndMinute() uint32 {
+	if x != nil {
+		return x.EndMinute
+	}
+	return 0
+}
+
+func (x *ScheduleWindow) GetPerConn() *Rate {
+	if x != nil {
+		return x.PerConn
+	}
+	return nil
+}
+
+func (x *ScheduleWindow) GetTotal() *Rate {
+	if x != nil {
+		return x.Total
+	}
+	return nil
+}
+
+type Schedule struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TimeZone      string                 `protobuf:"bytes,1,opt,name=time_zone,json=timeZone,proto3" json:"time_zone,omitempty"` // IANA, "UTC", "Local" или "+03:00"
+	Windows       []*ScheduleWindow      `protobuf:"bytes,2,rep,name=windows,proto3" json:"windows,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Schedule) Reset() {
+	*x = Schedule{}
+	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[73]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Schedule) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Schedule) ProtoMessage() {}
+
+func (x *Schedule) ProtoReflect() protoreflect.Message {
+	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[73]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Schedule.ProtoReflect.Descriptor instead.
+func (*Schedule) Descriptor() ([]byte, []int) {
+	return file_app_ratelimit_api_ratelimit_proto_rawDescGZIP(), []int{73}
+}
+
+func (x *Schedule) GetTimeZone() string {
+	if x != nil {
+		return x.TimeZone
+	}
+	return ""
+}
+
+func (x *Schedule) GetWindows() []*ScheduleWindow {
+	if x != nil {
+		return x.Windows
+	}
+	return nil
+}
+
+type SetScheduleRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Uuid          string                 `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"`
+	Level         uint32                 `protobuf:"varint,2,opt,name=level,proto3" json:"level,omitempty"` // если uuid пуст
+	Schedule      *Schedule              `protobuf:"bytes,3,opt,name=schedule,proto3" json:"schedule,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetScheduleRequest) Reset() {
+	*x = SetScheduleRequest{}
+	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[74]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetScheduleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetScheduleRequest) ProtoMessage() {}
+
+func (x *SetScheduleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[74]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetScheduleRequest.ProtoReflect.Descriptor instead.
+func (*SetScheduleRequest) Descriptor() ([]byte, []int) {
+	return file_app_ratelimit_api_ratelimit_proto_rawDescGZIP(), []int{74}
+}
+
+func (x *SetScheduleRequest) GetUuid() string {
+	if x != nil {
+		return x.Uuid
+	}
+	return ""
+}
+
+func (x *SetScheduleRequest) GetLevel() uint32 {
+	if x != nil {
+		return x.Level
+	}
 	return 0
 }
 
-func (x *SetUserDefaultPerConnLimitRequest) GetUpBps() uint64 {
+func (x *SetScheduleRequest) GetSchedule() *Schedule {
+	if x != nil {
+		return x.Schedule
+	}
+	return nil
+}
+
+type SetScheduleResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetScheduleResponse) Reset() {
+	*x = SetScheduleResponse{}
+	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[75]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetScheduleResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetScheduleResponse) ProtoMessage() {}
+
+func (x *SetScheduleResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[75]
 	if x != nil {
-		return x.UpBps
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return 0
+	return mi.MessageOf(x)
 }
 
-type SetUserDefaultPerConnLimitResponse struct {
+// Deprecated: Use SetScheduleResponse.ProtoReflect.Descriptor instead.
+func (*SetScheduleResponse) Descriptor() ([]byte, []int) {
+	return file_app_ratelimit_api_ratelimit_proto_rawDescGZIP(), []int{75}
+}
+
+type ClearScheduleRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
+	Uuid          string                 `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"`
+	Level         uint32                 `protobuf:"varint,2,opt,name=level,proto3" json:"level,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *SetUserDefaultPerConnLimitResponse) Reset() {
-	*x = SetUserDefaultPerConnLimitResponse{}
-	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[24]
+func (x *ClearScheduleRequest) Reset() {
+	*x = ClearScheduleRequest{}
+	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[76]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *SetUserDefaultPerConnLimitResponse) String() string {
+func (x *ClearScheduleRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SetUserDefaultPerConnLimitResponse) ProtoMessage() {}
+func (*ClearScheduleRequest) ProtoMessage() {}
 
-func (x *SetUserDefaultPerConnLimitResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[24]
+func (x *ClearScheduleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[76]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1242,35 +4271,47 @@ func (x *SetUserDefaultPerConnLimitResponse) ProtoReflect() protoreflect.Message
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SetUserDefaultPerConnLimitResponse.ProtoReflect.Descriptor instead.
-func (*SetUserDefaultPerConnLimitResponse) Descriptor() ([]byte, []int) {
-	return file_app_ratelimit_api_ratelimit_proto_rawDescGZIP(), []int{24}
+// Deprecated: Use ClearScheduleRequest.ProtoReflect.Descriptor instead.
+func (*ClearScheduleRequest) Descriptor() ([]byte, []int) {
+	return file_app_ratelimit_api_ratelimit_proto_rawDescGZIP(), []int{76}
 }
 
-type UserDefaultPerConnLimit struct {
+func (x *ClearScheduleRequest) GetUuid() string {
+	if x != nil {
+		return x.Uuid
+	}
+	return ""
+}
+
+func (x *ClearScheduleRequest) GetLevel() uint32 {
+	if x != nil {
+		return x.Level
+	}
+	return 0
+}
+
+type ClearScheduleResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Uuid          string                 `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"`
-	DownBps       uint64                 `protobuf:"varint,2,opt,name=down_bps,json=downBps,proto3" json:"down_bps,omitempty"`
-	UpBps         uint64                 `protobuf:"varint,3,opt,name=up_bps,json=upBps,proto3" json:"up_bps,omitempty"`
+	Cleared       bool                   `protobuf:"varint,1,opt,name=cleared,proto3" json:"cleared,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *UserDefaultPerConnLimit) Reset() {
-	*x = UserDefaultPerConnLimit{}
-	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[25]
+func (x *ClearScheduleResponse) Reset() {
+	*x = ClearScheduleResponse{}
+	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[77]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *UserDefaultPerConnLimit) String() string {
+func (x *ClearScheduleResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UserDefaultPerConnLimit) ProtoMessage() {}
+func (*ClearScheduleResponse) ProtoMessage() {}
 
-func (x *UserDefaultPerConnLimit) ProtoReflect() protoreflect.Message {
-	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[25]
+func (x *ClearScheduleResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[77]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1281,54 +4322,94 @@ func (x *UserDefaultPerConnLimit) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UserDefaultPerConnLimit.ProtoReflect.Descriptor instead.
-func (*UserDefaultPerConnLimit) Descriptor() ([]byte, []int) {
-	return file_app_ratelimit_api_ratelimit_proto_rawDescGZIP(), []int{25}
+// Deprecated: Use ClearScheduleResponse.ProtoReflect.Descriptor instead.
+func (*ClearScheduleResponse) Descriptor() ([]byte, []int) {
+	return file_app_ratelimit_api_ratelimit_proto_rawDescGZIP(), []int{77}
 }
 
-func (x *UserDefaultPerConnLimit) GetUuid() string {
+func (x *ClearScheduleResponse) GetCleared() bool {
 	if x != nil {
-		return x.Uuid
+		return x.Cleared
 	}
-	return ""
+	return false
 }
 
-func (x *UserDefaultPerConnLimit) GetDownBps() uint64 {
+type GetScheduleRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Uuid          string                 `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"`
+	Level         uint32                 `protobuf:"varint,2,opt,name=level,proto3" json:"level,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetScheduleRequest) Reset() {
+	*x = GetScheduleRequest{}
+	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[78]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetScheduleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetScheduleRequest) ProtoMessage() {}
+
+func (x *GetScheduleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[78]
 	if x != nil {
-		return x.DownBps
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return 0
+	return mi.MessageOf(x)
 }
 
-func (x *UserDefaultPerConnLimit) GetUpBps() uint64 {
+// Deprecated: Use GetScheduleRequest.ProtoReflect.Descriptor instead.
+func (*GetScheduleRequest) Descriptor() ([]byte, []int) {
+	return file_app_ratelimit_api_ratelimit_proto_rawDescGZIP(), []int{78}
+}
+
+func (x *GetScheduleRequest) GetUuid() string {
 	if x != nil {
-		return x.UpBps
+		return x.Uuid
+	}
+	return ""
+}
+
+func (x *GetScheduleRequest) GetLevel() uint32 {
+	if x != nil {
+		return x.Level
 	}
 	return 0
 }
 
-type SetUserDefaultPerConnLimitsRequest struct {
-	state         protoimpl.MessageState     `protogen:"open.v1"`
-	Limits        []*UserDefaultPerConnLimit `protobuf:"bytes,1,rep,name=limits,proto3" json:"limits,omitempty"`
+type GetScheduleResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Schedule      *Schedule              `protobuf:"bytes,1,opt,name=schedule,proto3" json:"schedule,omitempty"`
+	Found         bool                   `protobuf:"varint,2,opt,name=found,proto3" json:"found,omitempty"`
+	ActiveWindow  int32                  `protobuf:"varint,3,opt,name=active_window,json=activeWindow,proto3" json:"active_window,omitempty"` // -1 — сейчас действуют обычные лимиты
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *SetUserDefaultPerConnLimitsRequest) Reset() {
-	*x = SetUserDefaultPerConnLimitsRequest{}
-	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[26]
+func (x *GetScheduleResponse) Reset() {
+	*x = GetScheduleResponse{}
+	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[79]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *SetUserDefaultPerConnLimitsRequest) String() string {
+func (x *GetScheduleResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SetUserDefaultPerConnLimitsRequest) ProtoMessage() {}
+func (*GetScheduleResponse) ProtoMessage() {}
 
-func (x *SetUserDefaultPerConnLimitsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[26]
+func (x *GetScheduleResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[79]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1339,41 +4420,56 @@ func (x *SetUserDefaultPerConnLimitsRequest) ProtoReflect() protoreflect.Message
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SetUserDefaultPerConnLimitsRequest.ProtoReflect.Descriptor instead.
-func (*SetUserDefaultPerConnLimitsRequest) Descriptor() ([]byte, []int) {
-	return file_app_ratelimit_api_ratelimit_proto_rawDescGZIP(), []int{26}
+// Deprecated: Use GetScheduleResponse.ProtoReflect.Descriptor instead.
+func (*GetScheduleResponse) Descriptor() ([]byte, []int) {
+	return file_app_ratelimit_api_ratelimit_proto_rawDescGZIP(), []int{79}
 }
 
-func (x *SetUserDefaultPerConnLimitsRequest) GetLimits() []*UserDefaultPerConnLimit {
+func (x *GetScheduleResponse) GetSchedule() *Schedule {
 	if x != nil {
-		return x.Limits
+		return x.Schedule
 	}
 	return nil
 }
 
-type SetUserDefaultPerConnLimitsResponse struct {
-	state            protoimpl.MessageState `protogen:"open.v1"`
-	Updated          uint32                 `protobuf:"varint,1,opt,name=updated,proto3" json:"updated,omitempty"`
-	ClearedOverrides uint32                 `protobuf:"varint,2,opt,name=cleared_overrides,json=clearedOverrides,proto3" json:"cleared_overrides,omitempty"`
-	unknownFields    protoimpl.UnknownFields
-	sizeCache        protoimpl.SizeCache
+func (x *GetScheduleResponse) GetFound() bool {
+	if x != nil {
+		return x.Found
+	}
+	return false
 }
 
-func (x *SetUserDefaultPerConnLimitsResponse) Reset() {
-	*x = SetUserDefaultPerConnLimitsResponse{}
-	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[27]
+func (x *GetScheduleResponse) GetActiveWindow() int32 {
+	if x != nil {
+		return x.ActiveWindow
+	}
+	return 0
+}
+
+type BucketShape struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	BurstBytes    uint64                 `protobuf:"varint,1,opt,name=burst_bytes,json=burstBytes,proto3" json:"burst_bytes,omitempty"`             // 0 — от rate по burst_ms
+	BurstMs       uint32                 `protobuf:"varint,2,opt,name=burst_ms,json=burstMs,proto3" json:"burst_ms,omitempty"`                      // 0 — 200ms (но не меньше 32KB)
+	InitialBytes  *uint64                `protobuf:"varint,3,opt,name=initial_bytes,json=initialBytes,proto3,oneof" json:"initial_bytes,omitempty"` // не задан — старт с полным burst
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BucketShape) Reset() {
+	*x = BucketShape{}
+	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[80]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *SetUserDefaultPerConnLimitsResponse) String() string {
+func (x *BucketShape) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SetUserDefaultPerConnLimitsResponse) ProtoMessage() {}
+func (*BucketShape) ProtoMessage() {}
 
-func (x *SetUserDefaultPerConnLimitsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[27]
+func (x *BucketShape) ProtoReflect() protoreflect.Message {
+	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[80]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1384,47 +4480,57 @@ func (x *SetUserDefaultPerConnLimitsResponse) ProtoReflect() protoreflect.Messag
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SetUserDefaultPerConnLimitsResponse.ProtoReflect.Descriptor instead.
-func (*SetUserDefaultPerConnLimitsResponse) Descriptor() ([]byte, []int) {
-	return file_app_ratelimit_api_ratelimit_proto_rawDescGZIP(), []int{27}
+// Deprecated: Use BucketShape.ProtoReflect.Descriptor instead.
+func (*BucketShape) Descriptor() ([]byte, []int) {
+	return file_app_ratelimit_api_ratelimit_proto_rawDescGZIP(), []int{80}
 }
 
-func (x *SetUserDefaultPerConnLimitsResponse) GetUpdated() uint32 {
+func (x *BucketShape) GetBurstBytes() uint64 {
 	if x != nil {
-		return x.Updated
+		return x.BurstBytes
 	}
 	return 0
 }
 
-func (x *SetUserDefaultPerConnLimitsResponse) GetClearedOverrides() uint32 {
+func (x *BucketShape) GetBurstMs() uint32 {
 	if x != nil {
-		return x.ClearedOverrides
+		return x.BurstMs
 	}
 	return 0
 }
 
-type ListUserConnectionsRequest struct {
+func (x *BucketShape) GetInitialBytes() uint64 {
+	if x != nil && x.InitialBytes != nil {
+		return *x.InitialBytes
+	}
+	return 0
+}
+
+// Boost: первые bytes байт и/или seconds секунд сессии устройства без
+// лимитов устройства и пользователя. Кончается по тому, что наступит раньше.
+type Boost struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Uuid          string                 `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"`
+	Bytes         uint64                 `protobuf:"varint,1,opt,name=bytes,proto3" json:"bytes,omitempty"`
+	Seconds       uint32                 `protobuf:"varint,2,opt,name=seconds,proto3" json:"seconds,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListUserConnectionsRequest) Reset() {
-	*x = ListUserConnectionsRequest{}
-	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[28]
+func (x *Boost) Reset() {
+	*x = Boost{}
+	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[81]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListUserConnectionsRequest) String() string {
+func (x *Boost) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListUserConnectionsRequest) ProtoMessage() {}
+func (*Boost) ProtoMessage() {}
 
-func (x *ListUserConnectionsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[28]
+func (x *Boost) ProtoReflect() protoreflect.Message {
+	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[81]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1435,40 +4541,51 @@ func (x *ListUserConnectionsRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListUserConnectionsRequest.ProtoReflect.Descriptor instead.
-func (*ListUserConnectionsRequest) Descriptor() ([]byte, []int) {
-	return file_app_ratelimit_api_ratelimit_proto_rawDescGZIP(), []int{28}
+// Deprecated: Use Boost.ProtoReflect.Descriptor instead.
+func (*Boost) Descriptor() ([]byte, []int) {
+	return file_app_ratelimit_api_ratelimit_proto_rawDescGZIP(), []int{81}
 }
 
-func (x *ListUserConnectionsRequest) GetUuid() string {
+func (x *Boost) GetBytes() uint64 {
 	if x != nil {
-		return x.Uuid
+		return x.Bytes
 	}
-	return ""
+	return 0
 }
 
-type ListUserConnectionsResponse struct {
+func (x *Boost) GetSeconds() uint32 {
+	if x != nil {
+		return x.Seconds
+	}
+	return 0
+}
+
+// Незаданное поле у uuid берётся из общих значений, у общих — по умолчанию.
+// Set заменяет запись целиком.
+type LimitShape struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Connections   []*ConnectionInfo      `protobuf:"bytes,1,rep,name=connections,proto3" json:"connections,omitempty"`
+	PerConn       *BucketShape           `protobuf:"bytes,1,opt,name=per_conn,json=perConn,proto3" json:"per_conn,omitempty"`
+	Total         *BucketShape           `protobuf:"bytes,2,opt,name=total,proto3" json:"total,omitempty"`
+	Boost         *Boost                 `protobuf:"bytes,3,opt,name=boost,proto3" json:"boost,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListUserConnectionsResponse) Reset() {
-	*x = ListUserConnectionsResponse{}
-	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[29]
+func (x *LimitShape) Reset() {
+	*x = LimitShape{}
+	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[82]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListUserConnectionsResponse) String() string {
+func (x *LimitShape) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListUserConnectionsResponse) ProtoMessage() {}
+func (*LimitShape) ProtoMessage() {}
 
-func (x *ListUserConnectionsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[29]
+func (x *LimitShape) ProtoReflect() protoreflect.Message {
+	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[82]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1479,42 +4596,55 @@ func (x *ListUserConnectionsResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListUserConnectionsResponse.ProtoReflect.Descriptor instead.
-func (*ListUserConnectionsResponse) Descriptor() ([]byte, []int) {
-	return file_app_ratelimit_api_ratelimit_proto_rawDescGZIP(), []int{29}
+// Deprecated: Use LimitShape.ProtoReflect.Descriptor instead.
+func (*LimitShape) Descriptor() ([]byte, []int) {
+	return file_app_ratelimit_api_ratelimit_proto_rawDescGZIP(), []int{82}
 }
 
-func (x *ListUserConnectionsResponse) GetConnections() []*ConnectionInfo {
+func (x *LimitShape) GetPerConn() *BucketShape {
 	if x != nil {
-		return x.Connections
+		return x.PerConn
 	}
 	return nil
 }
 
-type SetConnectionLimitRequest struct {
+func (x *LimitShape) GetTotal() *BucketShape {
+	if x != nil {
+		return x.Total
+	}
+	return nil
+}
+
+func (x *LimitShape) GetBoost() *Boost {
+	if x != nil {
+		return x.Boost
+	}
+	return nil
+}
+
+type SetBucketShapeRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	ConnId        uint64                 `protobuf:"varint,1,opt,name=conn_id,json=connId,proto3" json:"conn_id,omitempty"`
-	DownBps       uint64                 `protobuf:"varint,2,opt,name=down_bps,json=downBps,proto3" json:"down_bps,omitempty"`
-	UpBps         uint64                 `protobuf:"varint,3,opt,name=up_bps,json=upBps,proto3" json:"up_bps,omitempty"`
+	Uuid          string                 `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"`
+	Shape         *LimitShape            `protobuf:"bytes,2,opt,name=shape,proto3" json:"shape,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *SetConnectionLimitRequest) Reset() {
-	*x = SetConnectionLimitRequest{}
-	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[30]
+func (x *SetBucketShapeRequest) Reset() {
+	*x = SetBucketShapeRequest{}
+	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[83]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *SetConnectionLimitRequest) String() string {
+func (x *SetBucketShapeRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SetConnectionLimitRequest) ProtoMessage() {}
+func (*SetBucketShapeRequest) ProtoMessage() {}
 
-func (x *SetConnectionLimitRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[30]
+func (x *SetBucketShapeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[83]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1525,53 +4655,46 @@ func (x *SetConnectionLimitRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SetConnectionLimitRequest.ProtoReflect.Descriptor instead.
-func (*SetConnectionLimitRequest) Descriptor() ([]byte, []int) {
-	return file_app_ratelimit_api_ratelimit_proto_rawDescGZIP(), []int{30}
-}
-
-func (x *SetConnectionLimitRequest) GetConnId() uint64 {
-	if x != nil {
-		return x.ConnId
-	}
-	return 0
+// Deprecated: Use SetBucketShapeRequest.ProtoReflect.Descriptor instead.
+func (*SetBucketShapeRequest) Descriptor() ([]byte, []int) {
+	return file_app_ratelimit_api_ratelimit_proto_rawDescGZIP(), []int{83}
 }
 
-func (x *SetConnectionLimitRequest) GetDownBps() uint64 {
+func (x *SetBucketShapeRequest) GetUuid() string {
 	if x != nil {
-		return x.DownBps
+		return x.Uuid
 	}
-	return 0
+	return ""
 }
 
-func (x *SetConnectionLimitRequest) GetUpBps() uint64 {
+func (x *SetBucketShapeRequest) GetShape() *LimitShape {
 	if x != nil {
-		return x.UpBps
+		return x.Shape
 	}
-	return 0
+	return nil
 }
 
-type SetConnectionLimitResponse struct {
+type SetBucketShapeResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *SetConnectionLimitResponse) Reset() {
-	*x = SetConnectionLimitResponse{}
-	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[31]
+func (x *SetBucketShapeResponse) Reset() {
+	*x = SetBucketShapeResponse{}
+	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[84]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *SetConnectionLimitResponse) String() string {
+func (x *SetBucketShapeResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SetConnectionLimitResponse) ProtoMessage() {}
+func (*SetBucketShapeResponse) ProtoMessage() {}
 
-func (x *SetConnectionLimitResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[31]
+func (x *SetBucketShapeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[84]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1582,33 +4705,33 @@ func (x *SetConnectionLimitResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SetConnectionLimitResponse.ProtoReflect.Descriptor instead.
-func (*SetConnectionLimitResponse) Descriptor() ([]byte, []int) {
-	return file_app_ratelimit_api_ratelimit_proto_rawDescGZIP(), []int{31}
+// Deprecated: Use SetBucketShapeResponse.ProtoReflect.Descriptor instead.
+func (*SetBucketShapeResponse) Descriptor() ([]byte, []int) {
+	return file_app_ratelimit_api_ratelimit_proto_rawDescGZIP(), []int{84}
 }
 
-type ClearConnectionLimitRequest struct {
+type ClearBucketShapeRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	ConnId        uint64                 `protobuf:"varint,1,opt,name=conn_id,json=connId,proto3" json:"conn_id,omitempty"`
+	Uuid          string                 `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ClearConnectionLimitRequest) Reset() {
-	*x = ClearConnectionLimitRequest{}
-	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[32]
+func (x *ClearBucketShapeRequest) Reset() {
+	*x = ClearBucketShapeRequest{}
+	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[85]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ClearConnectionLimitRequest) String() string {
+func (x *ClearBucketShapeRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ClearConnectionLimitRequest) ProtoMessage() {}
+func (*ClearBucketShapeRequest) ProtoMessage() {}
 
-func (x *ClearConnectionLimitRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[32]
+func (x *ClearBucketShapeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[85]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1619,39 +4742,40 @@ func (x *ClearConnectionLimitRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ClearConnectionLimitRequest.ProtoReflect.Descriptor instead.
-func (*ClearConnectionLimitRequest) Descriptor() ([]byte, []int) {
-	return file_app_ratelimit_api_ratelimit_proto_rawDescGZIP(), []int{32}
+// Deprecated: Use ClearBucketShapeRequest.ProtoReflect.Descriptor instead.
+func (*ClearBucketShapeRequest) Descriptor() ([]byte, []int) {
+	return file_app_ratelimit_api_ratelimit_proto_rawDescGZIP(), []int{85}
 }
 
-func (x *ClearConnectionLimitRequest) GetConnId() uint64 {
+func (x *ClearBucketShapeRequest) GetUuid() string {
 	if x != nil {
-		return x.ConnId
+		return x.Uuid
 	}
-	return 0
+	return ""
 }
 
-type ClearConnectionLimitResponse struct {
+type ClearBucketShapeResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
+	Cleared       bool                   `protobuf:"varint,1,opt,name=cleared,proto3" json:"cleared,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ClearConnectionLimitResponse) Reset() {
-	*x = ClearConnectionLimitResponse{}
-	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[33]
+func (x *ClearBucketShapeResponse) Reset() {
+	*x = ClearBucketShapeResponse{}
+	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[86]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ClearConnectionLimitResponse) String() string {
+func (x *ClearBucketShapeResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ClearConnectionLimitResponse) ProtoMessage() {}
+func (*ClearBucketShapeResponse) ProtoMessage() {}
 
-func (x *ClearConnectionLimitResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[33]
+func (x *ClearBucketShapeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[86]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1662,47 +4786,40 @@ func (x *ClearConnectionLimitResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ClearConnectionLimitResponse.ProtoReflect.Descriptor instead.
-func (*ClearConnectionLimitResponse) Descriptor() ([]byte, []int) {
-	return file_app_ratelimit_api_ratelimit_proto_rawDescGZIP(), []int{33}
+// Deprecated: Use ClearBucketShapeResponse.ProtoReflect.Descriptor instead.
+func (*ClearBucketShapeResponse) Descriptor() ([]byte, []int) {
+	return file_app_ratelimit_api_ratelimit_proto_rawDescGZIP(), []int{86}
 }
 
-type DeviceInfo struct {
-	state protoimpl.MessageState `protogen:"open.v1"`
-	Uuid  string                 `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"`
-	// IP адрес, который сервер видит как Source IP клиента (без порта)
-	SrcIp string `protobuf:"bytes,2,opt,name=src_ip,json=srcIp,proto3" json:"src_ip,omitempty"`
-	// Уникальный ключ "устройства" на сервере: uuid + "|" + src_ip
-	DeviceKey string `protobuf:"bytes,3,opt,name=device_key,json=deviceKey,proto3" json:"device_key,omitempty"`
-	// Внутренний ConnID, к которому привязан лимитер (может меняться между рестартами)
-	ConnId uint64 `protobuf:"varint,4,opt,name=conn_id,json=connId,proto3" json:"conn_id,omitempty"`
-	// Сколько inbound TCP соединений сейчас у этого устройства (refcount)
-	RefCount uint32 `protobuf:"varint,5,opt,name=ref_count,json=refCount,proto3" json:"ref_count,omitempty"`
-	// Момент, когда устройство было создано (первый старт) и последнее событие
-	StartedAtUnix uint64 `protobuf:"varint,6,opt,name=started_at_unix,json=startedAtUnix,proto3" json:"started_at_unix,omitempty"`
-	LastSeenUnix  uint64 `protobuf:"varint,7,opt,name=last_seen_unix,json=lastSeenUnix,proto3" json:"last_seen_unix,omitempty"`
-	// Статистика трафика
-	RxBytes       uint64 `protobuf:"varint,8,opt,name=rx_bytes,json=rxBytes,proto3" json:"rx_bytes,omitempty"`
-	TxBytes       uint64 `protobuf:"varint,9,opt,name=tx_bytes,json=txBytes,proto3" json:"tx_bytes,omitempty"`
+func (x *ClearBucketShapeResponse) GetCleared() bool {
+	if x != nil {
+		return x.Cleared
+	}
+	return false
+}
+
+type GetBucketShapeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Uuid          string                 `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *DeviceInfo) Reset() {
-	*x = DeviceInfo{}
-	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[34]
+func (x *GetBucketShapeRequest) Reset() {
+	*x = GetBucketShapeRequest{}
+	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[87]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *DeviceInfo) String() string {
+func (x *GetBucketShapeRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DeviceInfo) ProtoMessage() {}
+func (*GetBucketShapeRequest) ProtoMessage() {}
 
-func (x *DeviceInfo) ProtoReflect() protoreflect.Message {
-	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[34]
+func (x *GetBucketShapeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[87]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1713,95 +4830,101 @@ func (x *DeviceInfo) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DeviceInfo.ProtoReflect.Descriptor instead.
-func (*DeviceInfo) Descriptor() ([]byte, []int) {
-	return file_app_ratelimit_api_ratelimit_proto_rawDescGZIP(), []int{34}
+// Deprecated: Use GetBucketShapeRequest.ProtoReflect.Descriptor instead.
+func (*GetBucketShapeRequest) Descriptor() ([]byte, []int) {
+	return file_app_ratelimit_api_ratelimit_proto_rawDescGZIP(), []int{87}
 }
 
-func (x *DeviceInfo) GetUuid() string {
+func (x *GetBucketShapeRequest) GetUuid() string {
 	if x != nil {
 		return x.Uuid
 	}
 	return ""
 }
 
-func (x *DeviceInfo) GetSrcIp() string {
-	if x != nil {
-		return x.SrcIp
-	}
-	return ""
+type GetBucketShapeResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Shape         *LimitShape            `protobuf:"bytes,1,opt,name=shape,proto3" json:"shape,omitempty"` // задано для uuid (или общее, если uuid пуст)
+	Found         bool                   `protobuf:"varint,2,opt,name=found,proto3" json:"found,omitempty"`
+	Effective     *LimitShape            `protobuf:"bytes,3,opt,name=effective,proto3" json:"effective,omitempty"` // действует для uuid
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *DeviceInfo) GetDeviceKey() string {
-	if x != nil {
-		return x.DeviceKey
-	}
-	return ""
+func (x *GetBucketShapeResponse) Reset() {
+	*x = GetBucketShapeResponse{}
+	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[88]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
-func (x *DeviceInfo) GetConnId() uint64 {
-	if x != nil {
-		return x.ConnId
-	}
-	return 0
+func (x *GetBucketShapeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-func (x *DeviceInfo) GetRefCount() uint32 {
+func (*GetBucketShapeResponse) ProtoMessage() {}
+
+func (x *GetBucketShapeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[88]
 	if x != nil {
-		return x.RefCount
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return 0
+	return mi.MessageOf(x)
 }
 
-func (x *DeviceInfo) GetStartedAtUnix() uint64 {
-	if x != nil {
-		return x.StartedAtUnix
-	}
-	return 0
+// Deprecated: Use GetBucketShapeResponse.ProtoReflect.Descriptor instead.
+func (*GetBucketShapeResponse) Descriptor() ([]byte, []int) {
+	return file_app_ratelimit_api_ratelimit_proto_rawDescGZIP(), []int{88}
 }
 
-func (x *DeviceInfo) GetLastSeenUnix() uint64 {
+func (x *GetBucketShapeResponse) GetShape() *LimitShape {
 	if x != nil {
-		return x.LastSeenUnix
+		return x.Shape
 	}
-	return 0
+	return nil
 }
 
-func (x *DeviceInfo) GetRxBytes() uint64 {
+func (x *GetBucketShapeResponse) GetFound() bool {
 	if x != nil {
-		return x.RxBytes
+		return x.Found
 	}
-	return 0
+	return false
 }
 
-func (x *DeviceInfo) GetTxBytes() uint64 {
+func (x *GetBucketShapeResponse) GetEffective() *LimitShape {
 	if x != nil {
-		return x.TxBytes
+		return x.Effective
 	}
-	return 0
+	return nil
 }
 
-type GetActiveDevicesSnapshotRequest struct {
+type SetEgressBindingRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
+	Uuid          string                 `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"`
+	OutboundTag   string                 `protobuf:"bytes,2,opt,name=outbound_tag,json=outboundTag,proto3" json:"outbound_tag,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetActiveDevicesSnapshotRequest) Reset() {
-	*x = GetActiveDevicesSnapshotRequest{}
-	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[35]
+func (x *SetEgressBindingRequest) Reset() {
+	*x = SetEgressBindingRequest{}
+	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[89]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetActiveDevicesSnapshotRequest) String() string {
+func (x *SetEgressBindingRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetActiveDevicesSnapshotRequest) ProtoMessage() {}
+func (*SetEgressBindingRequest) ProtoMessage() {}
 
-func (x *GetActiveDevicesSnapshotRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[35]
+func (x *SetEgressBindingRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[89]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1812,33 +4935,46 @@ func (x *GetActiveDevicesSnapshotRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetActiveDevicesSnapshotRequest.ProtoReflect.Descriptor instead.
-func (*GetActiveDevicesSnapshotRequest) Descriptor() ([]byte, []int) {
-	return file_app_ratelimit_api_ratelimit_proto_rawDescGZIP(), []int{35}
+// Deprecated: Use SetEgressBindingRequest.ProtoReflect.Descriptor instead.
+func (*SetEgressBindingRequest) Descriptor() ([]byte, []int) {
+	return file_app_ratelimit_api_ratelimit_proto_rawDescGZIP(), []int{89}
 }
 
-type GetActiveDevicesSnapshotResponse struct {
+func (x *SetEgressBindingRequest) GetUuid() string {
+	if x != nil {
+		return x.Uuid
+	}
+	return ""
+}
+
+func (x *SetEgressBindingRequest) GetOutboundTag() string {
+	if x != nil {
+		return x.OutboundTag
+	}
+	return ""
+}
+
+type SetEgressBindingResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Devices       []*DeviceInfo          `protobuf:"bytes,1,rep,name=devices,proto3" json:"devices,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetActiveDevicesSnapshotResponse) Reset() {
-	*x = GetActiveDevicesSnapshotResponse{}
-	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[36]
+func (x *SetEgressBindingResponse) Reset() {
+	*x = SetEgressBindingResponse{}
+	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[90]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetActiveDevicesSnapshotResponse) String() string {
+func (x *SetEgressBindingResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetActiveDevicesSnapshotResponse) ProtoMessage() {}
+func (*SetEgressBindingResponse) ProtoMessage() {}
 
-func (x *GetActiveDevicesSnapshotResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[36]
+func (x *SetEgressBindingResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[90]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1849,40 +4985,33 @@ func (x *GetActiveDevicesSnapshotResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetActiveDevicesSnapshotResponse.ProtoReflect.Descriptor instead.
-func (*GetActiveDevicesSnapshotResponse) Descriptor() ([]byte, []int) {
-	return file_app_ratelimit_api_ratelimit_proto_rawDescGZIP(), []int{36}
-}
-
-func (x *GetActiveDevicesSnapshotResponse) GetDevices() []*DeviceInfo {
-	if x != nil {
-		return x.Devices
-	}
-	return nil
+// Deprecated: Use SetEgressBindingResponse.ProtoReflect.Descriptor instead.
+func (*SetEgressBindingResponse) Descriptor() ([]byte, []int) {
+	return file_app_ratelimit_api_ratelimit_proto_rawDescGZIP(), []int{90}
 }
 
-type ListUserDevicesRequest struct {
+type ClearEgressBindingRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Uuid          string                 `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListUserDevicesRequest) Reset() {
-	*x = ListUserDevicesRequest{}
-	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[37]
+func (x *ClearEgressBindingRequest) Reset() {
+	*x = ClearEgressBindingRequest{}
+	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[91]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListUserDevicesRequest) String() string {
+func (x *ClearEgressBindingRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListUserDevicesRequest) ProtoMessage() {}
+func (*ClearEgressBindingRequest) ProtoMessage() {}
 
-func (x *ListUserDevicesRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[37]
+func (x *ClearEgressBindingRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[91]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1893,40 +5022,40 @@ func (x *ListUserDevicesRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListUserDevicesRequest.ProtoReflect.Descriptor instead.
-func (*ListUserDevicesRequest) Descriptor() ([]byte, []int) {
-	return file_app_ratelimit_api_ratelimit_proto_rawDescGZIP(), []int{37}
+// Deprecated: Use ClearEgressBindingRequest.ProtoReflect.Descriptor instead.
+func (*ClearEgressBindingRequest) Descriptor() ([]byte, []int) {
+	return file_app_ratelimit_api_ratelimit_proto_rawDescGZIP(), []int{91}
 }
 
-func (x *ListUserDevicesRequest) GetUuid() string {
+func (x *ClearEgressBindingRequest) GetUuid() string {
 	if x != nil {
 		return x.Uuid
 	}
 	return ""
 }
 
-type ListUserDevicesResponse struct {
+type ClearEgressBindingResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Devices       []*DeviceInfo          `protobuf:"bytes,1,rep,name=devices,proto3" json:"devices,omitempty"`
+	Cleared       bool                   `protobuf:"varint,1,opt,name=cleared,proto3" json:"cleared,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListUserDevicesResponse) Reset() {
-	*x = ListUserDevicesResponse{}
-	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[38]
+func (x *ClearEgressBindingResponse) Reset() {
+	*x = ClearEgressBindingResponse{}
+	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[92]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListUserDevicesResponse) String() string {
+func (x *ClearEgressBindingResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListUserDevicesResponse) ProtoMessage() {}
+func (*ClearEgressBindingResponse) ProtoMessage() {}
 
-func (x *ListUserDevicesResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[38]
+func (x *ClearEgressBindingResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[92]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1937,42 +5066,41 @@ func (x *ListUserDevicesResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListUserDevicesResponse.ProtoReflect.Descriptor instead.
-func (*ListUserDevicesResponse) Descriptor() ([]byte, []int) {
-	return file_app_ratelimit_api_ratelimit_proto_rawDescGZIP(), []int{38}
+// Deprecated: Use ClearEgressBindingResponse.ProtoReflect.Descriptor instead.
+func (*ClearEgressBindingResponse) Descriptor() ([]byte, []int) {
+	return file_app_ratelimit_api_ratelimit_proto_rawDescGZIP(), []int{92}
 }
 
-func (x *ListUserDevicesResponse) GetDevices() []*DeviceInfo {
+func (x *ClearEgressBindingResponse) GetCleared() bool {
 	if x != nil {
-		return x.Devices
+		return x.Cleared
 	}
-	return nil
+	return false
 }
 
-type SetDeviceLimitRequest struct {
+type EgressBinding struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	DeviceKey     string                 `protobuf:"bytes,1,opt,name=device_key,json=deviceKey,proto3" json:"device_key,omitempty"`
-	DownBps       uint64                 `protobuf:"varint,2,opt,name=down_bps,json=downBps,proto3" json:"down_bps,omitempty"`
-	UpBps         uint64                 `protobuf:"varint,3,opt,name=up_bps,json=upBps,proto3" json:"up_bps,omitempty"`
+	Uuid          string                 `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"`
+	OutboundTag   string                 `protobuf:"bytes,2,opt,name=outbound_tag,json=outboundTag,proto3" json:"outbound_tag,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *SetDeviceLimitRequest) Reset() {
-	*x = SetDeviceLimitRequest{}
-	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[39]
+func (x *EgressBinding) Reset() {
+	*x = EgressBinding{}
+	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[93]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *SetDeviceLimitRequest) String() string {
+func (x *EgressBinding) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SetDeviceLimitRequest) ProtoMessage() {}
+func (*EgressBinding) ProtoMessage() {}
 
-func (x *SetDeviceLimitRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[39]
+func (x *EgressBinding) ProtoReflect() protoreflect.Message {
+	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[93]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1983,53 +5111,46 @@ func (x *SetDeviceLimitRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SetDeviceLimitRequest.ProtoReflect.Descriptor instead.
-func (*SetDeviceLimitRequest) Descriptor() ([]byte, []int) {
-	return file_app_ratelimit_api_ratelimit_proto_rawDescGZIP(), []int{39}
+// Deprecated: Use EgressBinding.ProtoReflect.Descriptor instead.
+func (*EgressBinding) Descriptor() ([]byte, []int) {
+	return file_app_ratelimit_api_ratelimit_proto_rawDescGZIP(), []int{93}
 }
 
-func (x *SetDeviceLimitRequest) GetDeviceKey() string {
+func (x *EgressBinding) GetUuid() string {
 	if x != nil {
-		return x.DeviceKey
+		return x.Uuid
 	}
 	return ""
 }
 
-func (x *SetDeviceLimitRequest) GetDownBps() uint64 {
-	if x != nil {
-		return x.DownBps
-	}
-	return 0
-}
-
-func (x *SetDeviceLimitRequest) GetUpBps() uint64 {
+func (x *EgressBinding) GetOutboundTag() string {
 	if x != nil {
-		return x.UpBps
+		return x.OutboundTag
 	}
-	return 0
+	return ""
 }
 
-type SetDeviceLimitResponse struct {
+type ListEgressBindingsRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *SetDeviceLimitResponse) Reset() {
-	*x = SetDeviceLimitResponse{}
-	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[40]
+func (x *ListEgressBindingsRequest) Reset() {
+	*x = ListEgressBindingsRequest{}
+	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[94]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *SetDeviceLimitResponse) String() string {
+func (x *ListEgressBindingsRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SetDeviceLimitResponse) ProtoMessage() {}
+func (*ListEgressBindingsRequest) ProtoMessage() {}
 
-func (x *SetDeviceLimitResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[40]
+func (x *ListEgressBindingsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[94]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2040,33 +5161,37 @@ func (x *SetDeviceLimitResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SetDeviceLimitResponse.ProtoReflect.Descriptor instead.
-func (*SetDeviceLimitResponse) Descriptor() ([]byte, []int) {
-	return file_app_ratelimit_api_ratelimit_proto_rawDescGZIP(), []int{40}
+// Deprecated: Use ListEgressBindingsRequest.ProtoReflect.Descriptor instead.
+func (*ListEgressBindingsRequest) Descriptor() ([]byte, []int) {
+	return file_app_ratelimit_api_ratelimit_proto_rawDescGZIP(), []int{94}
 }
 
-type ClearDeviceLimitRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	DeviceKey     string                 `protobuf:"bytes,1,opt,name=device_key,json=deviceKey,proto3" json:"device_key,omitempty"`
+type ListEgressBindingsResponse struct {
+	state         protoimpl.MessageState              `protogen:"open.v1"`
+	Bindings      []*EgressBinding                    `protobuf:"bytes,1,rep,name=bindings,proto3" json:"bindings,omitempty"`
+	Strategy      ListEgressBindingsResponse_Strategy `protobuf:"varint,2,opt,name=strategy,proto3,enum=ratelimit.v1.ListEgressBindingsResponse_Strategy" json:"strategy,omitempty"`
+	Outbounds     []string                            `protobuf:"bytes,3,rep,name=outbounds,proto3" json:"outbounds,omitempty"`
+	Weights       map[string]uint32                   `protobuf:"bytes,4,rep,name=weights,proto3" json:"weights,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`
+	HealthCheck   bool                                `protobuf:"varint,5,opt,name=health_check,json=healthCheck,proto3" json:"health_check,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ClearDeviceLimitRequest) Reset() {
-	*x = ClearDeviceLimitRequest{}
-	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[41]
+func (x *ListEgressBindingsResponse) Reset() {
+	*x = ListEgressBindingsResponse{}
+	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[95]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ClearDeviceLimitRequest) String() string {
+func (x *ListEgressBindingsResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ClearDeviceLimitRequest) ProtoMessage() {}
+func (*ListEgressBindingsResponse) ProtoMessage() {}
 
-func (x *ClearDeviceLimitRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[41]
+func (x *ListEgressBindingsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[95]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2077,52 +5202,44 @@ func (x *ClearDeviceLimitRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ClearDeviceLimitRequest.ProtoReflect.Descriptor instead.
-func (*ClearDeviceLimitRequest) Descriptor() ([]byte, []int) {
-	return file_app_ratelimit_api_ratelimit_proto_rawDescGZIP(), []int{41}
+// Deprecated: Use ListEgressBindingsResponse.ProtoReflect.Descriptor instead.
+func (*ListEgressBindingsResponse) Descriptor() ([]byte, []int) {
+	return file_app_ratelimit_api_ratelimit_proto_rawDescGZIP(), []int{95}
 }
 
-func (x *ClearDeviceLimitRequest) GetDeviceKey() string {
+func (x *ListEgressBindingsResponse) GetBindings() []*EgressBinding {
 	if x != nil {
-		return x.DeviceKey
+		return x.Bindings
 	}
-	return ""
-}
-
-type ClearDeviceLimitResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	return nil
 }
 
-func (x *ClearDeviceLimitResponse) Reset() {
-	*x = ClearDeviceLimitResponse{}
-	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[42]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
+func (x *ListEgressBindingsResponse) GetStrategy() ListEgressBindingsResponse_Strategy {
+	if x != nil {
+		return x.Strategy
+	}
+	return ListEgressBindingsResponse_MAP_ONLY
 }
 
-func (x *ClearDeviceLimitResponse) String() string {
-	return protoimpl.X.MessageStringOf(x)
+func (x *ListEgressBindingsResponse) GetOutbounds() []string {
+	if x != nil {
+		return x.Outbounds
+	}
+	return nil
 }
 
-func (*ClearDeviceLimitResponse) ProtoMessage() {}
-
-func (x *ClearDeviceLimitResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_app_ratelimit_api_ratelimit_proto_msgTypes[42]
+func (x *ListEgressBindingsResponse) GetWeights() map[string]uint32 {
 	if x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+		return x.Weights
 	}
-	return mi.MessageOf(x)
+	return nil
 }
 
-// Deprecated: Use ClearDeviceLimitResponse.ProtoReflect.Descriptor instead.
-func (*ClearDeviceLimitResponse) Descriptor() ([]byte, []int) {
-	return file_app_ratelimit_api_ratelimit_proto_rawDescGZIP(), []int{42}
+func (x *ListEgressBindingsResponse) GetHealthCheck() bool {
+	if x != nil {
+		return x.HealthCheck
+	}
+	return false
 }
 
 var File_app_ratelimit_api_ratelimit_proto protoreflect.FileDescriptor
@@ -2178,11 +5295,36 @@ const file_app_ratelimit_api_ratelimit_proto_rawDesc = "" +
 	"\x18SetUserTotalLimitRequest\x12\x12\n" +
 	"\x04uuid\x18\x01 \x01(\tR\x04uuid\x12\x19\n" +
 	"\bdown_bps\x18\x02 \x01(\x04R\adownBps\x12\x15\n" +
-	"\x06up_bps\x18\x03 \x01(\x04R\x05upBps\"\x98\x01\n" +
+	"\x06up_bps\x18\x03 \x01(\x04R\x05upBps\"\xe8\x01\n" +
 	"\x19SetUserTotalLimitResponse\x12!\n" +
-	"\fdevice_count\x18\x01 \x01(\rR\vdeviceCount\x12-\n" +
-	"\x13per_device_down_bps\x18\x02 \x01(\x04R\x10perDeviceDownBps\x12)\n" +
-	"\x11per_device_up_bps\x18\x03 \x01(\x04R\x0eperDeviceUpBps\"\xad\x01\n" +
+	"\fdevice_count\x18\x01 \x01(\rR\vdeviceCount\x121\n" +
+	"\x13per_device_down_bps\x18\x02 \x01(\x04B\x02\x18\x01R\x10perDeviceDownBps\x12-\n" +
+	"\x11per_device_up_bps\x18\x03 \x01(\x04B\x02\x18\x01R\x0eperDeviceUpBps\x12$\n" +
+	"\x0etotal_down_bps\x18\x04 \x01(\x04R\ftotalDownBps\x12 \n" +
+	"\ftotal_up_bps\x18\x05 \x01(\x04R\n" +
+	"totalUpBps\"0\n" +
+	"\x1aClearUserTotalLimitRequest\x12\x12\n" +
+	"\x04uuid\x18\x01 \x01(\tR\x04uuid\"7\n" +
+	"\x1bClearUserTotalLimitResponse\x12\x18\n" +
+	"\acleared\x18\x01 \x01(\bR\acleared\"p\n" +
+	"\x1bSetInboundTotalLimitRequest\x12\x1f\n" +
+	"\vinbound_tag\x18\x01 \x01(\tR\n" +
+	"inboundTag\x12\x19\n" +
+	"\bdown_bps\x18\x02 \x01(\x04R\adownBps\x12\x15\n" +
+	"\x06up_bps\x18\x03 \x01(\x04R\x05upBps\"\x1e\n" +
+	"\x1cSetInboundTotalLimitResponse\"@\n" +
+	"\x1dClearInboundTotalLimitRequest\x12\x1f\n" +
+	"\vinbound_tag\x18\x01 \x01(\tR\n" +
+	"inboundTag\":\n" +
+	"\x1eClearInboundTotalLimitResponse\x12\x18\n" +
+	"\acleared\x18\x01 \x01(\bR\acleared\"N\n" +
+	"\x1aSetGlobalTotalLimitRequest\x12\x19\n" +
+	"\bdown_bps\x18\x01 \x01(\x04R\adownBps\x12\x15\n" +
+	"\x06up_bps\x18\x02 \x01(\x04R\x05upBps\"\x1d\n" +
+	"\x1bSetGlobalTotalLimitResponse\"\x1e\n" +
+	"\x1cClearGlobalTotalLimitRequest\"9\n" +
+	"\x1dClearGlobalTotalLimitResponse\x12\x18\n" +
+	"\acleared\x18\x01 \x01(\bR\acleared\"\xad\x01\n" +
 	"\x0eConnectionInfo\x12\x17\n" +
 	"\aconn_id\x18\x01 \x01(\x04R\x06connId\x12&\n" +
 	"\x0fstarted_at_unix\x18\x02 \x01(\x04R\rstartedAtUnix\x12$\n" +
@@ -2243,7 +5385,194 @@ const file_app_ratelimit_api_ratelimit_proto_rawDesc = "" +
 	"\x17ClearDeviceLimitRequest\x12\x1d\n" +
 	"\n" +
 	"device_key\x18\x01 \x01(\tR\tdeviceKey\"\x1a\n" +
-	"\x18ClearDeviceLimitResponse2\xb9\x11\n" +
+	"\x18ClearDeviceLimitResponse\"\xa4\x03\n" +
+	"\x05Quota\x122\n" +
+	"\x06period\x18\x01 \x01(\x0e2\x1a.ratelimit.v1.Quota.PeriodR\x06period\x12;\n" +
+	"\tdirection\x18\x02 \x01(\x0e2\x1d.ratelimit.v1.Quota.DirectionR\tdirection\x12\x1f\n" +
+	"\vlimit_bytes\x18\x03 \x01(\x04R\n" +
+	"limitBytes\x122\n" +
+	"\x06action\x18\x04 \x01(\x0e2\x1a.ratelimit.v1.Quota.ActionR\x06action\x12(\n" +
+	"\x10penalty_down_bps\x18\x05 \x01(\x04R\x0epenaltyDownBps\x12$\n" +
+	"\x0epenalty_up_bps\x18\x06 \x01(\x04R\fpenaltyUpBps\".\n" +
+	"\x06Period\x12\f\n" +
+	"\bABSOLUTE\x10\x00\x12\t\n" +
+	"\x05DAILY\x10\x01\x12\v\n" +
+	"\aMONTHLY\x10\x02\"(\n" +
+	"\tDirection\x12\t\n" +
+	"\x05TOTAL\x10\x00\x12\x06\n" +
+	"\x02UP\x10\x01\x12\b\n" +
+	"\x04DOWN\x10\x02\"+\n" +
+	"\x06Action\x12\t\n" +
+	"\x05BLOCK\x10\x00\x12\t\n" +
+	"\x05CLOSE\x10\x01\x12\v\n" +
+	"\aPENALTY\x10\x02\"T\n" +
+	"\x13SetUserQuotaRequest\x12\x12\n" +
+	"\x04uuid\x18\x01 \x01(\tR\x04uuid\x12)\n" +
+	"\x05quota\x18\x02 \x01(\v2\x13.ratelimit.v1.QuotaR\x05quota\"\x16\n" +
+	"\x14SetUserQuotaResponse\"+\n" +
+	"\x15ClearUserQuotaRequest\x12\x12\n" +
+	"\x04uuid\x18\x01 \x01(\tR\x04uuid\"2\n" +
+	"\x16ClearUserQuotaResponse\x12\x18\n" +
+	"\acleared\x18\x01 \x01(\bR\acleared\")\n" +
+	"\x13GetUserQuotaRequest\x12\x12\n" +
+	"\x04uuid\x18\x01 \x01(\tR\x04uuid\"\xd7\x01\n" +
+	"\x14GetUserQuotaResponse\x12\x12\n" +
+	"\x04uuid\x18\x01 \x01(\tR\x04uuid\x12)\n" +
+	"\x05quota\x18\x02 \x01(\v2\x13.ratelimit.v1.QuotaR\x05quota\x12\x19\n" +
+	"\bup_bytes\x18\x03 \x01(\x04R\aupBytes\x12\x1d\n" +
+	"\n" +
+	"down_bytes\x18\x04 \x01(\x04R\tdownBytes\x12*\n" +
+	"\x11period_start_unix\x18\x05 \x01(\x04R\x0fperiodStartUnix\x12\x1a\n" +
+	"\bexceeded\x18\x06 \x01(\bR\bexceeded\"0\n" +
+	"\x1aResetUserQuotaUsageRequest\x12\x12\n" +
+	"\x04uuid\x18\x01 \x01(\tR\x04uuid\"3\n" +
+	"\x1bResetUserQuotaUsageResponse\x12\x14\n" +
+	"\x05found\x18\x01 \x01(\bR\x05found\"\x94\x01\n" +
+	"\n" +
+	"MaxDevices\x12\x10\n" +
+	"\x03max\x18\x01 \x01(\rR\x03max\x127\n" +
+	"\x06policy\x18\x02 \x01(\x0e2\x1f.ratelimit.v1.MaxDevices.PolicyR\x06policy\";\n" +
+	"\x06Policy\x12\x11\n" +
+	"\rREJECT_NEWEST\x10\x00\x12\x10\n" +
+	"\fEVICT_OLDEST\x10\x01\x12\f\n" +
+	"\bLOG_ONLY\x10\x02\"Z\n" +
+	"\x14SetMaxDevicesRequest\x12\x12\n" +
+	"\x04uuid\x18\x01 \x01(\tR\x04uuid\x12.\n" +
+	"\x05limit\x18\x02 \x01(\v2\x18.ratelimit.v1.MaxDevicesR\x05limit\"\x17\n" +
+	"\x15SetMaxDevicesResponse\",\n" +
+	"\x16ClearMaxDevicesRequest\x12\x12\n" +
+	"\x04uuid\x18\x01 \x01(\tR\x04uuid\"3\n" +
+	"\x17ClearMaxDevicesResponse\x12\x18\n" +
+	"\acleared\x18\x01 \x01(\bR\acleared\"*\n" +
+	"\x14GetMaxDevicesRequest\x12\x12\n" +
+	"\x04uuid\x18\x01 \x01(\tR\x04uuid\"\x85\x02\n" +
+	"\x15GetMaxDevicesResponse\x12\x12\n" +
+	"\x04uuid\x18\x01 \x01(\tR\x04uuid\x12.\n" +
+	"\x05limit\x18\x02 \x01(\v2\x18.ratelimit.v1.MaxDevicesR\x05limit\x12\x14\n" +
+	"\x05found\x18\x03 \x01(\bR\x05found\x12\x19\n" +
+	"\bper_user\x18\x04 \x01(\bR\aperUser\x12%\n" +
+	"\x0eactive_devices\x18\x05 \x01(\rR\ractiveDevices\x12\x1a\n" +
+	"\brejected\x18\x06 \x01(\x04R\brejected\x12\x18\n" +
+	"\aevicted\x18\a \x01(\x04R\aevicted\x12\x1a\n" +
+	"\bexceeded\x18\b \x01(\x04R\bexceeded\"2\n" +
+	"\x1cSubscribeDeviceEventsRequest\x12\x12\n" +
+	"\x04uuid\x18\x01 \x01(\tR\x04uuid\"\x98\x04\n" +
+	"\vDeviceEvent\x122\n" +
+	"\x04type\x18\x01 \x01(\x0e2\x1e.ratelimit.v1.DeviceEvent.TypeR\x04type\x12 \n" +
+	"\ftime_unix_ms\x18\x02 \x01(\x04R\n" +
+	"timeUnixMs\x12\x12\n" +
+	"\x04uuid\x18\x03 \x01(\tR\x04uuid\x12\x1d\n" +
+	"\n" +
+	"device_key\x18\x04 \x01(\tR\tdeviceKey\x12\x17\n" +
+	"\aconn_id\x18\x05 \x01(\x04R\x06connId\x12E\n" +
+	"\vlimit_scope\x18\x06 \x01(\x0e2$.ratelimit.v1.DeviceEvent.LimitScopeR\n" +
+	"limitScope\x12\x19\n" +
+	"\bdown_bps\x18\a \x01(\x04R\adownBps\x12\x15\n" +
+	"\x06up_bps\x18\b \x01(\x04R\x05upBps\x12#\n" +
+	"\rlimit_cleared\x18\t \x01(\bR\flimitCleared\x12\x1d\n" +
+	"\n" +
+	"egress_tag\x18\n" +
+	" \x01(\tR\tegressTag\"n\n" +
+	"\x04Type\x12\v\n" +
+	"\aSTARTED\x10\x00\x12\t\n" +
+	"\x05GRACE\x10\x01\x12\v\n" +
+	"\aRESUMED\x10\x02\x12\r\n" +
+	"\tDESTROYED\x10\x03\x12\v\n" +
+	"\aEVICTED\x10\x04\x12\x11\n" +
+	"\rLIMIT_CHANGED\x10\x05\x12\x12\n" +
+	"\x0eEGRESS_CHANGED\x10\x06\":\n" +
+	"\n" +
+	"LimitScope\x12\n" +
+	"\n" +
+	"\x06DEVICE\x10\x00\x12\x10\n" +
+	"\fUSER_DEFAULT\x10\x01\x12\x0e\n" +
+	"\n" +
+	"USER_TOTAL\x10\x02\"8\n" +
+	"\x04Rate\x12\x19\n" +
+	"\bdown_bps\x18\x01 \x01(\x04R\adownBps\x12\x15\n" +
+	"\x06up_bps\x18\x02 \x01(\x04R\x05upBps\"\xbf\x01\n" +
+	"\x0eScheduleWindow\x12\x12\n" +
+	"\x04days\x18\x01 \x03(\rR\x04days\x12!\n" +
+	"\fstart_minute\x18\x02 \x01(\rR\vstartMinute\x12\x1d\n" +
+	"\n" +
+	"end_minute\x18\x03 \x01(\rR\tendMinute\x12-\n" +
+	"\bper_conn\x18\x04 \x01(\v2\x12.ratelimit.v1.RateR\aperConn\x12(\n" +
+	"\x05total\x18\x05 \x01(\v2\x12.ratelimit.v1.RateR\x05total\"_\n" +
+	"\bSchedule\x12\x1b\n" +
+	"\ttime_zone\x18\x01 \x01(\tR\btimeZone\x126\n" +
+	"\awindows\x18\x02 \x03(\v2\x1c.ratelimit.v1.ScheduleWindowR\awindows\"r\n" +
+	"\x12SetScheduleRequest\x12\x12\n" +
+	"\x04uuid\x18\x01 \x01(\tR\x04uuid\x12\x14\n" +
+	"\x05level\x18\x02 \x01(\rR\x05level\x122\n" +
+	"\bschedule\x18\x03 \x01(\v2\x16.ratelimit.v1.ScheduleR\bschedule\"\x15\n" +
+	"\x13SetScheduleResponse\"@\n" +
+	"\x14ClearScheduleRequest\x12\x12\n" +
+	"\x04uuid\x18\x01 \x01(\tR\x04uuid\x12\x14\n" +
+	"\x05level\x18\x02 \x01(\rR\x05level\"1\n" +
+	"\x15ClearScheduleResponse\x12\x18\n" +
+	"\acleared\x18\x01 \x01(\bR\acleared\">\n" +
+	"\x12GetScheduleRequest\x12\x12\n" +
+	"\x04uuid\x18\x01 \x01(\tR\x04uuid\x12\x14\n" +
+	"\x05level\x18\x02 \x01(\rR\x05level\"\x84\x01\n" +
+	"\x13GetScheduleResponse\x122\n" +
+	"\bschedule\x18\x01 \x01(\v2\x16.ratelimit.v1.ScheduleR\bschedule\x12\x14\n" +
+	"\x05found\x18\x02 \x01(\bR\x05found\x12#\n" +
+	"\ractive_window\x18\x03 \x01(\x05R\factiveWindow\"\x85\x01\n" +
+	"\vBucketShape\x12\x1f\n" +
+	"\vburst_bytes\x18\x01 \x01(\x04R\n" +
+	"burstBytes\x12\x19\n" +
+	"\bburst_ms\x18\x02 \x01(\rR\aburstMs\x12(\n" +
+	"\rinitial_bytes\x18\x03 \x01(\x04H\x00R\finitialBytes\x88\x01\x01B\x10\n" +
+	"\x0e_initial_bytes\"7\n" +
+	"\x05Boost\x12\x14\n" +
+	"\x05bytes\x18\x01 \x01(\x04R\x05bytes\x12\x18\n" +
+	"\aseconds\x18\x02 \x01(\rR\aseconds\"\x9e\x01\n" +
+	"\n" +
+	"LimitShape\x124\n" +
+	"\bper_conn\x18\x01 \x01(\v2\x19.ratelimit.v1.BucketShapeR\aperConn\x12/\n" +
+	"\x05total\x18\x02 \x01(\v2\x19.ratelimit.v1.BucketShapeR\x05total\x12)\n" +
+	"\x05boost\x18\x03 \x01(\v2\x13.ratelimit.v1.BoostR\x05boost\"[\n" +
+	"\x15SetBucketShapeRequest\x12\x12\n" +
+	"\x04uuid\x18\x01 \x01(\tR\x04uuid\x12.\n" +
+	"\x05shape\x18\x02 \x01(\v2\x18.ratelimit.v1.LimitShapeR\x05shape\"\x18\n" +
+	"\x16SetBucketShapeResponse\"-\n" +
+	"\x17ClearBucketShapeRequest\x12\x12\n" +
+	"\x04uuid\x18\x01 \x01(\tR\x04uuid\"4\n" +
+	"\x18ClearBucketShapeResponse\x12\x18\n" +
+	"\acleared\x18\x01 \x01(\bR\acleared\"+\n" +
+	"\x15GetBucketShapeRequest\x12\x12\n" +
+	"\x04uuid\x18\x01 \x01(\tR\x04uuid\"\x96\x01\n" +
+	"\x16GetBucketShapeResponse\x12.\n" +
+	"\x05shape\x18\x01 \x01(\v2\x18.ratelimit.v1.LimitShapeR\x05shape\x12\x14\n" +
+	"\x05found\x18\x02 \x01(\bR\x05found\x126\n" +
+	"\teffective\x18\x03 \x01(\v2\x18.ratelimit.v1.LimitShapeR\teffective\"P\n" +
+	"\x17SetEgressBindingRequest\x12\x12\n" +
+	"\x04uuid\x18\x01 \x01(\tR\x04uuid\x12!\n" +
+	"\foutbound_tag\x18\x02 \x01(\tR\voutboundTag\"\x1a\n" +
+	"\x18SetEgressBindingResponse\"/\n" +
+	"\x19ClearEgressBindingRequest\x12\x12\n" +
+	"\x04uuid\x18\x01 \x01(\tR\x04uuid\"6\n" +
+	"\x1aClearEgressBindingResponse\x12\x18\n" +
+	"\acleared\x18\x01 \x01(\bR\acleared\"F\n" +
+	"\rEgressBinding\x12\x12\n" +
+	"\x04uuid\x18\x01 \x01(\tR\x04uuid\x12!\n" +
+	"\foutbound_tag\x18\x02 \x01(\tR\voutboundTag\"\x1b\n" +
+	"\x19ListEgressBindingsRequest\"\xb5\x03\n" +
+	"\x1aListEgressBindingsResponse\x127\n" +
+	"\bbindings\x18\x01 \x03(\v2\x1b.ratelimit.v1.EgressBindingR\bbindings\x12M\n" +
+	"\bstrategy\x18\x02 \x01(\x0e21.ratelimit.v1.ListEgressBindingsResponse.StrategyR\bstrategy\x12\x1c\n" +
+	"\toutbounds\x18\x03 \x03(\tR\toutbounds\x12O\n" +
+	"\aweights\x18\x04 \x03(\v25.ratelimit.v1.ListEgressBindingsResponse.WeightsEntryR\aweights\x12!\n" +
+	"\fhealth_check\x18\x05 \x01(\bR\vhealthCheck\x1a:\n" +
+	"\fWeightsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\rR\x05value:\x028\x01\"A\n" +
+	"\bStrategy\x12\f\n" +
+	"\bMAP_ONLY\x10\x00\x12\b\n" +
+	"\x04HASH\x10\x01\x12\x11\n" +
+	"\rLEAST_DEVICES\x10\x02\x12\n" +
+	"\n" +
+	"\x06RANDOM\x10\x032\xa8\"\n" +
 	"\x10RateLimitService\x12\x7f\n" +
 	"\x1aSetUserDefaultPerConnLimit\x12/.ratelimit.v1.SetUserDefaultPerConnLimitRequest\x1a0.ratelimit.v1.SetUserDefaultPerConnLimitResponse\x12j\n" +
 	"\x13ListUserConnections\x12(.ratelimit.v1.ListUserConnectionsRequest\x1a).ratelimit.v1.ListUserConnectionsResponse\x12g\n" +
@@ -2254,7 +5583,29 @@ const file_app_ratelimit_api_ratelimit_proto_rawDesc = "" +
 	"\x0fListUserDevices\x12$.ratelimit.v1.ListUserDevicesRequest\x1a%.ratelimit.v1.ListUserDevicesResponse\x12[\n" +
 	"\x0eSetDeviceLimit\x12#.ratelimit.v1.SetDeviceLimitRequest\x1a$.ratelimit.v1.SetDeviceLimitResponse\x12a\n" +
 	"\x10ClearDeviceLimit\x12%.ratelimit.v1.ClearDeviceLimitRequest\x1a&.ratelimit.v1.ClearDeviceLimitResponse\x12d\n" +
-	"\x11SetUserTotalLimit\x12&.ratelimit.v1.SetUserTotalLimitRequest\x1a'.ratelimit.v1.SetUserTotalLimitResponse\x12U\n" +
+	"\x11SetUserTotalLimit\x12&.ratelimit.v1.SetUserTotalLimitRequest\x1a'.ratelimit.v1.SetUserTotalLimitResponse\x12j\n" +
+	"\x13ClearUserTotalLimit\x12(.ratelimit.v1.ClearUserTotalLimitRequest\x1a).ratelimit.v1.ClearUserTotalLimitResponse\x12m\n" +
+	"\x14SetInboundTotalLimit\x12).ratelimit.v1.SetInboundTotalLimitRequest\x1a*.ratelimit.v1.SetInboundTotalLimitResponse\x12s\n" +
+	"\x16ClearInboundTotalLimit\x12+.ratelimit.v1.ClearInboundTotalLimitRequest\x1a,.ratelimit.v1.ClearInboundTotalLimitResponse\x12j\n" +
+	"\x13SetGlobalTotalLimit\x12(.ratelimit.v1.SetGlobalTotalLimitRequest\x1a).ratelimit.v1.SetGlobalTotalLimitResponse\x12p\n" +
+	"\x15ClearGlobalTotalLimit\x12*.ratelimit.v1.ClearGlobalTotalLimitRequest\x1a+.ratelimit.v1.ClearGlobalTotalLimitResponse\x12U\n" +
+	"\fSetUserQuota\x12!.ratelimit.v1.SetUserQuotaRequest\x1a\".ratelimit.v1.SetUserQuotaResponse\x12[\n" +
+	"\x0eClearUserQuota\x12#.ratelimit.v1.ClearUserQuotaRequest\x1a$.ratelimit.v1.ClearUserQuotaResponse\x12U\n" +
+	"\fGetUserQuota\x12!.ratelimit.v1.GetUserQuotaRequest\x1a\".ratelimit.v1.GetUserQuotaResponse\x12j\n" +
+	"\x13ResetUserQuotaUsage\x12(.ratelimit.v1.ResetUserQuotaUsageRequest\x1a).ratelimit.v1.ResetUserQuotaUsageResponse\x12X\n" +
+	"\rSetMaxDevices\x12\".ratelimit.v1.SetMaxDevicesRequest\x1a#.ratelimit.v1.SetMaxDevicesResponse\x12^\n" +
+	"\x0fClearMaxDevices\x12$.ratelimit.v1.ClearMaxDevicesRequest\x1a%.ratelimit.v1.ClearMaxDevicesResponse\x12X\n" +
+	"\rGetMaxDevices\x12\".ratelimit.v1.GetMaxDevicesRequest\x1a#.ratelimit.v1.GetMaxDevicesResponse\x12R\n" +
+	"\vSetSchedule\x12 .ratelimit.v1.SetScheduleRequest\x1a!.ratelimit.v1.SetScheduleResponse\x12X\n" +
+	"\rClearSchedule\x12\".ratelimit.v1.ClearScheduleRequest\x1a#.ratelimit.v1.ClearScheduleResponse\x12R\n" +
+	"\vGetSchedule\x12 .ratelimit.v1.GetScheduleRequest\x1a!.ratelimit.v1.GetScheduleResponse\x12[\n" +
+	"\x0eSetBucketShape\x12#.ratelimit.v1.SetBucketShapeRequest\x1a$.ratelimit.v1.SetBucketShapeResponse\x12a\n" +
+	"\x10ClearBucketShape\x12%.ratelimit.v1.ClearBucketShapeRequest\x1a&.ratelimit.v1.ClearBucketShapeResponse\x12[\n" +
+	"\x0eGetBucketShape\x12#.ratelimit.v1.GetBucketShapeRequest\x1a$.ratelimit.v1.GetBucketShapeResponse\x12a\n" +
+	"\x10SetEgressBinding\x12%.ratelimit.v1.SetEgressBindingRequest\x1a&.ratelimit.v1.SetEgressBindingResponse\x12g\n" +
+	"\x12ClearEgressBinding\x12'.ratelimit.v1.ClearEgressBindingRequest\x1a(.ratelimit.v1.ClearEgressBindingResponse\x12g\n" +
+	"\x12ListEgressBindings\x12'.ratelimit.v1.ListEgressBindingsRequest\x1a(.ratelimit.v1.ListEgressBindingsResponse\x12`\n" +
+	"\x15SubscribeDeviceEvents\x12*.ratelimit.v1.SubscribeDeviceEventsRequest\x1a\x19.ratelimit.v1.DeviceEvent0\x01\x12U\n" +
 	"\fGetUserStats\x12!.ratelimit.v1.GetUserStatsRequest\x1a\".ratelimit.v1.GetUserStatsResponse\x12O\n" +
 	"\n" +
 	"SetKeyMode\x12\x1f.ratelimit.v1.SetKeyModeRequest\x1a .ratelimit.v1.SetKeyModeResponse\x12O\n" +
@@ -2281,108 +5632,237 @@ func file_app_ratelimit_api_ratelimit_proto_rawDescGZIP() []byte {
 	return file_app_ratelimit_api_ratelimit_proto_rawDescData
 }
 
-var file_app_ratelimit_api_ratelimit_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_app_ratelimit_api_ratelimit_proto_msgTypes = make([]protoimpl.MessageInfo, 43)
+var file_app_ratelimit_api_ratelimit_proto_enumTypes = make([]protoimpl.EnumInfo, 8)
+var file_app_ratelimit_api_ratelimit_proto_msgTypes = make([]protoimpl.MessageInfo, 97)
 var file_app_ratelimit_api_ratelimit_proto_goTypes = []any{
 	(SetKeyModeRequest_Mode)(0),                  // 0: ratelimit.v1.SetKeyModeRequest.Mode
-	(*ClearAllRateLimitsRequest)(nil),            // 1: ratelimit.v1.ClearAllRateLimitsRequest
-	(*ClearAllRateLimitsResponse)(nil),           // 2: ratelimit.v1.ClearAllRateLimitsResponse
-	(*ClearUserRateLimitsRequest)(nil),           // 3: ratelimit.v1.ClearUserRateLimitsRequest
-	(*ClearUserRateLimitsResponse)(nil),          // 4: ratelimit.v1.ClearUserRateLimitsResponse
-	(*ClearUserConnOverrideLimitsRequest)(nil),   // 5: ratelimit.v1.ClearUserConnOverrideLimitsRequest
-	(*ClearUserConnOverrideLimitsResponse)(nil),  // 6: ratelimit.v1.ClearUserConnOverrideLimitsResponse
-	(*ClearUserDefaultPerConnLimitRequest)(nil),  // 7: ratelimit.v1.ClearUserDefaultPerConnLimitRequest
-	(*ClearUserDefaultPerConnLimitResponse)(nil), // 8: ratelimit.v1.ClearUserDefaultPerConnLimitResponse
-	(*ClearUserEgressCacheRequest)(nil),          // 9: ratelimit.v1.ClearUserEgressCacheRequest
-	(*ClearUserEgressCacheResponse)(nil),         // 10: ratelimit.v1.ClearUserEgressCacheResponse
-	(*SetGraceRequest)(nil),                      // 11: ratelimit.v1.SetGraceRequest
-	(*SetGraceResponse)(nil),                     // 12: ratelimit.v1.SetGraceResponse
-	(*GetGraceRequest)(nil),                      // 13: ratelimit.v1.GetGraceRequest
-	(*GetGraceResponse)(nil),                     // 14: ratelimit.v1.GetGraceResponse
-	(*SetKeyModeRequest)(nil),                    // 15: ratelimit.v1.SetKeyModeRequest
-	(*SetKeyModeResponse)(nil),                   // 16: ratelimit.v1.SetKeyModeResponse
-	(*GetKeyModeRequest)(nil),                    // 17: ratelimit.v1.GetKeyModeRequest
-	(*GetKeyModeResponse)(nil),                   // 18: ratelimit.v1.GetKeyModeResponse
-	(*GetUserStatsRequest)(nil),                  // 19: ratelimit.v1.GetUserStatsRequest
-	(*GetUserStatsResponse)(nil),                 // 20: ratelimit.v1.GetUserStatsResponse
-	(*SetUserTotalLimitRequest)(nil),             // 21: ratelimit.v1.SetUserTotalLimitRequest
-	(*SetUserTotalLimitResponse)(nil),            // 22: ratelimit.v1.SetUserTotalLimitResponse
-	(*ConnectionInfo)(nil),                       // 23: ratelimit.v1.ConnectionInfo
-	(*SetUserDefaultPerConnLimitRequest)(nil),    // 24: ratelimit.v1.SetUserDefaultPerConnLimitRequest
-	(*SetUserDefaultPerConnLimitResponse)(nil),   // 25: ratelimit.v1.SetUserDefaultPerConnLimitResponse
-	(*UserDefaultPerConnLimit)(nil),              // 26: ratelimit.v1.UserDefaultPerConnLimit
-	(*SetUserDefaultPerConnLimitsRequest)(nil),   // 27: ratelimit.v1.SetUserDefaultPerConnLimitsRequest
-	(*SetUserDefaultPerConnLimitsResponse)(nil),  // 28: ratelimit.v1.SetUserDefaultPerConnLimitsResponse
-	(*ListUserConnectionsRequest)(nil),           // 29: ratelimit.v1.ListUserConnectionsRequest
-	(*ListUserConnectionsResponse)(nil),          // 30: ratelimit.v1.ListUserConnectionsResponse
-	(*SetConnectionLimitRequest)(nil),            // 31: ratelimit.v1.SetConnectionLimitRequest
-	(*SetConnectionLimitResponse)(nil),           // 32: ratelimit.v1.SetConnectionLimitResponse
-	(*ClearConnectionLimitRequest)(nil),          // 33: ratelimit.v1.ClearConnectionLimitRequest
-	(*ClearConnectionLimitResponse)(nil),         // 34: ratelimit.v1.ClearConnectionLimitResponse
-	(*DeviceInfo)(nil),                           // 35: ratelimit.v1.DeviceInfo
-	(*GetActiveDevicesSnapshotRequest)(nil),      // 36: ratelimit.v1.GetActiveDevicesSnapshotRequest
-	(*GetActiveDevicesSnapshotResponse)(nil),     // 37: ratelimit.v1.GetActiveDevicesSnapshotResponse
-	(*ListUserDevicesRequest)(nil),               // 38: ratelimit.v1.ListUserDevicesRequest
-	(*ListUserDevicesResponse)(nil),              // 39: ratelimit.v1.ListUserDevicesResponse
-	(*SetDeviceLimitRequest)(nil),                // 40: ratelimit.v1.SetDeviceLimitRequest
-	(*SetDeviceLimitResponse)(nil),               // 41: ratelimit.v1.SetDeviceLimitResponse
-	(*ClearDeviceLimitRequest)(nil),              // 42: ratelimit.v1.ClearDeviceLimitRequest
-	(*ClearDeviceLimitResponse)(nil),             // 43: ratelimit.v1.ClearDeviceLimitResponse
+	(Quota_Period)(0),                            // 1: ratelimit.v1.Quota.Period
+	(Quota_Direction)(0),                         // 2: ratelimit.v1.Quota.Direction
+	(Quota_Action)(0),                            // 3: ratelimit.v1.Quota.Action
+	(MaxDevices_Policy)(0),                       // 4: ratelimit.v1.MaxDevices.Policy
+	(DeviceEvent_Type)(0),                        // 5: ratelimit.v1.DeviceEvent.Type
+	(DeviceEvent_LimitScope)(0),                  // 6: ratelimit.v1.DeviceEvent.LimitScope
+	(ListEgressBindingsResponse_Strategy)(0),     // 7: ratelimit.v1.ListEgressBindingsResponse.Strategy
+	(*ClearAllRateLimitsRequest)(nil),            // 8: ratelimit.v1.ClearAllRateLimitsRequest
+	(*ClearAllRateLimitsResponse)(nil),           // 9: ratelimit.v1.ClearAllRateLimitsResponse
+	(*ClearUserRateLimitsRequest)(nil),           // 10: ratelimit.v1.ClearUserRateLimitsRequest
+	(*ClearUserRateLimitsResponse)(nil),          // 11: ratelimit.v1.ClearUserRateLimitsResponse
+	(*ClearUserConnOverrideLimitsRequest)(nil),   // 12: ratelimit.v1.ClearUserConnOverrideLimitsRequest
+	(*ClearUserConnOverrideLimitsResponse)(nil),  // 13: ratelimit.v1.ClearUserConnOverrideLimitsResponse
+	(*ClearUserDefaultPerConnLimitRequest)(nil),  // 14: ratelimit.v1.ClearUserDefaultPerConnLimitRequest
+	(*ClearUserDefaultPerConnLimitResponse)(nil), // 15: ratelimit.v1.ClearUserDefaultPerConnLimitResponse
+	(*ClearUserEgressCacheRequest)(nil),          // 16: ratelimit.v1.ClearUserEgressCacheRequest
+	(*ClearUserEgressCacheResponse)(nil),         // 17: ratelimit.v1.ClearUserEgressCacheResponse
+	(*SetGraceRequest)(nil),                      // 18: ratelimit.v1.SetGraceRequest
+	(*SetGraceResponse)(nil),                     // 19: ratelimit.v1.SetGraceResponse
+	(*GetGraceRequest)(nil),                      // 20: ratelimit.v1.GetGraceRequest
+	(*GetGraceResponse)(nil),                     // 21: ratelimit.v1.GetGraceResponse
+	(*SetKeyModeRequest)(nil),                    // 22: ratelimit.v1.SetKeyModeRequest
+	(*SetKeyModeResponse)(nil),                   // 23: ratelimit.v1.SetKeyModeResponse
+	(*GetKeyModeRequest)(nil),                    // 24: ratelimit.v1.GetKeyModeRequest
+	(*GetKeyModeResponse)(nil),                   // 25: ratelimit.v1.GetKeyModeResponse
+	(*GetUserStatsRequest)(nil),                  // 26: ratelimit.v1.GetUserStatsRequest
+	(*GetUserStatsResponse)(nil),                 // 27: ratelimit.v1.GetUserStatsResponse
+	(*SetUserTotalLimitRequest)(nil),             // 28: ratelimit.v1.SetUserTotalLimitRequest
+	(*SetUserTotalLimitResponse)(nil),            // 29: ratelimit.v1.SetUserTotalLimitResponse
+	(*ClearUserTotalLimitRequest)(nil),           // 30: ratelimit.v1.ClearUserTotalLimitRequest
+	(*ClearUserTotalLimitResponse)(nil),          // 31: ratelimit.v1.ClearUserTotalLimitResponse
+	(*SetInboundTotalLimitRequest)(nil),          // 32: ratelimit.v1.SetInboundTotalLimitRequest
+	(*SetInboundTotalLimitResponse)(nil),         // 33: ratelimit.v1.SetInboundTotalLimitResponse
+	(*ClearInboundTotalLimitRequest)(nil),        // 34: ratelimit.v1.ClearInboundTotalLimitRequest
+	(*ClearInboundTotalLimitResponse)(nil),       // 35: ratelimit.v1.ClearInboundTotalLimitResponse
+	(*SetGlobalTotalLimitRequest)(nil),           // 36: ratelimit.v1.SetGlobalTotalLimitRequest
+	(*SetGlobalTotalLimitResponse)(nil),          // 37: ratelimit.v1.SetGlobalTotalLimitResponse
+	(*ClearGlobalTotalLimitRequest)(nil),         // 38: ratelimit.v1.ClearGlobalTotalLimitRequest
+	(*ClearGlobalTotalLimitResponse)(nil),        // 39: ratelimit.v1.ClearGlobalTotalLimitResponse
+	(*ConnectionInfo)(nil),                       // 40: ratelimit.v1.ConnectionInfo
+	(*SetUserDefaultPerConnLimitRequest)(nil),    // 41: ratelimit.v1.SetUserDefaultPerConnLimitRequest
+	(*SetUserDefaultPerConnLimitResponse)(nil),   // 42: ratelimit.v1.SetUserDefaultPerConnLimitResponse
+	(*UserDefaultPerConnLimit)(nil),              // 43: ratelimit.v1.UserDefaultPerConnLimit
+	(*SetUserDefaultPerConnLimitsRequest)(nil),   // 44: ratelimit.v1.SetUserDefaultPerConnLimitsRequest
+	(*SetUserDefaultPerConnLimitsResponse)(nil),  // 45: ratelimit.v1.SetUserDefaultPerConnLimitsResponse
+	(*ListUserConnectionsRequest)(nil),           // 46: ratelimit.v1.ListUserConnectionsRequest
+	(*ListUserConnectionsResponse)(nil),          // 47: ratelimit.v1.ListUserConnectionsResponse
+	(*SetConnectionLimitRequest)(nil),            // 48: ratelimit.v1.SetConnectionLimitRequest
+	(*SetConnectionLimitResponse)(nil),           // 49: ratelimit.v1.SetConnectionLimitResponse
+	(*ClearConnectionLimitRequest)(nil),          // 50: ratelimit.v1.ClearConnectionLimitRequest
+	(*ClearConnectionLimitResponse)(nil),         // 51: ratelimit.v1.ClearConnectionLimitResponse
+	(*DeviceInfo)(nil),                           // 52: ratelimit.v1.DeviceInfo
+	(*GetActiveDevicesSnapshotRequest)(nil),      // 53: ratelimit.v1.GetActiveDevicesSnapshotRequest
+	(*GetActiveDevicesSnapshotResponse)(nil),     // 54: ratelimit.v1.GetActiveDevicesSnapshotResponse
+	(*ListUserDevicesRequest)(nil),               // 55: ratelimit.v1.ListUserDevicesRequest
+	(*ListUserDevicesResponse)(nil),              // 56: ratelimit.v1.ListUserDevicesResponse
+	(*SetDeviceLimitRequest)(nil),                // 57: ratelimit.v1.SetDeviceLimitRequest
+	(*SetDeviceLimitResponse)(nil),               // 58: ratelimit.v1.SetDeviceLimitResponse
+	(*ClearDeviceLimitRequest)(nil),              // 59: ratelimit.v1.ClearDeviceLimitRequest
+	(*ClearDeviceLimitResponse)(nil),             // 60: ratelimit.v1.ClearDeviceLimitResponse
+	(*Quota)(nil),                                // 61: ratelimit.v1.Quota
+	(*SetUserQuotaRequest)(nil),                  // 62: ratelimit.v1.SetUserQuotaRequest
+	(*SetUserQuotaResponse)(nil),                 // 63: ratelimit.v1.SetUserQuotaResponse
+	(*ClearUserQuotaRequest)(nil),                // 64: ratelimit.v1.ClearUserQuotaRequest
+	(*ClearUserQuotaResponse)(nil),               // 65: ratelimit.v1.ClearUserQuotaResponse
+	(*GetUserQuotaRequest)(nil),                  // 66: ratelimit.v1.GetUserQuotaRequest
+	(*GetUserQuotaResponse)(nil),                 // 67: ratelimit.v1.GetUserQuotaResponse
+	(*ResetUserQuotaUsageRequest)(nil),           // 68: ratelimit.v1.ResetUserQuotaUsageRequest
+	(*ResetUserQuotaUsageResponse)(nil),          // 69: ratelimit.v1.ResetUserQuotaUsageResponse
+	(*MaxDevices)(nil),                           // 70: ratelimit.v1.MaxDevices
+	(*SetMaxDevicesRequest)(nil),                 // 71: ratelimit.v1.SetMaxDevicesRequest
+	(*SetMaxDevicesResponse)(nil),                // 72: ratelimit.v1.SetMaxDevicesResponse
+	(*ClearMaxDevicesRequest)(nil),               // 73: ratelimit.v1.ClearMaxDevicesRequest
+	(*ClearMaxDevicesResponse)(nil),              // 74: ratelimit.v1.ClearMaxDevicesResponse
+	(*GetMaxDevicesRequest)(nil),                 // 75: ratelimit.v1.GetMaxDevicesRequest
+	(*GetMaxDevicesResponse)(nil),                // 76: ratelimit.v1.GetMaxDevicesResponse
+	(*SubscribeDeviceEventsRequest)(nil),         // 77: ratelimit.v1.SubscribeDeviceEventsRequest
+	(*DeviceEvent)(nil),                          // 78: ratelimit.v1.DeviceEvent
+	(*Rate)(nil),                                 // 79: ratelimit.v1.Rate
+	(*ScheduleWindow)(nil),                       // 80: ratelimit.v1.ScheduleWindow
+	(*Schedule)(nil),                             // 81: ratelimit.v1.Schedule
+	(*SetScheduleRequest)(nil),                   // 82: ratelimit.v1.SetScheduleRequest
+	(*SetScheduleResponse)(nil),                  // 83: ratelimit.v1.SetScheduleResponse
+	(*ClearScheduleRequest)(nil),                 // 84: ratelimit.v1.ClearScheduleRequest
+	(*ClearScheduleResponse)(nil),                // 85: ratelimit.v1.ClearScheduleResponse
+	(*GetScheduleRequest)(nil),                   // 86: ratelimit.v1.GetScheduleRequest
+	(*GetScheduleResponse)(nil),                  // 87: ratelimit.v1.GetScheduleResponse
+	(*BucketShape)(nil),                          // 88: ratelimit.v1.BucketShape
+	(*Boost)(nil),                                // 89: ratelimit.v1.Boost
+	(*LimitShape)(nil),                           // 90: ratelimit.v1.LimitShape
+	(*SetBucketShapeRequest)(nil),                // 91: ratelimit.v1.SetBucketShapeRequest
+	(*SetBucketShapeResponse)(nil),               // 92: ratelimit.v1.SetBucketShapeResponse
+	(*ClearBucketShapeRequest)(nil),              // 93: ratelimit.v1.ClearBucketShapeRequest
+	(*ClearBucketShapeResponse)(nil),             // 94: ratelimit.v1.ClearBucketShapeResponse
+	(*GetBucketShapeRequest)(nil),                // 95: ratelimit.v1.GetBucketShapeRequest
+	(*GetBucketShapeResponse)(nil),               // 96: ratelimit.v1.GetBucketShapeResponse
+	(*SetEgressBindingRequest)(nil),              // 97: ratelimit.v1.SetEgressBindingRequest
+	(*SetEgressBindingResponse)(nil),             // 98: ratelimit.v1.SetEgressBindingResponse
+	(*ClearEgressBindingRequest)(nil),            // 99: ratelimit.v1.ClearEgressBindingRequest
+	(*ClearEgressBindingResponse)(nil),           // 100: ratelimit.v1.ClearEgressBindingResponse
+	(*EgressBinding)(nil),                        // 101: ratelimit.v1.EgressBinding
+	(*ListEgressBindingsRequest)(nil),            // 102: ratelimit.v1.ListEgressBindingsRequest
+	(*ListEgressBindingsResponse)(nil),           // 103: ratelimit.v1.ListEgressBindingsResponse
+	nil,                                          // 104: ratelimit.v1.ListEgressBindingsResponse.WeightsEntry
 }
 var file_app_ratelimit_api_ratelimit_proto_depIdxs = []int32{
-	0,  // 0: ratelimit.v1.SetKeyModeRequest.mode:type_name -> ratelimit.v1.SetKeyModeRequest.Mode
-	0,  // 1: ratelimit.v1.GetKeyModeResponse.mode:type_name -> ratelimit.v1.SetKeyModeRequest.Mode
-	26, // 2: ratelimit.v1.SetUserDefaultPerConnLimitsRequest.limits:type_name -> ratelimit.v1.UserDefaultPerConnLimit
-	23, // 3: ratelimit.v1.ListUserConnectionsResponse.connections:type_name -> ratelimit.v1.ConnectionInfo
-	35, // 4: ratelimit.v1.GetActiveDevicesSnapshotResponse.devices:type_name -> ratelimit.v1.DeviceInfo
-	35, // 5: ratelimit.v1.ListUserDevicesResponse.devices:type_name -> ratelimit.v1.DeviceInfo
-	24, // 6: ratelimit.v1.RateLimitService.SetUserDefaultPerConnLimit:input_type -> ratelimit.v1.SetUserDefaultPerConnLimitRequest
-	29, // 7: ratelimit.v1.RateLimitService.ListUserConnections:input_type -> ratelimit.v1.ListUserConnectionsRequest
-	31, // 8: ratelimit.v1.RateLimitService.SetConnectionLimit:input_type -> ratelimit.v1.SetConnectionLimitRequest
-	33, // 9: ratelimit.v1.RateLimitService.ClearConnectionLimit:input_type -> ratelimit.v1.ClearConnectionLimitRequest
-	36, // 10: ratelimit.v1.RateLimitService.GetActiveDevicesSnapshot:input_type -> ratelimit.v1.GetActiveDevicesSnapshotRequest
-	36, // 11: ratelimit.v1.RateLimitService.PeekActiveDevicesSnapshot:input_type -> ratelimit.v1.GetActiveDevicesSnapshotRequest
-	38, // 12: ratelimit.v1.RateLimitService.ListUserDevices:input_type -> ratelimit.v1.ListUserDevicesRequest
-	40, // 13: ratelimit.v1.RateLimitService.SetDeviceLimit:input_type -> ratelimit.v1.SetDeviceLimitRequest
-	42, // 14: ratelimit.v1.RateLimitService.ClearDeviceLimit:input_type -> ratelimit.v1.ClearDeviceLimitRequest
-	21, // 15: ratelimit.v1.RateLimitService.SetUserTotalLimit:input_type -> ratelimit.v1.SetUserTotalLimitRequest
-	19, // 16: ratelimit.v1.RateLimitService.GetUserStats:input_type -> ratelimit.v1.GetUserStatsRequest
-	15, // 17: ratelimit.v1.RateLimitService.SetKeyMode:input_type -> ratelimit.v1.SetKeyModeRequest
-	17, // 18: ratelimit.v1.RateLimitService.GetKeyMode:input_type -> ratelimit.v1.GetKeyModeRequest
-	11, // 19: ratelimit.v1.RateLimitService.SetGrace:input_type -> ratelimit.v1.SetGraceRequest
-	13, // 20: ratelimit.v1.RateLimitService.GetGrace:input_type -> ratelimit.v1.GetGraceRequest
-	9,  // 21: ratelimit.v1.RateLimitService.ClearUserEgressCache:input_type -> ratelimit.v1.ClearUserEgressCacheRequest
-	7,  // 22: ratelimit.v1.RateLimitService.ClearUserDefaultPerConnLimit:input_type -> ratelimit.v1.ClearUserDefaultPerConnLimitRequest
-	5,  // 23: ratelimit.v1.RateLimitService.ClearUserConnOverrideLimits:input_type -> ratelimit.v1.ClearUserConnOverrideLimitsRequest
-	1,  // 24: ratelimit.v1.RateLimitService.ClearAllRateLimits:input_type -> ratelimit.v1.ClearAllRateLimitsRequest
-	3,  // 25: ratelimit.v1.RateLimitService.ClearUserRateLimits:input_type -> ratelimit.v1.ClearUserRateLimitsRequest
-	27, // 26: ratelimit.v1.RateLimitService.SetUserDefaultPerConnLimits:input_type -> ratelimit.v1.SetUserDefaultPerConnLimitsRequest
-	25, // 27: ratelimit.v1.RateLimitService.SetUserDefaultPerConnLimit:output_type -> ratelimit.v1.SetUserDefaultPerConnLimitResponse
-	30, // 28: ratelimit.v1.RateLimitService.ListUserConnections:output_type -> ratelimit.v1.ListUserConnectionsResponse
-	32, // 29: ratelimit.v1.RateLimitService.SetConnectionLimit:output_type -> ratelimit.v1.SetConnectionLimitResponse
-	34, // 30: ratelimit.v1.RateLimitService.ClearConnectionLimit:output_type -> ratelimit.v1.ClearConnectionLimitResponse
-	37, // 31: ratelimit.v1.RateLimitService.GetActiveDevicesSnapshot:output_type -> ratelimit.v1.GetActiveDevicesSnapshotResponse
-	37, // 32: ratelimit.v1.RateLimitService.PeekActiveDevicesSnapshot:output_type -> ratelimit.v1.GetActiveDevicesSnapshotResponse
-	39, // 33: ratelimit.v1.RateLimitService.ListUserDevices:output_type -> ratelimit.v1.ListUserDevicesResponse
-	41, // 34: ratelimit.v1.RateLimitService.SetDeviceLimit:output_type -> ratelimit.v1.SetDeviceLimitResponse
-	43, // 35: ratelimit.v1.RateLimitService.ClearDeviceLimit:output_type -> ratelimit.v1.ClearDeviceLimitResponse
-	22, // 36: ratelimit.v1.RateLimitService.SetUserTotalLimit:output_type -> ratelimit.v1.SetUserTotalLimitResponse
-	20, // 37: ratelimit.v1.RateLimitService.GetUserStats:output_type -> ratelimit.v1.GetUserStatsResponse
-	16, // 38: ratelimit.v1.RateLimitService.SetKeyMode:output_type -> ratelimit.v1.SetKeyModeResponse
-	18, // 39: ratelimit.v1.RateLimitService.GetKeyMode:output_type -> ratelimit.v1.GetKeyModeResponse
-	12, // 40: ratelimit.v1.RateLimitService.SetGrace:output_type -> ratelimit.v1.SetGraceResponse
-	14, // 41: ratelimit.v1.RateLimitService.GetGrace:output_type -> ratelimit.v1.GetGraceResponse
-	10, // 42: ratelimit.v1.RateLimitService.ClearUserEgressCache:output_type -> ratelimit.v1.ClearUserEgressCacheResponse
-	8,  // 43: ratelimit.v1.RateLimitService.ClearUserDefaultPerConnLimit:output_type -> ratelimit.v1.ClearUserDefaultPerConnLimitResponse
-	6,  // 44: ratelimit.v1.RateLimitService.ClearUserConnOverrideLimits:output_type -> ratelimit.v1.ClearUserConnOverrideLimitsResponse
-	2,  // 45: ratelimit.v1.RateLimitService.ClearAllRateLimits:output_type -> ratelimit.v1.ClearAllRateLimitsResponse
-	4,  // 46: ratelimit.v1.RateLimitService.ClearUserRateLimits:output_type -> ratelimit.v1.ClearUserRateLimitsResponse
-	28, // 47: ratelimit.v1.RateLimitService.SetUserDefaultPerConnLimits:output_type -> ratelimit.v1.SetUserDefaultPerConnLimitsResponse
-	27, // [27:48] is the sub-list for method output_type
-	6,  // [6:27] is the sub-list for method input_type
-	6,  // [6:6] is the sub-list for extension type_name
-	6,  // [6:6] is the sub-list for extension extendee
-	0,  // [0:6] is the sub-list for field type_name
+	0,   // 0: ratelimit.v1.SetKeyModeRequest.mode:type_name -> ratelimit.v1.SetKeyModeRequest.Mode
+	0,   // 1: ratelimit.v1.GetKeyModeResponse.mode:type_name -> ratelimit.v1.SetKeyModeRequest.Mode
+	43,  // 2: ratelimit.v1.SetUserDefaultPerConnLimitsRequest.limits:type_name -> ratelimit.v1.UserDefaultPerConnLimit
+	40,  // 3: ratelimit.v1.ListUserConnectionsResponse.connections:type_name -> ratelimit.v1.ConnectionInfo
+	52,  // 4: ratelimit.v1.GetActiveDevicesSnapshotResponse.devices:type_name -> ratelimit.v1.DeviceInfo
+	52,  // 5: ratelimit.v1.ListUserDevicesResponse.devices:type_name -> ratelimit.v1.DeviceInfo
+	1,   // 6: ratelimit.v1.Quota.period:type_name -> ratelimit.v1.Quota.Period
+	2,   // 7: ratelimit.v1.Quota.direction:type_name -> ratelimit.v1.Quota.Direction
+	3,   // 8: ratelimit.v1.Quota.action:type_name -> ratelimit.v1.Quota.Action
+	61,  // 9: ratelimit.v1.SetUserQuotaRequest.quota:type_name -> ratelimit.v1.Quota
+	61,  // 10: ratelimit.v1.GetUserQuotaResponse.quota:type_name -> ratelimit.v1.Quota
+	4,   // 11: ratelimit.v1.MaxDevices.policy:type_name -> ratelimit.v1.MaxDevices.Policy
+	70,  // 12: ratelimit.v1.SetMaxDevicesRequest.limit:type_name -> ratelimit.v1.MaxDevices
+	70,  // 13: ratelimit.v1.GetMaxDevicesResponse.limit:type_name -> ratelimit.v1.MaxDevices
+	5,   // 14: ratelimit.v1.DeviceEvent.type:type_name -> ratelimit.v1.DeviceEvent.Type
+	6,   // 15: ratelimit.v1.DeviceEvent.limit_scope:type_name -> ratelimit.v1.DeviceEvent.LimitScope
+	79,  // 16: ratelimit.v1.ScheduleWindow.per_conn:type_name -> ratelimit.v1.Rate
+	79,  // 17: ratelimit.v1.ScheduleWindow.total:type_name -> ratelimit.v1.Rate
+	80,  // 18: ratelimit.v1.Schedule.windows:type_name -> ratelimit.v1.ScheduleWindow
+	81,  // 19: ratelimit.v1.SetScheduleRequest.schedule:type_name -> ratelimit.v1.Schedule
+	81,  // 20: ratelimit.v1.GetScheduleResponse.schedule:type_name -> ratelimit.v1.Schedule
+	88,  // 21: ratelimit.v1.LimitShape.per_conn:type_name -> ratelimit.v1.BucketShape
+	88,  // 22: ratelimit.v1.LimitShape.total:type_name -> ratelimit.v1.BucketShape
+	89,  // 23: ratelimit.v1.LimitShape.boost:type_name -> ratelimit.v1.Boost
+	90,  // 24: ratelimit.v1.SetBucketShapeRequest.shape:type_name -> ratelimit.v1.LimitShape
+	90,  // 25: ratelimit.v1.GetBucketShapeResponse.shape:type_name -> ratelimit.v1.LimitShape
+	90,  // 26: ratelimit.v1.GetBucketShapeResponse.effective:type_name -> ratelimit.v1.LimitShape
+	101, // 27: ratelimit.v1.ListEgressBindingsResponse.bindings:type_name -> ratelimit.v1.EgressBinding
+	7,   // 28: ratelimit.v1.ListEgressBindingsResponse.strategy:type_name -> ratelimit.v1.ListEgressBindingsResponse.Strategy
+	104, // 29: ratelimit.v1.ListEgressBindingsResponse.weights:type_name -> ratelimit.v1.ListEgressBindingsResponse.WeightsEntry
+	41,  // 30: ratelimit.v1.RateLimitService.SetUserDefaultPerConnLimit:input_type -> ratelimit.v1.SetUserDefaultPerConnLimitRequest
+	46,  // 31: ratelimit.v1.RateLimitService.ListUserConnections:input_type -> ratelimit.v1.ListUserConnectionsRequest
+	48,  // 32: ratelimit.v1.RateLimitService.SetConnectionLimit:input_type -> ratelimit.v1.SetConnectionLimitRequest
+	50,  // 33: ratelimit.v1.RateLimitService.ClearConnectionLimit:input_type -> ratelimit.v1.ClearConnectionLimitRequest
+	53,  // 34: ratelimit.v1.RateLimitService.GetActiveDevicesSnapshot:input_type -> ratelimit.v1.GetActiveDevicesSnapshotRequest
+	53,  // 35: ratelimit.v1.RateLimitService.PeekActiveDevicesSnapshot:input_type -> ratelimit.v1.GetActiveDevicesSnapshotRequest
+	55,  // 36: ratelimit.v1.RateLimitService.ListUserDevices:input_type -> ratelimit.v1.ListUserDevicesRequest
+	57,  // 37: ratelimit.v1.RateLimitService.SetDeviceLimit:input_type -> ratelimit.v1.SetDeviceLimitRequest
+	59,  // 38: ratelimit.v1.RateLimitService.ClearDeviceLimit:input_type -> ratelimit.v1.ClearDeviceLimitRequest
+	28,  // 39: ratelimit.v1.RateLimitService.SetUserTotalLimit:input_type -> ratelimit.v1.SetUserTotalLimitRequest
+	30,  // 40: ratelimit.v1.RateLimitService.ClearUserTotalLimit:input_type -> ratelimit.v1.ClearUserTotalLimitRequest
+	32,  // 41: ratelimit.v1.RateLimitService.SetInboundTotalLimit:input_type -> ratelimit.v1.SetInboundTotalLimitRequest
+	34,  // 42: ratelimit.v1.RateLimitService.ClearInboundTotalLimit:input_type -> ratelimit.v1.ClearInboundTotalLimitRequest
+	36,  // 43: ratelimit.v1.RateLimitService.SetGlobalTotalLimit:input_type -> ratelimit.v1.SetGlobalTotalLimitRequest
+	38,  // 44: ratelimit.v1.RateLimitService.ClearGlobalTotalLimit:input_type -> ratelimit.v1.ClearGlobalTotalLimitRequest
+	62,  // 45: ratelimit.v1.RateLimitService.SetUserQuota:input_type -> ratelimit.v1.SetUserQuotaRequest
+	64,  // 46: ratelimit.v1.RateLimitService.ClearUserQuota:input_type -> ratelimit.v1.ClearUserQuotaRequest
+	66,  // 47: ratelimit.v1.RateLimitService.GetUserQuota:input_type -> ratelimit.v1.GetUserQuotaRequest
+	68,  // 48: ratelimit.v1.RateLimitService.ResetUserQuotaUsage:input_type -> ratelimit.v1.ResetUserQuotaUsageRequest
+	71,  // 49: ratelimit.v1.RateLimitService.SetMaxDevices:input_type -> ratelimit.v1.SetMaxDevicesRequest
+	73,  // 50: ratelimit.v1.RateLimitService.ClearMaxDevices:input_type -> ratelimit.v1.ClearMaxDevicesRequest
+	75,  // 51: ratelimit.v1.RateLimitService.GetMaxDevices:input_type -> ratelimit.v1.GetMaxDevicesRequest
+	82,  // 52: ratelimit.v1.RateLimitService.SetSchedule:input_type -> ratelimit.v1.SetScheduleRequest
+	84,  // 53: ratelimit.v1.RateLimitService.ClearSchedule:input_type -> ratelimit.v1.ClearScheduleRequest
+	86,  // 54: ratelimit.v1.RateLimitService.GetSchedule:input_type -> ratelimit.v1.GetScheduleRequest
+	91,  // 55: ratelimit.v1.RateLimitService.SetBucketShape:input_type -> ratelimit.v1.SetBucketShapeRequest
+	93,  // 56: ratelimit.v1.RateLimitService.ClearBucketShape:input_type -> ratelimit.v1.ClearBucketShapeRequest
+	95,  // 57: ratelimit.v1.RateLimitService.GetBucketShape:input_type -> ratelimit.v1.GetBucketShapeRequest
+	97,  // 58: ratelimit.v1.RateLimitService.SetEgressBinding:input_type -> ratelimit.v1.SetEgressBindingRequest
+	99,  // 59: ratelimit.v1.RateLimitService.ClearEgressBinding:input_type -> ratelimit.v1.ClearEgressBindingRequest
+	102, // 60: ratelimit.v1.RateLimitService.ListEgressBindings:input_type -> ratelimit.v1.ListEgressBindingsRequest
+	77,  // 61: ratelimit.v1.RateLimitService.SubscribeDeviceEvents:input_type -> ratelimit.v1.SubscribeDeviceEventsRequest
+	26,  // 62: ratelimit.v1.RateLimitService.GetUserStats:input_type -> ratelimit.v1.GetUserStatsRequest
+	22,  // 63: ratelimit.v1.RateLimitService.SetKeyMode:input_type -> ratelimit.v1.SetKeyModeRequest
+	24,  // 64: ratelimit.v1.RateLimitService.GetKeyMode:input_type -> ratelimit.v1.GetKeyModeRequest
+	18,  // 65: ratelimit.v1.RateLimitService.SetGrace:input_type -> ratelimit.v1.SetGraceRequest
+	20,  // 66: ratelimit.v1.RateLimitService.GetGrace:input_type -> ratelimit.v1.GetGraceRequest
+	16,  // 67: ratelimit.v1.RateLimitService.ClearUserEgressCache:input_type -> ratelimit.v1.ClearUserEgressCacheRequest
+	14,  // 68: ratelimit.v1.RateLimitService.ClearUserDefaultPerConnLimit:input_type -> ratelimit.v1.ClearUserDefaultPerConnLimitRequest
+	12,  // 69: ratelimit.v1.RateLimitService.ClearUserConnOverrideLimits:input_type -> ratelimit.v1.ClearUserConnOverrideLimitsRequest
+	8,   // 70: ratelimit.v1.RateLimitService.ClearAllRateLimits:input_type -> ratelimit.v1.ClearAllRateLimitsRequest
+	10,  // 71: ratelimit.v1.RateLimitService.ClearUserRateLimits:input_type -> ratelimit.v1.ClearUserRateLimitsRequest
+	44,  // 72: ratelimit.v1.RateLimitService.SetUserDefaultPerConnLimits:input_type -> ratelimit.v1.SetUserDefaultPerConnLimitsRequest
+	42,  // 73: ratelimit.v1.RateLimitService.SetUserDefaultPerConnLimit:output_type -> ratelimit.v1.SetUserDefaultPerConnLimitResponse
+	47,  // 74: ratelimit.v1.RateLimitService.ListUserConnections:output_type -> ratelimit.v1.ListUserConnectionsResponse
+	49,  // 75: ratelimit.v1.RateLimitService.SetConnectionLimit:output_type -> ratelimit.v1.SetConnectionLimitResponse
+	51,  // 76: ratelimit.v1.RateLimitService.ClearConnectionLimit:output_type -> ratelimit.v1.ClearConnectionLimitResponse
+	54,  // 77: ratelimit.v1.RateLimitService.GetActiveDevicesSnapshot:output_type -> ratelimit.v1.GetActiveDevicesSnapshotResponse
+	54,  // 78: ratelimit.v1.RateLimitService.PeekActiveDevicesSnapshot:output_type -> ratelimit.v1.GetActiveDevicesSnapshotResponse
+	56,  // 79: ratelimit.v1.RateLimitService.ListUserDevices:output_type -> ratelimit.v1.ListUserDevicesResponse
+	58,  // 80: ratelimit.v1.RateLimitService.SetDeviceLimit:output_type -> ratelimit.v1.SetDeviceLimitResponse
+	60,  // 81: ratelimit.v1.RateLimitService.ClearDeviceLimit:output_type -> ratelimit.v1.ClearDeviceLimitResponse
+	29,  // 82: ratelimit.v1.RateLimitService.SetUserTotalLimit:output_type -> ratelimit.v1.SetUserTotalLimitResponse
+	31,  // 83: ratelimit.v1.RateLimitService.ClearUserTotalLimit:output_type -> ratelimit.v1.ClearUserTotalLimitResponse
+	33,  // 84: ratelimit.v1.RateLimitService.SetInboundTotalLimit:output_type -> ratelimit.v1.SetInboundTotalLimitResponse
+	35,  // 85: ratelimit.v1.RateLimitService.ClearInboundTotalLimit:output_type -> ratelimit.v1.ClearInboundTotalLimitResponse
+	37,  // 86: ratelimit.v1.RateLimitService.SetGlobalTotalLimit:output_type -> ratelimit.v1.SetGlobalTotalLimitResponse
+	39,  // 87: ratelimit.v1.RateLimitService.ClearGlobalTotalLimit:output_type -> ratelimit.v1.ClearGlobalTotalLimitResponse
+	63,  // 88: ratelimit.v1.RateLimitService.SetUserQuota:output_type -> ratelimit.v1.SetUserQuotaResponse
+	65,  // 89: ratelimit.v1.RateLimitService.ClearUserQuota:output_type -> ratelimit.v1.ClearUserQuotaResponse
+	67,  // 90: ratelimit.v1.RateLimitService.GetUserQuota:output_type -> ratelimit.v1.GetUserQuotaResponse
+	69,  // 91: ratelimit.v1.RateLimitService.ResetUserQuotaUsage:output_type -> ratelimit.v1.ResetUserQuotaUsageResponse
+	72,  // 92: ratelimit.v1.RateLimitService.SetMaxDevices:output_type -> ratelimit.v1.SetMaxDevicesResponse
+	74,  // 93: ratelimit.v1.RateLimitService.ClearMaxDevices:output_type -> ratelimit.v1.ClearMaxDevicesResponse
+	76,  // 94: ratelimit.v1.RateLimitService.GetMaxDevices:output_type -> ratelimit.v1.GetMaxDevicesResponse
+	83,  // 95: ratelimit.v1.RateLimitService.SetSchedule:output_type -> ratelimit.v1.SetScheduleResponse
+	85,  // 96: ratelimit.v1.RateLimitService.ClearSchedule:output_type -> ratelimit.v1.ClearScheduleResponse
+	87,  // 97: ratelimit.v1.RateLimitService.GetSchedule:output_type -> ratelimit.v1.GetScheduleResponse
+	92,  // 98: ratelimit.v1.RateLimitService.SetBucketShape:output_type -> ratelimit.v1.SetBucketShapeResponse
+	94,  // 99: ratelimit.v1.RateLimitService.ClearBucketShape:output_type -> ratelimit.v1.ClearBucketShapeResponse
+	96,  // 100: ratelimit.v1.RateLimitService.GetBucketShape:output_type -> ratelimit.v1.GetBucketShapeResponse
+	98,  // 101: ratelimit.v1.RateLimitService.SetEgressBinding:output_type -> ratelimit.v1.SetEgressBindingResponse
+	100, // 102: ratelimit.v1.RateLimitService.ClearEgressBinding:output_type -> ratelimit.v1.ClearEgressBindingResponse
+	103, // 103: ratelimit.v1.RateLimitService.ListEgressBindings:output_type -> ratelimit.v1.ListEgressBindingsResponse
+	78,  // 104: ratelimit.v1.RateLimitService.SubscribeDeviceEvents:output_type -> ratelimit.v1.DeviceEvent
+	27,  // 105: ratelimit.v1.RateLimitService.GetUserStats:output_type -> ratelimit.v1.GetUserStatsResponse
+	23,  // 106: ratelimit.v1.RateLimitService.SetKeyMode:output_type -> ratelimit.v1.SetKeyModeResponse
+	25,  // 107: ratelimit.v1.RateLimitService.GetKeyMode:output_type -> ratelimit.v1.GetKeyModeResponse
+	19,  // 108: ratelimit.v1.RateLimitService.SetGrace:output_type -> ratelimit.v1.SetGraceResponse
+	21,  // 109: ratelimit.v1.RateLimitService.GetGrace:output_type -> ratelimit.v1.GetGraceResponse
+	17,  // 110: ratelimit.v1.RateLimitService.ClearUserEgressCache:output_type -> ratelimit.v1.ClearUserEgressCacheResponse
+	15,  // 111: ratelimit.v1.RateLimitService.ClearUserDefaultPerConnLimit:output_type -> ratelimit.v1.ClearUserDefaultPerConnLimitResponse
+	13,  // 112: ratelimit.v1.RateLimitService.ClearUserConnOverrideLimits:output_type -> ratelimit.v1.ClearUserConnOverrideLimitsResponse
+	9,   // 113: ratelimit.v1.RateLimitService.ClearAllRateLimits:output_type -> ratelimit.v1.ClearAllRateLimitsResponse
+	11,  // 114: ratelimit.v1.RateLimitService.ClearUserRateLimits:output_type -> ratelimit.v1.ClearUserRateLimitsResponse
+	45,  // 115: ratelimit.v1.RateLimitService.SetUserDefaultPerConnLimits:output_type -> ratelimit.v1.SetUserDefaultPerConnLimitsResponse
+	73,  // [73:116] is the sub-list for method output_type
+	30,  // [30:73] is the sub-list for method input_type
+	30,  // [30:30] is the sub-list for extension type_name
+	30,  // [30:30] is the sub-list for extension extendee
+	0,   // [0:30] is the sub-list for field type_name
 }
 
 func init() { file_app_ratelimit_api_ratelimit_proto_init() }
@@ -2390,13 +5870,14 @@ func file_app_ratelimit_api_ratelimit_proto_init() {
 	if File_app_ratelimit_api_ratelimit_proto != nil {
 		return
 	}
+	file_app_ratelimit_api_ratelimit_proto_msgTypes[80].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_app_ratelimit_api_ratelimit_proto_rawDesc), len(file_app_ratelimit_api_ratelimit_proto_rawDesc)),
-			NumEnums:      1,
-			NumMessages:   43,
+			NumEnums:      8,
+			NumMessages:   97,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
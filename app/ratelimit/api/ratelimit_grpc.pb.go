@@ -29,6 +29,28 @@ const (
 	RateLimitService_SetDeviceLimit_FullMethodName               = "/ratelimit.v1.RateLimitService/SetDeviceLimit"
 	RateLimitService_ClearDeviceLimit_FullMethodName             = "/ratelimit.v1.RateLimitService/ClearDeviceLimit"
 	RateLimitService_SetUserTotalLimit_FullMethodName            = "/ratelimit.v1.RateLimitService/SetUserTotalLimit"
+	RateLimitService_ClearUserTotalLimit_FullMethodName          = "/ratelimit.v1.RateLimitService/ClearUserTotalLimit"
+	RateLimitService_SetInboundTotalLimit_FullMethodName         = "/ratelimit.v1.RateLimitService/SetInboundTotalLimit"
+	RateLimitService_ClearInboundTotalLimit_FullMethodName       = "/ratelimit.v1.RateLimitService/ClearInboundTotalLimit"
+	RateLimitService_SetGlobalTotalLimit_FullMethodName          = "/ratelimit.v1.RateLimitService/SetGlobalTotalLimit"
+	RateLimitService_ClearGlobalTotalLimit_FullMethodName        = "/ratelimit.v1.RateLimitService/ClearGlobalTotalLimit"
+	RateLimitService_SetUserQuota_FullMethodName                 = "/ratelimit.v1.RateLimitService/SetUserQuota"
+	RateLimitService_ClearUserQuota_FullMethodName               = "/ratelimit.v1.RateLimitService/ClearUserQuota"
+	RateLimitService_GetUserQuota_FullMethodName                 = "/ratelimit.v1.RateLimitService/GetUserQuota"
+	RateLimitService_ResetUserQuotaUsage_FullMethodName          = "/ratelimit.v1.RateLimitService/ResetUserQuotaUsage"
+	RateLimitService_SetMaxDevices_FullMethodName                = "/ratelimit.v1.RateLimitService/SetMaxDevices"
+	RateLimitService_ClearMaxDevices_FullMethodName              = "/ratelimit.v1.RateLimitService/ClearMaxDevices"
+	RateLimitService_GetMaxDevices_FullMethodName                = "/ratelimit.v1.RateLimitService/GetMaxDevices"
+	RateLimitService_SetSchedule_FullMethodName                  = "/ratelimit.v1.RateLimitService/SetSchedule"
+	RateLimitService_ClearSchedule_FullMethodName                = "/ratelimit.v1.RateLimitService/ClearSchedule"
+	RateLimitService_GetSchedule_FullMethodName                  = "/ratelimit.v1.RateLimitService/GetSchedule"
+	RateLimitService_SetBucketShape_FullMethodName               = "/ratelimit.v1.RateLimitService/SetBucketShape"
+	RateLimitService_ClearBucketShape_FullMethodName             = "/ratelimit.v1.RateLimitService/ClearBucketShape"
+	RateLimitService_GetBucketShape_FullMethodName               = "/ratelimit.v1.RateLimitService/GetBucketShape"
+	RateLimitService_SetEgressBinding_FullMethodName             = "/ratelimit.v1.RateLimitService/SetEgressBinding"
+	RateLimitService_ClearEgressBinding_FullMethodName           = "/ratelimit.v1.RateLimitService/ClearEgressBinding"
+	RateLimitService_ListEgressBindings_FullMethodName           = "/ratelimit.v1.RateLimitService/ListEgressBindings"
+	RateLimitService_SubscribeDeviceEvents_FullMethodName        = "/ratelimit.v1.RateLimitService/SubscribeDeviceEvents"
 	RateLimitService_GetUserStats_FullMethodName                 = "/ratelimit.v1.RateLimitService/GetUserStats"
 	RateLimitService_SetKeyMode_FullMethodName                   = "/ratelimit.v1.RateLimitService/SetKeyMode"
 	RateLimitService_GetKeyMode_FullMethodName                   = "/ratelimit.v1.RateLimitService/GetKeyMode"
@@ -60,9 +82,47 @@ type RateLimitServiceClient interface {
 	SetDeviceLimit(ctx context.Context, in *SetDeviceLimitRequest, opts ...grpc.CallOption) (*SetDeviceLimitResponse, error)
 	ClearDeviceLimit(ctx context.Context, in *ClearDeviceLimitRequest, opts ...grpc.CallOption) (*ClearDeviceLimitResponse, error)
 	// Установить ОБЩИЙ лимит для пользователя (UUID).
-	// Лимит будет РАВНОМЕРНО поделен между всеми активными устройствами
-	// этого UUID на данном сервере.
+	// Bucket каждого устройства списывает из общего parent-bucket'а пользователя,
+	// поэтому полоса делится между АКТИВНЫМИ устройствами, а новые устройства
+	// автоматически попадают под лимит.
 	SetUserTotalLimit(ctx context.Context, in *SetUserTotalLimitRequest, opts ...grpc.CallOption) (*SetUserTotalLimitResponse, error)
+	ClearUserTotalLimit(ctx context.Context, in *ClearUserTotalLimitRequest, opts ...grpc.CallOption) (*ClearUserTotalLimitResponse, error)
+	// Общий лимит на все соединения, пришедшие через inbound с данным tag.
+	SetInboundTotalLimit(ctx context.Context, in *SetInboundTotalLimitRequest, opts ...grpc.CallOption) (*SetInboundTotalLimitResponse, error)
+	ClearInboundTotalLimit(ctx context.Context, in *ClearInboundTotalLimitRequest, opts ...grpc.CallOption) (*ClearInboundTotalLimitResponse, error)
+	// Общий лимит на весь сервер.
+	SetGlobalTotalLimit(ctx context.Context, in *SetGlobalTotalLimitRequest, opts ...grpc.CallOption) (*SetGlobalTotalLimitResponse, error)
+	ClearGlobalTotalLimit(ctx context.Context, in *ClearGlobalTotalLimitRequest, opts ...grpc.CallOption) (*ClearGlobalTotalLimitResponse, error)
+	SetUserQuota(ctx context.Context, in *SetUserQuotaRequest, opts ...grpc.CallOption) (*SetUserQuotaResponse, error)
+	ClearUserQuota(ctx context.Context, in *ClearUserQuotaRequest, opts ...grpc.CallOption) (*ClearUserQuotaResponse, error)
+	GetUserQuota(ctx context.Context, in *GetUserQuotaRequest, opts ...grpc.CallOption) (*GetUserQuotaResponse, error)
+	// Обнулить расход пользователя в текущем периоде (квота остаётся).
+	ResetUserQuotaUsage(ctx context.Context, in *ResetUserQuotaUsageRequest, opts ...grpc.CallOption) (*ResetUserQuotaUsageResponse, error)
+	// Пустой uuid — общий лимит для всех пользователей без собственного.
+	SetMaxDevices(ctx context.Context, in *SetMaxDevicesRequest, opts ...grpc.CallOption) (*SetMaxDevicesResponse, error)
+	ClearMaxDevices(ctx context.Context, in *ClearMaxDevicesRequest, opts ...grpc.CallOption) (*ClearMaxDevicesResponse, error)
+	// Действующий лимит для uuid и счётчики срабатываний.
+	GetMaxDevices(ctx context.Context, in *GetMaxDevicesRequest, opts ...grpc.CallOption) (*GetMaxDevicesResponse, error)
+	// Расписание для uuid или, если uuid пуст, для policy level.
+	// Расписание uuid заменяет расписание его level.
+	SetSchedule(ctx context.Context, in *SetScheduleRequest, opts ...grpc.CallOption) (*SetScheduleResponse, error)
+	ClearSchedule(ctx context.Context, in *ClearScheduleRequest, opts ...grpc.CallOption) (*ClearScheduleResponse, error)
+	GetSchedule(ctx context.Context, in *GetScheduleRequest, opts ...grpc.CallOption) (*GetScheduleResponse, error)
+	// Пустой uuid — общие значения; поля uuid перекрывают их по отдельности.
+	SetBucketShape(ctx context.Context, in *SetBucketShapeRequest, opts ...grpc.CallOption) (*SetBucketShapeResponse, error)
+	ClearBucketShape(ctx context.Context, in *ClearBucketShapeRequest, opts ...grpc.CallOption) (*ClearBucketShapeResponse, error)
+	// Заданное для uuid и действующее (с учётом общих значений).
+	GetBucketShape(ctx context.Context, in *GetBucketShapeRequest, opts ...grpc.CallOption) (*GetBucketShapeResponse, error)
+	// Явная привязка uuid -> outbound tag; важнее стратегии chooser'а.
+	// Уже выбранные egress устройств uuid сбрасываются.
+	SetEgressBinding(ctx context.Context, in *SetEgressBindingRequest, opts ...grpc.CallOption) (*SetEgressBindingResponse, error)
+	ClearEgressBinding(ctx context.Context, in *ClearEgressBindingRequest, opts ...grpc.CallOption) (*ClearEgressBindingResponse, error)
+	// Все привязки и текущая настройка chooser'а.
+	ListEgressBindings(ctx context.Context, in *ListEgressBindingsRequest, opts ...grpc.CallOption) (*ListEgressBindingsResponse, error)
+	// Старт/grace/удаление устройств и смена их лимитов/egress — вместо
+	// периодического опроса GetActiveDevicesSnapshot. Медленный подписчик
+	// теряет события, но не тормозит трафик.
+	SubscribeDeviceEvents(ctx context.Context, in *SubscribeDeviceEventsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[DeviceEvent], error)
 	GetUserStats(ctx context.Context, in *GetUserStatsRequest, opts ...grpc.CallOption) (*GetUserStatsResponse, error)
 	SetKeyMode(ctx context.Context, in *SetKeyModeRequest, opts ...grpc.CallOption) (*SetKeyModeResponse, error)
 	GetKeyMode(ctx context.Context, in *GetKeyModeRequest, opts ...grpc.CallOption) (*GetKeyModeResponse, error)
@@ -184,6 +244,235 @@ func (c *rateLimitServiceClient) SetUserTotalLimit(ctx context.Context, in *SetU
 	return out, nil
 }
 
+func (c *rateLimitServiceClient) ClearUserTotalLimit(ctx context.Context, in *ClearUserTotalLimitRequest, opts ...grpc.CallOption) (*ClearUserTotalLimitResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ClearUserTotalLimitResponse)
+	err := c.cc.Invoke(ctx, RateLimitService_ClearUserTotalLimit_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *rateLimitServiceClient) SetInboundTotalLimit(ctx context.Context, in *SetInboundTotalLimitRequest, opts ...grpc.CallOption) (*SetInboundTotalLimitResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SetInboundTotalLimitResponse)
+	err := c.cc.Invoke(ctx, RateLimitService_SetInboundTotalLimit_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *rateLimitServiceClient) ClearInboundTotalLimit(ctx context.Context, in *ClearInboundTotalLimitRequest, opts ...grpc.CallOption) (*ClearInboundTotalLimitResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ClearInboundTotalLimitResponse)
+	err := c.cc.Invoke(ctx, RateLimitService_ClearInboundTotalLimit_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *rateLimitServiceClient) SetGlobalTotalLimit(ctx context.Context, in *SetGlobalTotalLimitRequest, opts ...grpc.CallOption) (*SetGlobalTotalLimitResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SetGlobalTotalLimitResponse)
+	err := c.cc.Invoke(ctx, RateLimitService_SetGlobalTotalLimit_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *rateLimitServiceClient) ClearGlobalTotalLimit(ctx context.Context, in *ClearGlobalTotalLimitRequest, opts ...grpc.CallOption) (*ClearGlobalTotalLimitResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ClearGlobalTotalLimitResponse)
+	err := c.cc.Invoke(ctx, RateLimitService_ClearGlobalTotalLimit_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *rateLimitServiceClient) SetUserQuota(ctx context.Context, in *SetUserQuotaRequest, opts ...grpc.CallOption) (*SetUserQuotaResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SetUserQuotaResponse)
+	err := c.cc.Invoke(ctx, RateLimitService_SetUserQuota_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *rateLimitServiceClient) ClearUserQuota(ctx context.Context, in *ClearUserQuotaRequest, opts ...grpc.CallOption) (*ClearUserQuotaResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ClearUserQuotaResponse)
+	err := c.cc.Invoke(ctx, RateLimitService_ClearUserQuota_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *rateLimitServiceClient) GetUserQuota(ctx context.Context, in *GetUserQuotaRequest, opts ...grpc.CallOption) (*GetUserQuotaResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetUserQuotaResponse)
+	err := c.cc.Invoke(ctx, RateLimitService_GetUserQuota_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *rateLimitServiceClient) ResetUserQuotaUsage(ctx context.Context, in *ResetUserQuotaUsageRequest, opts ...grpc.CallOption) (*ResetUserQuotaUsageResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ResetUserQuotaUsageResponse)
+	err := c.cc.Invoke(ctx, RateLimitService_ResetUserQuotaUsage_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *rateLimitServiceClient) SetMaxDevices(ctx context.Context, in *SetMaxDevicesRequest, opts ...grpc.CallOption) (*SetMaxDevicesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SetMaxDevicesResponse)
+	err := c.cc.Invoke(ctx, RateLimitService_SetMaxDevices_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *rateLimitServiceClient) ClearMaxDevices(ctx context.Context, in *ClearMaxDevicesRequest, opts ...grpc.CallOption) (*ClearMaxDevicesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ClearMaxDevicesResponse)
+	err := c.cc.Invoke(ctx, RateLimitService_ClearMaxDevices_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *rateLimitServiceClient) GetMaxDevices(ctx context.Context, in *GetMaxDevicesRequest, opts ...grpc.CallOption) (*GetMaxDevicesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetMaxDevicesResponse)
+	err := c.cc.Invoke(ctx, RateLimitService_GetMaxDevices_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *rateLimitServiceClient) SetSchedule(ctx context.Context, in *SetScheduleRequest, opts ...grpc.CallOption) (*SetScheduleResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SetScheduleResponse)
+	err := c.cc.Invoke(ctx, RateLimitService_SetSchedule_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *rateLimitServiceClient) ClearSchedule(ctx context.Context, in *ClearScheduleRequest, opts ...grpc.CallOption) (*ClearScheduleResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ClearScheduleResponse)
+	err := c.cc.Invoke(ctx, RateLimitService_ClearSchedule_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *rateLimitServiceClient) GetSchedule(ctx context.Context, in *GetScheduleRequest, opts ...grpc.CallOption) (*GetScheduleResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetScheduleResponse)
+	err := c.cc.Invoke(ctx, RateLimitService_GetSchedule_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *rateLimitServiceClient) SetBucketShape(ctx context.Context, in *SetBucketShapeRequest, opts ...grpc.CallOption) (*SetBucketShapeResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SetBucketShapeResponse)
+	err := c.cc.Invoke(ctx, RateLimitService_SetBucketShape_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *rateLimitServiceClient) ClearBucketShape(ctx context.Context, in *ClearBucketShapeRequest, opts ...grpc.CallOption) (*ClearBucketShapeResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ClearBucketShapeResponse)
+	err := c.cc.Invoke(ctx, RateLimitService_ClearBucketShape_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *rateLimitServiceClient) GetBucketShape(ctx context.Context, in *GetBucketShapeRequest, opts ...grpc.CallOption) (*GetBucketShapeResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetBucketShapeResponse)
+	err := c.cc.Invoke(ctx, RateLimitService_GetBucketShape_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *rateLimitServiceClient) SetEgressBinding(ctx context.Context, in *SetEgressBindingRequest, opts ...grpc.CallOption) (*SetEgressBindingResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SetEgressBindingResponse)
+	err := c.cc.Invoke(ctx, RateLimitService_SetEgressBinding_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *rateLimitServiceClient) ClearEgressBinding(ctx context.Context, in *ClearEgressBindingRequest, opts ...grpc.CallOption) (*ClearEgressBindingResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ClearEgressBindingResponse)
+	err := c.cc.Invoke(ctx, RateLimitService_ClearEgressBinding_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *rateLimitServiceClient) ListEgressBindings(ctx context.Context, in *ListEgressBindingsRequest, opts ...grpc.CallOption) (*ListEgressBindingsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListEgressBindingsResponse)
+	err := c.cc.Invoke(ctx, RateLimitService_ListEgressBindings_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *rateLimitServiceClient) SubscribeDeviceEvents(ctx context.Context, in *SubscribeDeviceEventsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[DeviceEvent], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &RateLimitService_ServiceDesc.Streams[0], RateLimitService_SubscribeDeviceEvents_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[SubscribeDeviceEventsRequest, DeviceEvent]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type RateLimitService_SubscribeDeviceEventsClient = grpc.ServerStreamingClient[DeviceEvent]
+
 func (c *rateLimitServiceClient) GetUserStats(ctx context.Context, in *GetUserStatsRequest, opts ...grpc.CallOption) (*GetUserStatsResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(GetUserStatsResponse)
@@ -312,9 +601,47 @@ type RateLimitServiceServer interface {
 	SetDeviceLimit(context.Context, *SetDeviceLimitRequest) (*SetDeviceLimitResponse, error)
 	ClearDeviceLimit(context.Context, *ClearDeviceLimitRequest) (*ClearDeviceLimitResponse, error)
 	// Установить ОБЩИЙ лимит для пользователя (UUID).
-	// Лимит будет РАВНОМЕРНО поделен между всеми активными устройствами
-	// этого UUID на данном сервере.
+	// Bucket каждого устройства списывает из общего parent-bucket'а пользователя,
+	// поэтому полоса делится между АКТИВНЫМИ устройствами, а новые устройства
+	// автоматически попадают под лимит.
 	SetUserTotalLimit(context.Context, *SetUserTotalLimitRequest) (*SetUserTotalLimitResponse, error)
+	ClearUserTotalLimit(context.Context, *ClearUserTotalLimitRequest) (*ClearUserTotalLimitResponse, error)
+	// Общий лимит на все соединения, пришедшие через inbound с данным tag.
+	SetInboundTotalLimit(context.Context, *SetInboundTotalLimitRequest) (*SetInboundTotalLimitResponse, error)
+	ClearInboundTotalLimit(context.Context, *ClearInboundTotalLimitRequest) (*ClearInboundTotalLimitResponse, error)
+	// Общий лимит на весь сервер.
+	SetGlobalTotalLimit(context.Context, *SetGlobalTotalLimitRequest) (*SetGlobalTotalLimitResponse, error)
+	ClearGlobalTotalLimit(context.Context, *ClearGlobalTotalLimitRequest) (*ClearGlobalTotalLimitResponse, error)
+	SetUserQuota(context.Context, *SetUserQuotaRequest) (*SetUserQuotaResponse, error)
+	ClearUserQuota(context.Context, *ClearUserQuotaRequest) (*ClearUserQuotaResponse, error)
+	GetUserQuota(context.Context, *GetUserQuotaRequest) (*GetUserQuotaResponse, error)
+	// Обнулить расход пользователя в текущем периоде (квота остаётся).
+	ResetUserQuotaUsage(context.Context, *ResetUserQuotaUsageRequest) (*ResetUserQuotaUsageResponse, error)
+	// Пустой uuid — общий лимит для всех пользователей без собственного.
+	SetMaxDevices(context.Context, *SetMaxDevicesRequest) (*SetMaxDevicesResponse, error)
+	ClearMaxDevices(context.Context, *ClearMaxDevicesRequest) (*ClearMaxDevicesResponse, error)
+	// Действующий лимит для uuid и счётчики срабатываний.
+	GetMaxDevices(context.Context, *GetMaxDevicesRequest) (*GetMaxDevicesResponse, error)
+	// Расписание для uuid или, если uuid пуст, для policy level.
+	// Расписание uuid заменяет расписание его level.
+	SetSchedule(context.Context, *SetScheduleRequest) (*SetScheduleResponse, error)
+	ClearSchedule(context.Context, *ClearScheduleRequest) (*ClearScheduleResponse, error)
+	GetSchedule(context.Context, *GetScheduleRequest) (*GetScheduleResponse, error)
+	// Пустой uuid — общие значения; поля uuid перекрывают их по отдельности.
+	SetBucketShape(context.Context, *SetBucketShapeRequest) (*SetBucketShapeResponse, error)
+	ClearBucketShape(context.Context, *ClearBucketShapeRequest) (*ClearBucketShapeResponse, error)
+	// Заданное для uuid и действующее (с учётом общих значений).
+	GetBucketShape(context.Context, *GetBucketShapeRequest) (*GetBucketShapeResponse, error)
+	// Явная привязка uuid -> outbound tag; важнее стратегии chooser'а.
+	// Уже выбранные egress устройств uuid сбрасываются.
+	SetEgressBinding(context.Context, *SetEgressBindingRequest) (*SetEgressBindingResponse, error)
+	ClearEgressBinding(context.Context, *ClearEgressBindingRequest) (*ClearEgressBindingResponse, error)
+	// Все привязки и текущая настройка chooser'а.
+	ListEgressBindings(context.Context, *ListEgressBindingsRequest) (*ListEgressBindingsResponse, error)
+	// Старт/grace/удаление устройств и смена их лимитов/egress — вместо
+	// периодического опроса GetActiveDevicesSnapshot. Медленный подписчик
+	// теряет события, но не тормозит трафик.
+	SubscribeDeviceEvents(*SubscribeDeviceEventsRequest, grpc.ServerStreamingServer[DeviceEvent]) error
 	GetUserStats(context.Context, *GetUserStatsRequest) (*GetUserStatsResponse, error)
 	SetKeyMode(context.Context, *SetKeyModeRequest) (*SetKeyModeResponse, error)
 	GetKeyMode(context.Context, *GetKeyModeRequest) (*GetKeyModeResponse, error)
@@ -366,6 +693,72 @@ func (UnimplementedRateLimitServiceServer) ClearDeviceLimit(context.Context, *Cl
 func (UnimplementedRateLimitServiceServer) SetUserTotalLimit(context.Context, *SetUserTotalLimitRequest) (*SetUserTotalLimitResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method SetUserTotalLimit not implemented")
 }
+func (UnimplementedRateLimitServiceServer) ClearUserTotalLimit(context.Context, *ClearUserTotalLimitRequest) (*ClearUserTotalLimitResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ClearUserTotalLimit not implemented")
+}
+func (UnimplementedRateLimitServiceServer) SetInboundTotalLimit(context.Context, *SetInboundTotalLimitRequest) (*SetInboundTotalLimitResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method SetInboundTotalLimit not implemented")
+}
+func (UnimplementedRateLimitServiceServer) ClearInboundTotalLimit(context.Context, *ClearInboundTotalLimitRequest) (*ClearInboundTotalLimitResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ClearInboundTotalLimit not implemented")
+}
+func (UnimplementedRateLimitServiceServer) SetGlobalTotalLimit(context.Context, *SetGlobalTotalLimitRequest) (*SetGlobalTotalLimitResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method SetGlobalTotalLimit not implemented")
+}
+func (UnimplementedRateLimitServiceServer) ClearGlobalTotalLimit(context.Context, *ClearGlobalTotalLimitRequest) (*ClearGlobalTotalLimitResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ClearGlobalTotalLimit not implemented")
+}
+func (UnimplementedRateLimitServiceServer) SetUserQuota(context.Context, *SetUserQuotaRequest) (*SetUserQuotaResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method SetUserQuota not implemented")
+}
+func (UnimplementedRateLimitServiceServer) ClearUserQuota(context.Context, *ClearUserQuotaRequest) (*ClearUserQuotaResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ClearUserQuota not implemented")
+}
+func (UnimplementedRateLimitServiceServer) GetUserQuota(context.Context, *GetUserQuotaRequest) (*GetUserQuotaResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetUserQuota not implemented")
+}
+func (UnimplementedRateLimitServiceServer) ResetUserQuotaUsage(context.Context, *ResetUserQuotaUsageRequest) (*ResetUserQuotaUsageResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ResetUserQuotaUsage not implemented")
+}
+func (UnimplementedRateLimitServiceServer) SetMaxDevices(context.Context, *SetMaxDevicesRequest) (*SetMaxDevicesResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method SetMaxDevices not implemented")
+}
+func (UnimplementedRateLimitServiceServer) ClearMaxDevices(context.Context, *ClearMaxDevicesRequest) (*ClearMaxDevicesResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ClearMaxDevices not implemented")
+}
+func (UnimplementedRateLimitServiceServer) GetMaxDevices(context.Context, *GetMaxDevicesRequest) (*GetMaxDevicesResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetMaxDevices not implemented")
+}
+func (UnimplementedRateLimitServiceServer) SetSchedule(context.Context, *SetScheduleRequest) (*SetScheduleResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method SetSchedule not implemented")
+}
+func (UnimplementedRateLimitServiceServer) ClearSchedule(context.Context, *ClearScheduleRequest) (*ClearScheduleResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ClearSchedule not implemented")
+}
+func (UnimplementedRateLimitServiceServer) GetSchedule(context.Context, *GetScheduleRequest) (*GetScheduleResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetSchedule not implemented")
+}
+func (UnimplementedRateLimitServiceServer) SetBucketShape(context.Context, *SetBucketShapeRequest) (*SetBucketShapeResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method SetBucketShape not implemented")
+}
+func (UnimplementedRateLimitServiceServer) ClearBucketShape(context.Context, *ClearBucketShapeRequest) (*ClearBucketShapeResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ClearBucketShape not implemented")
+}
+func (UnimplementedRateLimitServiceServer) GetBucketShape(context.Context, *GetBucketShapeRequest) (*GetBucketShapeResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetBucketShape not implemented")
+}
+func (UnimplementedRateLimitServiceServer) SetEgressBinding(context.Context, *SetEgressBindingRequest) (*SetEgressBindingResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method SetEgressBinding not implemented")
+}
+func (UnimplementedRateLimitServiceServer) ClearEgressBinding(context.Context, *ClearEgressBindingRequest) (*ClearEgressBindingResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ClearEgressBinding not implemented")
+}
+func (UnimplementedRateLimitServiceServer) ListEgressBindings(context.Context, *ListEgressBindingsRequest) (*ListEgressBindingsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListEgressBindings not implemented")
+}
+func (UnimplementedRateLimitServiceServer) SubscribeDeviceEvents(*SubscribeDeviceEventsRequest, grpc.ServerStreamingServer[DeviceEvent]) error {
+	return status.Error(codes.Unimplemented, "method SubscribeDeviceEvents not implemented")
+}
 func (UnimplementedRateLimitServiceServer) GetUserStats(context.Context, *GetUserStatsRequest) (*GetUserStatsResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method GetUserStats not implemented")
 }
@@ -600,6 +993,395 @@ func _RateLimitService_SetUserTotalLimit_Handler(srv interface{}, ctx context.Co
 	return interceptor(ctx, in, info, handler)
 }
 
+func _RateLimitService_ClearUserTotalLimit_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ClearUserTotalLimitRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RateLimitServiceServer).ClearUserTotalLimit(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RateLimitService_ClearUserTotalLimit_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RateLimitServiceServer).ClearUserTotalLimit(ctx, req.(*ClearUserTotalLimitRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RateLimitService_SetInboundTotalLimit_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetInboundTotalLimitRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RateLimitServiceServer).SetInboundTotalLimit(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RateLimitService_SetInboundTotalLimit_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RateLimitServiceServer).SetInboundTotalLimit(ctx, req.(*SetInboundTotalLimitRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RateLimitService_ClearInboundTotalLimit_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ClearInboundTotalLimitRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RateLimitServiceServer).ClearInboundTotalLimit(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RateLimitService_ClearInboundTotalLimit_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RateLimitServiceServer).ClearInboundTotalLimit(ctx, req.(*ClearInboundTotalLimitRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RateLimitService_SetGlobalTotalLimit_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetGlobalTotalLimitRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RateLimitServiceServer).SetGlobalTotalLimit(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RateLimitService_SetGlobalTotalLimit_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RateLimitServiceServer).SetGlobalTotalLimit(ctx, req.(*SetGlobalTotalLimitRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RateLimitService_ClearGlobalTotalLimit_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ClearGlobalTotalLimitRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RateLimitServiceServer).ClearGlobalTotalLimit(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RateLimitService_ClearGlobalTotalLimit_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RateLimitServiceServer).ClearGlobalTotalLimit(ctx, req.(*ClearGlobalTotalLimitRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RateLimitService_SetUserQuota_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetUserQuotaRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RateLimitServiceServer).SetUserQuota(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RateLimitService_SetUserQuota_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RateLimitServiceServer).SetUserQuota(ctx, req.(*SetUserQuotaRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RateLimitService_ClearUserQuota_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ClearUserQuotaRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RateLimitServiceServer).ClearUserQuota(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RateLimitService_ClearUserQuota_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RateLimitServiceServer).ClearUserQuota(ctx, req.(*ClearUserQuotaRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RateLimitService_GetUserQuota_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetUserQuotaRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RateLimitServiceServer).GetUserQuota(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RateLimitService_GetUserQuota_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RateLimitServiceServer).GetUserQuota(ctx, req.(*GetUserQuotaRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RateLimitService_ResetUserQuotaUsage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResetUserQuotaUsageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RateLimitServiceServer).ResetUserQuotaUsage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RateLimitService_ResetUserQuotaUsage_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RateLimitServiceServer).ResetUserQuotaUsage(ctx, req.(*ResetUserQuotaUsageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RateLimitService_SetMaxDevices_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetMaxDevicesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RateLimitServiceServer).SetMaxDevices(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RateLimitService_SetMaxDevices_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RateLimitServiceServer).SetMaxDevices(ctx, req.(*SetMaxDevicesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RateLimitService_ClearMaxDevices_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ClearMaxDevicesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RateLimitServiceServer).ClearMaxDevices(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RateLimitService_ClearMaxDevices_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RateLimitServiceServer).ClearMaxDevices(ctx, req.(*ClearMaxDevicesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RateLimitService_GetMaxDevices_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetMaxDevicesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RateLimitServiceServer).GetMaxDevices(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RateLimitService_GetMaxDevices_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RateLimitServiceServer).GetMaxDevices(ctx, req.(*GetMaxDevicesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RateLimitService_SetSchedule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetScheduleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RateLimitServiceServer).SetSchedule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RateLimitService_SetSchedule_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RateLimitServiceServer).SetSchedule(ctx, req.(*SetScheduleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RateLimitService_ClearSchedule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ClearScheduleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RateLimitServiceServer).ClearSchedule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RateLimitService_ClearSchedule_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RateLimitServiceServer).ClearSchedule(ctx, req.(*ClearScheduleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RateLimitService_GetSchedule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetScheduleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RateLimitServiceServer).GetSchedule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RateLimitService_GetSchedule_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RateLimitServiceServer).GetSchedule(ctx, req.(*GetScheduleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RateLimitService_SetBucketShape_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetBucketShapeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RateLimitServiceServer).SetBucketShape(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RateLimitService_SetBucketShape_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RateLimitServiceServer).SetBucketShape(ctx, req.(*SetBucketShapeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RateLimitService_ClearBucketShape_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ClearBucketShapeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RateLimitServiceServer).ClearBucketShape(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RateLimitService_ClearBucketShape_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RateLimitServiceServer).ClearBucketShape(ctx, req.(*ClearBucketShapeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RateLimitService_GetBucketShape_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetBucketShapeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RateLimitServiceServer).GetBucketShape(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RateLimitService_GetBucketShape_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RateLimitServiceServer).GetBucketShape(ctx, req.(*GetBucketShapeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RateLimitService_SetEgressBinding_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetEgressBindingRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RateLimitServiceServer).SetEgressBinding(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RateLimitService_SetEgressBinding_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RateLimitServiceServer).SetEgressBinding(ctx, req.(*SetEgressBindingRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RateLimitService_ClearEgressBinding_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ClearEgressBindingRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RateLimitServiceServer).ClearEgressBinding(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RateLimitService_ClearEgressBinding_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RateLimitServiceServer).ClearEgressBinding(ctx, req.(*ClearEgressBindingRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RateLimitService_ListEgressBindings_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListEgressBindingsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RateLimitServiceServer).ListEgressBindings(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RateLimitService_ListEgressBindings_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RateLimitServiceServer).ListEgressBindings(ctx, req.(*ListEgressBindingsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RateLimitService_SubscribeDeviceEvents_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribeDeviceEventsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(RateLimitServiceServer).SubscribeDeviceEvents(m, &grpc.GenericServerStream[SubscribeDeviceEventsRequest, DeviceEvent]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type RateLimitService_SubscribeDeviceEventsServer = grpc.ServerStreamingServer[DeviceEvent]
+
 func _RateLimitService_GetUserStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(GetUserStatsRequest)
 	if err := dec(in); err != nil {
@@ -845,6 +1627,90 @@ var RateLimitService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "SetUserTotalLimit",
 			Handler:    _RateLimitService_SetUserTotalLimit_Handler,
 		},
+		{
+			MethodName: "ClearUserTotalLimit",
+			Handler:    _RateLimitService_ClearUserTotalLimit_Handler,
+		},
+		{
+			MethodName: "SetInboundTotalLimit",
+			Handler:    _RateLimitService_SetInboundTotalLimit_Handler,
+		},
+		{
+			MethodName: "ClearInboundTotalLimit",
+			Handler:    _RateLimitService_ClearInboundTotalLimit_Handler,
+		},
+		{
+			MethodName: "SetGlobalTotalLimit",
+			Handler:    _RateLimitService_SetGlobalTotalLimit_Handler,
+		},
+		{
+			MethodName: "ClearGlobalTotalLimit",
+			Handler:    _RateLimitService_ClearGlobalTotalLimit_Handler,
+		},
+		{
+			MethodName: "SetUserQuota",
+			Handler:    _RateLimitService_SetUserQuota_Handler,
+		},
+		{
+			MethodName: "ClearUserQuota",
+			Handler:    _RateLimitService_ClearUserQuota_Handler,
+		},
+		{
+			MethodName: "GetUserQuota",
+			Handler:    _RateLimitService_GetUserQuota_Handler,
+		},
+		{
+			MethodName: "ResetUserQuotaUsage",
+			Handler:    _RateLimitService_ResetUserQuotaUsage_Handler,
+		},
+		{
+			MethodName: "SetMaxDevices",
+			Handler:    _RateLimitService_SetMaxDevices_Handler,
+		},
+		{
+			MethodName: "ClearMaxDevices",
+			Handler:    _RateLimitService_ClearMaxDevices_Handler,
+		},
+		{
+			MethodName: "GetMaxDevices",
+			Handler:    _RateLimitService_GetMaxDevices_Handler,
+		},
+		{
+			MethodName: "SetSchedule",
+			Handler:    _RateLimitService_SetSchedule_Handler,
+		},
+		{
+			MethodName: "ClearSchedule",
+			Handler:    _RateLimitService_ClearSchedule_Handler,
+		},
+		{
+			MethodName: "GetSchedule",
+			Handler:    _RateLimitService_GetSchedule_Handler,
+		},
+		{
+			MethodName: "SetBucketShape",
+			Handler:    _RateLimitService_SetBucketShape_Handler,
+		},
+		{
+			MethodName: "ClearBucketShape",
+			Handler:    _RateLimitService_ClearBucketShape_Handler,
+		},
+		{
+			MethodName: "GetBucketShape",
+			Handler:    _RateLimitService_GetBucketShape_Handler,
+		},
+		{
+			MethodName: "SetEgressBinding",
+			Handler:    _RateLimitService_SetEgressBinding_Handler,
+		},
+		{
+			MethodName: "ClearEgressBinding",
+			Handler:    _RateLimitService_ClearEgressBinding_Handler,
+		},
+		{
+			MethodName: "ListEgressBindings",
+			Handler:    _RateLimitService_ListEgressBindings_Handler,
+		},
 		{
 			MethodName: "GetUserStats",
 			Handler:    _RateLimitService_GetUserStats_Handler,
@@ -890,6 +1756,12 @@ var RateLimitService_ServiceDesc = grpc.ServiceDesc{
 			Handler:    _RateLimitService_SetUserDefaultPerConnLimits_Handler,
 		},
 	},
-	Streams:  []grpc.StreamDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "SubscribeDeviceEvents",
+			Handler:       _RateLimitService_SubscribeDeviceEvents_Handler,
+			ServerStreams: true,
+		},
+	},
 	Metadata: "app/ratelimit/api/ratelimit.proto",
 }
@@ -22,33 +22,136 @@ func bpsToBytesPerSec(bps uint64) float64 {
 	return float64(bps) / 8.0
 }
 
-// GetOrCreate возвращает up/down bucket для conn_id и подстраивает rate под текущие лимиты.
-func (b *Buckets) GetOrCreate(conn ConnID, upBps, downBps uint64) (*TokenBucket, *TokenBucket) {
+// GetOrCreate возвращает up/down bucket для conn_id и подстраивает rate и форму под текущие лимиты.
+func (b *Buckets) GetOrCreate(conn ConnID, upBps, downBps uint64, shape BucketShape) (*TokenBucket, *TokenBucket) {
 	b.mu.Lock()
 	defer b.mu.Unlock()
 
 	up := b.up[conn]
 	if up == nil {
-		up = NewTokenBucket(bpsToBytesPerSec(upBps))
+		up = NewShapedTokenBucket(bpsToBytesPerSec(upBps), shape)
 		b.up[conn] = up
 	} else {
-		up.SetRate(bpsToBytesPerSec(upBps))
+		up.Reshape(bpsToBytesPerSec(upBps), shape)
 	}
 
 	down := b.down[conn]
 	if down == nil {
-		down = NewTokenBucket(bpsToBytesPerSec(downBps))
+		down = NewShapedTokenBucket(bpsToBytesPerSec(downBps), shape)
 		b.down[conn] = down
 	} else {
-		down.SetRate(bpsToBytesPerSec(downBps))
+		down.Reshape(bpsToBytesPerSec(downBps), shape)
 	}
 
 	return up, down
 }
 
+// SetRate перенастраивает существующие bucket'ы conn; новые не создаёт.
+func (b *Buckets) SetRate(conn ConnID, upBps, downBps uint64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if up := b.up[conn]; up != nil {
+		up.SetRate(bpsToBytesPerSec(upBps))
+	}
+	if down := b.down[conn]; down != nil {
+		down.SetRate(bpsToBytesPerSec(downBps))
+	}
+}
+
 func (b *Buckets) Remove(conn ConnID) {
 	b.mu.Lock()
 	defer b.mu.Unlock()
+	releaseBucket(b.up[conn])
+	releaseBucket(b.down[conn])
 	delete(b.up, conn)
 	delete(b.down, conn)
 }
+
+// releaseBucket снимает ограничение с удаляемого bucket'а: те, кто уже
+// ждёт в его очереди, просыпаются сразу, а не по старому таймеру.
+func releaseBucket(b *TokenBucket) {
+	if b != nil {
+		b.SetRate(0)
+	}
+}
+
+// SharedBuckets — parent-bucket'ы, общие для группы устройств
+// (по uuid, по inbound tag или один глобальный).
+type SharedBuckets struct {
+	mu   sync.Mutex
+	up   map[string]*TokenBucket
+	down map[string]*TokenBucket
+}
+
+func NewSharedBuckets() *SharedBuckets {
+	return &SharedBuckets{
+		up:   make(map[string]*TokenBucket),
+		down: make(map[string]*TokenBucket),
+	}
+}
+
+var (
+	userBuckets    = NewSharedBuckets()
+	inboundBuckets = NewSharedBuckets()
+	globalBuckets  = NewSharedBuckets()
+	// штрафные bucket'ы пользователей с исчерпанной квотой (QuotaActionPenalty)
+	penaltyBuckets = NewSharedBuckets()
+)
+
+// GetOrCreate возвращает up/down parent-bucket для key и подстраивает rate и форму под текущие лимиты.
+func (b *SharedBuckets) GetOrCreate(key string, upBps, downBps uint64, shape BucketShape) (*TokenBucket, *TokenBucket) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	up := b.up[key]
+	if up == nil {
+		up = NewShapedTokenBucket(bpsToBytesPerSec(upBps), shape)
+		b.up[key] = up
+	} else {
+		up.Reshape(bpsToBytesPerSec(upBps), shape)
+	}
+
+	down := b.down[key]
+	if down == nil {
+		down = NewShapedTokenBucket(bpsToBytesPerSec(downBps), shape)
+		b.down[key] = down
+	} else {
+		down.Reshape(bpsToBytesPerSec(downBps), shape)
+	}
+
+	return up, down
+}
+
+// SetRate перенастраивает существующие bucket'ы key; новые не создаёт.
+func (b *SharedBuckets) SetRate(key string, upBps, downBps uint64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if up := b.up[key]; up != nil {
+		up.SetRate(bpsToBytesPerSec(upBps))
+	}
+	if down := b.down[key]; down != nil {
+		down.SetRate(bpsToBytesPerSec(downBps))
+	}
+}
+
+func (b *SharedBuckets) Remove(key string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	releaseBucket(b.up[key])
+	releaseBucket(b.down[key])
+	delete(b.up, key)
+	delete(b.down, key)
+}
+
+func (b *SharedBuckets) RemoveAll() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, tb := range b.up {
+		releaseBucket(tb)
+	}
+	for _, tb := range b.down {
+		releaseBucket(tb)
+	}
+	b.up = make(map[string]*TokenBucket)
+	b.down = make(map[string]*TokenBucket)
+}
@@ -12,6 +12,8 @@ var chooser atomic.Value // stores ChooseOutboundFunc
 // DefaultChooser: заглушка.
 // Возвращает ok=false => routedDispatch НЕ будет форсить outbound,
 // и дальше сработает routing (если включён) или default outbound.
+// По умолчанию стоит Egress.Choose, который ведёт себя так же, пока
+// не настроен и не получил ни одной привязки.
 func DefaultChooser(ctx context.Context, uuid string) (string, bool, error) {
 	return "", false, nil
 }
@@ -32,11 +34,19 @@ func chooseRaw(ctx context.Context, uuid string) (string, bool, error) {
 }
 
 // Выбор outbound c учётом deviceKey:
-// 1) смотрим deviceEntry.egressTag
+// 1) смотрим deviceEntry.egressTag (если включён health check Egress —
+// только пока observatory считает этот outbound живым)
 // 2) если нет — вызываем raw chooser, сохраняем в deviceEntry.egressTag
 func ChooseOutboundTagForDevice(ctx context.Context, uuid string, deviceKey string) (string, bool, error) {
+	// один снимок observatory и на проверку, и на выбор в Egress.Choose
+	alive := Egress.liveness(ctx)
+	ctx = context.WithValue(ctx, livenessKey{}, alive)
+
 	if tag, ok := DeviceGetEgress(deviceKey); ok && tag != "" {
-		return tag, true, nil
+		if alive(tag) {
+			return tag, true, nil
+		}
+		// мёртвый outbound не держим всю жизнь устройства: выбираем заново
 	}
 
 	tag, ok, err := chooseRaw(ctx, uuid)
@@ -49,5 +59,5 @@ func ChooseOutboundTagForDevice(ctx context.Context, uuid string, deviceKey stri
 }
 
 func init() {
-	SetOutboundChooser(DefaultChooser)
+	SetOutboundChooser(Egress.Choose)
 }
@@ -2,6 +2,7 @@ package command
 
 import (
 	"context"
+	"sort"
 	"strings"
 	"time"
 
@@ -235,6 +236,10 @@ func (s *Service) ClearDeviceLimit(ctx context.Context, req *ratelimitpb.ClearDe
 
 	connID, ok := ratelimit.DeviceConnID(req.DeviceKey)
 	if !ok {
+		// устройство могло ещё не переподключиться после рестарта
+		if ratelimit.ClearRestoredDeviceLimit(req.DeviceKey) {
+			return &ratelimitpb.ClearDeviceLimitResponse{}, nil
+		}
 		return nil, errors.New("device_key not found: ", req.DeviceKey)
 	}
 
@@ -251,29 +256,355 @@ func (s *Service) SetUserTotalLimit(
 		return nil, errors.New("uuid is empty")
 	}
 
-	devices := ratelimit.ListDevicesByUUID(req.Uuid)
-	n := len(devices)
+	// Лимит живёт на уровне пользователя: per-conn bucket'ы устройств
+	// списывают из общего parent-bucket'а, новые устройства подхватывают его сами.
+	ratelimit.Limits.SetUserTotal(req.Uuid, req.DownBps, req.UpBps)
+
+	total, _ := ratelimit.Limits.GetUserTotal(req.Uuid)
+	resp := &ratelimitpb.SetUserTotalLimitResponse{
+		TotalDownBps: total.Down,
+		TotalUpBps:   total.Up,
+	}
+	if n := len(ratelimit.ListDevicesByUUID(req.Uuid)); n > 0 {
+		resp.DeviceCount = uint32(n)
+		resp.PerDeviceDownBps = req.DownBps / uint64(n)
+		resp.PerDeviceUpBps = req.UpBps / uint64(n)
+	}
+	return resp, nil
+}
+
+func (s *Service) ClearUserTotalLimit(
+	ctx context.Context,
+	req *ratelimitpb.ClearUserTotalLimitRequest,
+) (*ratelimitpb.ClearUserTotalLimitResponse, error) {
+
+	if req.Uuid == "" {
+		return nil, errors.New("uuid is empty")
+	}
+
+	cleared := ratelimit.Limits.ClearUserTotal(req.Uuid)
+	return &ratelimitpb.ClearUserTotalLimitResponse{Cleared: cleared}, nil
+}
+
+func (s *Service) SetInboundTotalLimit(
+	ctx context.Context,
+	req *ratelimitpb.SetInboundTotalLimitRequest,
+) (*ratelimitpb.SetInboundTotalLimitResponse, error) {
+
+	if req.InboundTag == "" {
+		return nil, errors.New("inbound_tag is empty")
+	}
+
+	ratelimit.Limits.SetInboundTotal(req.InboundTag, req.DownBps, req.UpBps)
+	return &ratelimitpb.SetInboundTotalLimitResponse{}, nil
+}
+
+func (s *Service) ClearInboundTotalLimit(
+	ctx context.Context,
+	req *ratelimitpb.ClearInboundTotalLimitRequest,
+) (*ratelimitpb.ClearInboundTotalLimitResponse, error) {
+
+	if req.InboundTag == "" {
+		return nil, errors.New("inbound_tag is empty")
+	}
+
+	cleared := ratelimit.Limits.ClearInboundTotal(req.InboundTag)
+	return &ratelimitpb.ClearInboundTotalLimitResponse{Cleared: cleared}, nil
+}
+
+func (s *Service) SetGlobalTotalLimit(
+	ctx context.Context,
+	req *ratelimitpb.SetGlobalTotalLimitRequest,
+) (*ratelimitpb.SetGlobalTotalLimitResponse, error) {
+	ratelimit.Limits.SetGlobalTotal(req.DownBps, req.UpBps)
+	return &ratelimitpb.SetGlobalTotalLimitResponse{}, nil
+}
+
+func (s *Service) ClearGlobalTotalLimit(
+	ctx context.Context,
+	req *ratelimitpb.ClearGlobalTotalLimitRequest,
+) (*ratelimitpb.ClearGlobalTotalLimitResponse, error) {
+	cleared := ratelimit.Limits.ClearGlobalTotal()
+	return &ratelimitpb.ClearGlobalTotalLimitResponse{Cleared: cleared}, nil
+}
+
+func quotaFromPB(q *ratelimitpb.Quota) ratelimit.Quota {
+	return ratelimit.Quota{
+		Period:     ratelimit.QuotaPeriod(q.Period),
+		Direction:  ratelimit.QuotaDirection(q.Direction),
+		LimitBytes: q.LimitBytes,
+		Action:     ratelimit.QuotaAction(q.Action),
+		Penalty: ratelimit.RateBps{
+			Down: q.PenaltyDownBps,
+			Up:   q.PenaltyUpBps,
+		},
+	}
+}
+
+func quotaToPB(q ratelimit.Quota) *ratelimitpb.Quota {
+	return &ratelimitpb.Quota{
+		Period:         ratelimitpb.Quota_Period(q.Period),
+		Direction:      ratelimitpb.Quota_Direction(q.Direction),
+		LimitBytes:     q.LimitBytes,
+		Action:         ratelimitpb.Quota_Action(q.Action),
+		PenaltyDownBps: q.Penalty.Down,
+		PenaltyUpBps:   q.Penalty.Up,
+	}
+}
+
+func (s *Service) SetUserQuota(ctx context.Context, req *ratelimitpb.SetUserQuotaRequest) (*ratelimitpb.SetUserQuotaResponse, error) {
+	if req.Uuid == "" {
+		return nil, errors.New("uuid is empty")
+	}
+	if req.Quota == nil {
+		return nil, errors.New("quota is empty")
+	}
+
+	ratelimit.Quotas.Set(req.Uuid, quotaFromPB(req.Quota))
+	return &ratelimitpb.SetUserQuotaResponse{}, nil
+}
 
-	if n == 0 {
-		return &ratelimitpb.SetUserTotalLimitResponse{
-			DeviceCount: 0,
-		}, nil
+func (s *Service) ClearUserQuota(ctx context.Context, req *ratelimitpb.ClearUserQuotaRequest) (*ratelimitpb.ClearUserQuotaResponse, error) {
+	if req.Uuid == "" {
+		return nil, errors.New("uuid is empty")
 	}
 
-	down := req.DownBps / uint64(n)
-	up := req.UpBps / uint64(n)
+	cleared := ratelimit.Quotas.Clear(req.Uuid)
+	return &ratelimitpb.ClearUserQuotaResponse{Cleared: cleared}, nil
+}
+
+func (s *Service) GetUserQuota(ctx context.Context, req *ratelimitpb.GetUserQuotaRequest) (*ratelimitpb.GetUserQuotaResponse, error) {
+	if req.Uuid == "" {
+		return nil, errors.New("uuid is empty")
+	}
+
+	usage, ok := ratelimit.Quotas.Get(req.Uuid)
+	if !ok {
+		return nil, errors.New("quota not found: ", req.Uuid)
+	}
 
-	for _, d := range devices {
-		ratelimit.Limits.SetConnLimit(d.ConnID, down, up)
+	var periodStart uint64
+	if !usage.PeriodStart.IsZero() {
+		periodStart = uint64(usage.PeriodStart.Unix())
 	}
 
-	return &ratelimitpb.SetUserTotalLimitResponse{
-		DeviceCount:      uint32(n),
-		PerDeviceDownBps: down,
-		PerDeviceUpBps:   up,
+	return &ratelimitpb.GetUserQuotaResponse{
+		Uuid:            req.Uuid,
+		Quota:           quotaToPB(usage.Quota),
+		UpBytes:         usage.UpBytes,
+		DownBytes:       usage.DownBytes,
+		PeriodStartUnix: periodStart,
+		Exceeded:        usage.Exceeded,
 	}, nil
 }
 
+func (s *Service) ResetUserQuotaUsage(ctx context.Context, req *ratelimitpb.ResetUserQuotaUsageRequest) (*ratelimitpb.ResetUserQuotaUsageResponse, error) {
+	if req.Uuid == "" {
+		return nil, errors.New("uuid is empty")
+	}
+
+	found := ratelimit.Quotas.Reset(req.Uuid)
+	return &ratelimitpb.ResetUserQuotaUsageResponse{Found: found}, nil
+}
+
+func (s *Service) SetMaxDevices(ctx context.Context, req *ratelimitpb.SetMaxDevicesRequest) (*ratelimitpb.SetMaxDevicesResponse, error) {
+	if req.Limit == nil {
+		return nil, errors.New("limit is empty")
+	}
+
+	m := ratelimit.MaxDevices{
+		Max:    req.Limit.Max,
+		Policy: ratelimit.DevicePolicy(req.Limit.Policy),
+	}
+	if req.Uuid == "" {
+		ratelimit.MaxDevicesLimits.SetDefault(m)
+	} else {
+		ratelimit.MaxDevicesLimits.SetUser(req.Uuid, m)
+	}
+	return &ratelimitpb.SetMaxDevicesResponse{}, nil
+}
+
+func (s *Service) ClearMaxDevices(ctx context.Context, req *ratelimitpb.ClearMaxDevicesRequest) (*ratelimitpb.ClearMaxDevicesResponse, error) {
+	var cleared bool
+	if req.Uuid == "" {
+		cleared = ratelimit.MaxDevicesLimits.ClearDefault()
+	} else {
+		cleared = ratelimit.MaxDevicesLimits.ClearUser(req.Uuid)
+	}
+	return &ratelimitpb.ClearMaxDevicesResponse{Cleared: cleared}, nil
+}
+
+func (s *Service) GetMaxDevices(ctx context.Context, req *ratelimitpb.GetMaxDevicesRequest) (*ratelimitpb.GetMaxDevicesResponse, error) {
+	resp := &ratelimitpb.GetMaxDevicesResponse{Uuid: req.Uuid}
+
+	var (
+		m  ratelimit.MaxDevices
+		ok bool
+	)
+	if req.Uuid == "" {
+		m, ok = ratelimit.MaxDevicesLimits.GetDefault()
+	} else {
+		m, ok = ratelimit.MaxDevicesLimits.Get(req.Uuid)
+		resp.PerUser = ratelimit.MaxDevicesLimits.HasUser(req.Uuid)
+
+		c := ratelimit.MaxDevicesLimits.Counters(req.Uuid)
+		resp.ActiveDevices = uint32(ratelimit.ActiveDeviceCount(req.Uuid))
+		resp.Rejected = c.Rejected
+		resp.Evicted = c.Evicted
+		resp.Exceeded = c.Exceeded
+	}
+
+	resp.Found = ok
+	if ok {
+		resp.Limit = &ratelimitpb.MaxDevices{
+			Max:    m.Max,
+			Policy: ratelimitpb.MaxDevices_Policy(m.Policy),
+		}
+	}
+	return resp, nil
+}
+
+func rateFromPB(r *ratelimitpb.Rate) *ratelimit.RateBps {
+	if r == nil {
+		return nil
+	}
+	return &ratelimit.RateBps{Down: r.DownBps, Up: r.UpBps}
+}
+
+func rateToPB(r *ratelimit.RateBps) *ratelimitpb.Rate {
+	if r == nil {
+		return nil
+	}
+	return &ratelimitpb.Rate{DownBps: r.Down, UpBps: r.Up}
+}
+
+func scheduleFromPB(sch *ratelimitpb.Schedule) ratelimit.Schedule {
+	out := ratelimit.Schedule{TimeZone: sch.TimeZone}
+	for _, w := range sch.Windows {
+		win := ratelimit.ScheduleWindow{
+			StartMinute: int(w.StartMinute),
+			EndMinute:   int(w.EndMinute),
+			PerConn:     rateFromPB(w.PerConn),
+			Total:       rateFromPB(w.Total),
+		}
+		for _, d := range w.Days {
+			win.Days = append(win.Days, time.Weekday(d))
+		}
+		out.Windows = append(out.Windows, win)
+	}
+	return out
+}
+
+func scheduleToPB(sch ratelimit.Schedule) *ratelimitpb.Schedule {
+	out := &ratelimitpb.Schedule{TimeZone: sch.TimeZone}
+	for _, w := range sch.Windows {
+		win := &ratelimitpb.ScheduleWindow{
+			StartMinute: uint32(w.StartMinute),
+			EndMinute:   uint32(w.EndMinute),
+			PerConn:     rateToPB(w.PerConn),
+			Total:       rateToPB(w.Total),
+		}
+		for _, d := range w.Days {
+			win.Days = append(win.Days, uint32(d))
+		}
+		out.Windows = append(out.Windows, win)
+	}
+	return out
+}
+
+func (s *Service) SetSchedule(ctx context.Context, req *ratelimitpb.SetScheduleRequest) (*ratelimitpb.SetScheduleResponse, error) {
+	if req.Schedule == nil {
+		return nil, errors.New("schedule is empty")
+	}
+
+	sch := scheduleFromPB(req.Schedule)
+	var err error
+	if req.Uuid != "" {
+		err = ratelimit.Schedules.SetUser(req.Uuid, sch)
+	} else {
+		err = ratelimit.Schedules.SetLevel(req.Level, sch)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &ratelimitpb.SetScheduleResponse{}, nil
+}
+
+func (s *Service) ClearSchedule(ctx context.Context, req *ratelimitpb.ClearScheduleRequest) (*ratelimitpb.ClearScheduleResponse, error) {
+	var cleared bool
+	if req.Uuid != "" {
+		cleared = ratelimit.Schedules.ClearUser(req.Uuid)
+	} else {
+		cleared = ratelimit.Schedules.ClearLevel(req.Level)
+	}
+	return &ratelimitpb.ClearScheduleResponse{Cleared: cleared}, nil
+}
+
+func (s *Service) GetSchedule(ctx context.Context, req *ratelimitpb.GetScheduleRequest) (*ratelimitpb.GetScheduleResponse, error) {
+	var (
+		sch    ratelimit.Schedule
+		active int
+		ok     bool
+	)
+	if req.Uuid != "" {
+		sch, active, ok = ratelimit.Schedules.GetUser(req.Uuid)
+	} else {
+		sch, active, ok = ratelimit.Schedules.GetLevel(req.Level)
+	}
+	if !ok {
+		return &ratelimitpb.GetScheduleResponse{ActiveWindow: -1}, nil
+	}
+	return &ratelimitpb.GetScheduleResponse{
+		Schedule:     scheduleToPB(sch),
+		Found:        true,
+		ActiveWindow: int32(active),
+	}, nil
+}
+
+func deviceEventToPB(ev ratelimit.DeviceEvent) *ratelimitpb.DeviceEvent {
+	return &ratelimitpb.DeviceEvent{
+		Type:         ratelimitpb.DeviceEvent_Type(ev.Type),
+		TimeUnixMs:   uint64(ev.Time.UnixMilli()),
+		Uuid:         ev.UUID,
+		DeviceKey:    ev.DeviceKey,
+		ConnId:       uint64(ev.ConnID),
+		LimitScope:   ratelimitpb.DeviceEvent_LimitScope(ev.LimitScope),
+		DownBps:      ev.Limit.Down,
+		UpBps:        ev.Limit.Up,
+		LimitCleared: ev.LimitCleared,
+		EgressTag:    ev.EgressTag,
+	}
+}
+
+func (s *Service) SubscribeDeviceEvents(req *ratelimitpb.SubscribeDeviceEventsRequest, stream ratelimitpb.RateLimitService_SubscribeDeviceEventsServer) error {
+	subscriber, err := ratelimit.DeviceEvents.Subscribe()
+	if err != nil {
+		return err
+	}
+	defer ratelimit.DeviceEvents.Unsubscribe(subscriber)
+
+	for {
+		select {
+		case value, ok := <-subscriber:
+			if !ok {
+				return errors.New("device event channel closed")
+			}
+			ev, ok := value.(ratelimit.DeviceEvent)
+			if !ok {
+				return errors.New("malformed device event")
+			}
+			if req.Uuid != "" && ev.UUID != req.Uuid {
+				continue
+			}
+			if err := stream.Send(deviceEventToPB(ev)); err != nil {
+				return err
+			}
+		case <-stream.Context().Done():
+			return stream.Context().Err()
+		}
+	}
+}
+
 func (s *Service) ListUserConnections(ctx context.Context, req *ratelimitpb.ListUserConnectionsRequest) (*ratelimitpb.ListUserConnectionsResponse, error) {
 	conns := ratelimit.Global.ListByUUID(req.Uuid)
 
@@ -398,6 +729,138 @@ func (s *Service) ClearConnectionLimit(ctx context.Context, req *ratelimitpb.Cle
 	return &ratelimitpb.ClearConnectionLimitResponse{}, nil
 }
 
+func bucketShapeFromPB(s *ratelimitpb.BucketShape) *ratelimit.BucketShape {
+	if s == nil {
+		return nil
+	}
+	return &ratelimit.BucketShape{
+		BurstBytes:   s.BurstBytes,
+		BurstMs:      s.BurstMs,
+		InitialBytes: s.InitialBytes,
+	}
+}
+
+func bucketShapeToPB(s *ratelimit.BucketShape) *ratelimitpb.BucketShape {
+	if s == nil {
+		return nil
+	}
+	return &ratelimitpb.BucketShape{
+		BurstBytes:   s.BurstBytes,
+		BurstMs:      s.BurstMs,
+		InitialBytes: s.InitialBytes,
+	}
+}
+
+func limitShapeFromPB(l *ratelimitpb.LimitShape) ratelimit.LimitShape {
+	out := ratelimit.LimitShape{
+		PerConn: bucketShapeFromPB(l.PerConn),
+		Total:   bucketShapeFromPB(l.Total),
+	}
+	if l.Boost != nil {
+		out.Boost = &ratelimit.Boost{
+			Bytes:    l.Boost.Bytes,
+			Duration: time.Duration(l.Boost.Seconds) * time.Second,
+		}
+	}
+	return out
+}
+
+func limitShapeToPB(l ratelimit.LimitShape) *ratelimitpb.LimitShape {
+	out := &ratelimitpb.LimitShape{
+		PerConn: bucketShapeToPB(l.PerConn),
+		Total:   bucketShapeToPB(l.Total),
+	}
+	if l.Boost != nil {
+		out.Boost = &ratelimitpb.Boost{
+			Bytes:   l.Boost.Bytes,
+			Seconds: uint32(l.Boost.Duration / time.Second),
+		}
+	}
+	return out
+}
+
+func (s *Service) SetBucketShape(ctx context.Context, req *ratelimitpb.SetBucketShapeRequest) (*ratelimitpb.SetBucketShapeResponse, error) {
+	if req.Shape == nil {
+		return nil, errors.New("shape is empty")
+	}
+
+	l := limitShapeFromPB(req.Shape)
+	if req.Uuid == "" {
+		ratelimit.Shapes.SetDefault(l)
+	} else {
+		ratelimit.Shapes.SetUser(req.Uuid, l)
+	}
+	return &ratelimitpb.SetBucketShapeResponse{}, nil
+}
+
+func (s *Service) ClearBucketShape(ctx context.Context, req *ratelimitpb.ClearBucketShapeRequest) (*ratelimitpb.ClearBucketShapeResponse, error) {
+	var cleared bool
+	if req.Uuid == "" {
+		cleared = ratelimit.Shapes.ClearDefault()
+	} else {
+		cleared = ratelimit.Shapes.ClearUser(req.Uuid)
+	}
+	return &ratelimitpb.ClearBucketShapeResponse{Cleared: cleared}, nil
+}
+
+func (s *Service) GetBucketShape(ctx context.Context, req *ratelimitpb.GetBucketShapeRequest) (*ratelimitpb.GetBucketShapeResponse, error) {
+	var (
+		l  ratelimit.LimitShape
+		ok bool
+	)
+	if req.Uuid == "" {
+		l = ratelimit.Shapes.GetDefault()
+		ok = l != (ratelimit.LimitShape{})
+	} else {
+		l, ok = ratelimit.Shapes.GetUser(req.Uuid)
+	}
+
+	resp := &ratelimitpb.GetBucketShapeResponse{
+		Found:     ok,
+		Effective: limitShapeToPB(ratelimit.Shapes.Get(req.Uuid)),
+	}
+	if ok {
+		resp.Shape = limitShapeToPB(l)
+	}
+	return resp, nil
+}
+
+func (s *Service) SetEgressBinding(ctx context.Context, req *ratelimitpb.SetEgressBindingRequest) (*ratelimitpb.SetEgressBindingResponse, error) {
+	if req.Uuid == "" || req.OutboundTag == "" {
+		return nil, errors.New("uuid and outbound tag are required")
+	}
+	ratelimit.Egress.SetBinding(req.Uuid, req.OutboundTag)
+	return &ratelimitpb.SetEgressBindingResponse{}, nil
+}
+
+func (s *Service) ClearEgressBinding(ctx context.Context, req *ratelimitpb.ClearEgressBindingRequest) (*ratelimitpb.ClearEgressBindingResponse, error) {
+	if req.Uuid == "" {
+		return nil, errors.New("uuid is empty")
+	}
+	return &ratelimitpb.ClearEgressBindingResponse{Cleared: ratelimit.Egress.ClearBinding(req.Uuid)}, nil
+}
+
+func (s *Service) ListEgressBindings(ctx context.Context, req *ratelimitpb.ListEgressBindingsRequest) (*ratelimitpb.ListEgressBindingsResponse, error) {
+	cfg := ratelimit.Egress.Config()
+	resp := &ratelimitpb.ListEgressBindingsResponse{
+		Strategy:    ratelimitpb.ListEgressBindingsResponse_Strategy(cfg.Strategy),
+		Outbounds:   cfg.Outbounds,
+		Weights:     cfg.Weights,
+		HealthCheck: cfg.HealthCheck,
+	}
+
+	bindings := ratelimit.Egress.Bindings()
+	uuids := make([]string, 0, len(bindings))
+	for uuid := range bindings {
+		uuids = append(uuids, uuid)
+	}
+	sort.Strings(uuids)
+	for _, uuid := range uuids {
+		resp.Bindings = append(resp.Bindings, &ratelimitpb.EgressBinding{Uuid: uuid, OutboundTag: bindings[uuid]})
+	}
+	return resp, nil
+}
+
 // ---- wiring через common.CreateObject ----
 
 // New создаёт объект, который Commander добавит в список services
@@ -70,3 +70,48 @@ func TestServiceDeviceMethodsWorkInUUIDMode(t *testing.T) {
 		t.Fatal("expected device limit override to be cleared")
 	}
 }
+
+func TestServiceSetUserTotalLimitDoesNotWriteOverrides(t *testing.T) {
+	ratelimit.SetKeyMode(ratelimit.KeyModeDevice)
+	t.Cleanup(func() { ratelimit.SetKeyMode(ratelimit.KeyModeUUID) })
+
+	uuid := fmt.Sprintf("svc-user-total-%d", time.Now().UnixNano())
+	deviceKey := ratelimit.BuildDeviceKey(uuid, "198.51.100.25")
+	connID := ratelimit.DeviceStart(deviceKey, uuid)
+	t.Cleanup(func() {
+		ratelimit.DeviceEnd(deviceKey)
+		ratelimit.Limits.ClearUserTotal(uuid)
+	})
+
+	svc := &Service{}
+	ctx := context.Background()
+
+	resp, err := svc.SetUserTotalLimit(ctx, &ratelimitpb.SetUserTotalLimitRequest{
+		Uuid:    uuid,
+		DownBps: 80000,
+		UpBps:   40000,
+	})
+	if err != nil {
+		t.Fatalf("SetUserTotalLimit returned error: %v", err)
+	}
+	if resp.DeviceCount != 1 || resp.TotalDownBps != 80000 || resp.TotalUpBps != 40000 ||
+		resp.PerDeviceDownBps != 80000 || resp.PerDeviceUpBps != 40000 {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+
+	if _, ok := ratelimit.Limits.GetForConn(uuid, connID); ok {
+		t.Fatal("expected no per-conn override to be written")
+	}
+	total, ok := ratelimit.Limits.GetUserTotal(uuid)
+	if !ok || total.Down != 80000 || total.Up != 40000 {
+		t.Fatalf("unexpected user total: %+v, %v", total, ok)
+	}
+
+	clearResp, err := svc.ClearUserTotalLimit(ctx, &ratelimitpb.ClearUserTotalLimitRequest{Uuid: uuid})
+	if err != nil {
+		t.Fatalf("ClearUserTotalLimit returned error: %v", err)
+	}
+	if !clearResp.Cleared {
+		t.Fatal("expected user total to be cleared")
+	}
+}
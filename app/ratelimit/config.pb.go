@@ -0,0 +1,136 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        v7.34.1
+// source: app/ratelimit/config.proto
+
+package ratelimit
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// StateConfig включает сохранение состояния ratelimit (лимиты, квоты,
+// key mode, grace, egress-привязки) между рестартами.
+type StateConfig struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Путь к state-файлу (JSON).
+	Path string `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	// Как часто сбрасывать снимок на диск; 0 = 60 секунд.
+	IntervalSeconds uint32 `protobuf:"varint,2,opt,name=interval_seconds,json=intervalSeconds,proto3" json:"interval_seconds,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *StateConfig) Reset() {
+	*x = StateConfig{}
+	mi := &file_app_ratelimit_config_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StateConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StateConfig) ProtoMessage() {}
+
+func (x *StateConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_app_ratelimit_config_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StateConfig.ProtoReflect.Descriptor instead.
+func (*StateConfig) Descriptor() ([]byte, []int) {
+	return file_app_ratelimit_config_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *StateConfig) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *StateConfig) GetIntervalSeconds() uint32 {
+	if x != nil {
+		return x.IntervalSeconds
+	}
+	return 0
+}
+
+var File_app_ratelimit_config_proto protoreflect.FileDescriptor
+
+const file_app_ratelimit_config_proto_rawDesc = "" +
+	"\n" +
+	"\x1aapp/ratelimit/config.proto\x12\x12xray.app.ratelimit\"L\n" +
+	"\vStateConfig\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\x12)\n" +
+	"\x10interval_seconds\x18\x02 \x01(\rR\x0fintervalSecondsBX\n" +
+	"\x16com.xray.app.ratelimitP\x01Z'github.com/xtls/xray-core/app/ratelimit\xaa\x02\x12Xray.App.Ratelimitb\x06proto3"
+
+var (
+	file_app_ratelimit_config_proto_rawDescOnce sync.Once
+	file_app_ratelimit_config_proto_rawDescData []byte
+)
+
+func file_app_ratelimit_config_proto_rawDescGZIP() []byte {
+	file_app_ratelimit_config_proto_rawDescOnce.Do(func() {
+		file_app_ratelimit_config_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_app_ratelimit_config_proto_rawDesc), len(file_app_ratelimit_config_proto_rawDesc)))
+	})
+	return file_app_ratelimit_config_proto_rawDescData
+}
+
+var file_app_ratelimit_config_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_app_ratelimit_config_proto_goTypes = []any{
+	(*StateConfig)(nil), // 0: xray.app.ratelimit.StateConfig
+}
+var file_app_ratelimit_config_proto_depIdxs = []int32{
+	0, // [0:0] is the sub-list for method output_type
+	0, // [0:0] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_app_ratelimit_config_proto_init() }
+func file_app_ratelimit_config_proto_init() {
+	if File_app_ratelimit_config_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_app_ratelimit_config_proto_rawDesc), len(file_app_ratelimit_config_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   1,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_app_ratelimit_config_proto_goTypes,
+		DependencyIndexes: file_app_ratelimit_config_proto_depIdxs,
+		MessageInfos:      file_app_ratelimit_config_proto_msgTypes,
+	}.Build()
+	File_app_ratelimit_config_proto = out.File
+	file_app_ratelimit_config_proto_goTypes = nil
+	file_app_ratelimit_config_proto_depIdxs = nil
+}
@@ -9,9 +9,19 @@ func init() {
 }
 
 func destroyConnID(id ConnID) {
+	ci := Global.Get(id)
+
 	buckets.Remove(id)
-	Limits.ClearConnLimit(id)
+	Limits.clearConnLimit(id)
 	Global.Remove(id)
+
+	// последний device пользователя ушёл — parent-bucket больше не нужен,
+	// при следующем подключении он создастся заново из Limits.
+	if ci != nil && len(Global.ListByUUID(ci.UUID)) == 0 {
+		userBuckets.Remove(ci.UUID)
+		forgetUserThrottle(ci.UUID)
+		Schedules.forgetUser(ci.UUID)
+	}
 }
 
 func deviceGC() {
@@ -21,7 +31,7 @@ func deviceGC() {
 	for range t.C {
 		now := time.Now()
 
-		var toDelete []ConnID
+		var toDelete []DeviceEvent
 
 		deviceEntries.mu.Lock()
 		for key, e := range deviceEntries.m {
@@ -31,14 +41,15 @@ func deviceGC() {
 			}
 			if e.refCount == 0 && !e.expires.IsZero() && now.After(e.expires) {
 				delete(deviceEntries.m, key)
-				toDelete = append(toDelete, e.id)
+				toDelete = append(toDelete, DeviceEvent{Type: DeviceEventDestroyed, UUID: e.uuid, DeviceKey: key, ConnID: e.id})
 			}
 		}
 		deviceEntries.mu.Unlock()
 
 		// чистим вне lock
-		for _, id := range toDelete {
-			destroyConnID(id)
+		for _, ev := range toDelete {
+			destroyConnID(ev.ConnID)
+			publishDeviceEvent(ev)
 		}
 	}
 }
@@ -8,6 +8,7 @@ import (
 type deviceEntry struct {
 	id       ConnID
 	refCount int
+	started  time.Time
 	lastSeen time.Time
 	expires  time.Time
 	uuid     string
@@ -18,31 +19,46 @@ type deviceEntry struct {
 var deviceEntries = struct {
 	mu sync.Mutex
 	m  map[string]*deviceEntry
+	// устройства, выкинутые лимитом устройств, но с ещё живыми соединениями
+	evicted map[ConnID]*deviceEntry
 }{
-	m: make(map[string]*deviceEntry),
+	m:       make(map[string]*deviceEntry),
+	evicted: make(map[ConnID]*deviceEntry),
 }
 
 func DeviceStart(deviceKey string, uuid string) ConnID {
 	deviceEntries.mu.Lock()
 	defer deviceEntries.mu.Unlock()
 
+	return deviceStartLocked(deviceKey, uuid)
+}
+
+func deviceStartLocked(deviceKey string, uuid string) ConnID {
 	now := time.Now()
 
 	if e := deviceEntries.m[deviceKey]; e != nil {
+		if e.refCount == 0 {
+			publishDeviceEvent(DeviceEvent{Type: DeviceEventResumed, UUID: e.uuid, DeviceKey: deviceKey, ConnID: e.id})
+		}
 		e.refCount++
 		e.lastSeen = now
 		e.expires = time.Time{} // сброс удаления
 		return e.id
 	}
 
-	ci := Global.Add(uuid)
+	ci := Global.AddDevice(uuid, deviceKey)
 	id := ci.ConnID
-	deviceEntries.m[deviceKey] = &deviceEntry{
+	e := &deviceEntry{
 		id:       id,
 		refCount: 1,
+		started:  now,
 		lastSeen: now,
 		uuid:     uuid,
 	}
+	applyRestoredDeviceLocked(deviceKey, e)
+	deviceEntries.m[deviceKey] = e
+
+	publishDeviceEvent(DeviceEvent{Type: DeviceEventStarted, UUID: uuid, DeviceKey: deviceKey, ConnID: id, EgressTag: e.egressTag})
 	return id
 }
 
@@ -61,6 +77,8 @@ func DeviceEnd(deviceKey string) {
 		// не удаляем сразу — ставим время истечения
 		e.refCount = 0
 		e.expires = time.Now().Add(GetGrace())
+
+		publishDeviceEvent(DeviceEvent{Type: DeviceEventGrace, UUID: e.uuid, DeviceKey: deviceKey, ConnID: e.id})
 	}
 }
 
@@ -87,22 +105,30 @@ func DeviceSetEgress(deviceKey string, tag string) {
 	if e == nil {
 		return // если entry нет — привязку пока некуда писать
 	}
+	if e.egressTag == tag {
+		return
+	}
 	e.egressTag = tag
+
+	publishDeviceEvent(DeviceEvent{Type: DeviceEventEgressChanged, UUID: e.uuid, DeviceKey: deviceKey, ConnID: e.id, EgressTag: tag})
 }
 
 func DeviceClearEgressForUUID(uuid string) int {
 	if uuid == "" {
 		return 0
 	}
+	clearRestoredDevices(restoredOfUUIDs([]string{uuid}), false, true)
 
 	deviceEntries.mu.Lock()
 	defer deviceEntries.mu.Unlock()
 
 	n := 0
-	for _, e := range deviceEntries.m {
+	for key, e := range deviceEntries.m {
 		if e != nil && e.uuid == uuid && e.egressTag != "" {
 			e.egressTag = ""
 			n++
+
+			publishDeviceEvent(DeviceEvent{Type: DeviceEventEgressChanged, UUID: uuid, DeviceKey: key, ConnID: e.id})
 		}
 	}
 	return n
@@ -0,0 +1,280 @@
+package ratelimit
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/xtls/xray-core/common/errors"
+)
+
+// DevicePolicy — что делать, когда у пользователя появляется устройство сверх лимита.
+type DevicePolicy int32
+
+const (
+	DevicePolicyRejectNewest DevicePolicy = 0 // новое устройство не пускаем
+	DevicePolicyEvictOldest  DevicePolicy = 1 // выкидываем самые старые активные устройства
+	DevicePolicyLogOnly      DevicePolicy = 2 // пускаем, но пишем в лог и считаем
+)
+
+var (
+	ErrDeviceLimitExceeded = errors.New("ratelimit: too many devices for user")
+	ErrDeviceEvicted       = errors.New("ratelimit: device evicted by a newer device of the same user")
+)
+
+// MaxDevices — сколько устройств (deviceKey) пользователь может держать одновременно.
+// Max == 0 — без ограничения.
+type MaxDevices struct {
+	Max    uint32       `json:"max"`
+	Policy DevicePolicy `json:"policy"`
+}
+
+// DeviceLimitCounters — сколько раз срабатывал лимит устройств для uuid.
+type DeviceLimitCounters struct {
+	Rejected uint64
+	Evicted  uint64
+	Exceeded uint64 // только для DevicePolicyLogOnly
+}
+
+// MaxDevicesStore хранит лимит устройств: общий (default) и по uuid.
+// Per-uuid значение полностью перекрывает default.
+type MaxDevicesStore struct {
+	mu sync.RWMutex
+
+	def    MaxDevices
+	hasDef bool
+	users  map[string]MaxDevices
+
+	counters map[string]*DeviceLimitCounters
+}
+
+func NewMaxDevicesStore() *MaxDevicesStore {
+	return &MaxDevicesStore{
+		users:    make(map[string]MaxDevices),
+		counters: make(map[string]*DeviceLimitCounters),
+	}
+}
+
+var MaxDevicesLimits = NewMaxDevicesStore()
+
+func (s *MaxDevicesStore) SetDefault(m MaxDevices) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.def = m
+	s.hasDef = true
+}
+
+func (s *MaxDevicesStore) ClearDefault() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	had := s.hasDef
+	s.def = MaxDevices{}
+	s.hasDef = false
+	return had
+}
+
+func (s *MaxDevicesStore) GetDefault() (MaxDevices, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.def, s.hasDef
+}
+
+func (s *MaxDevicesStore) SetUser(uuid string, m MaxDevices) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.users[uuid] = m
+}
+
+func (s *MaxDevicesStore) ClearUser(uuid string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, ok := s.users[uuid]
+	delete(s.users, uuid)
+	return ok
+}
+
+func (s *MaxDevicesStore) HasUser(uuid string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	_, ok := s.users[uuid]
+	return ok
+}
+
+// Get возвращает действующий для uuid лимит (per-uuid или default).
+func (s *MaxDevicesStore) Get(uuid string) (MaxDevices, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if m, ok := s.users[uuid]; ok {
+		return m, true
+	}
+	return s.def, s.hasDef
+}
+
+func (s *MaxDevicesStore) Counters(uuid string) DeviceLimitCounters {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if c := s.counters[uuid]; c != nil {
+		return *c
+	}
+	return DeviceLimitCounters{}
+}
+
+func (s *MaxDevicesStore) record(uuid string, f func(c *DeviceLimitCounters)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c := s.counters[uuid]
+	if c == nil {
+		c = &DeviceLimitCounters{}
+		s.counters[uuid] = c
+	}
+	f(c)
+}
+
+func (s *MaxDevicesStore) snapshot() (*MaxDevices, map[string]MaxDevices) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var def *MaxDevices
+	if s.hasDef {
+		d := s.def
+		def = &d
+	}
+	if len(s.users) == 0 {
+		return def, nil
+	}
+	users := make(map[string]MaxDevices, len(s.users))
+	for uuid, m := range s.users {
+		users[uuid] = m
+	}
+	return def, users
+}
+
+func (s *MaxDevicesStore) restore(def *MaxDevices, users map[string]MaxDevices) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if def != nil {
+		s.def = *def
+		s.hasDef = true
+	}
+	for uuid, m := range users {
+		s.users[uuid] = m
+	}
+}
+
+// DeviceAdmission — чем закончился DeviceAdmit; нужен вызывающему для логов.
+type DeviceAdmission struct {
+	ConnID ConnID
+	Limit  MaxDevices
+	// сколько активных устройств у пользователя было до этого (без текущего)
+	Active int
+	// лимит превышен (для LogOnly устройство всё равно пущено)
+	OverLimit bool
+	// deviceKey устройств, выкинутых политикой EvictOldest
+	Evicted []string
+}
+
+// DeviceAdmit — DeviceStart с проверкой лимита устройств пользователя.
+// Повторный старт уже известного deviceKey (в т.ч. из grace) лимит не проверяет:
+// это то же самое устройство. Устройства в grace в лимит не входят.
+func DeviceAdmit(deviceKey string, uuid string) (DeviceAdmission, error) {
+	var adm DeviceAdmission
+
+	deviceEntries.mu.Lock()
+	defer deviceEntries.mu.Unlock()
+
+	if e := deviceEntries.m[deviceKey]; e == nil {
+		if limit, ok := MaxDevicesLimits.Get(uuid); ok && limit.Max > 0 {
+			adm.Limit = limit
+
+			active := activeDevicesLocked(uuid)
+			adm.Active = len(active)
+
+			if len(active) >= int(limit.Max) {
+				adm.OverLimit = true
+
+				switch limit.Policy {
+				case DevicePolicyEvictOldest:
+					// освобождаем место ровно под одно новое устройство
+					for _, key := range active[:len(active)-int(limit.Max)+1] {
+						evictDeviceLocked(key)
+						adm.Evicted = append(adm.Evicted, key)
+					}
+					MaxDevicesLimits.record(uuid, func(c *DeviceLimitCounters) { c.Evicted += uint64(len(adm.Evicted)) })
+				case DevicePolicyLogOnly:
+					MaxDevicesLimits.record(uuid, func(c *DeviceLimitCounters) { c.Exceeded++ })
+				default:
+					MaxDevicesLimits.record(uuid, func(c *DeviceLimitCounters) { c.Rejected++ })
+					return adm, ErrDeviceLimitExceeded
+				}
+			}
+		}
+	}
+
+	adm.ConnID = deviceStartLocked(deviceKey, uuid)
+	return adm, nil
+}
+
+// activeDevicesLocked — deviceKey активных устройств uuid, от самого старого к новому.
+func activeDevicesLocked(uuid string) []string {
+	var keys []string
+	for key, e := range deviceEntries.m {
+		if e != nil && e.uuid == uuid && e.refCount > 0 {
+			keys = append(keys, key)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		a, b := deviceEntries.m[keys[i]], deviceEntries.m[keys[j]]
+		if !a.started.Equal(b.started) {
+			return a.started.Before(b.started)
+		}
+		return a.id < b.id
+	})
+	return keys
+}
+
+// ActiveDeviceCount — сколько устройств uuid сейчас активно (без grace).
+func ActiveDeviceCount(uuid string) int {
+	deviceEntries.mu.Lock()
+	defer deviceEntries.mu.Unlock()
+	return len(activeDevicesLocked(uuid))
+}
+
+// evictDeviceLocked убирает устройство из deviceEntries: его активные соединения
+// закроются на ближайшем I/O (accountConn вернёт ErrDeviceEvicted), а ConnID
+// будет уничтожен, когда отпустится последнее из них (DeviceRelease).
+func evictDeviceLocked(deviceKey string) {
+	e := deviceEntries.m[deviceKey]
+	if e == nil {
+		return
+	}
+	delete(deviceEntries.m, deviceKey)
+	deviceEntries.evicted[e.id] = e
+
+	if ci := Global.Get(e.id); ci != nil {
+		ci.Evicted.Store(true)
+	}
+
+	publishDeviceEvent(DeviceEvent{Type: DeviceEventEvicted, UUID: e.uuid, DeviceKey: deviceKey, ConnID: e.id})
+}
+
+// DeviceRelease — парный к DeviceAdmit вызов: как DeviceEnd, но знает ConnID,
+// поэтому корректно отпускает и уже выкинутые устройства.
+func DeviceRelease(deviceKey string, id ConnID) {
+	deviceEntries.mu.Lock()
+	if e := deviceEntries.evicted[id]; e != nil {
+		e.refCount--
+		if e.refCount > 0 {
+			deviceEntries.mu.Unlock()
+			return
+		}
+		delete(deviceEntries.evicted, id)
+		deviceEntries.mu.Unlock()
+
+		destroyConnID(id)
+		publishDeviceEvent(DeviceEvent{Type: DeviceEventDestroyed, UUID: e.uuid, DeviceKey: deviceKey, ConnID: id})
+		return
+	}
+	deviceEntries.mu.Unlock()
+
+	DeviceEnd(deviceKey)
+}
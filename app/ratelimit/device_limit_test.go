@@ -0,0 +1,149 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	cctx "github.com/xtls/xray-core/common/ctx"
+	"github.com/xtls/xray-core/common/net"
+	"github.com/xtls/xray-core/common/protocol"
+	"github.com/xtls/xray-core/common/session"
+)
+
+func TestDeviceAdmitRejectNewest(t *testing.T) {
+	uuid := fmt.Sprintf("maxdev-reject-%d", time.Now().UnixNano())
+	MaxDevicesLimits.SetUser(uuid, MaxDevices{Max: 1, Policy: DevicePolicyRejectNewest})
+	t.Cleanup(func() { MaxDevicesLimits.ClearUser(uuid) })
+
+	keyA, keyB := uuid+"|10.0.0.1", uuid+"|10.0.0.2"
+
+	a, err := DeviceAdmit(keyA, uuid)
+	if err != nil {
+		t.Fatalf("first device rejected: %v", err)
+	}
+	t.Cleanup(func() { DeviceRelease(keyA, a.ConnID) })
+
+	if _, err := DeviceAdmit(keyB, uuid); err != ErrDeviceLimitExceeded {
+		t.Fatalf("expected ErrDeviceLimitExceeded, got %v", err)
+	}
+
+	// второе соединение того же устройства — не новое устройство
+	again, err := DeviceAdmit(keyA, uuid)
+	if err != nil || again.ConnID != a.ConnID {
+		t.Fatalf("same device should be admitted with the same ConnID, got %v, %v", again.ConnID, err)
+	}
+	DeviceRelease(keyA, again.ConnID)
+
+	if c := MaxDevicesLimits.Counters(uuid); c.Rejected != 1 {
+		t.Fatalf("expected 1 rejection, got %+v", c)
+	}
+}
+
+func TestDeviceAdmitEvictOldest(t *testing.T) {
+	uuid := fmt.Sprintf("maxdev-evict-%d", time.Now().UnixNano())
+	MaxDevicesLimits.SetUser(uuid, MaxDevices{Max: 1, Policy: DevicePolicyEvictOldest})
+	t.Cleanup(func() { MaxDevicesLimits.ClearUser(uuid) })
+
+	keyA, keyB := uuid+"|10.0.0.1", uuid+"|10.0.0.2"
+
+	a, err := DeviceAdmit(keyA, uuid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := DeviceAdmit(keyB, uuid)
+	if err != nil {
+		t.Fatalf("newest device should be admitted: %v", err)
+	}
+	t.Cleanup(func() { DeviceRelease(keyB, b.ConnID) })
+
+	if len(b.Evicted) != 1 || b.Evicted[0] != keyA {
+		t.Fatalf("expected %s to be evicted, got %v", keyA, b.Evicted)
+	}
+	if err := accountConn(context.Background(), a.ConnID, "", Down, 10); err != ErrDeviceEvicted {
+		t.Fatalf("expected ErrDeviceEvicted on evicted conn, got %v", err)
+	}
+	if err := accountConn(context.Background(), b.ConnID, "", Down, 10); err != nil {
+		t.Fatalf("unexpected error on new conn: %v", err)
+	}
+	if _, ok := DeviceConnID(keyA); ok {
+		t.Fatal("evicted device should be gone from the registry")
+	}
+
+	// ConnID выкинутого устройства живёт, пока его не отпустят
+	if Global.Get(a.ConnID) == nil {
+		t.Fatal("evicted ConnID destroyed too early")
+	}
+	DeviceRelease(keyA, a.ConnID)
+	if Global.Get(a.ConnID) != nil {
+		t.Fatal("evicted ConnID should be destroyed after release")
+	}
+
+	if c := MaxDevicesLimits.Counters(uuid); c.Evicted != 1 {
+		t.Fatalf("expected 1 eviction, got %+v", c)
+	}
+}
+
+func TestDeviceAdmitLogOnly(t *testing.T) {
+	uuid := fmt.Sprintf("maxdev-log-%d", time.Now().UnixNano())
+	MaxDevicesLimits.SetUser(uuid, MaxDevices{Max: 1, Policy: DevicePolicyLogOnly})
+	t.Cleanup(func() { MaxDevicesLimits.ClearUser(uuid) })
+
+	keyA, keyB := uuid+"|10.0.0.1", uuid+"|10.0.0.2"
+
+	a, _ := DeviceAdmit(keyA, uuid)
+	b, err := DeviceAdmit(keyB, uuid)
+	if err != nil || !b.OverLimit {
+		t.Fatalf("expected admitted over-limit device, got %+v, %v", b, err)
+	}
+	t.Cleanup(func() {
+		DeviceRelease(keyA, a.ConnID)
+		DeviceRelease(keyB, b.ConnID)
+	})
+
+	if n := ActiveDeviceCount(uuid); n != 2 {
+		t.Fatalf("expected 2 active devices, got %d", n)
+	}
+	if c := MaxDevicesLimits.Counters(uuid); c.Exceeded != 1 {
+		t.Fatalf("expected 1 exceeded, got %+v", c)
+	}
+}
+
+func TestEnsureConnIDFromContextRejectsOverLimit(t *testing.T) {
+	uuid := fmt.Sprintf("maxdev-ctx-%d", time.Now().UnixNano())
+	MaxDevicesLimits.SetUser(uuid, MaxDevices{Max: 1})
+	t.Cleanup(func() { MaxDevicesLimits.ClearUser(uuid) })
+
+	prev := GetKeyMode()
+	SetKeyMode(KeyModeDevice)
+	t.Cleanup(func() { SetKeyMode(prev) })
+
+	inboundCtx := func(ip string, sid cctx.ID) (context.Context, context.CancelFunc) {
+		ctx, cancel := context.WithCancel(context.Background())
+		ctx = cctx.ContextWithID(ctx, sid)
+		ctx = session.ContextWithInbound(ctx, &session.Inbound{
+			Source: net.TCPDestination(net.ParseAddress(ip), 443),
+			User:   &protocol.MemoryUser{Email: uuid},
+		})
+		return ctx, cancel
+	}
+
+	ctxA, cancelA := inboundCtx("10.0.0.1", cctx.ID(time.Now().UnixNano()))
+	defer cancelA()
+	if _, ok := EnsureConnIDFromContext(ctxA); !ok {
+		t.Fatal("first device should be admitted")
+	}
+	if err := CheckDeviceAllowed(ctxA); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ctxB, cancelB := inboundCtx("10.0.0.2", cctx.ID(time.Now().UnixNano()+1))
+	defer cancelB()
+	if _, ok := EnsureConnIDFromContext(ctxB); ok {
+		t.Fatal("second device should be rejected")
+	}
+	if err := CheckDeviceAllowed(ctxB); err != ErrDeviceLimitExceeded {
+		t.Fatalf("expected ErrDeviceLimitExceeded, got %v", err)
+	}
+}
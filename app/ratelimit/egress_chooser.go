@@ -0,0 +1,287 @@
+package ratelimit
+
+import (
+	"context"
+	"hash/fnv"
+	"math/rand/v2"
+	"sync"
+
+	"github.com/xtls/xray-core/app/observatory"
+	"github.com/xtls/xray-core/common/errors"
+	"github.com/xtls/xray-core/core"
+	"github.com/xtls/xray-core/features/extension"
+)
+
+// ChooserStrategy — как EgressChooser выбирает outbound для устройства,
+// у которого ещё нет привязки.
+type ChooserStrategy int32
+
+const (
+	ChooserMapOnly      ChooserStrategy = 0 // только явные привязки uuid -> tag
+	ChooserHash         ChooserStrategy = 1 // consistent hash uuid по Outbounds
+	ChooserLeastDevices ChooserStrategy = 2 // outbound с наименьшим числом активных устройств
+	ChooserRandom       ChooserStrategy = 3 // случайный outbound с весами Weights
+)
+
+// ChooserConfig — настройка встроенного chooser'а.
+type ChooserConfig struct {
+	Strategy  ChooserStrategy
+	Outbounds []string
+	// вес outbound'а для ChooserRandom; нет в карте — вес 1, 0 — не выбирать
+	Weights map[string]uint32
+	// не выбирать и не держать привязку к outbound'у, который observatory
+	// считает мёртвым
+	HealthCheck bool
+}
+
+func (c ChooserConfig) validate() error {
+	switch c.Strategy {
+	case ChooserMapOnly:
+		return nil
+	case ChooserHash, ChooserLeastDevices, ChooserRandom:
+		if len(c.Outbounds) == 0 {
+			return errors.New("ratelimit: chooser needs at least one outbound")
+		}
+		return nil
+	}
+	return errors.New("ratelimit: unknown chooser strategy ", int32(c.Strategy))
+}
+
+// EgressChooser — встроенная реализация ChooseOutboundFunc. Явная привязка
+// uuid -> tag (её можно менять через RPC) важнее стратегии.
+type EgressChooser struct {
+	mu       sync.RWMutex
+	cfg      ChooserConfig
+	bindings map[string]string
+
+	// снимок живости outbound'ов для HealthCheck; подменяется в тестах
+	observe func(ctx context.Context) liveness
+}
+
+// liveness — жив ли outbound по одному снимку observatory.
+type liveness func(tag string) bool
+
+func allAlive(string) bool { return true }
+
+type livenessKey struct{}
+
+func NewEgressChooser() *EgressChooser {
+	return &EgressChooser{
+		bindings: make(map[string]string),
+		observe:  observeOutbounds,
+	}
+}
+
+// Egress — chooser, которым по умолчанию пользуется ChooseOutboundTagForDevice.
+// Пока стратегия ChooserMapOnly и привязок нет, он ничего не выбирает.
+var Egress = NewEgressChooser()
+
+func (c *EgressChooser) Configure(cfg ChooserConfig) error {
+	if err := cfg.validate(); err != nil {
+		return err
+	}
+	cfg.Outbounds = append([]string(nil), cfg.Outbounds...)
+	weights := make(map[string]uint32, len(cfg.Weights))
+	for tag, w := range cfg.Weights {
+		weights[tag] = w
+	}
+	cfg.Weights = weights
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.cfg = cfg
+	return nil
+}
+
+func (c *EgressChooser) Config() ChooserConfig {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.cfg
+}
+
+// liveness — можно ли держать устройство на outbound'е: без HealthCheck
+// всегда да. Снимок observatory берётся один раз на выбор: если его уже
+// положил в ctx ChooseOutboundTagForDevice, используется он.
+func (c *EgressChooser) liveness(ctx context.Context) liveness {
+	c.mu.RLock()
+	check := c.cfg.HealthCheck
+	c.mu.RUnlock()
+	if !check {
+		return allAlive
+	}
+	if alive, ok := ctx.Value(livenessKey{}).(liveness); ok {
+		return alive
+	}
+	return c.observe(ctx)
+}
+
+// SetBinding привязывает uuid к outbound'у. Уже выбранные egress устройств
+// uuid сбрасываются, чтобы привязка подействовала на следующем соединении.
+func (c *EgressChooser) SetBinding(uuid, tag string) {
+	c.mu.Lock()
+	c.bindings[uuid] = tag
+	c.mu.Unlock()
+
+	DeviceClearEgressForUUID(uuid)
+}
+
+func (c *EgressChooser) ClearBinding(uuid string) bool {
+	c.mu.Lock()
+	_, ok := c.bindings[uuid]
+	delete(c.bindings, uuid)
+	c.mu.Unlock()
+
+	if ok {
+		DeviceClearEgressForUUID(uuid)
+	}
+	return ok
+}
+
+func (c *EgressChooser) Bindings() map[string]string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	out := make(map[string]string, len(c.bindings))
+	for uuid, tag := range c.bindings {
+		out[uuid] = tag
+	}
+	return out
+}
+
+func (c *EgressChooser) restoreBindings(bindings map[string]string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for uuid, tag := range bindings {
+		c.bindings[uuid] = tag
+	}
+}
+
+// Choose реализует ChooseOutboundFunc.
+func (c *EgressChooser) Choose(ctx context.Context, uuid string) (string, bool, error) {
+	c.mu.RLock()
+	cfg := c.cfg
+	tag, bound := c.bindings[uuid]
+	c.mu.RUnlock()
+
+	alive := c.liveness(ctx)
+
+	if bound {
+		if alive(tag) {
+			return tag, true, nil
+		}
+		// привязанный outbound мёртв — дальше решает стратегия или routing
+	}
+
+	candidates := make([]string, 0, len(cfg.Outbounds))
+	for _, t := range cfg.Outbounds {
+		if alive(t) {
+			candidates = append(candidates, t)
+		}
+	}
+	if len(candidates) == 0 {
+		return "", false, nil
+	}
+
+	switch cfg.Strategy {
+	case ChooserHash:
+		return hashChoose(uuid, candidates), true, nil
+	case ChooserLeastDevices:
+		return leastDevicesChoose(candidates), true, nil
+	case ChooserRandom:
+		if t, ok := weightedChoose(candidates, cfg.Weights); ok {
+			return t, true, nil
+		}
+	}
+	return "", false, nil
+}
+
+// hashChoose — rendezvous hashing: при выпадении outbound'а из candidates
+// переезжают только его пользователи.
+func hashChoose(uuid string, candidates []string) string {
+	var best string
+	var bestScore uint64
+	for _, tag := range candidates {
+		h := fnv.New64a()
+		h.Write([]byte(uuid))
+		h.Write([]byte{0})
+		h.Write([]byte(tag))
+		if s := h.Sum64(); best == "" || s > bestScore {
+			best, bestScore = tag, s
+		}
+	}
+	return best
+}
+
+func leastDevicesChoose(candidates []string) string {
+	counts := make(map[string]int, len(candidates))
+
+	deviceEntries.mu.Lock()
+	for _, e := range deviceEntries.m {
+		if e != nil && e.refCount > 0 && e.egressTag != "" {
+			counts[e.egressTag]++
+		}
+	}
+	deviceEntries.mu.Unlock()
+
+	best := candidates[0]
+	for _, tag := range candidates[1:] {
+		if counts[tag] < counts[best] {
+			best = tag
+		}
+	}
+	return best
+}
+
+func weightedChoose(candidates []string, weights map[string]uint32) (string, bool) {
+	var total uint64
+	for _, tag := range candidates {
+		total += uint64(chooserWeight(weights, tag))
+	}
+	if total == 0 {
+		return "", false
+	}
+	n := rand.Uint64N(total)
+	for _, tag := range candidates {
+		w := uint64(chooserWeight(weights, tag))
+		if n < w {
+			return tag, true
+		}
+		n -= w
+	}
+	return "", false
+}
+
+func chooserWeight(weights map[string]uint32, tag string) uint32 {
+	if w, ok := weights[tag]; ok {
+		return w
+	}
+	return 1
+}
+
+// observeOutbounds снимает живость outbound'ов с observatory. Если
+// observatory нет или она не следит за tag, outbound считается живым.
+func observeOutbounds(ctx context.Context) liveness {
+	inst := core.FromContext(ctx)
+	if inst == nil {
+		return allAlive
+	}
+	obs, ok := inst.GetFeature(extension.ObservatoryType()).(extension.Observatory)
+	if !ok || obs == nil {
+		return allAlive
+	}
+	msg, err := obs.GetObservation(ctx)
+	if err != nil {
+		return allAlive
+	}
+	result, ok := msg.(*observatory.ObservationResult)
+	if !ok {
+		return allAlive
+	}
+	status := make(map[string]bool, len(result.GetStatus()))
+	for _, s := range result.GetStatus() {
+		status[s.OutboundTag] = s.Alive
+	}
+	return func(tag string) bool {
+		alive, ok := status[tag]
+		return !ok || alive
+	}
+}
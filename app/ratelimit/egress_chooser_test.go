@@ -0,0 +1,170 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func newTestChooser(t *testing.T, cfg ChooserConfig, dead ...string) *EgressChooser {
+	t.Helper()
+	c := NewEgressChooser()
+	c.observe = func(ctx context.Context) liveness {
+		return func(tag string) bool {
+			for _, d := range dead {
+				if d == tag {
+					return false
+				}
+			}
+			return true
+		}
+	}
+	if err := c.Configure(cfg); err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func TestEgressChooserHashIsStable(t *testing.T) {
+	outbounds := []string{"a", "b", "c"}
+	c := newTestChooser(t, ChooserConfig{Strategy: ChooserHash, Outbounds: outbounds})
+	// "c" мёртв: переехать должны только пользователи "c"
+	dead := newTestChooser(t, ChooserConfig{Strategy: ChooserHash, Outbounds: outbounds, HealthCheck: true}, "c")
+
+	used := make(map[string]bool)
+	for i := 0; i < 100; i++ {
+		uuid := fmt.Sprintf("user-%d", i)
+		tag, ok, _ := c.Choose(context.Background(), uuid)
+		if !ok {
+			t.Fatalf("expected a choice for %s", uuid)
+		}
+		if again, _, _ := c.Choose(context.Background(), uuid); again != tag {
+			t.Fatalf("hash choice for %s changed: %s -> %s", uuid, tag, again)
+		}
+		used[tag] = true
+
+		moved, _, _ := dead.Choose(context.Background(), uuid)
+		if moved == "c" || (tag != "c" && moved != tag) {
+			t.Fatalf("user %s moved from %s to %s", uuid, tag, moved)
+		}
+	}
+	if len(used) != len(outbounds) {
+		t.Fatalf("expected users spread over all outbounds, got %v", used)
+	}
+}
+
+func TestEgressChooserBindingPrecedence(t *testing.T) {
+	c := newTestChooser(t, ChooserConfig{Strategy: ChooserHash, Outbounds: []string{"a"}, HealthCheck: true}, "dead")
+
+	c.SetBinding("u", "pinned")
+	if tag, ok, _ := c.Choose(context.Background(), "u"); !ok || tag != "pinned" {
+		t.Fatalf("expected binding to win, got %q %v", tag, ok)
+	}
+
+	// привязка к мёртвому outbound'у уступает стратегии
+	c.SetBinding("u", "dead")
+	if tag, _, _ := c.Choose(context.Background(), "u"); tag != "a" {
+		t.Fatalf("expected fallback to a, got %q", tag)
+	}
+
+	if !c.ClearBinding("u") || c.ClearBinding("u") {
+		t.Fatal("expected ClearBinding to report the binding once")
+	}
+}
+
+func TestEgressChooserMapOnly(t *testing.T) {
+	c := newTestChooser(t, ChooserConfig{})
+	if _, ok, _ := c.Choose(context.Background(), "u"); ok {
+		t.Fatal("expected no choice without bindings")
+	}
+	c.SetBinding("u", "x")
+	if tag, ok, _ := c.Choose(context.Background(), "u"); !ok || tag != "x" {
+		t.Fatalf("expected bound tag, got %q %v", tag, ok)
+	}
+}
+
+func TestEgressChooserWeights(t *testing.T) {
+	c := newTestChooser(t, ChooserConfig{
+		Strategy:  ChooserRandom,
+		Outbounds: []string{"a", "b", "c"},
+		Weights:   map[string]uint32{"a": 0, "b": 3},
+	})
+
+	counts := make(map[string]int)
+	for i := 0; i < 4000; i++ {
+		tag, ok, _ := c.Choose(context.Background(), "u")
+		if !ok {
+			t.Fatal("expected a choice")
+		}
+		counts[tag]++
+	}
+	if counts["a"] != 0 {
+		t.Fatalf("outbound with weight 0 was chosen %d times", counts["a"])
+	}
+	// b:c = 3:1
+	if counts["b"] < 2*counts["c"] {
+		t.Fatalf("unexpected distribution: %v", counts)
+	}
+
+	zero := newTestChooser(t, ChooserConfig{
+		Strategy:  ChooserRandom,
+		Outbounds: []string{"a"},
+		Weights:   map[string]uint32{"a": 0},
+	})
+	if _, ok, _ := zero.Choose(context.Background(), "u"); ok {
+		t.Fatal("expected no choice when all weights are zero")
+	}
+}
+
+func TestEgressChooserConfigValidation(t *testing.T) {
+	c := NewEgressChooser()
+	if err := c.Configure(ChooserConfig{Strategy: ChooserLeastDevices}); err == nil {
+		t.Fatal("expected error without outbounds")
+	}
+	if err := c.Configure(ChooserConfig{Strategy: 42, Outbounds: []string{"a"}}); err == nil {
+		t.Fatal("expected error for unknown strategy")
+	}
+}
+
+func TestChooseOutboundTagForDeviceObservesOnce(t *testing.T) {
+	oldCfg, oldObserve := Egress.Config(), Egress.observe
+	t.Cleanup(func() {
+		Egress.observe = oldObserve
+		if err := Egress.Configure(oldCfg); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	observed := 0
+	Egress.observe = func(ctx context.Context) liveness {
+		observed++
+		return func(tag string) bool { return tag != "a" }
+	}
+	if err := Egress.Configure(ChooserConfig{Strategy: ChooserHash, Outbounds: []string{"a", "b", "c"}, HealthCheck: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	uuid := fmt.Sprintf("observe-%d", time.Now().UnixNano())
+	deviceKey := uuid + "|192.0.2.40"
+	DeviceStart(deviceKey, uuid)
+	t.Cleanup(func() { DeviceEnd(deviceKey) })
+
+	tag, ok, err := ChooseOutboundTagForDevice(context.Background(), uuid, deviceKey)
+	if err != nil || !ok || tag == "a" {
+		t.Fatalf("unexpected choice %q %v %v", tag, ok, err)
+	}
+	if observed != 1 {
+		t.Fatalf("expected one observation per choice, got %d", observed)
+	}
+
+	// сохранённый egress умер: проверка и новый выбор — по тому же снимку
+	DeviceSetEgress(deviceKey, "a")
+	observed = 0
+	if tag, ok, _ := ChooseOutboundTagForDevice(context.Background(), uuid, deviceKey); !ok || tag == "a" {
+		t.Fatalf("dead egress kept: %q %v", tag, ok)
+	}
+	if observed != 1 {
+		t.Fatalf("expected one observation per choice, got %d", observed)
+	}
+}
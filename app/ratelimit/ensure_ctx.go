@@ -5,28 +5,33 @@ import (
 	"sync"
 
 	cctx "github.com/xtls/xray-core/common/ctx"
+	"github.com/xtls/xray-core/common/errors"
 	"github.com/xtls/xray-core/common/session"
 )
 
 // sid -> deviceKey/connID (чтобы один inbound не создавал много ConnID)
+type sidEntry struct {
+	deviceKey string
+	connID    ConnID
+	// устройство не пущено лимитом устройств; connID == 0
+	rejected error
+}
+
 var sidIndex = struct {
 	mu sync.Mutex
-	m  map[uint64]struct {
-		deviceKey string
-		connID    ConnID
-	}
+	m  map[uint64]sidEntry
 }{
-	m: make(map[uint64]struct {
-		deviceKey string
-		connID    ConnID
-	}),
+	m: make(map[uint64]sidEntry),
 }
 
 // EnsureConnIDFromContext:
 // - достаёт uuid + srcIP из session.Inbound
 // - достаёт sid из ctx (он создаётся в inbound worker: cctx.ContextWithID)
-// - на первый вызов делает DeviceStart(deviceKey, uuid) и ставит cleanup по ctx.Done()
+// - на первый вызов делает DeviceAdmit(deviceKey, uuid) и ставит cleanup по ctx.Done()
 // - дальше возвращает тот же connID для всех outbound внутри этого inbound
+//
+// Если устройство не прошло лимит устройств, возвращает (0, false), а
+// CheckDeviceAllowed(ctx) для этого inbound вернёт ошибку.
 func EnsureConnIDFromContext(ctx context.Context) (ConnID, bool) {
 	inb := session.InboundFromContext(ctx)
 	if inb == nil || inb.User == nil || inb.User.Email == "" {
@@ -40,6 +45,9 @@ func EnsureConnIDFromContext(ctx context.Context) (ConnID, bool) {
 	}
 	deviceKey := BuildDeviceKey(uuid, srcIP)
 
+	// level нужен расписаниям, привязанным к policy level
+	Schedules.noteLevel(uuid, inb.User.Level)
+
 	sid := cctx.IDFromContext(ctx)
 	if sid == 0 {
 		// Если sid нет, мы не можем гарантировать “один connID на inbound”.
@@ -51,27 +59,55 @@ func EnsureConnIDFromContext(ctx context.Context) (ConnID, bool) {
 	sidIndex.mu.Lock()
 	if e, ok := sidIndex.m[sidKey]; ok {
 		sidIndex.mu.Unlock()
-		return e.connID, true
+		return e.connID, e.rejected == nil
 	}
 
 	// первый раз для этого inbound
-	connID := DeviceStart(deviceKey, uuid)
-	sidIndex.m[sidKey] = struct {
-		deviceKey string
-		connID    ConnID
-	}{deviceKey: deviceKey, connID: connID}
+	adm, err := DeviceAdmit(deviceKey, uuid)
+	sidIndex.m[sidKey] = sidEntry{deviceKey: deviceKey, connID: adm.ConnID, rejected: err}
 	sidIndex.mu.Unlock()
 
+	logDeviceAdmission(ctx, uuid, deviceKey, adm, err)
+
 	// cleanup один раз на inbound
 	go func() {
 		<-ctx.Done()
 
-		DeviceEnd(deviceKey)
+		if err == nil {
+			DeviceRelease(deviceKey, adm.ConnID)
+		}
 
 		sidIndex.mu.Lock()
 		delete(sidIndex.m, sidKey)
 		sidIndex.mu.Unlock()
 	}()
 
-	return connID, true
+	return adm.ConnID, err == nil
+}
+
+// CheckDeviceAllowed возвращает ErrDeviceLimitExceeded, если inbound из ctx
+// не был пущен лимитом устройств в EnsureConnIDFromContext.
+func CheckDeviceAllowed(ctx context.Context) error {
+	sid := cctx.IDFromContext(ctx)
+	if sid == 0 {
+		return nil
+	}
+
+	sidIndex.mu.Lock()
+	defer sidIndex.mu.Unlock()
+	return sidIndex.m[uint64(sid)].rejected
+}
+
+func logDeviceAdmission(ctx context.Context, uuid string, deviceKey string, adm DeviceAdmission, err error) {
+	if !adm.OverLimit {
+		return
+	}
+	switch {
+	case err != nil:
+		errors.LogWarning(ctx, "ratelimit: device ", deviceKey, " rejected for user ", uuid, ": ", adm.Active, " of ", adm.Limit.Max, " devices already active")
+	case len(adm.Evicted) > 0:
+		errors.LogWarning(ctx, "ratelimit: device ", deviceKey, " of user ", uuid, " evicted ", adm.Evicted, " (max devices ", adm.Limit.Max, ")")
+	default:
+		errors.LogWarning(ctx, "ratelimit: user ", uuid, " exceeds max devices: ", adm.Active+1, " of ", adm.Limit.Max, " (new device ", deviceKey, ")")
+	}
 }
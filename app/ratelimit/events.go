@@ -0,0 +1,103 @@
+package ratelimit
+
+import (
+	"context"
+	"time"
+
+	"github.com/xtls/xray-core/app/stats"
+	"github.com/xtls/xray-core/common"
+)
+
+type DeviceEventType int32
+
+const (
+	DeviceEventStarted       DeviceEventType = 0 // DeviceStart создал новое устройство
+	DeviceEventGrace         DeviceEventType = 1 // закрылось последнее соединение, устройство в grace
+	DeviceEventResumed       DeviceEventType = 2 // устройство вернулось из grace до удаления
+	DeviceEventDestroyed     DeviceEventType = 3 // deviceGC (или освобождение после evict) удалил устройство
+	DeviceEventEvicted       DeviceEventType = 4 // устройство выкинуто лимитом устройств
+	DeviceEventLimitChanged  DeviceEventType = 5
+	DeviceEventEgressChanged DeviceEventType = 6
+)
+
+// LimitScope — какой лимит изменился в DeviceEventLimitChanged.
+type LimitScope int32
+
+const (
+	LimitScopeDevice      LimitScope = 0 // override на ConnID устройства
+	LimitScopeUserDefault LimitScope = 1 // per-conn default пользователя; DeviceKey пуст
+	LimitScopeUserTotal   LimitScope = 2 // общий лимит пользователя; DeviceKey пуст
+)
+
+type DeviceEvent struct {
+	Type      DeviceEventType
+	Time      time.Time
+	UUID      string
+	DeviceKey string
+	ConnID    ConnID
+
+	// для DeviceEventLimitChanged
+	LimitScope   LimitScope
+	Limit        RateBps
+	LimitCleared bool
+
+	// для DeviceEventEgressChanged; пусто — привязка снята
+	EgressTag string
+}
+
+// deviceEventCtx отменён заранее: события не ждут места в канале, и
+// подписчику с заполненным буфером они не доставляются — он отстал и должен
+// перечитать состояние.
+var deviceEventCtx = func() context.Context {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	return ctx
+}()
+
+// DeviceEvents — pub/sub для событий устройств. Канал неблокирующий:
+// медленный подписчик не тормозит data path, а лишь теряет события.
+// Канал запущен всё время жизни процесса; подписчики делают
+// Subscribe/Unsubscribe, но не Close — иначе закрытие гоняется со
+// следующим Start и рвёт новые подписки.
+var DeviceEvents = stats.NewChannel(&stats.ChannelConfig{
+	BufferSize: 256,
+	Blocking:   false,
+})
+
+func init() {
+	common.Must(DeviceEvents.Start())
+}
+
+func publishDeviceEvent(ev DeviceEvent) {
+	if len(DeviceEvents.Subscribers()) == 0 {
+		return
+	}
+	ev.Time = time.Now()
+
+	DeviceEvents.Publish(deviceEventCtx, ev)
+}
+
+func publishConnLimitEvent(conn ConnID, limit RateBps, cleared bool) {
+	ev := DeviceEvent{
+		Type:         DeviceEventLimitChanged,
+		ConnID:       conn,
+		LimitScope:   LimitScopeDevice,
+		Limit:        limit,
+		LimitCleared: cleared,
+	}
+	if ci := Global.Get(conn); ci != nil {
+		ev.UUID = ci.UUID
+		ev.DeviceKey = ci.DeviceKey
+	}
+	publishDeviceEvent(ev)
+}
+
+func publishUserLimitEvent(uuid string, scope LimitScope, limit RateBps, cleared bool) {
+	publishDeviceEvent(DeviceEvent{
+		Type:         DeviceEventLimitChanged,
+		UUID:         uuid,
+		LimitScope:   scope,
+		Limit:        limit,
+		LimitCleared: cleared,
+	})
+}
@@ -0,0 +1,94 @@
+package ratelimit
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func nextDeviceEvent(t *testing.T, sub chan interface{}, uuid string) DeviceEvent {
+	t.Helper()
+	timeout := time.After(2 * time.Second)
+	for {
+		select {
+		case v := <-sub:
+			if ev := v.(DeviceEvent); ev.UUID == uuid {
+				return ev
+			}
+		case <-timeout:
+			t.Fatal("timed out waiting for device event")
+		}
+	}
+}
+
+func TestDeviceEventsLifecycle(t *testing.T) {
+	sub, err := DeviceEvents.Subscribe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer DeviceEvents.Unsubscribe(sub)
+
+	uuid := fmt.Sprintf("events-%d", time.Now().UnixNano())
+	key := uuid + "|10.0.0.1"
+
+	connID := DeviceStart(key, uuid)
+	if ev := nextDeviceEvent(t, sub, uuid); ev.Type != DeviceEventStarted || ev.DeviceKey != key || ev.ConnID != connID {
+		t.Fatalf("unexpected event: %+v", ev)
+	}
+
+	Limits.SetConnLimit(connID, 1000, 500)
+	if ev := nextDeviceEvent(t, sub, uuid); ev.Type != DeviceEventLimitChanged || ev.LimitScope != LimitScopeDevice ||
+		ev.DeviceKey != key || ev.Limit != (RateBps{Down: 1000, Up: 500}) {
+		t.Fatalf("unexpected event: %+v", ev)
+	}
+
+	DeviceSetEgress(key, "out-a")
+	if ev := nextDeviceEvent(t, sub, uuid); ev.Type != DeviceEventEgressChanged || ev.EgressTag != "out-a" {
+		t.Fatalf("unexpected event: %+v", ev)
+	}
+
+	DeviceEnd(key)
+	if ev := nextDeviceEvent(t, sub, uuid); ev.Type != DeviceEventGrace {
+		t.Fatalf("unexpected event: %+v", ev)
+	}
+
+	DeviceStart(key, uuid)
+	if ev := nextDeviceEvent(t, sub, uuid); ev.Type != DeviceEventResumed || ev.ConnID != connID {
+		t.Fatalf("unexpected event: %+v", ev)
+	}
+
+	// имитируем истёкший grace
+	DeviceEnd(key)
+	nextDeviceEvent(t, sub, uuid)
+	deviceEntries.mu.Lock()
+	deviceEntries.m[key].expires = time.Now().Add(-time.Second)
+	deviceEntries.mu.Unlock()
+
+	if ev := nextDeviceEvent(t, sub, uuid); ev.Type != DeviceEventDestroyed || ev.DeviceKey != key {
+		t.Fatalf("unexpected event: %+v", ev)
+	}
+	if _, ok := Limits.GetForConn(uuid, connID); ok {
+		t.Fatal("device override should be gone after destroy")
+	}
+}
+
+func TestDeviceEventsUserLimit(t *testing.T) {
+	sub, err := DeviceEvents.Subscribe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer DeviceEvents.Unsubscribe(sub)
+
+	uuid := fmt.Sprintf("events-user-%d", time.Now().UnixNano())
+
+	Limits.SetUserTotal(uuid, 2000, 1000)
+	if ev := nextDeviceEvent(t, sub, uuid); ev.Type != DeviceEventLimitChanged || ev.LimitScope != LimitScopeUserTotal ||
+		ev.DeviceKey != "" || ev.Limit.Down != 2000 {
+		t.Fatalf("unexpected event: %+v", ev)
+	}
+
+	Limits.ClearUserTotal(uuid)
+	if ev := nextDeviceEvent(t, sub, uuid); ev.Type != DeviceEventLimitChanged || !ev.LimitCleared {
+		t.Fatalf("unexpected event: %+v", ev)
+	}
+}
@@ -1,8 +1,11 @@
 package ratelimit
 
 import (
+	"context"
 	"sync"
 	"time"
+
+	"github.com/xtls/xray-core/common/errors"
 )
 
 // TokenBucket считает "токены" в БАЙТАХ.
@@ -11,90 +14,304 @@ type TokenBucket struct {
 
 	rateBytesPerSec float64
 	burstBytes      float64
+	shape           BucketShape
 
 	tokens float64
+	// разовый стартовый кредит сверх burst (BucketShape.InitialBytes > burst);
+	// тратится раньше tokens и не пополняется
+	credit float64
 	last   time.Time
+
+	// закрывается и пересоздаётся при каждой смене rate: ждущие
+	// пересчитывают свои дедлайны, не дожидаясь старого таймера
+	changed chan struct{}
 }
 
 func NewTokenBucket(rateBytesPerSec float64) *TokenBucket {
+	return NewShapedTokenBucket(rateBytesPerSec, BucketShape{})
+}
+
+func NewShapedTokenBucket(rateBytesPerSec float64, shape BucketShape) *TokenBucket {
 	now := time.Now()
 	b := &TokenBucket{
 		rateBytesPerSec: rateBytesPerSec,
+		shape:           shape,
 		last:            now,
+		changed:         make(chan struct{}),
 	}
 	b.recalcBurstLocked()
 	// стартовый "запас", чтобы не душить мелкие записи
 	b.tokens = b.burstBytes
+	if shape.InitialBytes != nil {
+		initial := float64(*shape.InitialBytes)
+		if initial < b.tokens {
+			b.tokens = initial
+		} else {
+			b.credit = initial - b.tokens
+		}
+	}
 	return b
 }
 
 func (b *TokenBucket) recalcBurstLocked() {
-	// 200ms burst + минимум 32KB
-	b.burstBytes = b.rateBytesPerSec * 0.2
-	if b.burstBytes < 32*1024 {
-		b.burstBytes = 32 * 1024
-	}
+	b.burstBytes = b.shape.burst(b.rateBytesPerSec)
 	if b.tokens > b.burstBytes {
 		b.tokens = b.burstBytes
 	}
 }
 
+// setRateLocked меняет rate и будит ждущих. Уже "оплаченная" ожиданием
+// очередь (b.last впереди now) пересчитывается под новую скорость.
+func (b *TokenBucket) setRateLocked(rateBytesPerSec float64) {
+	if rateBytesPerSec == b.rateBytesPerSec {
+		return
+	}
+	now := time.Now()
+	if b.last.After(now) {
+		b.last = rescaleDeadline(now, b.last, b.rateBytesPerSec, rateBytesPerSec)
+	}
+	b.rateBytesPerSec = rateBytesPerSec
+
+	close(b.changed)
+	b.changed = make(chan struct{})
+}
+
+// rescaleDeadline — когда закончится ожидание, начатое при скорости from,
+// если с момента now скорость стала to. to <= 0 — без ограничения.
+func rescaleDeadline(now, deadline time.Time, from, to float64) time.Time {
+	if !deadline.After(now) {
+		return deadline
+	}
+	if to <= 0 || from <= 0 {
+		return now
+	}
+	remaining := float64(deadline.Sub(now)) * from / to
+	return now.Add(time.Duration(remaining))
+}
+
 func (b *TokenBucket) SetRate(rateBytesPerSec float64) {
 	b.mu.Lock()
 	defer b.mu.Unlock()
 
-	b.rateBytesPerSec = rateBytesPerSec
+	b.setRateLocked(rateBytesPerSec)
 	b.recalcBurstLocked()
 }
 
-// Wait блокирует (ждёт) столько, чтобы можно было пропустить n байт.
-func (b *TokenBucket) Wait(n int) {
-	if n <= 0 {
-		return
-	}
+// Reshape меняет rate и форму bucket'а. InitialBytes действует только при
+// создании, поэтому здесь игнорируется.
+func (b *TokenBucket) Reshape(rateBytesPerSec float64, shape BucketShape) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
 
-	var sleepDur time.Duration
+	b.setRateLocked(rateBytesPerSec)
+	b.shape = shape
+	b.recalcBurstLocked()
+}
 
+func (b *TokenBucket) Rate() float64 {
 	b.mu.Lock()
-	if b.rateBytesPerSec <= 0 {
-		b.mu.Unlock()
-		return
+	defer b.mu.Unlock()
+	return b.rateBytesPerSec
+}
+
+// Wait ждёт, пока можно будет пропустить n байт, или отмены ctx.
+func (b *TokenBucket) Wait(ctx context.Context, n int) error {
+	return WaitAll(ctx, n, b)
+}
+
+// reserve списывает n байт и возвращает, сколько нужно подождать от now.
+// Один bucket может делиться между несколькими горутинами (parent-bucket
+// пользователя/inbound'а), поэтому ожидание считается от "виртуального"
+// времени b.last: каждый следующий вызов встаёт в очередь за предыдущим.
+func (b *TokenBucket) reserve(n int, now time.Time) time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.reserveLocked(n, now)
+}
+
+func (b *TokenBucket) reserveLocked(n int, now time.Time) time.Duration {
+	if n <= 0 || b.rateBytesPerSec <= 0 {
+		return 0
 	}
 
-	now := time.Now()
-	// Если виртуальное время уже впереди, не даём now быть меньше last.
-	if now.Before(b.last) {
-		now = b.last
+	// Если виртуальное время уже впереди, не даём base быть меньше last.
+	base := now
+	if base.Before(b.last) {
+		base = b.last
 	}
 
 	// Пополнение токенов за прошедшее время
-	elapsed := now.Sub(b.last).Seconds()
+	elapsed := base.Sub(b.last).Seconds()
 	if elapsed > 0 {
 		b.tokens += elapsed * b.rateBytesPerSec
 		if b.tokens > b.burstBytes {
 			b.tokens = b.burstBytes
 		}
-		b.last = now
+		b.last = base
 	}
 
 	need := float64(n)
+	if b.credit > 0 {
+		if b.credit >= need {
+			b.credit -= need
+			return base.Sub(now)
+		}
+		need -= b.credit
+		b.credit = 0
+	}
 	if b.tokens >= need {
 		b.tokens -= need
-		b.mu.Unlock()
-		return
+		return base.Sub(now)
 	}
 
 	missing := need - b.tokens
 	waitSec := missing / b.rateBytesPerSec
-	sleepDur = time.Duration(waitSec * float64(time.Second))
 
 	// “Оплатили” chunk ожиданием: токены в ноль, last вперёд
 	b.tokens = 0
-	b.last = now.Add(sleepDur)
+	b.last = base.Add(time.Duration(waitSec * float64(time.Second)))
 
-	b.mu.Unlock()
+	return b.last.Sub(now)
+}
+
+// reservation — место в очереди одного bucket'а.
+type reservation struct {
+	b        *TokenBucket
+	n        int
+	deadline time.Time
+	// скорость, под которую посчитан deadline
+	rate    float64
+	changed <-chan struct{}
+}
+
+func (b *TokenBucket) newReservation(n int, now time.Time) reservation {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return reservation{
+		b:        b,
+		n:        n,
+		deadline: now.Add(b.reserveLocked(n, now)),
+		rate:     b.rateBytesPerSec,
+		changed:  b.changed,
+	}
+}
+
+// refresh подстраивает deadline под текущую скорость bucket'а.
+func (r *reservation) refresh(now time.Time) {
+	r.b.mu.Lock()
+	defer r.b.mu.Unlock()
+	if r.b.rateBytesPerSec != r.rate {
+		r.deadline = rescaleDeadline(now, r.deadline, r.rate, r.b.rateBytesPerSec)
+		r.rate = r.b.rateBytesPerSec
+	}
+	r.changed = r.b.changed
+}
 
-	if sleepDur > 0 {
-		time.Sleep(sleepDur)
+// cancel возвращает в bucket неоплаченную часть резерва, чтобы брошенное
+// ожидание (закрытое соединение) не тормозило остальных в очереди.
+func (r *reservation) cancel(now time.Time) {
+	if !r.deadline.After(now) {
+		return
+	}
+	r.b.mu.Lock()
+	defer r.b.mu.Unlock()
+	if r.b.rateBytesPerSec <= 0 || !r.b.last.After(now) {
+		return
+	}
+	unpaid := r.deadline.Sub(now)
+	if own := time.Duration(float64(r.n) / r.b.rateBytesPerSec * float64(time.Second)); unpaid > own {
+		unpaid = own
+	}
+	r.b.last = r.b.last.Add(-unpaid)
+	if r.b.last.Before(now) {
+		r.b.last = now
+	}
+}
+
+// maxWaitChain — сколько bucket'ов принимает WaitAll: глубина иерархии
+// в connChain.
+const maxWaitChain = 5
+
+// WaitAll списывает n байт сразу со всех уровней иерархии
+// (устройство -> пользователь -> inbound -> global) и ждёт по самому
+// медленному из них. nil-bucket'ы пропускаются.
+//
+// Ожидание прерывается отменой ctx (неоплаченный резерв возвращается
+// в bucket'ы) и пересчитывается, как только меняется rate любого из них.
+// Цепочка длиннее maxWaitChain — ошибка.
+func WaitAll(ctx context.Context, n int, chain ...*TokenBucket) error {
+	if len(chain) > maxWaitChain {
+		return errors.New("ratelimit: chain of ", len(chain), " buckets, at most ", maxWaitChain, " supported")
+	}
+	if n <= 0 {
+		return nil
+	}
+
+	now := time.Now()
+	var res [maxWaitChain]reservation
+	count := 0
+	for _, b := range chain {
+		if b == nil {
+			continue
+		}
+		res[count] = b.newReservation(n, now)
+		count++
+	}
+	if count == 0 {
+		return nil
+	}
+	all := res[:count]
+
+	var timer *time.Timer
+	defer func() {
+		if timer != nil {
+			timer.Stop()
+		}
+	}()
+
+	for {
+		var deadline time.Time
+		for i := range all {
+			if all[i].deadline.After(deadline) {
+				deadline = all[i].deadline
+			}
+		}
+
+		d := deadline.Sub(now)
+		if d <= 0 {
+			return nil
+		}
+		if timer == nil {
+			timer = time.NewTimer(d)
+		} else {
+			timer.Reset(d)
+		}
+
+		var ch [maxWaitChain]<-chan struct{}
+		for i := range all {
+			ch[i] = all[i].changed
+		}
+
+		select {
+		case <-timer.C:
+			return nil
+		case <-ctx.Done():
+			now = time.Now()
+			for i := range all {
+				all[i].cancel(now)
+			}
+			return ctx.Err()
+		case <-ch[0]:
+		case <-ch[1]:
+		case <-ch[2]:
+		case <-ch[3]:
+		case <-ch[4]:
+		}
+
+		// с go 1.23 Reset не требует вычитывать timer.C
+		now = time.Now()
+		for i := range all {
+			all[i].refresh(now)
+		}
 	}
 }
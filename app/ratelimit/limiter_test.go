@@ -0,0 +1,223 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/xtls/xray-core/common/buf"
+)
+
+func TestTokenBucketReserveQueuesSharedCallers(t *testing.T) {
+	// 64KB/s, burst = 32KB (минимум)
+	b := NewTokenBucket(64 * 1024)
+	now := time.Now()
+	b.last = now
+
+	if d := b.reserve(32*1024, now); d != 0 {
+		t.Fatalf("expected burst to pass without wait, got %v", d)
+	}
+
+	// два "устройства" одновременно просят по 32KB из общего bucket'а:
+	// второе должно встать в очередь за первым, а не ждать параллельно.
+	d1 := b.reserve(32*1024, now)
+	d2 := b.reserve(32*1024, now)
+	if d1 != 500*time.Millisecond {
+		t.Fatalf("expected first wait 500ms, got %v", d1)
+	}
+	if d2 != time.Second {
+		t.Fatalf("expected second wait 1s, got %v", d2)
+	}
+}
+
+func TestWaitConnUsesUserTotalForNewDevices(t *testing.T) {
+	SetKeyMode(KeyModeDevice)
+	t.Cleanup(func() { SetKeyMode(KeyModeUUID) })
+
+	uuid := fmt.Sprintf("user-total-%d", time.Now().UnixNano())
+	Limits.SetUserTotal(uuid, 8*64*1024, 0)
+	t.Cleanup(func() { Limits.ClearUserTotal(uuid) })
+
+	keyA := BuildDeviceKey(uuid, "192.0.2.1")
+	keyB := BuildDeviceKey(uuid, "192.0.2.2")
+	connA := DeviceStart(keyA, uuid)
+	connB := DeviceStart(keyB, uuid)
+	t.Cleanup(func() {
+		DeviceEnd(keyA)
+		DeviceEnd(keyB)
+	})
+
+	// у устройств нет собственных лимитов — только общий parent
+	if _, ok := Limits.GetForConn(uuid, connA); ok {
+		t.Fatal("expected no per-conn limit for device A")
+	}
+
+	// выбираем burst через устройство A ...
+	accountConn(context.Background(), connA, "", Down, 32*1024)
+
+	// ... и устройство B, подключившееся позже, уже упирается в тот же лимит
+	start := time.Now()
+	accountConn(context.Background(), connB, "", Down, 16*1024)
+	if elapsed := time.Since(start); elapsed < 150*time.Millisecond {
+		t.Fatalf("expected device B to be throttled by user total, waited %v", elapsed)
+	}
+}
+
+func TestWaitAllReturnsOnCancel(t *testing.T) {
+	// 16KB/s, burst 32KB: ещё 32KB — это 2s ожидания
+	b := NewTokenBucket(16 * 1024)
+	b.reserve(32*1024, time.Now())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(20*time.Millisecond, cancel)
+
+	start := time.Now()
+	if err := WaitAll(ctx, 32*1024, b); err != context.Canceled {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Fatalf("expected wait to stop on cancel, waited %v", elapsed)
+	}
+
+	// неоплаченный резерв вернулся: следующий в очереди ждёт меньше 2s
+	if d := b.reserve(1, time.Now()); d > time.Second {
+		t.Fatalf("expected cancelled reservation to be refunded, next wait %v", d)
+	}
+}
+
+func TestWaitAllRejectsLongChain(t *testing.T) {
+	chain := make([]*TokenBucket, maxWaitChain+1)
+	if err := WaitAll(context.Background(), 1, chain...); err == nil {
+		t.Fatal("expected a chain longer than maxWaitChain to be rejected")
+	}
+}
+
+func TestWaitAllWakesOnRateChange(t *testing.T) {
+	b := NewTokenBucket(16 * 1024)
+	b.reserve(32*1024, time.Now())
+
+	// rate x16: оставшиеся ~2s превращаются в ~125ms
+	time.AfterFunc(20*time.Millisecond, func() { b.SetRate(256 * 1024) })
+
+	start := time.Now()
+	if err := WaitAll(context.Background(), 32*1024, b); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("expected rate change to shorten the wait, waited %v", elapsed)
+	}
+}
+
+func TestWaitAllWakesOnBucketRemoval(t *testing.T) {
+	key := fmt.Sprintf("remove-%d", time.Now().UnixNano())
+	_, down := userBuckets.GetOrCreate(key, 0, 8*16*1024, BucketShape{})
+	down.reserve(32*1024, time.Now())
+
+	time.AfterFunc(20*time.Millisecond, func() { userBuckets.Remove(key) })
+
+	start := time.Now()
+	if err := WaitAll(context.Background(), 32*1024, down); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Fatalf("expected removed bucket to release waiters, waited %v", elapsed)
+	}
+}
+
+type chunkRecorder struct {
+	sizes []int32
+}
+
+func (w *chunkRecorder) WriteMultiBuffer(mb buf.MultiBuffer) error {
+	w.sizes = append(w.sizes, mb.Len())
+	buf.ReleaseMulti(mb)
+	return nil
+}
+
+func TestWrapWriterSplitsIntoChunks(t *testing.T) {
+	uuid := fmt.Sprintf("chunks-%d", time.Now().UnixNano())
+	// 8MB/s: порция 50ms = 400KB, но не больше maxAccountChunk
+	Limits.SetUserDefault(uuid, 8*8*1024*1024, 0)
+	t.Cleanup(func() { Limits.ClearUserDefault(uuid) })
+
+	ci := Global.Add(uuid)
+	t.Cleanup(func() {
+		Global.Remove(ci.ConnID)
+		buckets.Remove(ci.ConnID)
+	})
+
+	inner := &chunkRecorder{}
+	w := newWrapWriter(context.Background(), inner, ci.ConnID, "", Down, nil)
+
+	var mb buf.MultiBuffer
+	for i := 0; i < 32; i++ {
+		b := buf.New()
+		b.Extend(buf.Size)
+		mb = append(mb, b)
+	}
+	if err := w.WriteMultiBuffer(mb); err != nil {
+		t.Fatal(err)
+	}
+
+	var total int32
+	for _, s := range inner.sizes {
+		if s > maxAccountChunk {
+			t.Fatalf("chunk of %d bytes exceeds %d", s, maxAccountChunk)
+		}
+		total += s
+	}
+	if total != 32*buf.Size || len(inner.sizes) < 2 {
+		t.Fatalf("expected %d bytes in several chunks, got %v", 32*buf.Size, inner.sizes)
+	}
+	if tx := ci.TxBytes.Load(); tx != 32*buf.Size {
+		t.Fatalf("expected %d bytes accounted, got %d", 32*buf.Size, tx)
+	}
+}
+
+func BenchmarkWaitAllUnlimited(b *testing.B) {
+	chain := []*TokenBucket{NewTokenBucket(0), NewTokenBucket(0)}
+	ctx := context.Background()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		WaitAll(ctx, buf.Size, chain...)
+	}
+}
+
+func BenchmarkWaitAllWithinBurst(b *testing.B) {
+	// скорость с запасом: ожидания нет, меряем только бухгалтерию
+	chain := []*TokenBucket{NewTokenBucket(1 << 40), NewTokenBucket(1 << 40), NewTokenBucket(1 << 40)}
+	ctx := context.Background()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		WaitAll(ctx, buf.Size, chain...)
+	}
+}
+
+func BenchmarkWrapWriter(b *testing.B) {
+	uuid := fmt.Sprintf("bench-%d", time.Now().UnixNano())
+	Limits.SetUserDefault(uuid, 1<<50, 1<<50)
+	defer Limits.ClearUserDefault(uuid)
+
+	ci := Global.Add(uuid)
+	defer func() {
+		Global.Remove(ci.ConnID)
+		buckets.Remove(ci.ConnID)
+	}()
+
+	w := newWrapWriter(context.Background(), buf.Discard, ci.ConnID, "", Down, nil)
+
+	b.SetBytes(8 * buf.Size)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		mb := make(buf.MultiBuffer, 0, 8)
+		for j := 0; j < 8; j++ {
+			x := buf.New()
+			x.Extend(buf.Size)
+			mb = append(mb, x)
+		}
+		w.WriteMultiBuffer(mb)
+	}
+}
@@ -13,21 +13,35 @@ type LimitStore struct {
 	defaultPerConn map[string]RateBps
 	// conn_id overrides
 	overrides map[ConnID]RateBps
+
+	// uuid -> общий лимит пользователя (parent для всех его устройств)
+	userTotal map[string]RateBps
+	// inbound tag -> общий лимит inbound'а
+	inboundTotal map[string]RateBps
+	// глобальный лимит на весь сервер
+	globalTotal    RateBps
+	hasGlobalTotal bool
 }
 
 func NewLimitStore() *LimitStore {
 	return &LimitStore{
 		defaultPerConn: make(map[string]RateBps),
 		overrides:      make(map[ConnID]RateBps),
+		userTotal:      make(map[string]RateBps),
+		inboundTotal:   make(map[string]RateBps),
 	}
 }
 
 var Limits = NewLimitStore()
 
 func (s *LimitStore) SetUserDefault(uuid string, down, up uint64) {
+	limit := RateBps{Down: down, Up: up}
+
 	s.mu.Lock()
-	defer s.mu.Unlock()
-	s.defaultPerConn[uuid] = RateBps{Down: down, Up: up}
+	s.defaultPerConn[uuid] = limit
+	s.mu.Unlock()
+
+	publishUserLimitEvent(uuid, LimitScopeUserDefault, limit, false)
 }
 
 func (s *LimitStore) SetUserDefaults(limits map[string]RateBps) int {
@@ -41,14 +55,22 @@ func (s *LimitStore) SetUserDefaults(limits map[string]RateBps) int {
 		}
 		s.defaultPerConn[uuid] = limit
 		updated++
+
+		publishUserLimitEvent(uuid, LimitScopeUserDefault, limit, false)
 	}
 	return updated
 }
 
 func (s *LimitStore) ClearUserDefault(uuid string) {
 	s.mu.Lock()
-	defer s.mu.Unlock()
+	_, ok := s.defaultPerConn[uuid]
 	delete(s.defaultPerConn, uuid)
+	s.mu.Unlock()
+
+	if ok {
+		publishUserLimitEvent(uuid, LimitScopeUserDefault, RateBps{}, true)
+		forgetUserThrottle(uuid)
+	}
 }
 
 func (s *LimitStore) ClearUserDefaults(uuids []string) int {
@@ -63,12 +85,18 @@ func (s *LimitStore) ClearUserDefaults(uuids []string) int {
 		if _, ok := s.defaultPerConn[uuid]; ok {
 			delete(s.defaultPerConn, uuid)
 			cleared++
+
+			publishUserLimitEvent(uuid, LimitScopeUserDefault, RateBps{}, true)
 		}
 	}
 	return cleared
 }
 
+// ClearAll удаляет все лимиты, включая иерархические (user/inbound/global).
+// Возвращает количество очищенных per-conn defaults и overrides.
 func (s *LimitStore) ClearAll() (defaults int, overrides int) {
+	clearRestoredDevices(nil, true, false)
+
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
@@ -76,6 +104,16 @@ func (s *LimitStore) ClearAll() (defaults int, overrides int) {
 	overrides = len(s.overrides)
 	s.defaultPerConn = make(map[string]RateBps)
 	s.overrides = make(map[ConnID]RateBps)
+	s.userTotal = make(map[string]RateBps)
+	s.inboundTotal = make(map[string]RateBps)
+	s.globalTotal = RateBps{}
+	s.hasGlobalTotal = false
+
+	userBuckets.RemoveAll()
+	inboundBuckets.RemoveAll()
+	globalBuckets.RemoveAll()
+	userThrottle.reset()
+	inboundThrottle.reset()
 	return defaults, overrides
 }
 
@@ -86,6 +124,8 @@ func ClearUserOverride(uuid string) int {
 // ClearUserOverrides удаляет ВСЕ overrides (per-conn) для указанных uuid.
 // Возвращает количество очищенных записей.
 func ClearUserOverrides(uuids []string) int {
+	clearRestoredDevices(restoredOfUUIDs(uuids), true, false)
+
 	connIDs := make(map[ConnID]struct{})
 	for _, uuid := range uuids {
 		if uuid == "" {
@@ -109,6 +149,8 @@ func ClearUserOverrides(uuids []string) int {
 		if _, ok := s.overrides[connID]; ok {
 			delete(s.overrides, connID)
 			cleared++
+
+			publishConnLimitEvent(connID, RateBps{}, true)
 		}
 	}
 
@@ -118,27 +160,148 @@ func ClearUserOverrides(uuids []string) int {
 func ClearUserRateLimits(uuids []string) (defaults int, overrides int) {
 	defaults = Limits.ClearUserDefaults(uuids)
 	overrides = ClearUserOverrides(uuids)
+	for _, uuid := range uuids {
+		Limits.ClearUserTotal(uuid)
+		forgetUserThrottle(uuid)
+	}
 	return defaults, overrides
 }
 
 func (s *LimitStore) SetConnLimit(conn ConnID, down, up uint64) {
+	limit := RateBps{Down: down, Up: up}
+	s.setConnLimit(conn, limit)
+	publishConnLimitEvent(conn, limit, false)
+}
+
+func (s *LimitStore) setConnLimit(conn ConnID, limit RateBps) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	s.overrides[conn] = RateBps{Down: down, Up: up}
+	s.overrides[conn] = limit
 }
 
 func (s *LimitStore) ClearConnLimit(conn ConnID) {
+	if ci := Global.Get(conn); ci != nil && ci.DeviceKey != "" {
+		clearRestoredDevices(restoredOfKey(ci.DeviceKey), true, false)
+	}
+	if s.clearConnLimit(conn) {
+		publishConnLimitEvent(conn, RateBps{}, true)
+	}
+}
+
+func (s *LimitStore) clearConnLimit(conn ConnID) bool {
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	_, ok := s.overrides[conn]
 	delete(s.overrides, conn)
+	return ok
 }
 
+// GetForConn: override устройства -> активное окно расписания -> default пользователя.
 func (s *LimitStore) GetForConn(uuid string, conn ConnID) (RateBps, bool) {
 	s.mu.RLock()
-	defer s.mu.RUnlock()
-	if v, ok := s.overrides[conn]; ok {
+	v, ok := s.overrides[conn]
+	s.mu.RUnlock()
+	if ok {
 		return v, true
 	}
+	return s.GetUserDefault(uuid)
+}
+
+// GetUserDefault возвращает действующий per-conn default uuid с учётом расписания.
+func (s *LimitStore) GetUserDefault(uuid string) (RateBps, bool) {
+	if v, ok := Schedules.perConn(uuid); ok {
+		return v, true
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
 	v, ok := s.defaultPerConn[uuid]
 	return v, ok
 }
+
+// ---- иерархические лимиты ----
+
+// SetUserTotal задаёт общий лимит пользователя: bucket каждого устройства
+// дополнительно списывает из общего bucket'а uuid, поэтому неиспользованная
+// полоса простаивающих устройств достаётся активным.
+func (s *LimitStore) SetUserTotal(uuid string, down, up uint64) {
+	limit := RateBps{Down: down, Up: up}
+
+	s.mu.Lock()
+	s.userTotal[uuid] = limit
+	s.mu.Unlock()
+
+	publishUserLimitEvent(uuid, LimitScopeUserTotal, limit, false)
+}
+
+func (s *LimitStore) ClearUserTotal(uuid string) bool {
+	s.mu.Lock()
+	_, ok := s.userTotal[uuid]
+	delete(s.userTotal, uuid)
+	s.mu.Unlock()
+
+	userBuckets.Remove(uuid)
+	if ok {
+		publishUserLimitEvent(uuid, LimitScopeUserTotal, RateBps{}, true)
+		forgetUserThrottle(uuid)
+	}
+	return ok
+}
+
+// GetUserTotal возвращает действующий общий лимит uuid с учётом расписания.
+func (s *LimitStore) GetUserTotal(uuid string) (RateBps, bool) {
+	if v, ok := Schedules.total(uuid); ok {
+		return v, true
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	v, ok := s.userTotal[uuid]
+	return v, ok
+}
+
+func (s *LimitStore) SetInboundTotal(tag string, down, up uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.inboundTotal[tag] = RateBps{Down: down, Up: up}
+}
+
+func (s *LimitStore) ClearInboundTotal(tag string) bool {
+	s.mu.Lock()
+	_, ok := s.inboundTotal[tag]
+	delete(s.inboundTotal, tag)
+	s.mu.Unlock()
+
+	inboundBuckets.Remove(tag)
+	inboundThrottle.remove(tag)
+	return ok
+}
+
+func (s *LimitStore) GetInboundTotal(tag string) (RateBps, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	v, ok := s.inboundTotal[tag]
+	return v, ok
+}
+
+func (s *LimitStore) SetGlobalTotal(down, up uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.globalTotal = RateBps{Down: down, Up: up}
+	s.hasGlobalTotal = true
+}
+
+func (s *LimitStore) ClearGlobalTotal() bool {
+	s.mu.Lock()
+	ok := s.hasGlobalTotal
+	s.globalTotal = RateBps{}
+	s.hasGlobalTotal = false
+	s.mu.Unlock()
+
+	globalBuckets.RemoveAll()
+	return ok
+}
+
+func (s *LimitStore) GetGlobalTotal() (RateBps, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.globalTotal, s.hasGlobalTotal
+}
@@ -15,6 +15,8 @@ type DeviceSnapshot struct {
 	LastSeen  time.Time
 	RxBytes   uint64
 	TxBytes   uint64
+	// EgressTag — outbound, к которому привязано устройство; пусто — не привязано
+	EgressTag string
 }
 
 func ListDevicesAll() []DeviceSnapshot {
@@ -60,6 +62,7 @@ func ListDevicesAll() []DeviceSnapshot {
 			LastSeen:  lastSeen,
 			RxBytes:   rx,
 			TxBytes:   tx,
+			EgressTag: e.egressTag,
 		})
 	}
 
@@ -112,6 +115,7 @@ func ListDevicesByUUID(uuid string) []DeviceSnapshot {
 			LastSeen:  lastSeen,
 			RxBytes:   rx,
 			TxBytes:   tx,
+			EgressTag: e.egressTag,
 		})
 	}
 
@@ -0,0 +1,230 @@
+package ratelimit
+
+import (
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// throttleCounter — сколько времени соединения провели в ожидании лимитов, в ns.
+type throttleCounter [2]atomic.Int64 // [Up, Down]
+
+type throttleMap struct {
+	mu sync.RWMutex
+	m  map[string]*throttleCounter
+}
+
+func (t *throttleMap) add(key string, dir Direction, d time.Duration) {
+	t.mu.RLock()
+	c := t.m[key]
+	t.mu.RUnlock()
+
+	if c == nil {
+		t.mu.Lock()
+		if c = t.m[key]; c == nil {
+			c = new(throttleCounter)
+			t.m[key] = c
+		}
+		t.mu.Unlock()
+	}
+	c[dir].Add(int64(d))
+}
+
+func (t *throttleMap) get(key string) (up, down time.Duration) {
+	t.mu.RLock()
+	c := t.m[key]
+	t.mu.RUnlock()
+	if c == nil {
+		return 0, 0
+	}
+	return time.Duration(c[Up].Load()), time.Duration(c[Down].Load())
+}
+
+func (t *throttleMap) remove(key string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.m, key)
+}
+
+func (t *throttleMap) reset() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.m = make(map[string]*throttleCounter)
+}
+
+func (t *throttleMap) keys() []string {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	out := make([]string, 0, len(t.m))
+	for k := range t.m {
+		out = append(out, k)
+	}
+	return out
+}
+
+var (
+	userThrottle    = &throttleMap{m: make(map[string]*throttleCounter)}
+	inboundThrottle = &throttleMap{m: make(map[string]*throttleCounter)}
+)
+
+// minRecordedThrottle — ожидания короче этого не учитываются: это шум
+// планировщика, а не работа лимита, и не стоит им нагружать data path.
+const minRecordedThrottle = time.Millisecond
+
+// forgetUserThrottle убирает накопленное ожидание пользователя, у которого
+// не осталось ни соединений, ни лимитов, ни квоты: иначе он висел бы в
+// метриках вечно. Вызывается вне локов Global/Limits/Quotas.
+func forgetUserThrottle(uuid string) {
+	if len(Global.ListByUUID(uuid)) > 0 {
+		return
+	}
+	Limits.mu.RLock()
+	_, hasDefault := Limits.defaultPerConn[uuid]
+	_, hasTotal := Limits.userTotal[uuid]
+	Limits.mu.RUnlock()
+	if hasDefault || hasTotal {
+		return
+	}
+	if Quotas.get(uuid) != nil {
+		return
+	}
+	userThrottle.remove(uuid)
+}
+
+func recordThrottle(uuid, inboundTag string, dir Direction, d time.Duration) {
+	if d < minRecordedThrottle {
+		return
+	}
+	userThrottle.add(uuid, dir, d)
+	if inboundTag != "" {
+		inboundThrottle.add(inboundTag, dir, d)
+	}
+}
+
+// UserMetrics — срез ratelimit-данных пользователя для мониторинга.
+type UserMetrics struct {
+	UUID string
+
+	ActiveDevices int
+	Connections   int
+
+	// действующие лимиты (с учётом расписания); nil — не заданы
+	PerConn *RateBps
+	Total   *RateBps
+
+	ThrottledUp   time.Duration
+	ThrottledDown time.Duration
+
+	Quota *QuotaUsage
+
+	DeviceLimit DeviceLimitCounters
+}
+
+// InboundMetrics — то же для inbound'а.
+type InboundMetrics struct {
+	Tag string
+
+	Total *RateBps
+
+	ThrottledUp   time.Duration
+	ThrottledDown time.Duration
+}
+
+// CollectUserMetrics собирает метрики всех пользователей, о которых что-то
+// известно: с устройствами, лимитами, квотами или накопленным ожиданием.
+// Результат отсортирован по uuid.
+func CollectUserMetrics() []UserMetrics {
+	users := make(map[string]struct{})
+	add := func(uuid string) {
+		if uuid != "" {
+			users[uuid] = struct{}{}
+		}
+	}
+
+	Global.mu.RLock()
+	conns := make(map[string]int, len(Global.byUUID))
+	for uuid, m := range Global.byUUID {
+		conns[uuid] = len(m)
+		add(uuid)
+	}
+	Global.mu.RUnlock()
+
+	Limits.mu.RLock()
+	for uuid := range Limits.defaultPerConn {
+		add(uuid)
+	}
+	for uuid := range Limits.userTotal {
+		add(uuid)
+	}
+	Limits.mu.RUnlock()
+
+	Quotas.mu.RLock()
+	for uuid := range Quotas.m {
+		add(uuid)
+	}
+	Quotas.mu.RUnlock()
+
+	for _, uuid := range userThrottle.keys() {
+		add(uuid)
+	}
+
+	active := make(map[string]int)
+	deviceEntries.mu.Lock()
+	for _, e := range deviceEntries.m {
+		if e != nil && e.refCount > 0 {
+			active[e.uuid]++
+		}
+	}
+	deviceEntries.mu.Unlock()
+
+	out := make([]UserMetrics, 0, len(users))
+	for uuid := range users {
+		um := UserMetrics{
+			UUID:          uuid,
+			ActiveDevices: active[uuid],
+			Connections:   conns[uuid],
+			DeviceLimit:   MaxDevicesLimits.Counters(uuid),
+		}
+		if v, ok := Limits.GetUserDefault(uuid); ok {
+			um.PerConn = &v
+		}
+		if v, ok := Limits.GetUserTotal(uuid); ok {
+			um.Total = &v
+		}
+		if q, ok := Quotas.Get(uuid); ok {
+			um.Quota = &q
+		}
+		um.ThrottledUp, um.ThrottledDown = userThrottle.get(uuid)
+		out = append(out, um)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].UUID < out[j].UUID })
+	return out
+}
+
+// CollectInboundMetrics — метрики inbound'ов с лимитом или накопленным ожиданием.
+func CollectInboundMetrics() []InboundMetrics {
+	tags := make(map[string]struct{})
+
+	Limits.mu.RLock()
+	for tag := range Limits.inboundTotal {
+		tags[tag] = struct{}{}
+	}
+	Limits.mu.RUnlock()
+
+	for _, tag := range inboundThrottle.keys() {
+		tags[tag] = struct{}{}
+	}
+
+	out := make([]InboundMetrics, 0, len(tags))
+	for tag := range tags {
+		im := InboundMetrics{Tag: tag}
+		if v, ok := Limits.GetInboundTotal(tag); ok {
+			im.Total = &v
+		}
+		im.ThrottledUp, im.ThrottledDown = inboundThrottle.get(tag)
+		out = append(out, im)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Tag < out[j].Tag })
+	return out
+}
@@ -0,0 +1,54 @@
+package ratelimit
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func hasUserMetrics(uuid string) bool {
+	for _, um := range CollectUserMetrics() {
+		if um.UUID == uuid {
+			return true
+		}
+	}
+	return false
+}
+
+func TestThrottleForgottenWithUser(t *testing.T) {
+	uuid := fmt.Sprintf("metrics-%d", time.Now().UnixNano())
+	Limits.SetUserDefault(uuid, 1000, 1000)
+	connID := Global.Add(uuid).ConnID
+	recordThrottle(uuid, "", Down, time.Second)
+
+	// пока есть соединение, ожидание не теряется даже без лимитов
+	Limits.ClearUserDefault(uuid)
+	if up, down := userThrottle.get(uuid); up != 0 || down != time.Second {
+		t.Fatalf("unexpected throttle %v/%v", up, down)
+	}
+
+	destroyConnID(connID)
+	if _, down := userThrottle.get(uuid); down != 0 {
+		t.Fatalf("throttle of a gone user kept: %v", down)
+	}
+	if hasUserMetrics(uuid) {
+		t.Fatal("gone user still reported")
+	}
+
+	// пользователь с квотой остаётся, пока квоту не снимут
+	Quotas.Set(uuid, Quota{Period: QuotaMonthly, LimitBytes: 1 << 20})
+	recordThrottle(uuid, "metrics-in", Up, time.Second)
+	if !hasUserMetrics(uuid) {
+		t.Fatal("user with a quota not reported")
+	}
+	Quotas.Clear(uuid)
+	if hasUserMetrics(uuid) {
+		t.Fatal("user reported after the quota was cleared")
+	}
+
+	Limits.SetInboundTotal("metrics-in", 1000, 1000)
+	Limits.ClearInboundTotal("metrics-in")
+	if up, _ := inboundThrottle.get("metrics-in"); up != 0 {
+		t.Fatalf("throttle of inbound kept after clearing its limit: %v", up)
+	}
+}
@@ -0,0 +1,285 @@
+package ratelimit
+
+import (
+	"sync"
+	"time"
+
+	"github.com/xtls/xray-core/common/errors"
+)
+
+type QuotaPeriod int32
+
+const (
+	QuotaAbsolute QuotaPeriod = 0 // без сброса
+	QuotaDaily    QuotaPeriod = 1 // сброс в полночь (local time)
+	QuotaMonthly  QuotaPeriod = 2 // сброс 1-го числа
+)
+
+type QuotaDirection int32
+
+const (
+	QuotaTotal QuotaDirection = 0 // up + down
+	QuotaUp    QuotaDirection = 1
+	QuotaDown  QuotaDirection = 2
+)
+
+type QuotaAction int32
+
+const (
+	QuotaActionBlock   QuotaAction = 0 // новые соединения отклоняются, активные живут
+	QuotaActionClose   QuotaAction = 1 // новые отклоняются, активные закрываются на ближайшем I/O
+	QuotaActionPenalty QuotaAction = 2 // пользователь режется до Penalty
+)
+
+var ErrQuotaExceeded = errors.New("ratelimit: traffic quota exceeded")
+
+type Quota struct {
+	Period     QuotaPeriod
+	Direction  QuotaDirection
+	LimitBytes uint64
+	Action     QuotaAction
+	Penalty    RateBps
+}
+
+type QuotaUsage struct {
+	Quota       Quota
+	UpBytes     uint64
+	DownBytes   uint64
+	PeriodStart time.Time
+	Exceeded    bool
+}
+
+type quotaState struct {
+	mu sync.Mutex
+
+	quota       Quota
+	up          uint64
+	down        uint64
+	periodStart time.Time
+	exceeded    bool
+}
+
+// QuotaStore хранит квоты по uuid. Счётчики живут здесь, а не в ConnInfo,
+// поэтому переживают deviceGC и копятся между "жизнями" устройств.
+type QuotaStore struct {
+	mu sync.RWMutex
+	m  map[string]*quotaState
+}
+
+func NewQuotaStore() *QuotaStore {
+	return &QuotaStore{
+		m: make(map[string]*quotaState),
+	}
+}
+
+var Quotas = NewQuotaStore()
+
+func quotaPeriodStart(p QuotaPeriod, now time.Time) time.Time {
+	switch p {
+	case QuotaDaily:
+		y, m, d := now.Date()
+		return time.Date(y, m, d, 0, 0, 0, 0, now.Location())
+	case QuotaMonthly:
+		y, m, _ := now.Date()
+		return time.Date(y, m, 1, 0, 0, 0, 0, now.Location())
+	default:
+		return time.Time{}
+	}
+}
+
+// rollLocked сбрасывает счётчики, если начался новый период.
+func (q *quotaState) rollLocked(now time.Time) {
+	start := quotaPeriodStart(q.quota.Period, now)
+	if start.After(q.periodStart) {
+		q.periodStart = start
+		q.up = 0
+		q.down = 0
+		q.exceeded = false
+	}
+}
+
+func (q *quotaState) usedLocked() uint64 {
+	switch q.quota.Direction {
+	case QuotaUp:
+		return q.up
+	case QuotaDown:
+		return q.down
+	default:
+		return q.up + q.down
+	}
+}
+
+func (q *quotaState) recheckLocked() {
+	q.exceeded = q.quota.LimitBytes > 0 && q.usedLocked() >= q.quota.LimitBytes
+}
+
+// Set задаёт квоту пользователю. Уже накопленный расход сохраняется,
+// если период не поменялся.
+func (s *QuotaStore) Set(uuid string, quota Quota) {
+	now := time.Now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	q := s.m[uuid]
+	if q == nil {
+		q = &quotaState{}
+		s.m[uuid] = q
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.quota.Period != quota.Period {
+		q.periodStart = time.Time{}
+	}
+	q.quota = quota
+	if q.periodStart.IsZero() {
+		q.periodStart = quotaPeriodStart(quota.Period, now)
+	}
+	q.rollLocked(now)
+	q.recheckLocked()
+}
+
+func (s *QuotaStore) Clear(uuid string) bool {
+	s.mu.Lock()
+	_, ok := s.m[uuid]
+	delete(s.m, uuid)
+	s.mu.Unlock()
+
+	penaltyBuckets.Remove(uuid)
+	if ok {
+		forgetUserThrottle(uuid)
+	}
+	return ok
+}
+
+func (s *QuotaStore) get(uuid string) *quotaState {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.m[uuid]
+}
+
+func (s *QuotaStore) Get(uuid string) (QuotaUsage, bool) {
+	q := s.get(uuid)
+	if q == nil {
+		return QuotaUsage{}, false
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.rollLocked(time.Now())
+
+	return QuotaUsage{
+		Quota:       q.quota,
+		UpBytes:     q.up,
+		DownBytes:   q.down,
+		PeriodStart: q.periodStart,
+		Exceeded:    q.exceeded,
+	}, true
+}
+
+// Reset обнуляет расход пользователя в текущем периоде.
+func (s *QuotaStore) Reset(uuid string) bool {
+	q := s.get(uuid)
+	if q == nil {
+		return false
+	}
+
+	q.mu.Lock()
+	q.up = 0
+	q.down = 0
+	q.exceeded = false
+	q.mu.Unlock()
+
+	penaltyBuckets.Remove(uuid)
+	return true
+}
+
+// Add учитывает n байт пользователя. Возвращает ErrQuotaExceeded,
+// если квота исчерпана и её действие — закрыть активные соединения.
+func (s *QuotaStore) Add(uuid string, dir Direction, n uint64) error {
+	q := s.get(uuid)
+	if q == nil {
+		return nil
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.rollLocked(time.Now())
+	if dir == Up {
+		q.up += n
+	} else {
+		q.down += n
+	}
+	q.recheckLocked()
+
+	if q.exceeded && q.quota.Action == QuotaActionClose {
+		return ErrQuotaExceeded
+	}
+	return nil
+}
+
+// Exceeded сообщает, исчерпана ли квота пользователя, и какая это квота.
+func (s *QuotaStore) Exceeded(uuid string) (Quota, bool) {
+	q := s.get(uuid)
+	if q == nil {
+		return Quota{}, false
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.rollLocked(time.Now())
+	return q.quota, q.exceeded
+}
+
+// CheckUserAllowed проверяет, можно ли пользователю открыть новое соединение.
+func CheckUserAllowed(uuid string) error {
+	if quota, exceeded := Quotas.Exceeded(uuid); exceeded && quota.Action != QuotaActionPenalty {
+		return ErrQuotaExceeded
+	}
+	return nil
+}
+
+func (s *QuotaStore) snapshot() map[string]QuotaState {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if len(s.m) == 0 {
+		return nil
+	}
+	out := make(map[string]QuotaState, len(s.m))
+	for uuid, q := range s.m {
+		q.mu.Lock()
+		out[uuid] = QuotaState{
+			Quota:       q.quota,
+			UpBytes:     q.up,
+			DownBytes:   q.down,
+			PeriodStart: q.periodStart,
+		}
+		q.mu.Unlock()
+	}
+	return out
+}
+
+func (s *QuotaStore) restore(states map[string]QuotaState) {
+	now := time.Now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for uuid, st := range states {
+		q := &quotaState{
+			quota:       st.Quota,
+			up:          st.UpBytes,
+			down:        st.DownBytes,
+			periodStart: st.PeriodStart,
+		}
+		// если сервер лежал через границу периода — счётчики сбросятся здесь
+		q.rollLocked(now)
+		q.recheckLocked()
+		s.m[uuid] = q
+	}
+}
@@ -0,0 +1,103 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestQuotaSurvivesDeviceDestroy(t *testing.T) {
+	uuid := fmt.Sprintf("quota-%d", time.Now().UnixNano())
+	Quotas.Set(uuid, Quota{LimitBytes: 1000, Action: QuotaActionClose})
+	t.Cleanup(func() { Quotas.Clear(uuid) })
+
+	connID := DeviceStart(uuid, uuid)
+	if err := accountConn(context.Background(), connID, "", Up, 600); err != nil {
+		t.Fatalf("unexpected error before quota: %v", err)
+	}
+	DeviceEnd(uuid)
+
+	// устройство удалено GC, а расход пользователя остался
+	deviceEntries.mu.Lock()
+	delete(deviceEntries.m, uuid)
+	deviceEntries.mu.Unlock()
+	destroyConnID(connID)
+
+	connID = DeviceStart(uuid, uuid)
+	t.Cleanup(func() { DeviceEnd(uuid) })
+
+	if err := CheckUserAllowed(uuid); err != nil {
+		t.Fatalf("expected user to be allowed, got %v", err)
+	}
+	if err := accountConn(context.Background(), connID, "", Down, 500); err != ErrQuotaExceeded {
+		t.Fatalf("expected ErrQuotaExceeded, got %v", err)
+	}
+	if err := CheckUserAllowed(uuid); err != ErrQuotaExceeded {
+		t.Fatalf("expected new connections to be rejected, got %v", err)
+	}
+
+	usage, ok := Quotas.Get(uuid)
+	if !ok || usage.UpBytes != 600 || usage.DownBytes != 500 || !usage.Exceeded {
+		t.Fatalf("unexpected usage: %+v", usage)
+	}
+}
+
+func TestQuotaDirectionAndPenalty(t *testing.T) {
+	uuid := fmt.Sprintf("quota-penalty-%d", time.Now().UnixNano())
+	Quotas.Set(uuid, Quota{
+		Direction:  QuotaDown,
+		LimitBytes: 100,
+		Action:     QuotaActionPenalty,
+		Penalty:    RateBps{Down: 8000, Up: 8000},
+	})
+	t.Cleanup(func() { Quotas.Clear(uuid) })
+
+	if err := Quotas.Add(uuid, Up, 1000); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, exceeded := Quotas.Exceeded(uuid); exceeded {
+		t.Fatal("uplink must not count against a downlink quota")
+	}
+
+	if err := Quotas.Add(uuid, Down, 100); err != nil {
+		t.Fatalf("penalty action must not close connections, got %v", err)
+	}
+	if _, exceeded := Quotas.Exceeded(uuid); !exceeded {
+		t.Fatal("expected quota to be exceeded")
+	}
+	if err := CheckUserAllowed(uuid); err != nil {
+		t.Fatalf("penalty action must not block new connections, got %v", err)
+	}
+
+	if !Quotas.Reset(uuid) {
+		t.Fatal("expected reset to find the quota")
+	}
+	if _, exceeded := Quotas.Exceeded(uuid); exceeded {
+		t.Fatal("expected quota to be cleared by reset")
+	}
+}
+
+func TestQuotaDailyRollover(t *testing.T) {
+	uuid := fmt.Sprintf("quota-daily-%d", time.Now().UnixNano())
+	Quotas.Set(uuid, Quota{Period: QuotaDaily, LimitBytes: 10})
+	t.Cleanup(func() { Quotas.Clear(uuid) })
+
+	Quotas.Add(uuid, Up, 10)
+	if _, exceeded := Quotas.Exceeded(uuid); !exceeded {
+		t.Fatal("expected quota to be exceeded")
+	}
+
+	// имитируем, что расход был вчера
+	q := Quotas.get(uuid)
+	q.mu.Lock()
+	q.periodStart = q.periodStart.Add(-24 * time.Hour)
+	q.mu.Unlock()
+
+	if _, exceeded := Quotas.Exceeded(uuid); exceeded {
+		t.Fatal("expected quota to reset on a new day")
+	}
+	if usage, _ := Quotas.Get(uuid); usage.UpBytes != 0 {
+		t.Fatalf("expected usage to reset, got %d", usage.UpBytes)
+	}
+}
@@ -15,12 +15,18 @@ func NextConnID() ConnID {
 }
 
 type ConnInfo struct {
-	UUID     string
-	ConnID   ConnID
-	Started  time.Time
-	LastSeen atomic.Int64 // unix seconds
-	RxBytes  atomic.Uint64
-	TxBytes  atomic.Uint64
+	UUID      string
+	DeviceKey string // пусто для соединений, созданных через NewConn
+	ConnID    ConnID
+	Started   time.Time
+	LastSeen  atomic.Int64 // unix seconds
+	RxBytes   atomic.Uint64
+	TxBytes   atomic.Uint64
+	// Rx+Tx за всю сессию: в отличие от RxBytes/TxBytes не обнуляется
+	// снапшотами и сбросом статистики, по нему считается boost
+	SessionBytes atomic.Uint64
+	// устройство выкинуто лимитом устройств: активные соединения закрываются
+	Evicted atomic.Bool
 }
 
 type Registry struct {
@@ -39,13 +45,18 @@ func NewRegistry() *Registry {
 }
 
 func (r *Registry) Add(uuid string) *ConnInfo {
+	return r.AddDevice(uuid, "")
+}
+
+func (r *Registry) AddDevice(uuid string, deviceKey string) *ConnInfo {
 	cid := NextConnID()
 	now := time.Now()
 
 	ci := &ConnInfo{
-		UUID:    uuid,
-		ConnID:  cid,
-		Started: now,
+		UUID:      uuid,
+		DeviceKey: deviceKey,
+		ConnID:    cid,
+		Started:   now,
 	}
 	ci.LastSeen.Store(now.Unix())
 
@@ -99,6 +110,7 @@ func (r *Registry) AddRx(connID ConnID, n uint64) {
 		return
 	}
 	ci.RxBytes.Add(n)
+	ci.SessionBytes.Add(n)
 
 	ci.LastSeen.Store(time.Now().Unix())
 }
@@ -111,6 +123,7 @@ func (r *Registry) AddTx(connID ConnID, n uint64) {
 		return
 	}
 	ci.TxBytes.Add(n)
+	ci.SessionBytes.Add(n)
 
 	ci.LastSeen.Store(time.Now().Unix())
 }
@@ -0,0 +1,437 @@
+package ratelimit
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/xtls/xray-core/common/errors"
+)
+
+// ScheduleWindow — интервал недели, в который действует профиль лимитов.
+type ScheduleWindow struct {
+	// пусто — каждый день; для окна через полночь — день, в который оно начинается
+	Days []time.Weekday `json:"days,omitempty"`
+	// минуты от полуночи; EndMinute <= StartMinute — окно переходит через полночь
+	// (0..0 — весь день)
+	StartMinute int `json:"startMinute"`
+	EndMinute   int `json:"endMinute"`
+
+	// лимиты профиля; nil — этот уровень окно не трогает.
+	// RateBps{} — без ограничения (например, ночной безлимит).
+	PerConn *RateBps `json:"perConn,omitempty"`
+	Total   *RateBps `json:"total,omitempty"`
+}
+
+// Schedule — набор окон в часовом поясе TimeZone. Побеждает первое подходящее
+// окно; вне окон действуют обычные лимиты из Limits.
+type Schedule struct {
+	// IANA-имя ("Europe/Moscow"), "UTC", "Local" или смещение "+03:00"; пусто — Local
+	TimeZone string           `json:"timeZone,omitempty"`
+	Windows  []ScheduleWindow `json:"windows"`
+}
+
+func loadScheduleLocation(tz string) (*time.Location, error) {
+	switch tz {
+	case "", "Local":
+		return time.Local, nil
+	case "UTC":
+		return time.UTC, nil
+	}
+
+	if tz[0] == '+' || tz[0] == '-' {
+		hh, mm, ok := strings.Cut(tz[1:], ":")
+		h, err1 := strconv.Atoi(hh)
+		m, err2 := strconv.Atoi(mm)
+		if !ok || err1 != nil || err2 != nil || h > 14 || m > 59 {
+			return nil, errors.New("invalid UTC offset: ", tz)
+		}
+		offset := h*3600 + m*60
+		if tz[0] == '-' {
+			offset = -offset
+		}
+		return time.FixedZone(tz, offset), nil
+	}
+
+	return time.LoadLocation(tz)
+}
+
+func (w *ScheduleWindow) validate() error {
+	if w.StartMinute < 0 || w.StartMinute >= 24*60 || w.EndMinute < 0 || w.EndMinute > 24*60 {
+		return errors.New("window minutes out of range: ", w.StartMinute, "-", w.EndMinute)
+	}
+	for _, d := range w.Days {
+		if d < time.Sunday || d > time.Saturday {
+			return errors.New("invalid weekday: ", int(d))
+		}
+	}
+	if w.PerConn == nil && w.Total == nil {
+		return errors.New("window has neither perConn nor total limit")
+	}
+	return nil
+}
+
+func (w *ScheduleWindow) onDay(d time.Weekday) bool {
+	if len(w.Days) == 0 {
+		return true
+	}
+	for _, wd := range w.Days {
+		if wd == d {
+			return true
+		}
+	}
+	return false
+}
+
+func (w *ScheduleWindow) match(t time.Time) bool {
+	m := t.Hour()*60 + t.Minute()
+	wd := t.Weekday()
+
+	if w.StartMinute < w.EndMinute {
+		return w.onDay(wd) && m >= w.StartMinute && m < w.EndMinute
+	}
+	// через полночь: хвост предыдущего дня или начало окна сегодня
+	if m >= w.StartMinute && w.onDay(wd) {
+		return true
+	}
+	return m < w.EndMinute && w.onDay((wd+6)%7)
+}
+
+type compiledSchedule struct {
+	Schedule
+	loc *time.Location
+}
+
+func compileSchedule(s Schedule) (*compiledSchedule, error) {
+	loc, err := loadScheduleLocation(s.TimeZone)
+	if err != nil {
+		return nil, errors.New("ratelimit schedule: bad time zone ", s.TimeZone).Base(err)
+	}
+	if len(s.Windows) == 0 {
+		return nil, errors.New("ratelimit schedule: no windows")
+	}
+	// копия: окна отдаются в data path по указателю и не должны меняться снаружи
+	windows := make([]ScheduleWindow, len(s.Windows))
+	for i, w := range s.Windows {
+		if err := w.validate(); err != nil {
+			return nil, errors.New("ratelimit schedule: window ", i).Base(err)
+		}
+		w.Days = append([]time.Weekday(nil), w.Days...)
+		if w.PerConn != nil {
+			v := *w.PerConn
+			w.PerConn = &v
+		}
+		if w.Total != nil {
+			v := *w.Total
+			w.Total = &v
+		}
+		windows[i] = w
+	}
+	s.Windows = windows
+	return &compiledSchedule{Schedule: s, loc: loc}, nil
+}
+
+// activeWindow — индекс первого подходящего окна или -1.
+func (c *compiledSchedule) activeWindow(now time.Time) int {
+	t := now.In(c.loc)
+	for i := range c.Windows {
+		if c.Windows[i].match(t) {
+			return i
+		}
+	}
+	return -1
+}
+
+// ScheduleStore хранит расписания по uuid и по policy level.
+// Расписание uuid полностью заменяет расписание его level.
+// Активные окна пересчитываются на границе каждой минуты (scheduleLoop),
+// data path читает только готовый результат.
+type ScheduleStore struct {
+	mu sync.RWMutex
+
+	users  map[string]*compiledSchedule
+	levels map[uint32]*compiledSchedule
+	// uuid -> level, запоминается при подключении (EnsureConnIDFromContext)
+	// и забывается, когда GC удаляет последнее устройство uuid
+	userLevel map[string]uint32
+
+	activeUser  map[string]int
+	activeLevel map[uint32]int
+}
+
+func NewScheduleStore() *ScheduleStore {
+	return &ScheduleStore{
+		users:       make(map[string]*compiledSchedule),
+		levels:      make(map[uint32]*compiledSchedule),
+		userLevel:   make(map[string]uint32),
+		activeUser:  make(map[string]int),
+		activeLevel: make(map[uint32]int),
+	}
+}
+
+var Schedules = NewScheduleStore()
+
+func (s *ScheduleStore) SetUser(uuid string, sch Schedule) error {
+	c, err := compileSchedule(sch)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	s.users[uuid] = c
+	s.mu.Unlock()
+
+	s.Evaluate(time.Now())
+	return nil
+}
+
+func (s *ScheduleStore) ClearUser(uuid string) bool {
+	s.mu.Lock()
+	_, ok := s.users[uuid]
+	delete(s.users, uuid)
+	s.mu.Unlock()
+
+	if ok {
+		s.Evaluate(time.Now())
+	}
+	return ok
+}
+
+func (s *ScheduleStore) SetLevel(level uint32, sch Schedule) error {
+	c, err := compileSchedule(sch)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	s.levels[level] = c
+	s.mu.Unlock()
+
+	s.Evaluate(time.Now())
+	return nil
+}
+
+func (s *ScheduleStore) ClearLevel(level uint32) bool {
+	s.mu.Lock()
+	_, ok := s.levels[level]
+	delete(s.levels, level)
+	s.mu.Unlock()
+
+	if ok {
+		s.Evaluate(time.Now())
+	}
+	return ok
+}
+
+// GetUser возвращает расписание uuid и индекс активного окна (-1 — нет).
+func (s *ScheduleStore) GetUser(uuid string) (Schedule, int, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	c := s.users[uuid]
+	if c == nil {
+		return Schedule{}, -1, false
+	}
+	if i, ok := s.activeUser[uuid]; ok {
+		return c.Schedule, i, true
+	}
+	return c.Schedule, -1, true
+}
+
+func (s *ScheduleStore) GetLevel(level uint32) (Schedule, int, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	c := s.levels[level]
+	if c == nil {
+		return Schedule{}, -1, false
+	}
+	if i, ok := s.activeLevel[level]; ok {
+		return c.Schedule, i, true
+	}
+	return c.Schedule, -1, true
+}
+
+func (s *ScheduleStore) noteLevel(uuid string, level uint32) {
+	s.mu.RLock()
+	cur, ok := s.userLevel[uuid]
+	s.mu.RUnlock()
+	if ok && cur == level {
+		return
+	}
+
+	s.mu.Lock()
+	s.userLevel[uuid] = level
+	s.mu.Unlock()
+}
+
+// forgetUser забывает level uuid, у которого не осталось устройств;
+// при следующем подключении noteLevel запомнит его снова.
+func (s *ScheduleStore) forgetUser(uuid string) {
+	s.mu.Lock()
+	delete(s.userLevel, uuid)
+	s.mu.Unlock()
+}
+
+// active — действующее сейчас окно для uuid или nil.
+func (s *ScheduleStore) active(uuid string) *ScheduleWindow {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	// до первого Evaluate окна ещё нет в activeUser — считаем, что не активно
+	if c := s.users[uuid]; c != nil {
+		if i, ok := s.activeUser[uuid]; ok && i >= 0 {
+			return &c.Windows[i]
+		}
+		return nil
+	}
+	if len(s.levels) == 0 {
+		return nil
+	}
+	level, ok := s.userLevel[uuid]
+	if !ok {
+		return nil
+	}
+	if c := s.levels[level]; c != nil {
+		if i, ok := s.activeLevel[level]; ok && i >= 0 {
+			return &c.Windows[i]
+		}
+	}
+	return nil
+}
+
+func (s *ScheduleStore) perConn(uuid string) (RateBps, bool) {
+	if w := s.active(uuid); w != nil && w.PerConn != nil {
+		return *w.PerConn, true
+	}
+	return RateBps{}, false
+}
+
+func (s *ScheduleStore) total(uuid string) (RateBps, bool) {
+	if w := s.active(uuid); w != nil && w.Total != nil {
+		return *w.Total, true
+	}
+	return RateBps{}, false
+}
+
+// Evaluate пересчитывает активные окна на момент now и перенастраивает
+// bucket'ы пользователей, у которых окно сменилось.
+func (s *ScheduleStore) Evaluate(now time.Time) {
+	var changed []string
+
+	s.mu.Lock()
+	activeUser := make(map[string]int, len(s.users))
+	for uuid, c := range s.users {
+		activeUser[uuid] = c.activeWindow(now)
+	}
+	activeLevel := make(map[uint32]int, len(s.levels))
+	for level, c := range s.levels {
+		activeLevel[level] = c.activeWindow(now)
+	}
+
+	for uuid, i := range activeUser {
+		if prev, ok := s.activeUser[uuid]; !ok || prev != i {
+			changed = append(changed, uuid)
+		}
+	}
+	for uuid := range s.activeUser {
+		if _, ok := activeUser[uuid]; !ok {
+			changed = append(changed, uuid) // расписание снято
+		}
+	}
+	for level, i := range activeLevel {
+		prev, ok := s.activeLevel[level]
+		if ok && prev == i {
+			continue
+		}
+		for uuid, l := range s.userLevel {
+			if _, own := activeUser[uuid]; l == level && !own {
+				changed = append(changed, uuid)
+			}
+		}
+	}
+	for level := range s.activeLevel {
+		if _, ok := activeLevel[level]; ok {
+			continue
+		}
+		for uuid, l := range s.userLevel {
+			if _, own := activeUser[uuid]; l == level && !own {
+				changed = append(changed, uuid)
+			}
+		}
+	}
+
+	s.activeUser = activeUser
+	s.activeLevel = activeLevel
+	s.mu.Unlock()
+
+	for _, uuid := range changed {
+		rerateUser(uuid)
+	}
+}
+
+// rerateUser выставляет существующим bucket'ам uuid текущие лимиты через
+// SetRate, не дожидаясь следующего I/O.
+func rerateUser(uuid string) {
+	for _, ci := range Global.ListByUUID(uuid) {
+		if limit, ok := Limits.GetForConn(uuid, ci.ConnID); ok {
+			buckets.SetRate(ci.ConnID, limit.Up, limit.Down)
+		}
+	}
+	limit, ok := Limits.GetUserTotal(uuid)
+	if ok {
+		userBuckets.SetRate(uuid, limit.Up, limit.Down)
+	} else {
+		userBuckets.Remove(uuid)
+	}
+
+	perConn, pok := Limits.GetUserDefault(uuid)
+	publishUserLimitEvent(uuid, LimitScopeUserDefault, perConn, !pok)
+	publishUserLimitEvent(uuid, LimitScopeUserTotal, limit, !ok)
+}
+
+func scheduleLoop() {
+	for {
+		now := time.Now()
+		// окна задаются с точностью до минуты — достаточно проснуться на её границе
+		time.Sleep(now.Truncate(time.Minute).Add(time.Minute).Sub(now))
+		Schedules.Evaluate(time.Now())
+	}
+}
+
+func init() {
+	go scheduleLoop()
+}
+
+func (s *ScheduleStore) snapshot() (map[string]Schedule, map[uint32]Schedule) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var users map[string]Schedule
+	if len(s.users) > 0 {
+		users = make(map[string]Schedule, len(s.users))
+		for uuid, c := range s.users {
+			users[uuid] = c.Schedule
+		}
+	}
+	var levels map[uint32]Schedule
+	if len(s.levels) > 0 {
+		levels = make(map[uint32]Schedule, len(s.levels))
+		for level, c := range s.levels {
+			levels[level] = c.Schedule
+		}
+	}
+	return users, levels
+}
+
+func (s *ScheduleStore) restore(users map[string]Schedule, levels map[uint32]Schedule) error {
+	var errs []error
+	for uuid, sch := range users {
+		if err := s.SetUser(uuid, sch); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	for level, sch := range levels {
+		if err := s.SetLevel(level, sch); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Combine(errs...)
+}
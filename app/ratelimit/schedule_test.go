@@ -0,0 +1,169 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestScheduleWindowMatch(t *testing.T) {
+	night := &RateBps{}
+	peak := &RateBps{Down: 5000000}
+
+	c, err := compileSchedule(Schedule{
+		TimeZone: "+03:00",
+		Windows: []ScheduleWindow{
+			// пятница 23:00 -> суббота 07:00
+			{Days: []time.Weekday{time.Friday}, StartMinute: 23 * 60, EndMinute: 7 * 60, PerConn: night},
+			{Days: []time.Weekday{time.Monday, time.Friday}, StartMinute: 18 * 60, EndMinute: 23 * 60, PerConn: peak},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	msk := time.FixedZone("msk", 3*3600)
+	cases := []struct {
+		at   time.Time
+		want int
+	}{
+		{time.Date(2024, 3, 1, 23, 30, 0, 0, msk), 0},      // пятница, ночь
+		{time.Date(2024, 3, 2, 6, 59, 0, 0, msk), 0},       // суббота, хвост пятничного окна
+		{time.Date(2024, 3, 2, 7, 0, 0, 0, msk), -1},       // окно закончилось
+		{time.Date(2024, 3, 1, 18, 0, 0, 0, msk), 1},       // пятница, пик
+		{time.Date(2024, 3, 5, 19, 0, 0, 0, msk), -1},      // вторник
+		{time.Date(2024, 3, 1, 15, 30, 0, 0, time.UTC), 1}, // 18:30 по +03:00
+	}
+	for _, tc := range cases {
+		if got := c.activeWindow(tc.at); got != tc.want {
+			t.Errorf("activeWindow(%v) = %d, want %d", tc.at, got, tc.want)
+		}
+	}
+}
+
+func TestScheduleInvalid(t *testing.T) {
+	if _, err := compileSchedule(Schedule{TimeZone: "Mars/Olympus", Windows: []ScheduleWindow{{PerConn: &RateBps{}}}}); err == nil {
+		t.Error("expected error for unknown time zone")
+	}
+	if _, err := compileSchedule(Schedule{Windows: []ScheduleWindow{{StartMinute: 60}}}); err == nil {
+		t.Error("expected error for window without limits")
+	}
+	if _, err := compileSchedule(Schedule{Windows: []ScheduleWindow{{StartMinute: 25 * 60, PerConn: &RateBps{}}}}); err == nil {
+		t.Error("expected error for out of range minutes")
+	}
+}
+
+// окно, в которое гарантированно попадает текущее время (и ближайший тик scheduleLoop)
+func windowAroundNow(perConn, total *RateBps) ScheduleWindow {
+	now := time.Now().UTC()
+	m := now.Hour()*60 + now.Minute()
+	return ScheduleWindow{
+		StartMinute: (m + 24*60 - 60) % (24 * 60),
+		EndMinute:   (m + 60) % (24 * 60),
+		PerConn:     perConn,
+		Total:       total,
+	}
+}
+
+func TestScheduleReratesExistingBuckets(t *testing.T) {
+	uuid := fmt.Sprintf("schedule-%d", time.Now().UnixNano())
+	Limits.SetUserDefault(uuid, 8000000, 8000000)
+	t.Cleanup(func() { Limits.ClearUserDefault(uuid) })
+
+	connID := DeviceStart(uuid, uuid)
+	t.Cleanup(func() { DeviceEnd(uuid) })
+
+	if err := accountConn(context.Background(), connID, "", Down, 1); err != nil {
+		t.Fatal(err)
+	}
+	_, down := buckets.GetOrCreate(connID, 8000000, 8000000, BucketShape{})
+	down.mu.Lock()
+	got := down.rateBytesPerSec
+	down.mu.Unlock()
+	if got != 1000000 {
+		t.Fatalf("unexpected initial rate %v", got)
+	}
+
+	if err := Schedules.SetUser(uuid, Schedule{
+		TimeZone: "UTC",
+		Windows:  []ScheduleWindow{windowAroundNow(&RateBps{Down: 800000, Up: 800000}, nil)},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	down.mu.Lock()
+	got = down.rateBytesPerSec
+	down.mu.Unlock()
+	if got != 100000 {
+		t.Fatalf("expected bucket to be re-rated to 100000 B/s, got %v", got)
+	}
+	if _, active, ok := Schedules.GetUser(uuid); !ok || active != 0 {
+		t.Fatalf("expected window 0 to be active, got %d", active)
+	}
+
+	// снятие расписания возвращает обычный default
+	Schedules.ClearUser(uuid)
+	down.mu.Lock()
+	got = down.rateBytesPerSec
+	down.mu.Unlock()
+	if got != 1000000 {
+		t.Fatalf("expected bucket to be re-rated back to 1000000 B/s, got %v", got)
+	}
+}
+
+func TestScheduleLevelAndUserPrecedence(t *testing.T) {
+	uuid := fmt.Sprintf("schedule-level-%d", time.Now().UnixNano())
+	const level = 7
+
+	Schedules.noteLevel(uuid, level)
+	if err := Schedules.SetLevel(level, Schedule{
+		TimeZone: "UTC",
+		Windows:  []ScheduleWindow{windowAroundNow(nil, &RateBps{Down: 1000})},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { Schedules.ClearLevel(level) })
+
+	if limit, ok := Limits.GetUserTotal(uuid); !ok || limit.Down != 1000 {
+		t.Fatalf("expected level schedule total, got %+v, %v", limit, ok)
+	}
+
+	// расписание uuid заменяет расписание level, даже если его окна не активны
+	now := time.Now().UTC()
+	m := now.Hour()*60 + now.Minute()
+	if err := Schedules.SetUser(uuid, Schedule{
+		TimeZone: "UTC",
+		Windows: []ScheduleWindow{{
+			StartMinute: (m + 180) % (24 * 60),
+			EndMinute:   (m + 240) % (24 * 60),
+			Total:       &RateBps{Down: 2000},
+		}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { Schedules.ClearUser(uuid) })
+
+	if limit, ok := Limits.GetUserTotal(uuid); ok {
+		t.Fatalf("expected no total outside the user window, got %+v", limit)
+	}
+}
+
+func TestScheduleForgetsLevelOfGoneUser(t *testing.T) {
+	uuid := fmt.Sprintf("schedule-gone-%d", time.Now().UnixNano())
+	connID := DeviceStart(uuid, uuid)
+	Schedules.noteLevel(uuid, 3)
+	DeviceEnd(uuid)
+
+	deviceEntries.mu.Lock()
+	delete(deviceEntries.m, uuid)
+	deviceEntries.mu.Unlock()
+	destroyConnID(connID)
+
+	Schedules.mu.RLock()
+	_, ok := Schedules.userLevel[uuid]
+	Schedules.mu.RUnlock()
+	if ok {
+		t.Fatal("level of a user without devices is kept")
+	}
+}
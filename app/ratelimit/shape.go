@@ -0,0 +1,215 @@
+package ratelimit
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	defaultBurstMs       = 200
+	defaultMinBurstBytes = 32 * 1024
+)
+
+// BucketShape — форма token bucket'а: сколько можно пропустить "залпом"
+// и с каким запасом bucket стартует. Нулевое значение — поведение по
+// умолчанию: burst = 200ms от rate, но не меньше 32KB, старт с полным burst.
+type BucketShape struct {
+	// фиксированный burst в байтах; 0 — считать от rate по BurstMs
+	BurstBytes uint64 `json:"burstBytes,omitempty"`
+	// burst в миллисекундах от rate; 0 — 200ms
+	BurstMs uint32 `json:"burstMs,omitempty"`
+	// стартовый запас нового bucket'а; nil — полный burst. Может быть больше
+	// burst: излишек тратится один раз ("первые 50MB быстро").
+	InitialBytes *uint64 `json:"initialBytes,omitempty"`
+}
+
+func (s BucketShape) burst(rateBytesPerSec float64) float64 {
+	if s.BurstBytes > 0 {
+		return float64(s.BurstBytes)
+	}
+	ms := s.BurstMs
+	if ms == 0 {
+		ms = defaultBurstMs
+	}
+	burst := rateBytesPerSec * float64(ms) / 1000
+	if burst < defaultMinBurstBytes {
+		burst = defaultMinBurstBytes
+	}
+	return burst
+}
+
+// Boost — "разгон" в начале сессии устройства: пока он действует, лимиты
+// устройства и пользователя (per-conn и total) не применяются. Лимиты
+// inbound'а и сервера, а также штраф квоты действуют всегда.
+// Заданы оба поля — boost заканчивается по тому, что наступит раньше.
+type Boost struct {
+	// первые Bytes байт сессии (up + down); 0 — без ограничения по байтам
+	Bytes uint64 `json:"bytes,omitempty"`
+	// первые Duration сессии; 0 — без ограничения по времени
+	Duration time.Duration `json:"duration,omitempty"`
+}
+
+// active — действует ли boost для соединения/устройства ci.
+func (b *Boost) active(ci *ConnInfo, now time.Time) bool {
+	if b == nil || (b.Bytes == 0 && b.Duration == 0) {
+		return false
+	}
+	if b.Bytes > 0 && ci.SessionBytes.Load() >= b.Bytes {
+		return false
+	}
+	if b.Duration > 0 && now.Sub(ci.Started) >= b.Duration {
+		return false
+	}
+	return true
+}
+
+// LimitShape — формы bucket'ов и boost пользователя. nil-поле — не задано.
+type LimitShape struct {
+	PerConn *BucketShape `json:"perConn,omitempty"`
+	Total   *BucketShape `json:"total,omitempty"`
+	Boost   *Boost       `json:"boost,omitempty"`
+}
+
+func (l LimitShape) clone() LimitShape {
+	out := LimitShape{}
+	if l.PerConn != nil {
+		s := l.PerConn.clone()
+		out.PerConn = &s
+	}
+	if l.Total != nil {
+		s := l.Total.clone()
+		out.Total = &s
+	}
+	if l.Boost != nil {
+		b := *l.Boost
+		out.Boost = &b
+	}
+	return out
+}
+
+func (s BucketShape) clone() BucketShape {
+	if s.InitialBytes != nil {
+		v := *s.InitialBytes
+		s.InitialBytes = &v
+	}
+	return s
+}
+
+// ShapeStore хранит формы bucket'ов: общие (default) и по uuid.
+// Поля per-uuid записи перекрывают default по отдельности: можно задать
+// пользователю только boost, а burst взять общий.
+type ShapeStore struct {
+	mu sync.RWMutex
+
+	def   LimitShape
+	users map[string]LimitShape
+}
+
+func NewShapeStore() *ShapeStore {
+	return &ShapeStore{
+		users: make(map[string]LimitShape),
+	}
+}
+
+var Shapes = NewShapeStore()
+
+func (s *ShapeStore) SetDefault(l LimitShape) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.def = l.clone()
+}
+
+func (s *ShapeStore) ClearDefault() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	had := s.def != (LimitShape{})
+	s.def = LimitShape{}
+	return had
+}
+
+func (s *ShapeStore) GetDefault() LimitShape {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.def.clone()
+}
+
+func (s *ShapeStore) SetUser(uuid string, l LimitShape) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.users[uuid] = l.clone()
+}
+
+func (s *ShapeStore) ClearUser(uuid string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, ok := s.users[uuid]
+	delete(s.users, uuid)
+	return ok
+}
+
+// GetUser возвращает то, что задано именно для uuid, без default.
+func (s *ShapeStore) GetUser(uuid string) (LimitShape, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	l, ok := s.users[uuid]
+	return l.clone(), ok
+}
+
+// Get возвращает действующие для uuid формы: per-uuid поверх default.
+// Результат нельзя менять: указатели общие со store.
+func (s *ShapeStore) Get(uuid string) LimitShape {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	l := s.def
+	if u, ok := s.users[uuid]; ok {
+		if u.PerConn != nil {
+			l.PerConn = u.PerConn
+		}
+		if u.Total != nil {
+			l.Total = u.Total
+		}
+		if u.Boost != nil {
+			l.Boost = u.Boost
+		}
+	}
+	return l
+}
+
+func (s *ShapeStore) snapshot() (*LimitShape, map[string]LimitShape) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var def *LimitShape
+	if s.def != (LimitShape{}) {
+		d := s.def.clone()
+		def = &d
+	}
+	if len(s.users) == 0 {
+		return def, nil
+	}
+	users := make(map[string]LimitShape, len(s.users))
+	for uuid, l := range s.users {
+		users[uuid] = l.clone()
+	}
+	return def, users
+}
+
+func (s *ShapeStore) restore(def *LimitShape, users map[string]LimitShape) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if def != nil {
+		s.def = def.clone()
+	}
+	for uuid, l := range users {
+		s.users[uuid] = l.clone()
+	}
+}
+
+func shapeOrDefault(s *BucketShape) BucketShape {
+	if s == nil {
+		return BucketShape{}
+	}
+	return *s
+}
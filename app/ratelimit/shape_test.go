@@ -0,0 +1,132 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestBucketShapeBurst(t *testing.T) {
+	const rate = 1024 * 1024 // 1MB/s
+
+	cases := []struct {
+		shape BucketShape
+		want  float64
+	}{
+		{BucketShape{}, rate * 0.2},
+		{BucketShape{BurstMs: 2000}, rate * 2},
+		{BucketShape{BurstBytes: 10 << 20, BurstMs: 2000}, 10 << 20},
+		{BucketShape{BurstMs: 1}, 32 * 1024},
+	}
+	for _, c := range cases {
+		if got := c.shape.burst(rate); got != c.want {
+			t.Fatalf("burst(%+v) = %v, want %v", c.shape, got, c.want)
+		}
+	}
+}
+
+func TestTokenBucketInitialCredit(t *testing.T) {
+	// 64KB/s, burst 32KB, но стартовый кредит 1MB
+	initial := uint64(1 << 20)
+	b := NewShapedTokenBucket(64*1024, BucketShape{InitialBytes: &initial})
+	now := time.Now()
+	b.last = now
+
+	if d := b.reserve(1<<20, now); d != 0 {
+		t.Fatalf("expected initial credit to pass without wait, got %v", d)
+	}
+	// кредит и burst исчерпаны — дальше обычная скорость
+	if d := b.reserve(32*1024, now); d != 500*time.Millisecond {
+		t.Fatalf("expected 500ms wait after credit, got %v", d)
+	}
+
+	// SetRate/Reshape кредит не возвращают
+	b.Reshape(64*1024, BucketShape{InitialBytes: &initial})
+	if b.credit != 0 {
+		t.Fatalf("expected no credit after reshape, got %v", b.credit)
+	}
+
+	zero := uint64(0)
+	b = NewShapedTokenBucket(64*1024, BucketShape{InitialBytes: &zero})
+	b.last = now
+	if d := b.reserve(32*1024, now); d != 500*time.Millisecond {
+		t.Fatalf("expected empty bucket to wait 500ms, got %v", d)
+	}
+}
+
+func TestShapeStoreMergesUserOverDefault(t *testing.T) {
+	s := NewShapeStore()
+	s.SetDefault(LimitShape{
+		PerConn: &BucketShape{BurstMs: 1000},
+		Boost:   &Boost{Bytes: 1 << 20},
+	})
+	s.SetUser("u", LimitShape{Boost: &Boost{Duration: time.Minute}})
+
+	l := s.Get("u")
+	if l.PerConn == nil || l.PerConn.BurstMs != 1000 {
+		t.Fatalf("expected per-conn shape from default, got %+v", l.PerConn)
+	}
+	if l.Boost == nil || l.Boost.Bytes != 0 || l.Boost.Duration != time.Minute {
+		t.Fatalf("expected user boost to replace default, got %+v", l.Boost)
+	}
+	if l := s.Get("other"); l.Boost == nil || l.Boost.Bytes != 1<<20 {
+		t.Fatalf("expected default boost for other users, got %+v", l.Boost)
+	}
+}
+
+func TestAccountConnBoostBypassesUserLimits(t *testing.T) {
+	uuid := fmt.Sprintf("boost-%d", time.Now().UnixNano())
+	// 64KB/s на устройство
+	Limits.SetUserDefault(uuid, 8*64*1024, 0)
+	Shapes.SetUser(uuid, LimitShape{Boost: &Boost{Bytes: 512 * 1024}})
+	t.Cleanup(func() {
+		Limits.ClearUserDefault(uuid)
+		Shapes.ClearUser(uuid)
+	})
+
+	ci := Global.Add(uuid)
+	t.Cleanup(func() {
+		Global.Remove(ci.ConnID)
+		buckets.Remove(ci.ConnID)
+	})
+
+	// 512KB при 64KB/s заняли бы ~8s; под boost — мгновенно
+	start := time.Now()
+	for i := 0; i < 8; i++ {
+		accountConn(context.Background(), ci.ConnID, "", Down, 64*1024)
+	}
+	if elapsed := time.Since(start); elapsed > 100*time.Millisecond {
+		t.Fatalf("expected boost to skip the device limit, waited %v", elapsed)
+	}
+
+	// boost исчерпан: burst 32KB, дальше ждём
+	accountConn(context.Background(), ci.ConnID, "", Down, 32*1024)
+	start = time.Now()
+	accountConn(context.Background(), ci.ConnID, "", Down, 16*1024)
+	if elapsed := time.Since(start); elapsed < 150*time.Millisecond {
+		t.Fatalf("expected device limit after boost, waited %v", elapsed)
+	}
+}
+
+func TestBoostEndsDespiteSnapshots(t *testing.T) {
+	uuid := fmt.Sprintf("boost-snapshot-%d", time.Now().UnixNano())
+	Shapes.SetUser(uuid, LimitShape{Boost: &Boost{Bytes: 128 * 1024}})
+	t.Cleanup(func() { Shapes.ClearUser(uuid) })
+
+	ci := Global.Add(uuid)
+	t.Cleanup(func() {
+		Global.Remove(ci.ConnID)
+		buckets.Remove(ci.ConnID)
+	})
+
+	for i := 0; i < 2; i++ {
+		accountConn(context.Background(), ci.ConnID, "", Down, 64*1024)
+		// снапшот для панели забирает счётчики
+		ci.RxBytes.Swap(0)
+		ci.TxBytes.Swap(0)
+	}
+	if Shapes.Get(uuid).Boost.active(ci, time.Now()) {
+		t.Fatal("expected boost to end after 128KB despite snapshots")
+	}
+}
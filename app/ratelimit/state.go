@@ -0,0 +1,421 @@
+package ratelimit
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/xtls/xray-core/common"
+	"github.com/xtls/xray-core/common/errors"
+)
+
+const stateVersion = 1
+
+// restoredDeviceTTL — сколько лимит/egress устройства из state ждёт его
+// переподключения, считая от момента, когда устройство видели последний раз.
+const restoredDeviceTTL = 24 * time.Hour
+
+// State — снимок всего, что control panel пушит через API и что иначе
+// теряется при рестарте. ConnID между рестартами не стабильны, поэтому
+// устройства сохраняются по deviceKey.
+type State struct {
+	Version int       `json:"version"`
+	SavedAt time.Time `json:"savedAt"`
+
+	KeyMode      KeyMode `json:"keyMode"`
+	GraceSeconds int64   `json:"graceSeconds"`
+
+	UserDefaults  map[string]RateBps `json:"userDefaults,omitempty"`
+	UserTotals    map[string]RateBps `json:"userTotals,omitempty"`
+	InboundTotals map[string]RateBps `json:"inboundTotals,omitempty"`
+	GlobalTotal   *RateBps           `json:"globalTotal,omitempty"`
+
+	Devices map[string]DeviceState `json:"devices,omitempty"`
+	Quotas  map[string]QuotaState  `json:"quotas,omitempty"`
+
+	MaxDevicesDefault *MaxDevices           `json:"maxDevicesDefault,omitempty"`
+	MaxDevices        map[string]MaxDevices `json:"maxDevices,omitempty"`
+
+	UserSchedules  map[string]Schedule `json:"userSchedules,omitempty"`
+	LevelSchedules map[uint32]Schedule `json:"levelSchedules,omitempty"`
+
+	ShapeDefault *LimitShape           `json:"shapeDefault,omitempty"`
+	Shapes       map[string]LimitShape `json:"shapes,omitempty"`
+
+	EgressBindings map[string]string `json:"egressBindings,omitempty"`
+}
+
+type DeviceState struct {
+	UUID      string   `json:"uuid,omitempty"`
+	Limit     *RateBps `json:"limit,omitempty"`
+	EgressTag string   `json:"egressTag,omitempty"`
+	// SeenAt — когда устройство последний раз было подключено; state
+	// старых версий его не содержит, тогда берётся SavedAt
+	SeenAt time.Time `json:"seenAt,omitempty"`
+}
+
+func (ds DeviceState) expired(now time.Time) bool {
+	return now.Sub(ds.SeenAt) > restoredDeviceTTL
+}
+
+type QuotaState struct {
+	Quota       Quota     `json:"quota"`
+	UpBytes     uint64    `json:"upBytes"`
+	DownBytes   uint64    `json:"downBytes"`
+	PeriodStart time.Time `json:"periodStart"`
+}
+
+// StateStore — куда сохраняется State. По умолчанию FileStateStore,
+// но можно подставить свою реализацию (БД, KV и т.п.).
+type StateStore interface {
+	// Load возвращает (nil, nil), если сохранённого состояния ещё нет.
+	Load() (*State, error)
+	Save(*State) error
+}
+
+// FileStateStore хранит State в JSON-файле; запись атомарная (tmp + rename).
+type FileStateStore struct {
+	Path string
+}
+
+func (f *FileStateStore) Load() (*State, error) {
+	data, err := os.ReadFile(f.Path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	st := new(State)
+	if err := json.Unmarshal(data, st); err != nil {
+		return nil, errors.New("failed to parse ratelimit state file ", f.Path).Base(err)
+	}
+	return st, nil
+}
+
+func (f *FileStateStore) Save(st *State) error {
+	data, err := json.Marshal(st)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(f.Path), filepath.Base(f.Path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), f.Path)
+}
+
+// restoredDevices — лимиты/egress устройств из state, которые ещё не
+// подключились после рестарта. Применяются в DeviceStart, через
+// restoredDeviceTTL без переподключения отбрасываются.
+var restoredDevices = struct {
+	mu sync.Mutex
+	m  map[string]DeviceState
+}{
+	m: make(map[string]DeviceState),
+}
+
+// applyRestoredDeviceLocked вызывается под deviceEntries.mu при создании нового entry.
+func applyRestoredDeviceLocked(deviceKey string, e *deviceEntry) {
+	restoredDevices.mu.Lock()
+	ds, ok := restoredDevices.m[deviceKey]
+	delete(restoredDevices.m, deviceKey)
+	restoredDevices.mu.Unlock()
+
+	if !ok || ds.expired(time.Now()) {
+		return
+	}
+	if ds.EgressTag != "" {
+		e.egressTag = ds.EgressTag
+	}
+	if ds.Limit != nil {
+		// без события: устройство ещё не опубликовано (DeviceEventStarted идёт следом)
+		Limits.setConnLimit(e.id, *ds.Limit)
+	}
+}
+
+// SnapshotState собирает текущее состояние пакета.
+func SnapshotState() *State {
+	now := time.Now()
+	st := &State{
+		Version:      stateVersion,
+		SavedAt:      now,
+		KeyMode:      GetKeyMode(),
+		GraceSeconds: int64(GetGrace() / time.Second),
+		Devices:      make(map[string]DeviceState),
+	}
+
+	deviceEntries.mu.Lock()
+	type device struct{ key, uuid string }
+	byConn := make(map[ConnID]device, len(deviceEntries.m))
+	for key, e := range deviceEntries.m {
+		if e == nil {
+			continue
+		}
+		byConn[e.id] = device{key: key, uuid: e.uuid}
+		if e.egressTag != "" {
+			st.Devices[key] = DeviceState{UUID: e.uuid, EgressTag: e.egressTag, SeenAt: now}
+		}
+	}
+	deviceEntries.mu.Unlock()
+
+	Limits.mu.RLock()
+	st.UserDefaults = copyRates(Limits.defaultPerConn)
+	st.UserTotals = copyRates(Limits.userTotal)
+	st.InboundTotals = copyRates(Limits.inboundTotal)
+	if Limits.hasGlobalTotal {
+		g := Limits.globalTotal
+		st.GlobalTotal = &g
+	}
+	for id, limit := range Limits.overrides {
+		d, ok := byConn[id]
+		if !ok {
+			// override на "сырой" ConnID без устройства — после рестарта его не к чему привязать
+			continue
+		}
+		l := limit
+		ds := st.Devices[d.key]
+		ds.UUID = d.uuid
+		ds.Limit = &l
+		ds.SeenAt = now
+		st.Devices[d.key] = ds
+	}
+	Limits.mu.RUnlock()
+
+	// устройства из прошлого state, которые так и не переподключились
+	restoredDevices.mu.Lock()
+	for key, ds := range restoredDevices.m {
+		if ds.expired(now) {
+			delete(restoredDevices.m, key)
+			continue
+		}
+		if _, ok := st.Devices[key]; !ok {
+			st.Devices[key] = ds
+		}
+	}
+	restoredDevices.mu.Unlock()
+
+	st.Quotas = Quotas.snapshot()
+	st.MaxDevicesDefault, st.MaxDevices = MaxDevicesLimits.snapshot()
+	st.UserSchedules, st.LevelSchedules = Schedules.snapshot()
+	st.ShapeDefault, st.Shapes = Shapes.snapshot()
+	if b := Egress.Bindings(); len(b) > 0 {
+		st.EgressBindings = b
+	}
+	return st
+}
+
+// RestoreState применяет сохранённое состояние. Значения из state имеют
+// приоритет над статическим конфигом: это последнее, что прислал API.
+func RestoreState(st *State) {
+	if st == nil {
+		return
+	}
+
+	SetKeyMode(st.KeyMode)
+	SetGrace(time.Duration(st.GraceSeconds) * time.Second)
+
+	Limits.mu.Lock()
+	for uuid, limit := range st.UserDefaults {
+		Limits.defaultPerConn[uuid] = limit
+	}
+	for uuid, limit := range st.UserTotals {
+		Limits.userTotal[uuid] = limit
+	}
+	for tag, limit := range st.InboundTotals {
+		Limits.inboundTotal[tag] = limit
+	}
+	if st.GlobalTotal != nil {
+		Limits.globalTotal = *st.GlobalTotal
+		Limits.hasGlobalTotal = true
+	}
+	Limits.mu.Unlock()
+
+	now := time.Now()
+	restoredDevices.mu.Lock()
+	for key, ds := range st.Devices {
+		if ds.SeenAt.IsZero() {
+			ds.SeenAt = st.SavedAt
+		}
+		if ds.UUID == "" {
+			// deviceKey — "uuid" или "uuid|ip", см. BuildDeviceKey
+			ds.UUID, _, _ = strings.Cut(key, "|")
+		}
+		if ds.expired(now) {
+			continue
+		}
+		restoredDevices.m[key] = ds
+	}
+	restoredDevices.mu.Unlock()
+
+	Quotas.restore(st.Quotas)
+	MaxDevicesLimits.restore(st.MaxDevicesDefault, st.MaxDevices)
+	Shapes.restore(st.ShapeDefault, st.Shapes)
+	Egress.restoreBindings(st.EgressBindings)
+	if err := Schedules.restore(st.UserSchedules, st.LevelSchedules); err != nil {
+		// например, в системе нет tzdata для сохранённого часового пояса
+		errors.LogWarningInner(context.Background(), err, "ratelimit: failed to restore some schedules")
+	}
+}
+
+// clearRestoredDevices сбрасывает лимит и/или egress у восстановленных, но
+// ещё не переподключившихся устройств, чтобы очищенное через API не
+// вернулось при переподключении. match == nil — все устройства.
+func clearRestoredDevices(match func(key string, ds DeviceState) bool, limit, egress bool) int {
+	restoredDevices.mu.Lock()
+	defer restoredDevices.mu.Unlock()
+
+	cleared := 0
+	for key, ds := range restoredDevices.m {
+		if match != nil && !match(key, ds) {
+			continue
+		}
+		if (limit && ds.Limit != nil) || (egress && ds.EgressTag != "") {
+			cleared++
+		}
+		if limit {
+			ds.Limit = nil
+		}
+		if egress {
+			ds.EgressTag = ""
+		}
+		if ds.Limit == nil && ds.EgressTag == "" {
+			delete(restoredDevices.m, key)
+		} else {
+			restoredDevices.m[key] = ds
+		}
+	}
+	return cleared
+}
+
+func restoredOfUUIDs(uuids []string) func(string, DeviceState) bool {
+	set := make(map[string]struct{}, len(uuids))
+	for _, uuid := range uuids {
+		set[uuid] = struct{}{}
+	}
+	return func(_ string, ds DeviceState) bool {
+		_, ok := set[ds.UUID]
+		return ok
+	}
+}
+
+func restoredOfKey(deviceKey string) func(string, DeviceState) bool {
+	return func(key string, _ DeviceState) bool {
+		return key == deviceKey
+	}
+}
+
+// ClearRestoredDeviceLimit убирает сохранённый лимит устройства, которое
+// ещё не переподключилось после рестарта.
+func ClearRestoredDeviceLimit(deviceKey string) bool {
+	return clearRestoredDevices(restoredOfKey(deviceKey), true, false) > 0
+}
+
+func copyRates(m map[string]RateBps) map[string]RateBps {
+	if len(m) == 0 {
+		return nil
+	}
+	out := make(map[string]RateBps, len(m))
+	for k, v := range m {
+		out[k] = v
+	}
+	return out
+}
+
+// StatePersister загружает state при создании, периодически сохраняет
+// его и делает финальное сохранение в Close().
+type StatePersister struct {
+	store    StateStore
+	interval time.Duration
+
+	closeOnce sync.Once
+	done      chan struct{}
+}
+
+func NewStatePersister(store StateStore, interval time.Duration) (*StatePersister, error) {
+	if interval <= 0 {
+		interval = 60 * time.Second
+	}
+
+	st, err := store.Load()
+	if err != nil {
+		// битый state не должен мешать старту: работаем с чистого листа
+		errors.LogErrorInner(context.Background(), err, "ratelimit: failed to load state, starting empty")
+	} else if st != nil {
+		RestoreState(st)
+		errors.LogInfo(context.Background(), "ratelimit: state restored, saved at ", st.SavedAt)
+	}
+
+	return &StatePersister{
+		store:    store,
+		interval: interval,
+		done:     make(chan struct{}),
+	}, nil
+}
+
+func (p *StatePersister) Type() interface{} {
+	return (*StatePersister)(nil)
+}
+
+func (p *StatePersister) Start() error {
+	go p.loop()
+	return nil
+}
+
+func (p *StatePersister) loop() {
+	t := time.NewTicker(p.interval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-p.done:
+			return
+		case <-t.C:
+			if err := p.Save(); err != nil {
+				errors.LogWarningInner(context.Background(), err, "ratelimit: failed to save state")
+			}
+		}
+	}
+}
+
+func (p *StatePersister) Save() error {
+	return p.store.Save(SnapshotState())
+}
+
+func (p *StatePersister) Close() error {
+	var err error
+	p.closeOnce.Do(func() {
+		close(p.done)
+		err = p.Save()
+	})
+	return err
+}
+
+func init() {
+	common.Must(common.RegisterConfig((*StateConfig)(nil), func(ctx context.Context, cfg interface{}) (interface{}, error) {
+		c := cfg.(*StateConfig)
+		if c.Path == "" {
+			return nil, errors.New("ratelimit: state path is empty")
+		}
+		return NewStatePersister(&FileStateStore{Path: c.Path}, time.Duration(c.IntervalSeconds)*time.Second)
+	}))
+}
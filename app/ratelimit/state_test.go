@@ -0,0 +1,180 @@
+package ratelimit
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestFileStateStoreMissingFile(t *testing.T) {
+	store := &FileStateStore{Path: filepath.Join(t.TempDir(), "state.json")}
+	st, err := store.Load()
+	if err != nil || st != nil {
+		t.Fatalf("expected (nil, nil) for missing file, got (%v, %v)", st, err)
+	}
+}
+
+func TestStatePersisterRoundTrip(t *testing.T) {
+	oldMode := GetKeyMode()
+	oldGrace := GetGrace()
+	t.Cleanup(func() {
+		SetKeyMode(oldMode)
+		SetGrace(oldGrace)
+	})
+
+	SetKeyMode(KeyModeDevice)
+	SetGrace(33 * time.Second)
+
+	uuid := fmt.Sprintf("state-%d", time.Now().UnixNano())
+	deviceKey := BuildDeviceKey(uuid, "203.0.113.7")
+
+	Limits.SetUserDefault(uuid, 1000, 2000)
+	Limits.SetUserTotal(uuid, 3000, 4000)
+	Quotas.Set(uuid, Quota{Period: QuotaMonthly, LimitBytes: 1 << 20})
+	Quotas.Add(uuid, Down, 12345)
+
+	connID := DeviceStart(deviceKey, uuid)
+	Limits.SetConnLimit(connID, 500, 600)
+	DeviceSetEgress(deviceKey, "out-a")
+	t.Cleanup(func() {
+		Limits.ClearUserDefault(uuid)
+		Limits.ClearUserTotal(uuid)
+		Quotas.Clear(uuid)
+		DeviceEnd(deviceKey)
+	})
+
+	path := filepath.Join(t.TempDir(), "state.json")
+	p, err := NewStatePersister(&FileStateStore{Path: path}, time.Hour)
+	if err != nil {
+		t.Fatalf("NewStatePersister returned error: %v", err)
+	}
+	if err := p.Close(); err != nil {
+		t.Fatalf("Close returned error: %v", err)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Fatalf("expected state file to be written: %v", err)
+	}
+
+	// "рестарт": всё динамическое состояние пропадает
+	SetKeyMode(KeyModeUUID)
+	SetGrace(0)
+	Limits.ClearUserDefault(uuid)
+	Limits.ClearUserTotal(uuid)
+	Quotas.Clear(uuid)
+	DeviceEnd(deviceKey)
+	deviceEntries.mu.Lock()
+	delete(deviceEntries.m, deviceKey)
+	deviceEntries.mu.Unlock()
+	destroyConnID(connID)
+
+	if _, err := NewStatePersister(&FileStateStore{Path: path}, time.Hour); err != nil {
+		t.Fatalf("NewStatePersister returned error: %v", err)
+	}
+
+	if GetKeyMode() != KeyModeDevice || GetGrace() != 33*time.Second {
+		t.Fatalf("key mode/grace not restored: %v %v", GetKeyMode(), GetGrace())
+	}
+	if limit, ok := Limits.GetForConn(uuid, 0); !ok || limit.Down != 1000 || limit.Up != 2000 {
+		t.Fatalf("user default not restored: %+v %v", limit, ok)
+	}
+	if limit, ok := Limits.GetUserTotal(uuid); !ok || limit.Down != 3000 {
+		t.Fatalf("user total not restored: %+v %v", limit, ok)
+	}
+	if usage, ok := Quotas.Get(uuid); !ok || usage.DownBytes != 12345 {
+		t.Fatalf("quota usage not restored: %+v %v", usage, ok)
+	}
+
+	// устройство переподключается и получает свой лимит и egress обратно
+	connID = DeviceStart(deviceKey, uuid)
+	if limit, ok := Limits.GetForConn(uuid, connID); !ok || limit.Down != 500 || limit.Up != 600 {
+		t.Fatalf("device limit not restored: %+v %v", limit, ok)
+	}
+	if tag, ok := DeviceGetEgress(deviceKey); !ok || tag != "out-a" {
+		t.Fatalf("egress not restored: %q %v", tag, ok)
+	}
+}
+
+func restoreDevicesForTest(t *testing.T, savedAt time.Time, devices map[string]DeviceState) {
+	t.Helper()
+	oldMode := GetKeyMode()
+	t.Cleanup(func() { SetKeyMode(oldMode) })
+
+	RestoreState(&State{
+		Version:      stateVersion,
+		SavedAt:      savedAt,
+		KeyMode:      KeyModeDevice,
+		GraceSeconds: int64(GetGrace() / time.Second),
+		Devices:      devices,
+	})
+}
+
+func TestRestoredDeviceClearedBeforeReconnect(t *testing.T) {
+	uuid := fmt.Sprintf("state-clear-%d", time.Now().UnixNano())
+	other := uuid + "-other"
+	first := uuid + "|203.0.113.8"
+	second := other + "|203.0.113.9"
+	restoreDevicesForTest(t, time.Now(), map[string]DeviceState{
+		first:  {Limit: &RateBps{Down: 500, Up: 600}, EgressTag: "out-a"},
+		second: {Limit: &RateBps{Down: 700, Up: 800}},
+	})
+	t.Cleanup(func() {
+		DeviceEnd(first)
+		DeviceEnd(second)
+	})
+
+	// очищено, пока устройства не подключены: после подключения лимиты не возвращаются
+	ClearUserRateLimits([]string{uuid})
+	connID := DeviceStart(first, uuid)
+	if limit, ok := Limits.GetForConn(uuid, connID); ok {
+		t.Fatalf("cleared device limit came back: %+v", limit)
+	}
+	if tag, ok := DeviceGetEgress(first); !ok || tag != "out-a" {
+		t.Fatalf("egress should survive clearing limits: %q %v", tag, ok)
+	}
+
+	if !ClearRestoredDeviceLimit(second) {
+		t.Fatal("expected restored device limit to be cleared")
+	}
+	connID = DeviceStart(second, other)
+	if limit, ok := Limits.GetForConn(other, connID); ok {
+		t.Fatalf("cleared device limit came back: %+v", limit)
+	}
+}
+
+func TestRestoredDeviceExpires(t *testing.T) {
+	uuid := fmt.Sprintf("state-expire-%d", time.Now().UnixNano())
+	stale := uuid + "|203.0.113.10"
+	recent := uuid + "|203.0.113.11"
+	restoreDevicesForTest(t, time.Now().Add(-restoredDeviceTTL-time.Hour), map[string]DeviceState{
+		stale:  {Limit: &RateBps{Down: 500, Up: 600}},
+		recent: {Limit: &RateBps{Down: 700, Up: 800}, SeenAt: time.Now().Add(-time.Hour)},
+	})
+	t.Cleanup(func() { clearRestoredDevices(restoredOfUUIDs([]string{uuid}), true, true) })
+
+	st := SnapshotState()
+	if _, ok := st.Devices[stale]; ok {
+		t.Fatal("expired device saved again")
+	}
+	if ds, ok := st.Devices[recent]; !ok || ds.UUID != uuid {
+		t.Fatalf("unexpected saved device: %+v %v", ds, ok)
+	}
+
+	// устройство, не переподключившееся за restoredDeviceTTL, отбрасывается
+	restoredDevices.mu.Lock()
+	ds := restoredDevices.m[recent]
+	ds.SeenAt = time.Now().Add(-restoredDeviceTTL - time.Minute)
+	restoredDevices.m[recent] = ds
+	restoredDevices.mu.Unlock()
+
+	if _, ok := SnapshotState().Devices[recent]; ok {
+		t.Fatal("expired device saved again")
+	}
+	restoredDevices.mu.Lock()
+	_, ok := restoredDevices.m[recent]
+	restoredDevices.mu.Unlock()
+	if ok {
+		t.Fatal("expected expired device to be purged")
+	}
+}
@@ -1,6 +1,7 @@
 package ratelimit
 
 import (
+	"context"
 	"sync"
 	"time"
 
@@ -16,36 +17,183 @@ const (
 	Down                  // server -> client (downlink)
 )
 
+const (
+	// сколько времени трафика по самому медленному лимиту списывается за раз:
+	// большой MultiBuffer уходит порциями, а не одним залпом после долгой паузы
+	accountChunkDuration = 50 * time.Millisecond
+	minAccountChunk      = buf.Size
+	maxAccountChunk      = 64 * 1024
+)
+
+// connChain собирает bucket'ы всех уровней лимитов (conn -> uuid -> inbound ->
+// global, плюс штраф за исчерпанную квоту) для направления dir; conn и uuid —
+// с учётом BucketShape и Boost пользователя. nil-элементы — уровня нет.
+func connChain(ci *ConnInfo, inboundTag string, dir Direction) [5]*TokenBucket {
+	var chain [5]*TokenBucket
+	pick := func(up, down *TokenBucket) *TokenBucket {
+		if dir == Up {
+			return up
+		}
+		return down
+	}
+
+	// пока действует boost, лимиты устройства и пользователя пропускаем
+	shape := Shapes.Get(ci.UUID)
+	if !shape.Boost.active(ci, time.Now()) {
+		if limit, ok := Limits.GetForConn(ci.UUID, ci.ConnID); ok {
+			chain[0] = pick(buckets.GetOrCreate(ci.ConnID, limit.Up, limit.Down, shapeOrDefault(shape.PerConn)))
+		}
+		if limit, ok := Limits.GetUserTotal(ci.UUID); ok {
+			chain[1] = pick(userBuckets.GetOrCreate(ci.UUID, limit.Up, limit.Down, shapeOrDefault(shape.Total)))
+		}
+	}
+	if inboundTag != "" {
+		if limit, ok := Limits.GetInboundTotal(inboundTag); ok {
+			chain[2] = pick(inboundBuckets.GetOrCreate(inboundTag, limit.Up, limit.Down, BucketShape{}))
+		}
+	}
+	if limit, ok := Limits.GetGlobalTotal(); ok {
+		chain[3] = pick(globalBuckets.GetOrCreate("", limit.Up, limit.Down, BucketShape{}))
+	}
+	if quota, exceeded := Quotas.Exceeded(ci.UUID); exceeded && quota.Action == QuotaActionPenalty {
+		chain[4] = pick(penaltyBuckets.GetOrCreate(ci.UUID, quota.Penalty.Up, quota.Penalty.Down, BucketShape{}))
+	}
+	return chain
+}
+
+// chunkSize — сколько байт из n пропускать за одно ожидание.
+func chunkSize(n int, chain []*TokenBucket) int {
+	var slowest float64
+	for _, b := range chain {
+		if b == nil {
+			continue
+		}
+		if r := b.Rate(); r > 0 && (slowest == 0 || r < slowest) {
+			slowest = r
+		}
+	}
+	if slowest == 0 {
+		return n
+	}
+
+	size := int(slowest * accountChunkDuration.Seconds())
+	size = max(size, minAccountChunk)
+	size = min(size, maxAccountChunk)
+	return min(size, n)
+}
+
+// accountChunk ждёт, пока все уровни лимитов пропустят очередную порцию
+// из n байт в направлении dir, и учитывает её в registry и квоте
+// пользователя. Возвращает размер порции (не больше n).
+// Ошибка — ErrQuotaExceeded или ErrDeviceEvicted, если соединение нужно
+// закрыть, либо ошибка ctx, если ожидание прервано.
+func accountChunk(ctx context.Context, conn ConnID, inboundTag string, dir Direction, n int) (int, error) {
+	ci := Global.Get(conn)
+	if ci == nil {
+		return n, nil
+	}
+	if ci.Evicted.Load() {
+		return 0, ErrDeviceEvicted
+	}
+
+	chain := connChain(ci, inboundTag, dir)
+	n = chunkSize(n, chain[:])
+	start := time.Now()
+	err := WaitAll(ctx, n, chain[:]...)
+	recordThrottle(ci.UUID, inboundTag, dir, time.Since(start))
+	if err != nil {
+		return 0, err
+	}
+
+	if dir == Up {
+		Global.AddRx(conn, uint64(n))
+	} else {
+		Global.AddTx(conn, uint64(n))
+	}
+
+	return n, Quotas.Add(ci.UUID, dir, uint64(n))
+}
+
+// accountConn — accountChunk для всех n байт.
+func accountConn(ctx context.Context, conn ConnID, inboundTag string, dir Direction, n int) error {
+	for n > 0 {
+		done, err := accountChunk(ctx, conn, inboundTag, dir, n)
+		if err != nil {
+			return err
+		}
+		n -= done
+	}
+	return nil
+}
+
+// wrapReader отдаёт прочитанное порциями по мере того, как их пропускают
+// лимиты; непропущенный остаток ждёт в pending до следующего чтения.
 type wrapReader struct {
 	inner   buf.Reader
 	conn    ConnID
+	inbound string
 	onClose func()
-}
 
-func (r *wrapReader) ReadMultiBuffer() (buf.MultiBuffer, error) {
-	mb, err := r.inner.ReadMultiBuffer()
+	ctx    context.Context
+	cancel context.CancelFunc
 
-	if mb != nil && !mb.IsEmpty() {
-		nBytes := int(mb.Len())
+	pending buf.MultiBuffer
+}
 
-		ci := Global.Get(r.conn)
-		if ci != nil {
-			if limit, ok := Limits.GetForConn(ci.UUID, r.conn); ok {
-				upBucket, _ := buckets.GetOrCreate(r.conn, limit.Up, limit.Down)
-				upBucket.Wait(nBytes)
-			}
+func newWrapReader(ctx context.Context, inner buf.Reader, conn ConnID, inbound string, onClose func()) *wrapReader {
+	r := &wrapReader{inner: inner, conn: conn, inbound: inbound, onClose: onClose}
+	r.ctx, r.cancel = context.WithCancel(ctx)
+	return r
+}
+
+func (r *wrapReader) ReadMultiBuffer() (buf.MultiBuffer, error) {
+	if r.pending.IsEmpty() {
+		mb, err := r.inner.ReadMultiBuffer()
+		if err != nil {
+			return r.finish(mb, err)
 		}
+		r.pending = mb
+	}
+	return r.next()
+}
 
-		Global.AddRx(r.conn, uint64(nBytes))
+// next пропускает через лимиты очередную порцию pending.
+func (r *wrapReader) next() (buf.MultiBuffer, error) {
+	if r.pending.IsEmpty() {
+		return nil, nil
+	}
+	n, err := accountChunk(r.ctx, r.conn, r.inbound, Up, int(r.pending.Len()))
+	if err != nil {
+		buf.ReleaseMulti(r.pending)
+		r.pending = nil
+		if r.onClose != nil {
+			r.onClose()
+		}
+		return nil, err
 	}
 
-	if err != nil && r.onClose != nil {
+	var chunk buf.MultiBuffer
+	r.pending, chunk = buf.SplitSize(r.pending, int32(n))
+	return chunk, nil
+}
+
+// finish — последнее чтение вместе с ошибкой inner: отдаём всё сразу,
+// порций дальше не будет.
+func (r *wrapReader) finish(mb buf.MultiBuffer, err error) (buf.MultiBuffer, error) {
+	if !mb.IsEmpty() {
+		if qerr := accountConn(r.ctx, r.conn, r.inbound, Up, int(mb.Len())); qerr != nil {
+			buf.ReleaseMulti(mb)
+			mb, err = nil, qerr
+		}
+	}
+	if r.onClose != nil {
 		r.onClose()
 	}
 	return mb, err
 }
 
 func (r *wrapReader) Interrupt() {
+	r.cancel()
 	if r.onClose != nil {
 		r.onClose()
 	}
@@ -53,64 +201,80 @@ func (r *wrapReader) Interrupt() {
 }
 
 func (r *wrapReader) ReadMultiBufferTimeout(timeout time.Duration) (buf.MultiBuffer, error) {
+	if !r.pending.IsEmpty() {
+		return r.next()
+	}
 	if tr, ok := r.inner.(buf.TimeoutReader); ok {
 		mb, err := tr.ReadMultiBufferTimeout(timeout)
-		if mb != nil && !mb.IsEmpty() {
-			nBytes := int(mb.Len())
-			ci := Global.Get(r.conn)
-			if ci != nil {
-				limit, ok := Limits.GetForConn(ci.UUID, r.conn)
-				if ok {
-					upBucket, _ := buckets.GetOrCreate(r.conn, limit.Up, limit.Down)
-					upBucket.Wait(nBytes)
-				}
-			}
-			Global.AddRx(r.conn, uint64(nBytes))
+		if err != nil {
+			return r.finish(mb, err)
 		}
-		if err != nil && r.onClose != nil {
-			r.onClose()
-		}
-		return mb, err
+		r.pending = mb
+		return r.next()
 	}
 	// fallback
 	return r.ReadMultiBuffer()
 }
 
-// wrapWriter считает bytes и обновляет LastSeen.
-// При будущем throttling сюда же добавим limiter.Wait().
+// wrapWriter пропускает MultiBuffer через лимиты порциями и пишет каждую
+// порцию сразу, как только её пропустили.
 type wrapWriter struct {
 	inner   buf.Writer
 	conn    ConnID
+	inbound string
 	dir     Direction
 	onClose func()
+
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+func newWrapWriter(ctx context.Context, inner buf.Writer, conn ConnID, inbound string, dir Direction, onClose func()) *wrapWriter {
+	w := &wrapWriter{inner: inner, conn: conn, inbound: inbound, dir: dir, onClose: onClose}
+	w.ctx, w.cancel = context.WithCancel(ctx)
+	return w
 }
 
 func (w *wrapWriter) WriteMultiBuffer(mb buf.MultiBuffer) error {
-	nBytes := int(mb.Len())
-	if nBytes > 0 {
-		ci := Global.Get(w.conn)
-		if ci != nil {
-			if limit, ok := Limits.GetForConn(ci.UUID, w.conn); ok {
-				_, downBucket := buckets.GetOrCreate(w.conn, limit.Up, limit.Down)
-				downBucket.Wait(nBytes)
+	for !mb.IsEmpty() {
+		n, err := accountChunk(w.ctx, w.conn, w.inbound, Down, int(mb.Len()))
+		if err != nil {
+			buf.ReleaseMulti(mb)
+			if w.onClose != nil {
+				w.onClose()
 			}
+			return err
+		}
+
+		var chunk buf.MultiBuffer
+		mb, chunk = buf.SplitSize(mb, int32(n))
+		if err := w.inner.WriteMultiBuffer(chunk); err != nil {
+			buf.ReleaseMulti(mb)
+			if w.onClose != nil {
+				w.onClose()
+			}
+			return err
 		}
-		Global.AddTx(w.conn, uint64(nBytes))
-	}
-	err := w.inner.WriteMultiBuffer(mb)
-	if err != nil && w.onClose != nil {
-		w.onClose()
 	}
-	return err
+	return nil
 }
 
 func (w *wrapWriter) Close() error {
+	w.cancel()
 	if w.onClose != nil {
 		w.onClose()
 	}
 	return common.Close(w.inner)
 }
 
+func (w *wrapWriter) Interrupt() {
+	w.cancel()
+	if w.onClose != nil {
+		w.onClose()
+	}
+	common.Interrupt(w.inner)
+}
+
 // onceFunc — гарантирует, что Remove выполнится один раз,
 // даже если Close и Interrupt вызываются в разных местах.
 func onceFunc(f func()) func() {
@@ -119,7 +283,8 @@ func onceFunc(f func()) func() {
 }
 
 // NewConn wraps link endpoints and returns conn_id.
-func NewConn(uuid string, uplinkWriter buf.Writer, downlinkWriter buf.Writer, uplinkReader buf.Reader, downlinkReader buf.Reader) (ConnID, buf.Writer, buf.Writer, buf.Reader, buf.Reader) {
+// Ожидания лимитов прерываются отменой ctx.
+func NewConn(ctx context.Context, uuid string, uplinkWriter buf.Writer, downlinkWriter buf.Writer, uplinkReader buf.Reader, downlinkReader buf.Reader) (ConnID, buf.Writer, buf.Writer, buf.Reader, buf.Reader) {
 	ci := Global.Add(uuid)
 	connID := ci.ConnID
 
@@ -131,11 +296,11 @@ func NewConn(uuid string, uplinkWriter buf.Writer, downlinkWriter buf.Writer, up
 		buckets.Remove(connID)
 	})
 
-	uw := &wrapWriter{inner: uplinkWriter, conn: connID, dir: Up, onClose: cleanup}
-	dw := &wrapWriter{inner: downlinkWriter, conn: connID, dir: Down, onClose: cleanup}
+	uw := newWrapWriter(ctx, uplinkWriter, connID, "", Up, cleanup)
+	dw := newWrapWriter(ctx, downlinkWriter, connID, "", Down, cleanup)
 
-	ur := &wrapReader{inner: uplinkReader, conn: connID, onClose: cleanup}
-	dr := &wrapReader{inner: downlinkReader, conn: connID, onClose: cleanup}
+	ur := newWrapReader(ctx, uplinkReader, connID, "", cleanup)
+	dr := newWrapReader(ctx, downlinkReader, connID, "", cleanup)
 
 	return connID, uw, dw, ur, dr
 }
@@ -147,7 +312,9 @@ type wrappedConn interface {
 func (w *wrapReader) RateLimitConnID() ConnID { return w.conn }
 func (w *wrapWriter) RateLimitConnID() ConnID { return w.conn }
 
-func WrapLinkWithConnID(id ConnID, link *transport.Link) *transport.Link {
+// inboundTag нужен для per-inbound parent-лимита; может быть пустым.
+// Ожидания лимитов прерываются отменой ctx или Interrupt/Close обёрток.
+func WrapLinkWithConnID(ctx context.Context, id ConnID, inboundTag string, link *transport.Link) *transport.Link {
 	// ВАЖНО: тут НЕ должно быть cleanup удаления registry/buckets,
 	// потому что link-ов будет много, а ConnID один на inbound.
 	// cleanup делаем один раз при закрытии inbound соединения.
@@ -161,18 +328,9 @@ func WrapLinkWithConnID(id ConnID, link *transport.Link) *transport.Link {
 		}
 	}
 
-	link.Reader = &wrapReader{
-		inner: link.Reader,
-		conn:  id,
-		// onClose можно оставить nil
-	}
-
-	link.Writer = &wrapWriter{
-		inner: link.Writer,
-		conn:  id,
-		dir:   Down,
-		// onClose nil
-	}
+	// onClose можно оставить nil
+	link.Reader = newWrapReader(ctx, link.Reader, id, inboundTag, nil)
+	link.Writer = newWrapWriter(ctx, link.Writer, id, inboundTag, Down, nil)
 
 	return link
 }
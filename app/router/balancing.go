@@ -2,6 +2,7 @@ package router
 
 import (
 	"context"
+	"strings"
 	sync "sync"
 
 	"github.com/xtls/xray-core/app/observatory"
@@ -10,12 +11,26 @@ import (
 	"github.com/xtls/xray-core/core"
 	"github.com/xtls/xray-core/features/extension"
 	"github.com/xtls/xray-core/features/outbound"
+	"github.com/xtls/xray-core/features/routing"
 )
 
 type BalancingStrategy interface {
 	PickOutbound([]string) string
 }
 
+// ContextualBalancingStrategy is a BalancingStrategy that picks by the
+// routing context of the connection.
+type ContextualBalancingStrategy interface {
+	PickOutboundFor(ctx routing.Context, candidates []string) string
+}
+
+// PeekingBalancingStrategy is a BalancingStrategy with state a pick changes,
+// such as a round-robin cursor. PeekOutbound tells what the next pick for
+// ctx would be without changing that state, for dry runs like TraceRoute.
+type PeekingBalancingStrategy interface {
+	PeekOutbound(ctx routing.Context, candidates []string) string
+}
+
 type BalancingPrincipleTarget interface {
 	GetPrincipleTarget([]string) []string
 }
@@ -44,6 +59,34 @@ func (s *RoundRobinStrategy) GetPrincipleTarget(strings []string) []string {
 }
 
 func (s *RoundRobinStrategy) PickOutbound(tags []string) string {
+	tags = s.alive(tags)
+	n := len(tags)
+	if n == 0 {
+		// goes to fallbackTag
+		return ""
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	tag := tags[s.index%n]
+	s.index = (s.index + 1) % n
+	return tag
+}
+
+// PeekOutbound implements PeekingBalancingStrategy.
+func (s *RoundRobinStrategy) PeekOutbound(ctx routing.Context, tags []string) string {
+	tags = s.alive(tags)
+	n := len(tags)
+	if n == 0 {
+		return ""
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return tags[s.index%n]
+}
+
+func (s *RoundRobinStrategy) alive(tags []string) []string {
 	if s.observatory != nil {
 		observeReport, err := s.observatory.GetObservation(s.ctx)
 		if err == nil {
@@ -68,18 +111,7 @@ func (s *RoundRobinStrategy) PickOutbound(tags []string) string {
 			}
 		}
 	}
-
-	n := len(tags)
-	if n == 0 {
-		// goes to fallbackTag
-		return ""
-	}
-
-	s.mu.Lock()
-	defer s.mu.Unlock()
-	tag := tags[s.index%n]
-	s.index = (s.index + 1) % n
-	return tag
+	return tags
 }
 
 type Balancer struct {
@@ -87,12 +119,30 @@ type Balancer struct {
 	strategy    BalancingStrategy
 	ohm         outbound.Manager
 	fallbackTag string
+	failover    *Failover
 
 	override override
 }
 
 // PickOutbound picks the tag of a outbound
 func (b *Balancer) PickOutbound() (string, error) {
+	return b.PickOutboundFor(nil)
+}
+
+// PickOutboundFor picks the tag of a outbound for the connection of ctx,
+// which may be nil.
+func (b *Balancer) PickOutboundFor(ctx routing.Context) (string, error) {
+	return b.pickOutbound(ctx, false)
+}
+
+// PeekOutboundFor tells what PickOutboundFor would pick, leaving the state
+// of the strategy as it is. Strategies picking at random report one of
+// their possible picks.
+func (b *Balancer) PeekOutboundFor(ctx routing.Context) (string, error) {
+	return b.pickOutbound(ctx, true)
+}
+
+func (b *Balancer) pickOutbound(ctx routing.Context, peek bool) (string, error) {
 	candidates, err := b.SelectOutbounds()
 	if err != nil {
 		if b.fallbackTag != "" {
@@ -104,6 +154,10 @@ func (b *Balancer) PickOutbound() (string, error) {
 	var tag string
 	if o := b.override.Get(); o != "" {
 		tag = o
+	} else if s, ok := b.strategy.(PeekingBalancingStrategy); ok && peek {
+		tag = s.PeekOutbound(ctx, candidates)
+	} else if s, ok := b.strategy.(ContextualBalancingStrategy); ok && ctx != nil {
+		tag = s.PickOutboundFor(ctx, candidates)
 	} else {
 		tag = b.strategy.PickOutbound(candidates)
 	}
@@ -124,6 +178,16 @@ func (b *Balancer) InjectContext(ctx context.Context) {
 	}
 }
 
+// selects reports whether the selectors of the Balancer match tag.
+func (b *Balancer) selects(tag string) bool {
+	for _, selector := range b.selectors {
+		if strings.HasPrefix(tag, selector) {
+			return true
+		}
+	}
+	return false
+}
+
 // SelectOutbounds select outbounds with selectors of the Balancer
 func (b *Balancer) SelectOutbounds() ([]string, error) {
 	hs, ok := b.ohm.(outbound.HandlerSelector)
@@ -136,7 +200,7 @@ func (b *Balancer) SelectOutbounds() ([]string, error) {
 
 // GetPrincipleTarget implements routing.BalancerPrincipleTarget
 func (r *Router) GetPrincipleTarget(tag string) ([]string, error) {
-	if b, ok := r.balancers[tag]; ok {
+	if b, ok := r.getBalancer(tag); ok {
 		if s, ok := b.strategy.(BalancingPrincipleTarget); ok {
 			candidates, err := b.SelectOutbounds()
 			if err != nil {
@@ -151,7 +215,7 @@ func (r *Router) GetPrincipleTarget(tag string) ([]string, error) {
 
 // SetOverrideTarget implements routing.BalancerOverrider
 func (r *Router) SetOverrideTarget(tag, target string) error {
-	if b, ok := r.balancers[tag]; ok {
+	if b, ok := r.getBalancer(tag); ok {
 		b.override.Put(target)
 		return nil
 	}
@@ -160,7 +224,7 @@ func (r *Router) SetOverrideTarget(tag, target string) error {
 
 // GetOverrideTarget implements routing.BalancerOverrider
 func (r *Router) GetOverrideTarget(tag string) (string, error) {
-	if b, ok := r.balancers[tag]; ok {
+	if b, ok := r.getBalancer(tag); ok {
 		return b.override.Get(), nil
 	}
 	return "", errors.New("cannot find tag")
@@ -0,0 +1,149 @@
+package router
+
+import (
+	"maps"
+	"strconv"
+
+	"github.com/xtls/xray-core/common/errors"
+	"github.com/xtls/xray-core/common/serial"
+)
+
+func balancingRule(config *serial.TypedMessage) (*BalancingRule, error) {
+	inst, err := config.GetInstance()
+	if err != nil {
+		return nil, err
+	}
+	if br, ok := inst.(*BalancingRule); ok {
+		if br.Tag == "" {
+			return nil, errors.New("balancer tag is empty")
+		}
+		return br, nil
+	}
+	return nil, errors.New("config type error")
+}
+
+// getBalancer returns the balancer with the tag, if any.
+func (r *Router) getBalancer(tag string) (*Balancer, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	b, ok := r.balancers[tag]
+	return b, ok
+}
+
+// AddBalancer implements routing.BalancerManager.
+func (r *Router) AddBalancer(config *serial.TypedMessage) error {
+	br, err := balancingRule(config)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, found := r.balancers[br.Tag]; found {
+		return errors.New("duplicate balancer tag ", br.Tag)
+	}
+	balancer, err := r.buildBalancer(br)
+	if err != nil {
+		return err
+	}
+	balancers := maps.Clone(r.balancers)
+	balancers[br.Tag] = balancer
+	r.balancers = balancers
+	return nil
+}
+
+// UpdateBalancer implements routing.BalancerManager. Rules using the
+// balancer switch to the new one together; an override target is kept.
+func (r *Router) UpdateBalancer(config *serial.TypedMessage) error {
+	br, err := balancingRule(config)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	old, found := r.balancers[br.Tag]
+	if !found {
+		return errors.New("balancer ", br.Tag, " not found")
+	}
+	balancer, err := r.buildBalancer(br)
+	if err != nil {
+		return err
+	}
+	if target := old.override.Get(); target != "" {
+		balancer.override.Put(target)
+	}
+
+	current := r.loadRules()
+	rules := make([]*Rule, len(current))
+	for i, rule := range current {
+		if rule.Balancer == old {
+			updated := *rule
+			updated.Balancer = balancer
+			rule = &updated
+		}
+		rules[i] = rule
+	}
+
+	balancers := maps.Clone(r.balancers)
+	balancers[br.Tag] = balancer
+	r.balancers = balancers
+	r.rules.Store(&rules)
+	return nil
+}
+
+// RemoveBalancer implements routing.BalancerManager. A balancer still used
+// by a rule cannot be removed.
+func (r *Router) RemoveBalancer(tag string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	balancer, found := r.balancers[tag]
+	if !found {
+		return errors.New("balancer ", tag, " not found")
+	}
+	for i, rule := range r.loadRules() {
+		if rule.Balancer == balancer {
+			name := rule.RuleTag
+			if name == "" {
+				name = "#" + strconv.Itoa(i)
+			}
+			return errors.New("balancer ", tag, " is used by rule ", name)
+		}
+	}
+
+	balancers := maps.Clone(r.balancers)
+	delete(balancers, tag)
+	r.balancers = balancers
+	return nil
+}
+
+// onOutboundChange re-evaluates the balancers after an outbound is added or
+// removed at runtime: an override to a removed outbound is dropped, and a
+// balancer left without candidates is reported.
+func (r *Router) onOutboundChange(tag string, added bool) {
+	r.mu.Lock()
+	balancers := r.balancers
+	r.mu.Unlock()
+
+	for btag, b := range balancers {
+		if !added && b.override.Get() == tag {
+			b.override.Clear()
+			errors.LogInfo(r.ctx, "balancer ", btag, ": override target ", tag, " removed")
+		}
+		if !b.selects(tag) {
+			continue
+		}
+		candidates, err := b.SelectOutbounds()
+		if err != nil {
+			continue
+		}
+		if len(candidates) == 0 && b.fallbackTag == "" {
+			errors.LogWarning(r.ctx, "balancer ", btag, " has no outbounds left after ", tag, " was removed")
+		} else {
+			errors.LogDebug(r.ctx, "balancer ", btag, " now selects ", candidates)
+		}
+	}
+}
@@ -7,13 +7,7 @@ import (
 )
 
 func (r *Router) OverrideBalancer(balancer string, target string) error {
-	var b *Balancer
-	for tag, bl := range r.balancers {
-		if tag == balancer {
-			b = bl
-			break
-		}
-	}
+	b, _ := r.getBalancer(balancer)
 	if b == nil {
 		return errors.New("balancer '", balancer, "' not found")
 	}
@@ -67,6 +67,86 @@ func (s *routingServer) RemoveRule(ctx context.Context, request *RemoveRuleReque
 	return nil, errors.New("unsupported router implementation")
 }
 
+func (s *routingServer) ListRules(ctx context.Context, request *ListRulesRequest) (*ListRulesResponse, error) {
+	rm, ok := s.router.(routing.RuleManager)
+	if !ok {
+		return nil, errors.New("unsupported router implementation")
+	}
+	resp := &ListRulesResponse{}
+	for _, info := range rm.ListRules() {
+		rule := asRuleInfo(info)
+		rule.Config = nil
+		resp.Rules = append(resp.Rules, rule)
+	}
+	return resp, nil
+}
+
+func (s *routingServer) GetRule(ctx context.Context, request *GetRuleRequest) (*GetRuleResponse, error) {
+	rm, ok := s.router.(routing.RuleManager)
+	if !ok {
+		return nil, errors.New("unsupported router implementation")
+	}
+	rules := rm.ListRules()
+	if tag := request.GetRuleTag(); tag != "" {
+		for _, info := range rules {
+			if info.RuleTag == tag {
+				return &GetRuleResponse{Rule: asRuleInfo(info)}, nil
+			}
+		}
+		return nil, errors.New("rule ", tag, " not found")
+	}
+	index := int(request.GetIndex())
+	if index < 0 || index >= len(rules) {
+		return nil, errors.New("rule index ", index, " out of range")
+	}
+	return &GetRuleResponse{Rule: asRuleInfo(rules[index])}, nil
+}
+
+func (s *routingServer) InsertRule(ctx context.Context, request *InsertRuleRequest) (*InsertRuleResponse, error) {
+	if rm, ok := s.router.(routing.RuleManager); ok {
+		return &InsertRuleResponse{}, rm.InsertRule(request.Config, int(request.Index))
+	}
+	return nil, errors.New("unsupported router implementation")
+}
+
+func (s *routingServer) ReplaceRules(ctx context.Context, request *ReplaceRulesRequest) (*ReplaceRulesResponse, error) {
+	if rm, ok := s.router.(routing.RuleManager); ok {
+		return &ReplaceRulesResponse{}, rm.ReplaceRules(request.Config)
+	}
+	return nil, errors.New("unsupported router implementation")
+}
+
+func (s *routingServer) AddBalancer(ctx context.Context, request *AddBalancerRequest) (*AddBalancerResponse, error) {
+	if bm, ok := s.router.(routing.BalancerManager); ok {
+		return &AddBalancerResponse{}, bm.AddBalancer(request.Config)
+	}
+	return nil, errors.New("unsupported router implementation")
+}
+
+func (s *routingServer) UpdateBalancer(ctx context.Context, request *UpdateBalancerRequest) (*UpdateBalancerResponse, error) {
+	if bm, ok := s.router.(routing.BalancerManager); ok {
+		return &UpdateBalancerResponse{}, bm.UpdateBalancer(request.Config)
+	}
+	return nil, errors.New("unsupported router implementation")
+}
+
+func (s *routingServer) RemoveBalancer(ctx context.Context, request *RemoveBalancerRequest) (*RemoveBalancerResponse, error) {
+	if bm, ok := s.router.(routing.BalancerManager); ok {
+		return &RemoveBalancerResponse{}, bm.RemoveBalancer(request.BalancerTag)
+	}
+	return nil, errors.New("unsupported router implementation")
+}
+
+func asRuleInfo(info routing.RuleInfo) *RuleInfo {
+	return &RuleInfo{
+		Index:       int32(info.Index),
+		RuleTag:     info.RuleTag,
+		OutboundTag: info.OutboundTag,
+		BalancerTag: info.BalancerTag,
+		Config:      info.Config,
+	}
+}
+
 // NewRoutingServer creates a statistics service with statistics manager.
 func NewRoutingServer(router routing.Router, routingStats stats.Channel) RoutingServiceServer {
 	return &routingServer{
@@ -90,6 +170,57 @@ func (s *routingServer) TestRoute(ctx context.Context, request *TestRouteRequest
 	return AsProtobufMessage(request.FieldSelectors)(route), nil
 }
 
+func (s *routingServer) TraceRoute(ctx context.Context, request *TraceRouteRequest) (*TraceRouteResponse, error) {
+	if request.RoutingContext == nil {
+		return nil, errors.New("Invalid routing request.")
+	}
+	tracer, ok := s.router.(routing.RouteTracer)
+	if !ok {
+		return nil, errors.New("unsupported router implementation")
+	}
+	trace, err := tracer.TraceRoute(AsRoutingContext(request.RoutingContext))
+	if err != nil {
+		return nil, err
+	}
+	resp := &TraceRouteResponse{
+		DomainStrategy: trace.DomainStrategy,
+		Resolved:       trace.Resolved,
+	}
+	for _, ip := range trace.ResolvedIPs {
+		resp.ResolvedIPs = append(resp.ResolvedIPs, ip)
+	}
+	if trace.ResolveErr != nil {
+		resp.ResolveError = trace.ResolveErr.Error()
+	}
+	for _, rt := range trace.Rules {
+		rule := &RuleTrace{
+			Rule:            asRuleInfo(rt.RuleInfo),
+			Matched:         rt.Matched,
+			WithResolvedIPs: rt.WithResolvedIPs,
+		}
+		for _, c := range rt.Conditions {
+			rule.Conditions = append(rule.Conditions, &ConditionTrace{Name: c.Name, Matched: c.Matched})
+		}
+		resp.Rules = append(resp.Rules, rule)
+	}
+	if bt := trace.Balancer; bt != nil {
+		resp.Balancer = &BalancerTrace{
+			Tag:         bt.Tag,
+			Candidates:  bt.Candidates,
+			Override:    bt.Override,
+			FallbackTag: bt.FallbackTag,
+			Picked:      bt.Picked,
+		}
+		if bt.Err != nil {
+			resp.Balancer.Error = bt.Err.Error()
+		}
+	}
+	if trace.Route != nil {
+		resp.Route = AsProtobufMessage(nil)(trace.Route)
+	}
+	return resp, nil
+}
+
 func (s *routingServer) SubscribeRoutingStats(request *SubscribeRoutingStatsRequest, stream RoutingService_SubscribeRoutingStatsServer) error {
 	if s.routingStats == nil {
 		return errors.New("Routing statistics not enabled.")
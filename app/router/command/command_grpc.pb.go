@@ -21,10 +21,18 @@ const _ = grpc.SupportPackageIsVersion9
 const (
 	RoutingService_SubscribeRoutingStats_FullMethodName  = "/xray.app.router.command.RoutingService/SubscribeRoutingStats"
 	RoutingService_TestRoute_FullMethodName              = "/xray.app.router.command.RoutingService/TestRoute"
+	RoutingService_TraceRoute_FullMethodName             = "/xray.app.router.command.RoutingService/TraceRoute"
 	RoutingService_GetBalancerInfo_FullMethodName        = "/xray.app.router.command.RoutingService/GetBalancerInfo"
 	RoutingService_OverrideBalancerTarget_FullMethodName = "/xray.app.router.command.RoutingService/OverrideBalancerTarget"
 	RoutingService_AddRule_FullMethodName                = "/xray.app.router.command.RoutingService/AddRule"
 	RoutingService_RemoveRule_FullMethodName             = "/xray.app.router.command.RoutingService/RemoveRule"
+	RoutingService_ListRules_FullMethodName              = "/xray.app.router.command.RoutingService/ListRules"
+	RoutingService_GetRule_FullMethodName                = "/xray.app.router.command.RoutingService/GetRule"
+	RoutingService_InsertRule_FullMethodName             = "/xray.app.router.command.RoutingService/InsertRule"
+	RoutingService_ReplaceRules_FullMethodName           = "/xray.app.router.command.RoutingService/ReplaceRules"
+	RoutingService_AddBalancer_FullMethodName            = "/xray.app.router.command.RoutingService/AddBalancer"
+	RoutingService_UpdateBalancer_FullMethodName         = "/xray.app.router.command.RoutingService/UpdateBalancer"
+	RoutingService_RemoveBalancer_FullMethodName         = "/xray.app.router.command.RoutingService/RemoveBalancer"
 )
 
 // RoutingServiceClient is the client API for RoutingService service.
@@ -33,10 +41,18 @@ const (
 type RoutingServiceClient interface {
 	SubscribeRoutingStats(ctx context.Context, in *SubscribeRoutingStatsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[RoutingContext], error)
 	TestRoute(ctx context.Context, in *TestRouteRequest, opts ...grpc.CallOption) (*RoutingContext, error)
+	TraceRoute(ctx context.Context, in *TraceRouteRequest, opts ...grpc.CallOption) (*TraceRouteResponse, error)
 	GetBalancerInfo(ctx context.Context, in *GetBalancerInfoRequest, opts ...grpc.CallOption) (*GetBalancerInfoResponse, error)
 	OverrideBalancerTarget(ctx context.Context, in *OverrideBalancerTargetRequest, opts ...grpc.CallOption) (*OverrideBalancerTargetResponse, error)
 	AddRule(ctx context.Context, in *AddRuleRequest, opts ...grpc.CallOption) (*AddRuleResponse, error)
 	RemoveRule(ctx context.Context, in *RemoveRuleRequest, opts ...grpc.CallOption) (*RemoveRuleResponse, error)
+	ListRules(ctx context.Context, in *ListRulesRequest, opts ...grpc.CallOption) (*ListRulesResponse, error)
+	GetRule(ctx context.Context, in *GetRuleRequest, opts ...grpc.CallOption) (*GetRuleResponse, error)
+	InsertRule(ctx context.Context, in *InsertRuleRequest, opts ...grpc.CallOption) (*InsertRuleResponse, error)
+	ReplaceRules(ctx context.Context, in *ReplaceRulesRequest, opts ...grpc.CallOption) (*ReplaceRulesResponse, error)
+	AddBalancer(ctx context.Context, in *AddBalancerRequest, opts ...grpc.CallOption) (*AddBalancerResponse, error)
+	UpdateBalancer(ctx context.Context, in *UpdateBalancerRequest, opts ...grpc.CallOption) (*UpdateBalancerResponse, error)
+	RemoveBalancer(ctx context.Context, in *RemoveBalancerRequest, opts ...grpc.CallOption) (*RemoveBalancerResponse, error)
 }
 
 type routingServiceClient struct {
@@ -76,6 +92,16 @@ func (c *routingServiceClient) TestRoute(ctx context.Context, in *TestRouteReque
 	return out, nil
 }
 
+func (c *routingServiceClient) TraceRoute(ctx context.Context, in *TraceRouteRequest, opts ...grpc.CallOption) (*TraceRouteResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TraceRouteResponse)
+	err := c.cc.Invoke(ctx, RoutingService_TraceRoute_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *routingServiceClient) GetBalancerInfo(ctx context.Context, in *GetBalancerInfoRequest, opts ...grpc.CallOption) (*GetBalancerInfoResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(GetBalancerInfoResponse)
@@ -116,16 +142,94 @@ func (c *routingServiceClient) RemoveRule(ctx context.Context, in *RemoveRuleReq
 	return out, nil
 }
 
+func (c *routingServiceClient) ListRules(ctx context.Context, in *ListRulesRequest, opts ...grpc.CallOption) (*ListRulesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListRulesResponse)
+	err := c.cc.Invoke(ctx, RoutingService_ListRules_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *routingServiceClient) GetRule(ctx context.Context, in *GetRuleRequest, opts ...grpc.CallOption) (*GetRuleResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetRuleResponse)
+	err := c.cc.Invoke(ctx, RoutingService_GetRule_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *routingServiceClient) InsertRule(ctx context.Context, in *InsertRuleRequest, opts ...grpc.CallOption) (*InsertRuleResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(InsertRuleResponse)
+	err := c.cc.Invoke(ctx, RoutingService_InsertRule_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *routingServiceClient) ReplaceRules(ctx context.Context, in *ReplaceRulesRequest, opts ...grpc.CallOption) (*ReplaceRulesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReplaceRulesResponse)
+	err := c.cc.Invoke(ctx, RoutingService_ReplaceRules_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *routingServiceClient) AddBalancer(ctx context.Context, in *AddBalancerRequest, opts ...grpc.CallOption) (*AddBalancerResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AddBalancerResponse)
+	err := c.cc.Invoke(ctx, RoutingService_AddBalancer_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *routingServiceClient) UpdateBalancer(ctx context.Context, in *UpdateBalancerRequest, opts ...grpc.CallOption) (*UpdateBalancerResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UpdateBalancerResponse)
+	err := c.cc.Invoke(ctx, RoutingService_UpdateBalancer_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *routingServiceClient) RemoveBalancer(ctx context.Context, in *RemoveBalancerRequest, opts ...grpc.CallOption) (*RemoveBalancerResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RemoveBalancerResponse)
+	err := c.cc.Invoke(ctx, RoutingService_RemoveBalancer_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // RoutingServiceServer is the server API for RoutingService service.
 // All implementations must embed UnimplementedRoutingServiceServer
 // for forward compatibility.
 type RoutingServiceServer interface {
 	SubscribeRoutingStats(*SubscribeRoutingStatsRequest, grpc.ServerStreamingServer[RoutingContext]) error
 	TestRoute(context.Context, *TestRouteRequest) (*RoutingContext, error)
+	TraceRoute(context.Context, *TraceRouteRequest) (*TraceRouteResponse, error)
 	GetBalancerInfo(context.Context, *GetBalancerInfoRequest) (*GetBalancerInfoResponse, error)
 	OverrideBalancerTarget(context.Context, *OverrideBalancerTargetRequest) (*OverrideBalancerTargetResponse, error)
 	AddRule(context.Context, *AddRuleRequest) (*AddRuleResponse, error)
 	RemoveRule(context.Context, *RemoveRuleRequest) (*RemoveRuleResponse, error)
+	ListRules(context.Context, *ListRulesRequest) (*ListRulesResponse, error)
+	GetRule(context.Context, *GetRuleRequest) (*GetRuleResponse, error)
+	InsertRule(context.Context, *InsertRuleRequest) (*InsertRuleResponse, error)
+	ReplaceRules(context.Context, *ReplaceRulesRequest) (*ReplaceRulesResponse, error)
+	AddBalancer(context.Context, *AddBalancerRequest) (*AddBalancerResponse, error)
+	UpdateBalancer(context.Context, *UpdateBalancerRequest) (*UpdateBalancerResponse, error)
+	RemoveBalancer(context.Context, *RemoveBalancerRequest) (*RemoveBalancerResponse, error)
 	mustEmbedUnimplementedRoutingServiceServer()
 }
 
@@ -142,6 +246,9 @@ func (UnimplementedRoutingServiceServer) SubscribeRoutingStats(*SubscribeRouting
 func (UnimplementedRoutingServiceServer) TestRoute(context.Context, *TestRouteRequest) (*RoutingContext, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method TestRoute not implemented")
 }
+func (UnimplementedRoutingServiceServer) TraceRoute(context.Context, *TraceRouteRequest) (*TraceRouteResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method TraceRoute not implemented")
+}
 func (UnimplementedRoutingServiceServer) GetBalancerInfo(context.Context, *GetBalancerInfoRequest) (*GetBalancerInfoResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetBalancerInfo not implemented")
 }
@@ -154,6 +261,27 @@ func (UnimplementedRoutingServiceServer) AddRule(context.Context, *AddRuleReques
 func (UnimplementedRoutingServiceServer) RemoveRule(context.Context, *RemoveRuleRequest) (*RemoveRuleResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method RemoveRule not implemented")
 }
+func (UnimplementedRoutingServiceServer) ListRules(context.Context, *ListRulesRequest) (*ListRulesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListRules not implemented")
+}
+func (UnimplementedRoutingServiceServer) GetRule(context.Context, *GetRuleRequest) (*GetRuleResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRule not implemented")
+}
+func (UnimplementedRoutingServiceServer) InsertRule(context.Context, *InsertRuleRequest) (*InsertRuleResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method InsertRule not implemented")
+}
+func (UnimplementedRoutingServiceServer) ReplaceRules(context.Context, *ReplaceRulesRequest) (*ReplaceRulesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReplaceRules not implemented")
+}
+func (UnimplementedRoutingServiceServer) AddBalancer(context.Context, *AddBalancerRequest) (*AddBalancerResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddBalancer not implemented")
+}
+func (UnimplementedRoutingServiceServer) UpdateBalancer(context.Context, *UpdateBalancerRequest) (*UpdateBalancerResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateBalancer not implemented")
+}
+func (UnimplementedRoutingServiceServer) RemoveBalancer(context.Context, *RemoveBalancerRequest) (*RemoveBalancerResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveBalancer not implemented")
+}
 func (UnimplementedRoutingServiceServer) mustEmbedUnimplementedRoutingServiceServer() {}
 func (UnimplementedRoutingServiceServer) testEmbeddedByValue()                        {}
 
@@ -204,6 +332,24 @@ func _RoutingService_TestRoute_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _RoutingService_TraceRoute_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TraceRouteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RoutingServiceServer).TraceRoute(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RoutingService_TraceRoute_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RoutingServiceServer).TraceRoute(ctx, req.(*TraceRouteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _RoutingService_GetBalancerInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(GetBalancerInfoRequest)
 	if err := dec(in); err != nil {
@@ -276,6 +422,132 @@ func _RoutingService_RemoveRule_Handler(srv interface{}, ctx context.Context, de
 	return interceptor(ctx, in, info, handler)
 }
 
+func _RoutingService_ListRules_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListRulesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RoutingServiceServer).ListRules(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RoutingService_ListRules_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RoutingServiceServer).ListRules(ctx, req.(*ListRulesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RoutingService_GetRule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRuleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RoutingServiceServer).GetRule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RoutingService_GetRule_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RoutingServiceServer).GetRule(ctx, req.(*GetRuleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RoutingService_InsertRule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(InsertRuleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RoutingServiceServer).InsertRule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RoutingService_InsertRule_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RoutingServiceServer).InsertRule(ctx, req.(*InsertRuleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RoutingService_ReplaceRules_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReplaceRulesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RoutingServiceServer).ReplaceRules(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RoutingService_ReplaceRules_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RoutingServiceServer).ReplaceRules(ctx, req.(*ReplaceRulesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RoutingService_AddBalancer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddBalancerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RoutingServiceServer).AddBalancer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RoutingService_AddBalancer_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RoutingServiceServer).AddBalancer(ctx, req.(*AddBalancerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RoutingService_UpdateBalancer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateBalancerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RoutingServiceServer).UpdateBalancer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RoutingService_UpdateBalancer_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RoutingServiceServer).UpdateBalancer(ctx, req.(*UpdateBalancerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RoutingService_RemoveBalancer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveBalancerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RoutingServiceServer).RemoveBalancer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RoutingService_RemoveBalancer_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RoutingServiceServer).RemoveBalancer(ctx, req.(*RemoveBalancerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // RoutingService_ServiceDesc is the grpc.ServiceDesc for RoutingService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -287,6 +559,10 @@ var RoutingService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "TestRoute",
 			Handler:    _RoutingService_TestRoute_Handler,
 		},
+		{
+			MethodName: "TraceRoute",
+			Handler:    _RoutingService_TraceRoute_Handler,
+		},
 		{
 			MethodName: "GetBalancerInfo",
 			Handler:    _RoutingService_GetBalancerInfo_Handler,
@@ -303,6 +579,34 @@ var RoutingService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "RemoveRule",
 			Handler:    _RoutingService_RemoveRule_Handler,
 		},
+		{
+			MethodName: "ListRules",
+			Handler:    _RoutingService_ListRules_Handler,
+		},
+		{
+			MethodName: "GetRule",
+			Handler:    _RoutingService_GetRule_Handler,
+		},
+		{
+			MethodName: "InsertRule",
+			Handler:    _RoutingService_InsertRule_Handler,
+		},
+		{
+			MethodName: "ReplaceRules",
+			Handler:    _RoutingService_ReplaceRules_Handler,
+		},
+		{
+			MethodName: "AddBalancer",
+			Handler:    _RoutingService_AddBalancer_Handler,
+		},
+		{
+			MethodName: "UpdateBalancer",
+			Handler:    _RoutingService_UpdateBalancer_Handler,
+		},
+		{
+			MethodName: "RemoveBalancer",
+			Handler:    _RoutingService_RemoveBalancer_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
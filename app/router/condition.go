@@ -3,6 +3,7 @@ package router
 import (
 	"regexp"
 	"strings"
+	"time"
 
 	"github.com/xtls/xray-core/common/errors"
 	"github.com/xtls/xray-core/common/net"
@@ -302,3 +303,85 @@ func (m *AttributeMatcher) Apply(ctx routing.Context) bool {
 	}
 	return m.Match(attributes)
 }
+
+const minutesPerDay = 24 * 60
+
+type scheduleRange struct {
+	start, end int
+}
+
+// ScheduleMatcher matches connections by the time they are routed at.
+type ScheduleMatcher struct {
+	// bit n set for weekday n
+	weekdays uint8
+	ranges   []scheduleRange
+	location *time.Location
+	now      func() time.Time
+}
+
+// NewScheduleMatcher creates a ScheduleMatcher reading the time from now, or
+// from the wall clock if now is nil.
+func NewScheduleMatcher(s *Schedule, now func() time.Time) (*ScheduleMatcher, error) {
+	m := &ScheduleMatcher{
+		location: time.Local,
+		now:      now,
+	}
+	if m.now == nil {
+		m.now = time.Now
+	}
+	if tz := s.GetTimezone(); tz != "" {
+		location, err := time.LoadLocation(tz)
+		if err != nil {
+			return nil, errors.New("unknown time zone ", tz).Base(err)
+		}
+		m.location = location
+	}
+	for _, day := range s.GetWeekdays() {
+		if day > 6 {
+			return nil, errors.New("invalid weekday ", day)
+		}
+		m.weekdays |= 1 << day
+	}
+	if m.weekdays == 0 {
+		m.weekdays = 0x7f
+	}
+	for _, r := range s.GetRanges() {
+		if r.Start == r.End || r.Start >= minutesPerDay || r.End > minutesPerDay {
+			return nil, errors.New("invalid time range ", r.Start, "-", r.End)
+		}
+		m.ranges = append(m.ranges, scheduleRange{int(r.Start), int(r.End)})
+	}
+	return m, nil
+}
+
+func (m *ScheduleMatcher) onDay(day time.Weekday) bool {
+	return m.weekdays&(1<<day) != 0
+}
+
+// Apply implements Condition.
+func (m *ScheduleMatcher) Apply(ctx routing.Context) bool {
+	now := m.now().In(m.location)
+	day := now.Weekday()
+	if len(m.ranges) == 0 {
+		return m.onDay(day)
+	}
+	minute := now.Hour()*60 + now.Minute()
+	yesterday := (day + 6) % 7
+	for _, r := range m.ranges {
+		if r.start < r.end {
+			if m.onDay(day) && minute >= r.start && minute < r.end {
+				return true
+			}
+			continue
+		}
+		// past midnight: the evening part belongs to today, the morning
+		// part to the day before
+		if m.onDay(day) && minute >= r.start {
+			return true
+		}
+		if m.onDay(yesterday) && minute < r.end {
+			return true
+		}
+	}
+	return false
+}
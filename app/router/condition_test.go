@@ -3,6 +3,7 @@ package router_test
 import (
 	"strconv"
 	"testing"
+	"time"
 
 	. "github.com/xtls/xray-core/app/router"
 	"github.com/xtls/xray-core/common"
@@ -300,6 +301,69 @@ func TestRoutingRule(t *testing.T) {
 	}
 }
 
+func TestScheduleMatcher(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skip("no time zone database: ", err)
+	}
+	var now time.Time
+	clock := func() time.Time { return now }
+
+	// weekdays off-peak: Friday night into Saturday morning counts as Friday
+	m, err := NewScheduleMatcher(&Schedule{
+		Weekdays: []uint32{1, 2, 3, 4, 5},
+		Ranges: []*Schedule_TimeRange{
+			{Start: 22 * 60, End: 6 * 60},
+			{Start: 12 * 60, End: 13 * 60},
+		},
+		Timezone: "Europe/Berlin",
+	}, clock)
+	common.Must(err)
+
+	cases := []struct {
+		at   time.Time
+		want bool
+	}{
+		{time.Date(2026, 10, 16, 12, 30, 0, 0, berlin), true},   // Friday noon
+		{time.Date(2026, 10, 16, 13, 0, 0, 0, berlin), false},   // end is exclusive
+		{time.Date(2026, 10, 16, 23, 0, 0, 0, berlin), true},    // Friday night
+		{time.Date(2026, 10, 17, 5, 59, 0, 0, berlin), true},    // Saturday morning, from Friday
+		{time.Date(2026, 10, 17, 23, 0, 0, 0, berlin), false},   // Saturday night
+		{time.Date(2026, 10, 19, 5, 0, 0, 0, berlin), false},    // Monday morning, from Sunday
+		{time.Date(2026, 10, 19, 12, 0, 0, 0, berlin), true},    // Monday noon
+		{time.Date(2026, 10, 19, 10, 30, 0, 0, time.UTC), true}, // Monday noon in Berlin
+	}
+	for _, c := range cases {
+		now = c.at
+		if got := m.Apply(withBackground()); got != c.want {
+			t.Errorf("at %v: got %v, want %v", c.at, got, c.want)
+		}
+	}
+
+	// days only
+	m, err = NewScheduleMatcher(&Schedule{Weekdays: []uint32{0, 6}, Timezone: "UTC"}, clock)
+	common.Must(err)
+	now = time.Date(2026, 10, 18, 23, 59, 0, 0, time.UTC)
+	if !m.Apply(withBackground()) {
+		t.Error("expected Sunday to match")
+	}
+	now = now.Add(time.Minute)
+	if m.Apply(withBackground()) {
+		t.Error("expected Monday not to match")
+	}
+
+	for _, s := range []*Schedule{
+		{Weekdays: []uint32{7}},
+		{Ranges: []*Schedule_TimeRange{{Start: 60, End: 60}}},
+		{Ranges: []*Schedule_TimeRange{{Start: 24 * 60, End: 60}}},
+		{Timezone: "Nowhere/Nothing"},
+	} {
+		if _, err := NewScheduleMatcher(s, nil); err == nil {
+			t.Errorf("expected %v to be rejected", s)
+		}
+	}
+}
+
 func loadGeoSite(country string) ([]*Domain, error) {
 	path, err := getAssetPath("geosite.dat")
 	if err != nil {
@@ -4,6 +4,7 @@ import (
 	"context"
 	"regexp"
 	"strings"
+	"time"
 
 	"github.com/xtls/xray-core/common/errors"
 	"github.com/xtls/xray-core/features/outbound"
@@ -15,21 +16,76 @@ type Rule struct {
 	RuleTag   string
 	Balancer  *Balancer
 	Condition Condition
+
+	// the rule as configured, for listing over the API
+	config *RoutingRule
 }
 
 func (r *Rule) GetTag() (string, error) {
+	return r.GetTagFor(nil)
+}
+
+// GetTagFor is GetTag for the connection of ctx, which balancers may use.
+func (r *Rule) GetTagFor(ctx routing.Context) (string, error) {
 	if r.Balancer != nil {
-		return r.Balancer.PickOutbound()
+		return r.Balancer.PickOutboundFor(ctx)
 	}
 	return r.Tag, nil
 }
 
+// failoverFor returns the outbounds to try if tag, picked by the rule,
+// fails: for a balancer its other candidates and fallback, then the
+// configured ones. The failover settings of the rule win over those of
+// its balancer.
+func (r *Rule) failoverFor(tag string) *routing.Failover {
+	config := r.config.GetFailover()
+	var tags []string
+	if b := r.Balancer; b != nil && (config != nil || b.failover != nil) {
+		if candidates, err := b.SelectOutbounds(); err == nil {
+			tags = append(tags, candidates...)
+		}
+		if b.fallbackTag != "" {
+			tags = append(tags, b.fallbackTag)
+		}
+		tags = append(tags, b.failover.GetOutboundTag()...)
+		if config == nil {
+			config = b.failover
+		}
+	}
+	if config == nil {
+		return nil
+	}
+	tags = append(tags, config.OutboundTag...)
+
+	failover := &routing.Failover{
+		Attempts: int(config.Attempts),
+		Timeout:  time.Duration(config.Timeout) * time.Millisecond,
+	}
+	seen := map[string]bool{tag: true}
+	for _, t := range tags {
+		if !seen[t] {
+			seen[t] = true
+			failover.OutboundTags = append(failover.OutboundTags, t)
+		}
+	}
+	if len(failover.OutboundTags) == 0 {
+		return nil
+	}
+	return failover
+}
+
 // Apply checks rule matching of current routing context.
 func (r *Rule) Apply(ctx routing.Context) bool {
 	return r.Condition.Apply(ctx)
 }
 
 func (rr *RoutingRule) BuildCondition() (Condition, error) {
+	return rr.buildCondition(nil)
+}
+
+// buildCondition builds the condition of the rule with its rule sets looked
+// up in sets.
+func (rr *RoutingRule) buildCondition(sets map[string]*ruleSet) (Condition, error) {
 	conds := NewConditionChan()
 
 	if len(rr.InboundTag) > 0 {
@@ -72,16 +128,16 @@ func (rr *RoutingRule) BuildCondition() (Condition, error) {
 		conds.Add(&AttributeMatcher{configuredKeys})
 	}
 
-	if len(rr.Geoip) > 0 {
-		cond, err := NewIPMatcher(rr.Geoip, MatcherAsType_Target)
+	if len(rr.Geoip) > 0 || len(rr.IpRuleSet) > 0 {
+		cond, err := buildIPCondition(rr.Geoip, rr.IpRuleSet, sets, MatcherAsType_Target)
 		if err != nil {
 			return nil, err
 		}
 		conds.Add(cond)
 	}
 
-	if len(rr.SourceGeoip) > 0 {
-		cond, err := NewIPMatcher(rr.SourceGeoip, MatcherAsType_Source)
+	if len(rr.SourceGeoip) > 0 || len(rr.SourceIpRuleSet) > 0 {
+		cond, err := buildIPCondition(rr.SourceGeoip, rr.SourceIpRuleSet, sets, MatcherAsType_Source)
 		if err != nil {
 			return nil, err
 		}
@@ -97,13 +153,32 @@ func (rr *RoutingRule) BuildCondition() (Condition, error) {
 		errors.LogWarning(context.Background(), "Due to some limitations, in UDP connections, localIP is always equal to listen interface IP, so \"localIP\" rule condition does not work properly on UDP inbound connections that listen on all interfaces")
 	}
 
-	if len(rr.Domain) > 0 {
-		matcher, err := NewMphMatcherGroup(rr.Domain)
+	if rr.Schedule != nil {
+		cond, err := NewScheduleMatcher(rr.Schedule, nil)
 		if err != nil {
-			return nil, errors.New("failed to build domain condition with MphDomainMatcher").Base(err)
+			return nil, errors.New("failed to build schedule condition").Base(err)
 		}
-		errors.LogDebug(context.Background(), "MphDomainMatcher is enabled for ", len(rr.Domain), " domain rule(s)")
-		conds.Add(matcher)
+		conds.Add(cond)
+	}
+
+	if len(rr.Domain) > 0 || len(rr.DomainRuleSet) > 0 {
+		var domainConds anyCondition
+		if len(rr.Domain) > 0 {
+			matcher, err := NewMphMatcherGroup(rr.Domain)
+			if err != nil {
+				return nil, errors.New("failed to build domain condition with MphDomainMatcher").Base(err)
+			}
+			errors.LogDebug(context.Background(), "MphDomainMatcher is enabled for ", len(rr.Domain), " domain rule(s)")
+			domainConds = append(domainConds, matcher)
+		}
+		if len(rr.DomainRuleSet) > 0 {
+			ruleSets, err := lookupRuleSets(rr.DomainRuleSet, sets)
+			if err != nil {
+				return nil, err
+			}
+			domainConds = append(domainConds, &RuleSetDomainMatcher{sets: ruleSets})
+		}
+		conds.Add(oneOrAny(domainConds))
 	}
 
 	if conds.Len() == 0 {
@@ -113,6 +188,32 @@ func (rr *RoutingRule) BuildCondition() (Condition, error) {
 	return conds, nil
 }
 
+func buildIPCondition(geoips []*GeoIP, ruleSetTags []string, sets map[string]*ruleSet, asType MatcherAsType) (Condition, error) {
+	var ipConds anyCondition
+	if len(geoips) > 0 {
+		cond, err := NewIPMatcher(geoips, asType)
+		if err != nil {
+			return nil, err
+		}
+		ipConds = append(ipConds, cond)
+	}
+	if len(ruleSetTags) > 0 {
+		ruleSets, err := lookupRuleSets(ruleSetTags, sets)
+		if err != nil {
+			return nil, err
+		}
+		ipConds = append(ipConds, &RuleSetIPMatcher{sets: ruleSets, asType: asType})
+	}
+	return oneOrAny(ipConds), nil
+}
+
+func oneOrAny(conds anyCondition) Condition {
+	if len(conds) == 1 {
+		return conds[0]
+	}
+	return conds
+}
+
 // Build builds the balancing rule
 func (br *BalancingRule) Build(ohm outbound.Manager, dispatcher routing.Dispatcher) (*Balancer, error) {
 	switch strings.ToLower(br.Strategy) {
@@ -146,6 +247,24 @@ func (br *BalancingRule) Build(ohm outbound.Manager, dispatcher routing.Dispatch
 			fallbackTag: br.FallbackTag,
 			strategy:    leastLoadStrategy,
 		}, nil
+	case "consistenthash":
+		var s *StrategyConsistentHashConfig
+		if br.StrategySettings != nil {
+			i, err := br.StrategySettings.GetInstance()
+			if err != nil {
+				return nil, err
+			}
+			var ok bool
+			if s, ok = i.(*StrategyConsistentHashConfig); !ok {
+				return nil, errors.New("not a StrategyConsistentHashConfig").AtError()
+			}
+		}
+		return &Balancer{
+			selectors:   br.OutboundSelector,
+			ohm:         ohm,
+			fallbackTag: br.FallbackTag,
+			strategy:    NewConsistentHashStrategy(s),
+		}, nil
 	case "random":
 		fallthrough
 	case "":
@@ -0,0 +1,167 @@
+package router
+
+import (
+	"strings"
+
+	"github.com/xtls/xray-core/common/net"
+	"github.com/xtls/xray-core/features/dns"
+	"github.com/xtls/xray-core/features/routing"
+	routing_dns "github.com/xtls/xray-core/features/routing/dns"
+)
+
+// TraceRoute implements routing.RouteTracer. It evaluates the rules like
+// PickRoute and records every step. Balancers only peek, so a trace doesn't
+// change what they pick for live traffic.
+func (r *Router) TraceRoute(ctx routing.Context) (*routing.RouteTrace, error) {
+	trace := &routing.RouteTrace{
+		DomainStrategy: r.domainStrategy.String(),
+	}
+	skipDNSResolve := ctx.GetSkipDNSResolve()
+	rules := r.loadRules()
+
+	if r.domainStrategy == Config_IpOnDemand && !skipDNSResolve {
+		ctx = r.tracingDNSContext(ctx, trace)
+	}
+	rule := traceRules(ctx, rules, trace, false)
+
+	if rule == nil && r.domainStrategy == Config_IpIfNonMatch && len(ctx.GetTargetDomain()) > 0 && !skipDNSResolve {
+		ctx = r.tracingDNSContext(ctx, trace)
+		rule = traceRules(ctx, rules, trace, true)
+	}
+	if rule == nil {
+		return trace, nil
+	}
+
+	tag := rule.Tag
+	if rule.Balancer != nil {
+		b := rule.Balancer
+		bt := &routing.BalancerTrace{
+			Tag:         rule.config.GetBalancingTag(),
+			Override:    b.override.Get(),
+			FallbackTag: b.fallbackTag,
+		}
+		bt.Candidates, _ = b.SelectOutbounds()
+		bt.Picked, bt.Err = b.PeekOutboundFor(ctx)
+		trace.Balancer = bt
+		if bt.Err != nil {
+			return trace, nil
+		}
+		tag = bt.Picked
+	}
+	trace.Route = &Route{Context: ctx, outboundTag: tag, ruleTag: rule.RuleTag}
+	return trace, nil
+}
+
+func (r *Router) tracingDNSContext(ctx routing.Context, trace *routing.RouteTrace) routing.Context {
+	if r.dns == nil {
+		return ctx
+	}
+	return routing_dns.ContextWithDNSClient(ctx, &tracingDNS{Client: r.dns, trace: trace})
+}
+
+// traceRules returns the first rule matching ctx.
+func traceRules(ctx routing.Context, rules []*Rule, trace *routing.RouteTrace, resolved bool) *Rule {
+	for i, rule := range rules {
+		rt := routing.RuleTrace{
+			RuleInfo: routing.RuleInfo{
+				Index:       i,
+				RuleTag:     rule.RuleTag,
+				OutboundTag: rule.Tag,
+				BalancerTag: rule.config.GetBalancingTag(),
+			},
+			WithResolvedIPs: resolved,
+		}
+		rt.Conditions, rt.Matched = traceCondition(ctx, rule.Condition)
+		trace.Rules = append(trace.Rules, rt)
+		if rt.Matched {
+			return rule
+		}
+	}
+	return nil
+}
+
+func traceCondition(ctx routing.Context, cond Condition) ([]routing.ConditionTrace, bool) {
+	conds := []Condition{cond}
+	if chain, ok := cond.(*ConditionChan); ok {
+		conds = *chain
+	}
+	result := make([]routing.ConditionTrace, 0, len(conds))
+	for _, c := range conds {
+		matched := c.Apply(ctx)
+		result = append(result, routing.ConditionTrace{Name: conditionName(c), Matched: matched})
+		if !matched {
+			return result, false
+		}
+	}
+	return result, true
+}
+
+// conditionName names a condition after its field in the JSON config.
+func conditionName(cond Condition) string {
+	switch c := cond.(type) {
+	case *DomainMatcher:
+		return "domain"
+	case *IPMatcher:
+		return asTypeName(c.asType, "ip", "source", "localIP")
+	case *PortMatcher:
+		if c.asType == MatcherAsType_VlessRoute {
+			return "vlessRoute"
+		}
+		return asTypeName(c.asType, "port", "sourcePort", "localPort")
+	case NetworkMatcher:
+		return "network"
+	case *UserMatcher:
+		return "user"
+	case *InboundTagMatcher:
+		return "inboundTag"
+	case *ProtocolMatcher:
+		return "protocol"
+	case *AttributeMatcher:
+		return "attrs"
+	case *ScheduleMatcher:
+		return "schedule"
+	case *RuleSetDomainMatcher:
+		return "domain " + ruleSetNames(c.sets)
+	case *RuleSetIPMatcher:
+		return asTypeName(c.asType, "ip", "source", "localIP") + " " + ruleSetNames(c.sets)
+	case anyCondition:
+		names := make([]string, len(c))
+		for i, sub := range c {
+			names[i] = conditionName(sub)
+		}
+		return strings.Join(names, " or ")
+	}
+	return "unknown"
+}
+
+func asTypeName(asType MatcherAsType, target, source, local string) string {
+	switch asType {
+	case MatcherAsType_Source:
+		return source
+	case MatcherAsType_Local:
+		return local
+	}
+	return target
+}
+
+func ruleSetNames(sets []*ruleSet) string {
+	names := make([]string, len(sets))
+	for i, s := range sets {
+		names[i] = "ruleset:" + s.tag
+	}
+	return strings.Join(names, ",")
+}
+
+// tracingDNS records the lookup of the target domain.
+type tracingDNS struct {
+	dns.Client
+	trace *routing.RouteTrace
+}
+
+func (d *tracingDNS) LookupIP(domain string, option dns.IPOption) ([]net.IP, uint32, error) {
+	ips, ttl, err := d.Client.LookupIP(domain, option)
+	d.trace.Resolved = true
+	d.trace.ResolvedIPs = ips
+	d.trace.ResolveErr = err
+	return ips, ttl, err
+}
@@ -2,7 +2,10 @@ package router
 
 import (
 	"context"
+	"maps"
+	"slices"
 	sync "sync"
+	"sync/atomic"
 
 	"github.com/xtls/xray-core/common"
 	"github.com/xtls/xray-core/common/errors"
@@ -12,14 +15,23 @@ import (
 	"github.com/xtls/xray-core/features/outbound"
 	"github.com/xtls/xray-core/features/routing"
 	routing_dns "github.com/xtls/xray-core/features/routing/dns"
+	"github.com/xtls/xray-core/features/stats"
 )
 
 // Router is an implementation of routing.Router.
 type Router struct {
 	domainStrategy Config_DomainStrategy
-	rules          []*Rule
-	balancers      map[string]*Balancer
-	dns            dns.Client
+	// rules is replaced as a whole, never modified in place, so PickRoute
+	// sees either the old or the new list
+	rules atomic.Pointer[[]*Rule]
+	// balancers is copy-on-write as well, guarded by mu
+	balancers map[string]*Balancer
+	dns       dns.Client
+	// counters of tagged rules are kept in stats if ruleStats is set
+	ruleStats bool
+	stats     stats.Manager
+	// rule sets are fixed at Init; their content is reloaded in place
+	ruleSets map[string]*ruleSet
 
 	ctx        context.Context
 	ohm        outbound.Manager
@@ -33,6 +45,7 @@ type Route struct {
 	outboundGroupTags []string
 	outboundTag       string
 	ruleTag           string
+	failover          *routing.Failover
 }
 
 // Init initializes the Router.
@@ -42,37 +55,41 @@ func (r *Router) Init(ctx context.Context, config *Config, d dns.Client, ohm out
 	r.ctx = ctx
 	r.ohm = ohm
 	r.dispatcher = dispatcher
+	r.ruleStats = config.RuleStats
+
+	r.ruleSets = make(map[string]*ruleSet, len(config.RuleSet))
+	for _, rs := range config.RuleSet {
+		if _, found := r.ruleSets[rs.Tag]; found {
+			return errors.New("duplicate rule set ", rs.Tag)
+		}
+		set, err := newRuleSet(ctx, rs)
+		if err != nil {
+			return err
+		}
+		r.ruleSets[rs.Tag] = set
+	}
 
 	r.balancers = make(map[string]*Balancer, len(config.BalancingRule))
 	for _, rule := range config.BalancingRule {
-		balancer, err := rule.Build(ohm, dispatcher)
+		balancer, err := r.buildBalancer(rule)
 		if err != nil {
 			return err
 		}
-		balancer.InjectContext(ctx)
 		r.balancers[rule.Tag] = balancer
 	}
 
-	r.rules = make([]*Rule, 0, len(config.Rule))
+	rules := make([]*Rule, 0, len(config.Rule))
 	for _, rule := range config.Rule {
-		cond, err := rule.BuildCondition()
+		rr, err := buildRule(rule, r.balancers, r.ruleSets)
 		if err != nil {
 			return err
 		}
-		rr := &Rule{
-			Condition: cond,
-			Tag:       rule.GetTag(),
-			RuleTag:   rule.GetRuleTag(),
-		}
-		btag := rule.GetBalancingTag()
-		if len(btag) > 0 {
-			brule, found := r.balancers[btag]
-			if !found {
-				return errors.New("balancer ", btag, " not found")
-			}
-			rr.Balancer = brule
-		}
-		r.rules = append(r.rules, rr)
+		rules = append(rules, rr)
+	}
+	r.rules.Store(&rules)
+
+	if n, ok := ohm.(outbound.HandlerChangeNotifier); ok {
+		n.OnHandlerChange(r.onOutboundChange)
 	}
 
 	return nil
@@ -84,11 +101,11 @@ func (r *Router) PickRoute(ctx routing.Context) (routing.Route, error) {
 	if err != nil {
 		return nil, err
 	}
-	tag, err := rule.GetTag()
+	tag, err := rule.GetTagFor(ctx)
 	if err != nil {
 		return nil, err
 	}
-	return &Route{Context: ctx, outboundTag: tag, ruleTag: rule.RuleTag}, nil
+	return &Route{Context: ctx, outboundTag: tag, ruleTag: rule.RuleTag, failover: rule.failoverFor(tag)}, nil
 }
 
 // AddRule implements routing.Router.
@@ -105,56 +122,193 @@ func (r *Router) AddRule(config *serial.TypedMessage, shouldAppend bool) error {
 }
 
 func (r *Router) ReloadRules(config *Config, shouldAppend bool) error {
+	mode := dropBalancers
+	if shouldAppend {
+		mode = keepBalancers
+	}
+	return r.updateRules(config, mode, func(current, added []*Rule) ([]*Rule, error) {
+		if shouldAppend {
+			return slices.Concat(current, added), nil
+		}
+		return added, nil
+	})
+}
+
+// InsertRule implements routing.RuleManager.
+func (r *Router) InsertRule(config *serial.TypedMessage, index int) error {
+	c, err := ruleConfig(config)
+	if err != nil {
+		return err
+	}
+	return r.updateRules(c, keepBalancers, func(current, added []*Rule) ([]*Rule, error) {
+		if index < 0 || index > len(current) {
+			return nil, errors.New("rule index ", index, " out of range [0, ", len(current), "]")
+		}
+		return slices.Concat(current[:index], added, current[index:]), nil
+	})
+}
+
+// ReplaceRules implements routing.RuleManager. Existing balancers are kept,
+// unless config redefines them.
+func (r *Router) ReplaceRules(config *serial.TypedMessage) error {
+	c, err := ruleConfig(config)
+	if err != nil {
+		return err
+	}
+	return r.updateRules(c, replaceBalancers, func(current, added []*Rule) ([]*Rule, error) {
+		return added, nil
+	})
+}
+
+// ListRules implements routing.RuleManager.
+func (r *Router) ListRules() []routing.RuleInfo {
+	rules := r.loadRules()
+	infos := make([]routing.RuleInfo, 0, len(rules))
+	for i, rule := range rules {
+		info := routing.RuleInfo{
+			Index:       i,
+			RuleTag:     rule.RuleTag,
+			OutboundTag: rule.Tag,
+		}
+		if rule.config != nil {
+			info.BalancerTag = rule.config.GetBalancingTag()
+			info.Config = serial.ToTypedMessage(rule.config)
+		}
+		infos = append(infos, info)
+	}
+	return infos
+}
+
+func ruleConfig(config *serial.TypedMessage) (*Config, error) {
+	inst, err := config.GetInstance()
+	if err != nil {
+		return nil, err
+	}
+	if c, ok := inst.(*Config); ok {
+		return c, nil
+	}
+	return nil, errors.New("config type error")
+}
+
+// balancerMode tells updateRules what happens to the current balancers.
+type balancerMode int
+
+const (
+	// dropBalancers leaves only the balancers of the new config.
+	dropBalancers balancerMode = iota
+	// keepBalancers keeps the current balancers; the config may not redefine them.
+	keepBalancers
+	// replaceBalancers keeps the current balancers, except those the config redefines.
+	replaceBalancers
+)
+
+// updateRules builds the balancers and rules of config and publishes the
+// list returned by edit in one step. Nothing changes if any part fails.
+func (r *Router) updateRules(config *Config, mode balancerMode, edit func(current, added []*Rule) ([]*Rule, error)) error {
+	if len(config.RuleSet) > 0 {
+		return errors.New("rule sets are only defined at startup, not with rules")
+	}
+
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	if !shouldAppend {
-		r.balancers = make(map[string]*Balancer, len(config.BalancingRule))
-		r.rules = make([]*Rule, 0, len(config.Rule))
+	balancers := make(map[string]*Balancer, len(config.BalancingRule))
+	if mode != dropBalancers {
+		maps.Copy(balancers, r.balancers)
 	}
+	defined := make(map[string]bool, len(config.BalancingRule))
 	for _, rule := range config.BalancingRule {
-		_, found := r.balancers[rule.Tag]
-		if found {
-			return errors.New("duplicate balancer tag")
+		old, found := balancers[rule.Tag]
+		if defined[rule.Tag] || (found && mode != replaceBalancers) {
+			return errors.New("duplicate balancer tag ", rule.Tag)
 		}
-		balancer, err := rule.Build(r.ohm, r.dispatcher)
+		defined[rule.Tag] = true
+		balancer, err := r.buildBalancer(rule)
 		if err != nil {
 			return err
 		}
-		balancer.InjectContext(r.ctx)
-		r.balancers[rule.Tag] = balancer
+		if found {
+			// as in UpdateBalancer, an override target survives the redefinition
+			if target := old.override.Get(); target != "" {
+				balancer.override.Put(target)
+			}
+		}
+		balancers[rule.Tag] = balancer
 	}
 
+	added := make([]*Rule, 0, len(config.Rule))
 	for _, rule := range config.Rule {
-		if r.RuleExists(rule.GetRuleTag()) {
-			return errors.New("duplicate ruleTag ", rule.GetRuleTag())
-		}
-		cond, err := rule.BuildCondition()
+		rr, err := buildRule(rule, balancers, r.ruleSets)
 		if err != nil {
 			return err
 		}
-		rr := &Rule{
-			Condition: cond,
-			Tag:       rule.GetTag(),
-			RuleTag:   rule.GetRuleTag(),
+		added = append(added, rr)
+	}
+
+	current := r.loadRules()
+	rules, err := edit(current, added)
+	if err != nil {
+		return err
+	}
+	seen := make(map[string]bool, len(rules))
+	for _, rule := range rules {
+		if rule.RuleTag == "" {
+			continue
 		}
-		btag := rule.GetBalancingTag()
-		if len(btag) > 0 {
-			brule, found := r.balancers[btag]
-			if !found {
-				return errors.New("balancer ", btag, " not found")
-			}
-			rr.Balancer = brule
+		if seen[rule.RuleTag] {
+			return errors.New("duplicate ruleTag ", rule.RuleTag)
 		}
-		r.rules = append(r.rules, rr)
+		seen[rule.RuleTag] = true
 	}
 
+	r.balancers = balancers
+	r.rules.Store(&rules)
+	r.syncRuleStats(current, rules)
+	return nil
+}
+
+func (r *Router) buildBalancer(rule *BalancingRule) (*Balancer, error) {
+	balancer, err := rule.Build(r.ohm, r.dispatcher)
+	if err != nil {
+		return nil, err
+	}
+	balancer.InjectContext(r.ctx)
+	balancer.failover = rule.Failover
+	return balancer, nil
+}
+
+func buildRule(rule *RoutingRule, balancers map[string]*Balancer, ruleSets map[string]*ruleSet) (*Rule, error) {
+	cond, err := rule.buildCondition(ruleSets)
+	if err != nil {
+		return nil, err
+	}
+	rr := &Rule{
+		Condition: cond,
+		Tag:       rule.GetTag(),
+		RuleTag:   rule.GetRuleTag(),
+		config:    rule,
+	}
+	btag := rule.GetBalancingTag()
+	if len(btag) > 0 {
+		brule, found := balancers[btag]
+		if !found {
+			return nil, errors.New("balancer ", btag, " not found")
+		}
+		rr.Balancer = brule
+	}
+	return rr, nil
+}
+
+func (r *Router) loadRules() []*Rule {
+	if rules := r.rules.Load(); rules != nil {
+		return *rules
+	}
 	return nil
 }
 
 func (r *Router) RuleExists(tag string) bool {
 	if tag != "" {
-		for _, rule := range r.rules {
+		for _, rule := range r.loadRules() {
 			if rule.RuleTag == tag {
 				return true
 			}
@@ -170,12 +324,14 @@ func (r *Router) RemoveRule(tag string) error {
 
 	newRules := []*Rule{}
 	if tag != "" {
-		for _, rule := range r.rules {
+		current := r.loadRules()
+		for _, rule := range current {
 			if rule.RuleTag != tag {
 				newRules = append(newRules, rule)
 			}
 		}
-		r.rules = newRules
+		r.rules.Store(&newRules)
+		r.syncRuleStats(current, newRules)
 		return nil
 	}
 	return errors.New("empty tag name!")
@@ -186,12 +342,14 @@ func (r *Router) pickRouteInternal(ctx routing.Context) (*Rule, routing.Context,
 	// the DOH remote server maybe a domain name,
 	// this prevents cycle resolving dead loop
 	skipDNSResolve := ctx.GetSkipDNSResolve()
+	// both passes use the same list even if it is replaced meanwhile
+	rules := r.loadRules()
 
 	if r.domainStrategy == Config_IpOnDemand && !skipDNSResolve {
 		ctx = routing_dns.ContextWithDNSClient(ctx, r.dns)
 	}
 
-	for _, rule := range r.rules {
+	for _, rule := range rules {
 		if rule.Apply(ctx) {
 			return rule, ctx, nil
 		}
@@ -204,7 +362,7 @@ func (r *Router) pickRouteInternal(ctx routing.Context) (*Rule, routing.Context,
 	ctx = routing_dns.ContextWithDNSClient(ctx, r.dns)
 
 	// Try applying rules again if we have IPs.
-	for _, rule := range r.rules {
+	for _, rule := range rules {
 		if rule.Apply(ctx) {
 			return rule, ctx, nil
 		}
@@ -215,12 +373,23 @@ func (r *Router) pickRouteInternal(ctx routing.Context) (*Rule, routing.Context,
 
 // Start implements common.Runnable.
 func (r *Router) Start() error {
+	for _, rs := range r.ruleSets {
+		if err := rs.checker.Start(); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
 // Close implements common.Closable.
 func (r *Router) Close() error {
-	return nil
+	var errs []error
+	for _, rs := range r.ruleSets {
+		if err := rs.checker.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Combine(errs...)
 }
 
 // Type implements common.HasType.
@@ -228,6 +397,11 @@ func (*Router) Type() interface{} {
 	return routing.RouterType()
 }
 
+// GetFailover implements routing.FailoverRoute.
+func (r *Route) GetFailover() *routing.Failover {
+	return r.failover
+}
+
 // GetOutboundGroupTags implements routing.Route.
 func (r *Route) GetOutboundGroupTags() []string {
 	return r.outboundGroupTags
@@ -245,8 +419,12 @@ func (r *Route) GetRuleTag() string {
 func init() {
 	common.Must(common.RegisterConfig((*Config)(nil), func(ctx context.Context, config interface{}) (interface{}, error) {
 		r := new(Router)
-		if err := core.RequireFeatures(ctx, func(d dns.Client, ohm outbound.Manager, dispatcher routing.Dispatcher) error {
-			return r.Init(ctx, config.(*Config), d, ohm, dispatcher)
+		if err := core.RequireFeatures(ctx, func(d dns.Client, ohm outbound.Manager, dispatcher routing.Dispatcher, sm stats.Manager) error {
+			if err := r.Init(ctx, config.(*Config), d, ohm, dispatcher); err != nil {
+				return err
+			}
+			r.setStatsManager(sm)
+			return nil
 		}); err != nil {
 			return nil, err
 		}
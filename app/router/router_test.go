@@ -2,15 +2,20 @@ package router_test
 
 import (
 	"context"
+	"fmt"
+	"slices"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	. "github.com/xtls/xray-core/app/router"
 	"github.com/xtls/xray-core/common"
 	"github.com/xtls/xray-core/common/net"
+	"github.com/xtls/xray-core/common/serial"
 	"github.com/xtls/xray-core/common/session"
 	"github.com/xtls/xray-core/features/dns"
 	"github.com/xtls/xray-core/features/outbound"
+	"github.com/xtls/xray-core/features/routing"
 	routing_session "github.com/xtls/xray-core/features/routing/session"
 	"github.com/xtls/xray-core/testing/mocks"
 )
@@ -276,3 +281,378 @@ func TestIPIfNonMatchIP(t *testing.T) {
 		t.Error("expect tag 'test', bug actually ", tag)
 	}
 }
+
+func TestRuleManagement(t *testing.T) {
+	tcpRule := func(ruleTag, tag string) *RoutingRule {
+		return &RoutingRule{
+			RuleTag:   ruleTag,
+			TargetTag: &RoutingRule_Tag{Tag: tag},
+			Networks:  []net.Network{net.Network_TCP},
+		}
+	}
+
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+
+	r := new(Router)
+	common.Must(r.Init(context.TODO(), &Config{
+		Rule: []*RoutingRule{tcpRule("a", "out-a"), tcpRule("b", "out-b")},
+	}, mocks.NewDNSClient(mockCtl), &mockOutboundManager{
+		Manager:         mocks.NewOutboundManager(mockCtl),
+		HandlerSelector: mocks.NewOutboundHandlerSelector(mockCtl),
+	}, nil))
+
+	ruleTags := func() []string {
+		var tags []string
+		for i, info := range r.ListRules() {
+			if info.Index != i {
+				t.Fatalf("rule %s listed at %d with index %d", info.RuleTag, i, info.Index)
+			}
+			tags = append(tags, info.RuleTag)
+		}
+		return tags
+	}
+	pick := func() string {
+		ctx := session.ContextWithOutbounds(context.Background(), []*session.Outbound{{
+			Target: net.TCPDestination(net.DomainAddress("example.com"), 80),
+		}})
+		route, err := r.PickRoute(routing_session.AsRoutingContext(ctx))
+		common.Must(err)
+		return route.GetOutboundTag()
+	}
+
+	common.Must(r.InsertRule(serial.ToTypedMessage(&Config{Rule: []*RoutingRule{tcpRule("c", "out-c")}}), 1))
+	if tags := ruleTags(); !slices.Equal(tags, []string{"a", "c", "b"}) {
+		t.Fatalf("unexpected rules after insert: %v", tags)
+	}
+
+	info := r.ListRules()[1]
+	inst, err := info.Config.GetInstance()
+	common.Must(err)
+	if rr, ok := inst.(*RoutingRule); !ok || rr.GetTag() != "out-c" {
+		t.Fatalf("unexpected rule config: %v", inst)
+	}
+
+	if err := r.InsertRule(serial.ToTypedMessage(&Config{Rule: []*RoutingRule{tcpRule("d", "out-d")}}), 4); err == nil {
+		t.Fatal("expected error for index out of range")
+	}
+	if err := r.InsertRule(serial.ToTypedMessage(&Config{Rule: []*RoutingRule{tcpRule("b", "out-b")}}), 0); err == nil {
+		t.Fatal("expected error for duplicate ruleTag")
+	}
+	if tags := ruleTags(); !slices.Equal(tags, []string{"a", "c", "b"}) {
+		t.Fatalf("failed insert changed rules: %v", tags)
+	}
+
+	common.Must(r.ReplaceRules(serial.ToTypedMessage(&Config{Rule: []*RoutingRule{tcpRule("x", "out-x")}})))
+	if tags := ruleTags(); !slices.Equal(tags, []string{"x"}) {
+		t.Fatalf("unexpected rules after replace: %v", tags)
+	}
+	if tag := pick(); tag != "out-x" {
+		t.Fatalf("expected out-x, got %s", tag)
+	}
+}
+
+func TestReplaceRulesWhilePicking(t *testing.T) {
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+
+	r := new(Router)
+	common.Must(r.Init(context.TODO(), &Config{}, mocks.NewDNSClient(mockCtl), &mockOutboundManager{
+		Manager:         mocks.NewOutboundManager(mockCtl),
+		HandlerSelector: mocks.NewOutboundHandlerSelector(mockCtl),
+	}, nil))
+
+	// every rule set routes TCP to one tag: a mix would mean a torn read
+	ruleSet := func(tag string) *serial.TypedMessage {
+		return serial.ToTypedMessage(&Config{Rule: []*RoutingRule{
+			{TargetTag: &RoutingRule_Tag{Tag: tag}, Networks: []net.Network{net.Network_UDP}},
+			{TargetTag: &RoutingRule_Tag{Tag: tag}, Networks: []net.Network{net.Network_TCP}},
+		}})
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 200; i++ {
+			common.Must(r.ReplaceRules(ruleSet(fmt.Sprint("set-", i%2))))
+		}
+	}()
+
+	ctx := routing_session.AsRoutingContext(session.ContextWithOutbounds(context.Background(), []*session.Outbound{{
+		Target: net.TCPDestination(net.DomainAddress("example.com"), 80),
+	}}))
+	for {
+		select {
+		case <-done:
+			return
+		default:
+		}
+		route, err := r.PickRoute(ctx)
+		if err != nil {
+			continue // before the first set
+		}
+		if tag := route.GetOutboundTag(); tag != "set-0" && tag != "set-1" {
+			t.Fatalf("unexpected tag %s", tag)
+		}
+	}
+}
+
+type notifyingOutboundManager struct {
+	mockOutboundManager
+	onChange func(tag string, added bool)
+}
+
+func (m *notifyingOutboundManager) OnHandlerChange(f func(tag string, added bool)) {
+	m.onChange = f
+}
+
+func TestBalancerManagement(t *testing.T) {
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+
+	mockHs := mocks.NewOutboundHandlerSelector(mockCtl)
+	mockHs.EXPECT().Select(gomock.Eq([]string{"a-"})).Return([]string{"a-1"}).AnyTimes()
+	mockHs.EXPECT().Select(gomock.Eq([]string{"b-"})).Return([]string{"b-1"}).AnyTimes()
+
+	ohm := &notifyingOutboundManager{mockOutboundManager: mockOutboundManager{
+		Manager:         mocks.NewOutboundManager(mockCtl),
+		HandlerSelector: mockHs,
+	}}
+	r := new(Router)
+	common.Must(r.Init(context.TODO(), &Config{
+		Rule: []*RoutingRule{{
+			RuleTag:   "lb",
+			TargetTag: &RoutingRule_BalancingTag{BalancingTag: "balance"},
+			Networks:  []net.Network{net.Network_TCP},
+		}},
+		BalancingRule: []*BalancingRule{{Tag: "balance", OutboundSelector: []string{"a-"}}},
+	}, mocks.NewDNSClient(mockCtl), ohm, nil))
+
+	pick := func() string {
+		ctx := session.ContextWithOutbounds(context.Background(), []*session.Outbound{{
+			Target: net.TCPDestination(net.DomainAddress("example.com"), 80),
+		}})
+		route, err := r.PickRoute(routing_session.AsRoutingContext(ctx))
+		common.Must(err)
+		return route.GetOutboundTag()
+	}
+
+	if err := r.AddBalancer(serial.ToTypedMessage(&BalancingRule{Tag: "balance"})); err == nil {
+		t.Fatal("expected error for duplicate balancer")
+	}
+	common.Must(r.AddBalancer(serial.ToTypedMessage(&BalancingRule{Tag: "spare", OutboundSelector: []string{"b-"}})))
+
+	common.Must(r.SetOverrideTarget("balance", "pinned"))
+	common.Must(r.UpdateBalancer(serial.ToTypedMessage(&BalancingRule{Tag: "balance", OutboundSelector: []string{"b-"}})))
+	if tag, _ := r.GetOverrideTarget("balance"); tag != "pinned" {
+		t.Fatalf("expected override to survive update, got %q", tag)
+	}
+
+	// the outbound behind the override goes away
+	ohm.onChange("pinned", false)
+	if tag, _ := r.GetOverrideTarget("balance"); tag != "" {
+		t.Fatalf("expected override of removed outbound to be cleared, got %q", tag)
+	}
+	if tag := pick(); tag != "b-1" {
+		t.Fatalf("expected updated selector to pick b-1, got %s", tag)
+	}
+
+	if err := r.RemoveBalancer("balance"); err == nil {
+		t.Fatal("expected error removing a balancer in use")
+	}
+	common.Must(r.RemoveBalancer("spare"))
+	if _, err := r.GetOverrideTarget("spare"); err == nil {
+		t.Fatal("expected removed balancer to be gone")
+	}
+
+	// a full config swapped in at once redefines the balancer it carries
+	full := &Config{
+		Rule: []*RoutingRule{{
+			RuleTag:   "lb",
+			TargetTag: &RoutingRule_BalancingTag{BalancingTag: "balance"},
+			Networks:  []net.Network{net.Network_TCP},
+		}},
+		BalancingRule: []*BalancingRule{{Tag: "balance", OutboundSelector: []string{"a-"}}},
+	}
+	common.Must(r.ReplaceRules(serial.ToTypedMessage(full)))
+	if tag := pick(); tag != "a-1" {
+		t.Fatalf("expected replaced balancer to pick a-1, got %s", tag)
+	}
+	full.BalancingRule = append(full.BalancingRule, &BalancingRule{Tag: "balance", OutboundSelector: []string{"b-"}})
+	if err := r.ReplaceRules(serial.ToTypedMessage(full)); err == nil {
+		t.Fatal("expected error for a balancer defined twice")
+	}
+}
+
+func TestTraceRoute(t *testing.T) {
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+
+	mockDNS := mocks.NewDNSClient(mockCtl)
+	mockDNS.EXPECT().LookupIP(gomock.Eq("example.com"), gomock.Any()).Return([]net.IP{{192, 168, 0, 1}}, uint32(600), nil).AnyTimes()
+	mockHs := mocks.NewOutboundHandlerSelector(mockCtl)
+	mockHs.EXPECT().Select(gomock.Eq([]string{"lan-"})).Return([]string{"lan-1"}).AnyTimes()
+
+	r := new(Router)
+	common.Must(r.Init(context.TODO(), &Config{
+		DomainStrategy: Config_IpIfNonMatch,
+		Rule: []*RoutingRule{
+			{
+				RuleTag:   "udp",
+				TargetTag: &RoutingRule_Tag{Tag: "u"},
+				Networks:  []net.Network{net.Network_UDP},
+			},
+			{
+				RuleTag:   "lan",
+				TargetTag: &RoutingRule_BalancingTag{BalancingTag: "lb"},
+				Networks:  []net.Network{net.Network_TCP},
+				Geoip:     []*GeoIP{{Cidr: []*CIDR{{Ip: []byte{192, 168, 0, 0}, Prefix: 16}}}},
+			},
+		},
+		BalancingRule: []*BalancingRule{{Tag: "lb", OutboundSelector: []string{"lan-"}}},
+	}, mockDNS, mockOutboundManager{Manager: mocks.NewOutboundManager(mockCtl), HandlerSelector: mockHs}, nil))
+
+	ctx := session.ContextWithOutbounds(context.Background(), []*session.Outbound{{
+		Target: net.TCPDestination(net.DomainAddress("example.com"), 80),
+	}})
+	trace, err := r.TraceRoute(routing_session.AsRoutingContext(ctx))
+	common.Must(err)
+
+	var steps []string
+	for _, rule := range trace.Rules {
+		step := fmt.Sprintf("%s resolved=%v:", rule.RuleTag, rule.WithResolvedIPs)
+		for _, c := range rule.Conditions {
+			step += fmt.Sprintf(" %s=%v", c.Name, c.Matched)
+		}
+		steps = append(steps, step)
+	}
+	want := []string{
+		"udp resolved=false: network=false",
+		"lan resolved=false: network=true ip=false",
+		"udp resolved=true: network=false",
+		"lan resolved=true: network=true ip=true",
+	}
+	if !slices.Equal(steps, want) {
+		t.Fatalf("unexpected steps:\n%v\nwant\n%v", steps, want)
+	}
+	if !trace.Resolved || len(trace.ResolvedIPs) != 1 || trace.DomainStrategy != "IpIfNonMatch" {
+		t.Fatalf("expected domain to be resolved: %+v", trace)
+	}
+	if b := trace.Balancer; b == nil || b.Tag != "lb" || !slices.Equal(b.Candidates, []string{"lan-1"}) || b.Picked != "lan-1" {
+		t.Fatalf("unexpected balancer trace: %+v", trace.Balancer)
+	}
+	if trace.Route == nil || trace.Route.GetOutboundTag() != "lan-1" || trace.Route.GetRuleTag() != "lan" {
+		t.Fatalf("unexpected route: %+v", trace.Route)
+	}
+
+	ctx = session.ContextWithOutbounds(context.Background(), []*session.Outbound{{
+		Target: net.UDPDestination(net.ParseAddress("10.0.0.1"), 53),
+	}})
+	trace, err = r.TraceRoute(routing_session.AsRoutingContext(ctx))
+	common.Must(err)
+	if trace.Resolved || len(trace.Rules) != 1 || trace.Route.GetOutboundTag() != "u" {
+		t.Fatalf("expected the first rule to match without resolving: %+v", trace)
+	}
+}
+
+func TestFailoverRoute(t *testing.T) {
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+
+	mockHs := mocks.NewOutboundHandlerSelector(mockCtl)
+	mockHs.EXPECT().Select(gomock.Eq([]string{"lan-"})).Return([]string{"lan-1", "lan-2"}).AnyTimes()
+
+	r := new(Router)
+	common.Must(r.Init(context.TODO(), &Config{
+		Rule: []*RoutingRule{
+			{
+				TargetTag: &RoutingRule_Tag{Tag: "u"},
+				Networks:  []net.Network{net.Network_UDP},
+			},
+			{
+				TargetTag: &RoutingRule_BalancingTag{BalancingTag: "lb"},
+				Networks:  []net.Network{net.Network_TCP},
+				Failover:  &Failover{OutboundTag: []string{"backup", "lan-1"}, Attempts: 2, Timeout: 1500},
+			},
+		},
+		BalancingRule: []*BalancingRule{{
+			Tag:              "lb",
+			OutboundSelector: []string{"lan-"},
+			Failover:         &Failover{OutboundTag: []string{"spare"}, Attempts: 5},
+		}},
+	}, nil, mockOutboundManager{Manager: mocks.NewOutboundManager(mockCtl), HandlerSelector: mockHs}, nil))
+
+	route := func(dest net.Destination) routing.Route {
+		ctx := session.ContextWithOutbounds(context.Background(), []*session.Outbound{{Target: dest}})
+		rt, err := r.PickRoute(routing_session.AsRoutingContext(ctx))
+		common.Must(err)
+		return rt
+	}
+
+	rt := route(net.TCPDestination(net.ParseAddress("10.0.0.1"), 443))
+	failover := rt.(routing.FailoverRoute).GetFailover()
+	if failover == nil {
+		t.Fatal("expected a failover")
+	}
+	var want []string
+	for _, tag := range []string{"lan-1", "lan-2", "spare", "backup"} {
+		if tag != rt.GetOutboundTag() {
+			want = append(want, tag)
+		}
+	}
+	if !slices.Equal(failover.OutboundTags, want) || failover.Attempts != 2 || failover.Timeout != 1500*time.Millisecond {
+		t.Fatalf("unexpected failover from %s: %+v", rt.GetOutboundTag(), failover)
+	}
+
+	if f := route(net.UDPDestination(net.ParseAddress("10.0.0.1"), 53)).(routing.FailoverRoute).GetFailover(); f != nil {
+		t.Fatalf("unexpected failover: %+v", f)
+	}
+}
+
+func TestTraceRouteLeavesBalancers(t *testing.T) {
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+
+	mockHs := mocks.NewOutboundHandlerSelector(mockCtl)
+	mockHs.EXPECT().Select(gomock.Eq([]string{"lan-"})).Return([]string{"lan-1", "lan-2"}).AnyTimes()
+
+	r := new(Router)
+	common.Must(r.Init(context.TODO(), &Config{
+		Rule: []*RoutingRule{
+			{
+				TargetTag: &RoutingRule_BalancingTag{BalancingTag: "rr"},
+				Networks:  []net.Network{net.Network_TCP},
+			},
+		},
+		BalancingRule: []*BalancingRule{
+			{Tag: "rr", OutboundSelector: []string{"lan-"}, Strategy: "roundRobin"},
+		},
+	}, nil, mockOutboundManager{Manager: mocks.NewOutboundManager(mockCtl), HandlerSelector: mockHs}, nil))
+
+	routingContext := func(dest net.Destination) routing.Context {
+		return routing_session.AsRoutingContext(session.ContextWithOutbounds(context.Background(), []*session.Outbound{{Target: dest}}))
+	}
+	trace := func(ctx routing.Context) string {
+		tr, err := r.TraceRoute(ctx)
+		common.Must(err)
+		return tr.Balancer.Picked
+	}
+	pick := func(ctx routing.Context) string {
+		route, err := r.PickRoute(ctx)
+		common.Must(err)
+		return route.GetOutboundTag()
+	}
+
+	tcp := routingContext(net.TCPDestination(net.ParseAddress("10.0.0.1"), 443))
+	first, second := trace(tcp), trace(tcp)
+	if first != second {
+		t.Fatalf("tracing advanced roundRobin: %s, then %s", first, second)
+	}
+	if tag := pick(tcp); tag != first {
+		t.Fatalf("expected the traced %s to be picked, got %s", first, tag)
+	}
+	if tag := trace(tcp); tag == first {
+		t.Fatalf("expected roundRobin to move on after a real pick, got %s again", tag)
+	}
+
+}
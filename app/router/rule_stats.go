@@ -0,0 +1,61 @@
+package router
+
+import (
+	"github.com/xtls/xray-core/common/errors"
+	"github.com/xtls/xray-core/features/stats"
+)
+
+// ruleCounterNames are the stats counters of a rule with ruleTag tag. The
+// dispatcher counts into them, the router only registers them.
+func ruleCounterNames(tag string) []string {
+	prefix := "rule>>>" + tag + ">>>"
+	return []string{
+		prefix + "hits",
+		prefix + "traffic>>>uplink",
+		prefix + "traffic>>>downlink",
+	}
+}
+
+// setStatsManager starts keeping counters of tagged rules, if enabled.
+func (r *Router) setStatsManager(sm stats.Manager) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.ruleStats {
+		return
+	}
+	r.stats = sm
+	r.syncRuleStats(nil, r.loadRules())
+}
+
+// syncRuleStats registers counters of rules in rules and drops those of
+// rule tags that were in old only. Callers hold mu.
+func (r *Router) syncRuleStats(old, rules []*Rule) {
+	if r.stats == nil {
+		return
+	}
+
+	current := make(map[string]bool, len(rules))
+	for _, rule := range rules {
+		if rule.RuleTag == "" || current[rule.RuleTag] {
+			continue
+		}
+		current[rule.RuleTag] = true
+		for _, name := range ruleCounterNames(rule.RuleTag) {
+			if _, err := stats.GetOrRegisterCounter(r.stats, name); err != nil {
+				// e.g. no "stats" in the config: one warning is enough
+				errors.LogWarningInner(r.ctx, err, "failed to register rule counter ", name)
+				return
+			}
+		}
+	}
+	for _, rule := range old {
+		if rule.RuleTag == "" || current[rule.RuleTag] {
+			continue
+		}
+		current[rule.RuleTag] = true
+		for _, name := range ruleCounterNames(rule.RuleTag) {
+			r.stats.UnregisterCounter(name)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package router
+
+import (
+	"context"
+	"testing"
+
+	"github.com/xtls/xray-core/app/stats"
+	"github.com/xtls/xray-core/common"
+	"github.com/xtls/xray-core/common/net"
+	"github.com/xtls/xray-core/common/serial"
+)
+
+func taggedRule(tag string) *RoutingRule {
+	return &RoutingRule{
+		RuleTag:   tag,
+		TargetTag: &RoutingRule_Tag{Tag: "direct"},
+		Networks:  []net.Network{net.Network_TCP},
+	}
+}
+
+func TestRuleStatsFollowRules(t *testing.T) {
+	sm, err := stats.NewManager(context.Background(), &stats.Config{})
+	common.Must(err)
+
+	r := new(Router)
+	common.Must(r.Init(context.Background(), &Config{
+		Rule:      []*RoutingRule{taggedRule("a"), taggedRule("b"), {TargetTag: &RoutingRule_Tag{Tag: "direct"}, Networks: []net.Network{net.Network_UDP}}},
+		RuleStats: true,
+	}, nil, nil, nil))
+	r.setStatsManager(sm)
+
+	registered := func(tag string) bool {
+		for _, name := range ruleCounterNames(tag) {
+			if sm.GetCounter(name) == nil {
+				return false
+			}
+		}
+		return true
+	}
+	if !registered("a") || !registered("b") {
+		t.Fatal("expected counters of tagged rules")
+	}
+
+	sm.GetCounter("rule>>>a>>>hits").Add(3)
+	common.Must(r.ReplaceRules(serial.ToTypedMessage(&Config{
+		Rule: []*RoutingRule{taggedRule("a"), taggedRule("c")},
+	})))
+	if registered("b") || !registered("c") {
+		t.Fatal("expected counters to follow the replaced rules")
+	}
+	if v := sm.GetCounter("rule>>>a>>>hits").Value(); v != 3 {
+		t.Fatalf("kept rule lost its hits: %d", v)
+	}
+
+	common.Must(r.RemoveRule("c"))
+	if registered("c") {
+		t.Fatal("expected counters of removed rule to be dropped")
+	}
+}
+
+func TestRuleStatsDisabled(t *testing.T) {
+	sm, err := stats.NewManager(context.Background(), &stats.Config{})
+	common.Must(err)
+
+	r := new(Router)
+	common.Must(r.Init(context.Background(), &Config{
+		Rule: []*RoutingRule{taggedRule("a")},
+	}, nil, nil, nil))
+	r.setStatsManager(sm)
+
+	if sm.GetCounter("rule>>>a>>>hits") != nil {
+		t.Fatal("unexpected rule counter without ruleStats")
+	}
+}
@@ -0,0 +1,342 @@
+package router
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/xtls/xray-core/common/errors"
+	"github.com/xtls/xray-core/common/net"
+	"github.com/xtls/xray-core/common/platform"
+	"github.com/xtls/xray-core/common/task"
+	"github.com/xtls/xray-core/features/routing"
+	"google.golang.org/protobuf/proto"
+)
+
+const defaultRuleSetInterval = 10 * time.Second
+
+// ruleSet keeps the matchers of a RuleSet file and rebuilds them when the
+// file changes. Rules hold the ruleSet, not its matchers, so a reload takes
+// effect for new connections without touching the rules.
+type ruleSet struct {
+	tag    string
+	path   string
+	format RuleSet_Format
+	code   string
+	ctx    context.Context
+
+	matchers atomic.Pointer[ruleSetMatchers]
+	checker  *task.Periodic
+
+	// hash of the content loaded last; the file is read on every check, as
+	// a rewrite may keep both its size and its modification time. Guarded
+	// by the checker, which runs one reload at a time.
+	sum [sha256.Size]byte
+}
+
+// ruleSetMatchers are nil for a kind of entry the file has none of.
+type ruleSetMatchers struct {
+	domains *DomainMatcher
+	ips     GeoIPMatcher
+}
+
+func newRuleSet(ctx context.Context, config *RuleSet) (*ruleSet, error) {
+	if config.Tag == "" {
+		return nil, errors.New("empty rule set tag")
+	}
+	if config.Path == "" {
+		return nil, errors.New("empty path of rule set ", config.Tag)
+	}
+	s := &ruleSet{
+		tag:    config.Tag,
+		path:   config.Path,
+		format: config.Format,
+		code:   config.Code,
+		ctx:    ctx,
+	}
+	if !filepath.IsAbs(s.path) {
+		s.path = platform.GetAssetLocation(s.path)
+	}
+	if s.format == RuleSet_AUTO {
+		s.format = RuleSet_TEXT
+		if strings.EqualFold(filepath.Ext(s.path), ".json") {
+			s.format = RuleSet_JSON
+		}
+	}
+	if (s.format == RuleSet_GEOSITE || s.format == RuleSet_GEOIP) && s.code == "" {
+		return nil, errors.New("rule set ", s.tag, " needs the code of a list in ", s.path)
+	}
+	if err := s.reload(); err != nil {
+		return nil, err
+	}
+
+	interval := time.Duration(config.ReloadInterval) * time.Second
+	if interval == 0 {
+		interval = defaultRuleSetInterval
+	}
+	s.checker = &task.Periodic{
+		Interval: interval,
+		Execute: func() error {
+			if err := s.reload(); err != nil {
+				// keep matching with what was loaded last
+				errors.LogWarningInner(s.ctx, err, "failed to reload rule set ", s.tag)
+			}
+			return nil
+		},
+	}
+	return s, nil
+}
+
+func (s *ruleSet) load() *ruleSetMatchers {
+	return s.matchers.Load()
+}
+
+// reload rebuilds the matchers if the content of the file changed since the
+// last load.
+func (s *ruleSet) reload() error {
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		return errors.New("failed to read rule set ", s.tag).Base(err)
+	}
+	sum := sha256.Sum256(data)
+	if s.matchers.Load() != nil && sum == s.sum {
+		return nil
+	}
+
+	var domains []*Domain
+	var cidrs []*CIDR
+	switch s.format {
+	case RuleSet_TEXT:
+		domains, cidrs, err = parseRuleSetText(data)
+	case RuleSet_JSON:
+		domains, cidrs, err = parseRuleSetJSON(data)
+	case RuleSet_GEOSITE:
+		domains, err = parseRuleSetGeoSite(data, s.code)
+	case RuleSet_GEOIP:
+		cidrs, err = parseRuleSetGeoIP(data, s.code)
+	default:
+		err = errors.New("unknown format ", s.format)
+	}
+	if err != nil {
+		return errors.New("invalid rule set ", s.tag, " in ", s.path).Base(err)
+	}
+
+	m := new(ruleSetMatchers)
+	if len(domains) > 0 {
+		if m.domains, err = NewMphMatcherGroup(domains); err != nil {
+			return errors.New("invalid domains in rule set ", s.tag).Base(err)
+		}
+	}
+	if len(cidrs) > 0 {
+		// no country code: rule sets must not share the geoip cache
+		if m.ips, err = BuildOptimizedGeoIPMatcher(&GeoIP{Cidr: cidrs}); err != nil {
+			return errors.New("invalid IPs in rule set ", s.tag).Base(err)
+		}
+	}
+	s.matchers.Store(m)
+	s.sum = sum
+	errors.LogInfo(s.ctx, "rule set ", s.tag, " loaded with ", len(domains), " domains and ", len(cidrs), " IPs")
+	return nil
+}
+
+// parseRuleSetText reads one entry per line. IPs and CIDRs go to the IP
+// list, anything else is a domain with the usual "domain:", "full:",
+// "regexp:" and "keyword:" prefixes; without a prefix it matches the domain
+// and its subdomains.
+func parseRuleSetText(data []byte) ([]*Domain, []*CIDR, error) {
+	var domains []*Domain
+	var cidrs []*CIDR
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if cidr, ok := parseRuleSetCIDR(line); ok {
+			cidrs = append(cidrs, cidr)
+			continue
+		}
+		domain, err := parseRuleSetDomain(line)
+		if err != nil {
+			return nil, nil, err
+		}
+		domains = append(domains, domain)
+	}
+	return domains, cidrs, scanner.Err()
+}
+
+func parseRuleSetJSON(data []byte) ([]*Domain, []*CIDR, error) {
+	var list struct {
+		Domain []string `json:"domain"`
+		IP     []string `json:"ip"`
+	}
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, nil, err
+	}
+	domains := make([]*Domain, 0, len(list.Domain))
+	for _, entry := range list.Domain {
+		domain, err := parseRuleSetDomain(entry)
+		if err != nil {
+			return nil, nil, err
+		}
+		domains = append(domains, domain)
+	}
+	cidrs := make([]*CIDR, 0, len(list.IP))
+	for _, entry := range list.IP {
+		cidr, ok := parseRuleSetCIDR(entry)
+		if !ok {
+			return nil, nil, errors.New("invalid IP: ", entry)
+		}
+		cidrs = append(cidrs, cidr)
+	}
+	return domains, cidrs, nil
+}
+
+func parseRuleSetGeoSite(data []byte, code string) ([]*Domain, error) {
+	var list GeoSiteList
+	if err := proto.Unmarshal(data, &list); err != nil {
+		return nil, err
+	}
+	for _, site := range list.Entry {
+		if strings.EqualFold(site.CountryCode, code) {
+			return site.Domain, nil
+		}
+	}
+	return nil, errors.New("list ", code, " not found")
+}
+
+func parseRuleSetGeoIP(data []byte, code string) ([]*CIDR, error) {
+	var list GeoIPList
+	if err := proto.Unmarshal(data, &list); err != nil {
+		return nil, err
+	}
+	for _, geoip := range list.Entry {
+		if strings.EqualFold(geoip.CountryCode, code) {
+			return geoip.Cidr, nil
+		}
+	}
+	return nil, errors.New("list ", code, " not found")
+}
+
+func parseRuleSetDomain(entry string) (*Domain, error) {
+	entry = strings.TrimSpace(entry)
+	prefix, value, found := strings.Cut(entry, ":")
+	if found {
+		switch prefix {
+		case "domain":
+			return &Domain{Type: Domain_Domain, Value: value}, nil
+		case "full":
+			return &Domain{Type: Domain_Full, Value: value}, nil
+		case "regexp":
+			return &Domain{Type: Domain_Regex, Value: value}, nil
+		case "keyword":
+			return &Domain{Type: Domain_Plain, Value: value}, nil
+		}
+		return nil, errors.New("invalid domain: ", entry)
+	}
+	if entry == "" {
+		return nil, errors.New("empty domain")
+	}
+	return &Domain{Type: Domain_Domain, Value: entry}, nil
+}
+
+func parseRuleSetCIDR(entry string) (*CIDR, bool) {
+	entry = strings.TrimSpace(entry)
+	prefix, err := netip.ParsePrefix(entry)
+	if err != nil {
+		addr, err := netip.ParseAddr(entry)
+		if err != nil {
+			return nil, false
+		}
+		prefix = netip.PrefixFrom(addr, addr.BitLen())
+	}
+	addr := prefix.Addr().Unmap()
+	bits := prefix.Bits()
+	if prefix.Addr().Is4In6() {
+		bits -= 96
+	}
+	if bits < 0 {
+		return nil, false
+	}
+	return &CIDR{Ip: addr.AsSlice(), Prefix: uint32(bits)}, true
+}
+
+func lookupRuleSets(tags []string, sets map[string]*ruleSet) ([]*ruleSet, error) {
+	result := make([]*ruleSet, 0, len(tags))
+	for _, tag := range tags {
+		s, found := sets[tag]
+		if !found {
+			return nil, errors.New("rule set ", tag, " not found; rule sets are only defined at startup")
+		}
+		result = append(result, s)
+	}
+	return result, nil
+}
+
+// RuleSetDomainMatcher matches the target domain against rule sets as they
+// are at the time of the match.
+type RuleSetDomainMatcher struct {
+	sets []*ruleSet
+}
+
+// Apply implements Condition.
+func (m *RuleSetDomainMatcher) Apply(ctx routing.Context) bool {
+	domain := ctx.GetTargetDomain()
+	if len(domain) == 0 {
+		return false
+	}
+	for _, s := range m.sets {
+		if d := s.load().domains; d != nil && d.ApplyDomain(domain) {
+			return true
+		}
+	}
+	return false
+}
+
+// RuleSetIPMatcher matches IPs against rule sets as they are at the time of
+// the match.
+type RuleSetIPMatcher struct {
+	sets   []*ruleSet
+	asType MatcherAsType
+}
+
+// Apply implements Condition.
+func (m *RuleSetIPMatcher) Apply(ctx routing.Context) bool {
+	var ips []net.IP
+	switch m.asType {
+	case MatcherAsType_Source:
+		ips = ctx.GetSourceIPs()
+	case MatcherAsType_Target:
+		ips = ctx.GetTargetIPs()
+	default:
+		panic("unk asType")
+	}
+	for _, s := range m.sets {
+		if matcher := s.load().ips; matcher != nil && matcher.AnyMatch(ips) {
+			return true
+		}
+	}
+	return false
+}
+
+// anyCondition matches if one of its conditions does.
+type anyCondition []Condition
+
+// Apply implements Condition.
+func (v anyCondition) Apply(ctx routing.Context) bool {
+	for _, cond := range v {
+		if cond.Apply(ctx) {
+			return true
+		}
+	}
+	return false
+}
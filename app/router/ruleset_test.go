@@ -0,0 +1,164 @@
+package router
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/xtls/xray-core/common"
+	"github.com/xtls/xray-core/common/net"
+	"github.com/xtls/xray-core/common/session"
+	routing_session "github.com/xtls/xray-core/features/routing/session"
+	"google.golang.org/protobuf/proto"
+)
+
+func writeRuleSet(t *testing.T, path, content string) {
+	t.Helper()
+	common.Must(os.WriteFile(path, []byte(content), 0o644))
+	// every write keeps the modification time: reloads go by the content
+	mtime := time.Unix(1700000000, 0)
+	common.Must(os.Chtimes(path, mtime, mtime))
+}
+
+func targetContext(dest net.Destination) *routing_session.Context {
+	return &routing_session.Context{Outbound: &session.Outbound{Target: dest}}
+}
+
+func TestParseRuleSetText(t *testing.T) {
+	domains, cidrs, err := parseRuleSetText([]byte(`
+# ads
+ads.example.com
+full:tracker.example.org  # exact
+keyword:doubleclick
+10.0.0.0/8
+2001:db8::1
+`))
+	common.Must(err)
+	if len(domains) != 3 || len(cidrs) != 2 {
+		t.Fatalf("got %d domains and %d IPs", len(domains), len(cidrs))
+	}
+	if domains[0].Type != Domain_Domain || domains[1].Type != Domain_Full || domains[2].Type != Domain_Plain {
+		t.Fatalf("unexpected domain types: %v", domains)
+	}
+	if cidrs[0].Prefix != 8 || len(cidrs[0].Ip) != 4 || cidrs[1].Prefix != 128 || len(cidrs[1].Ip) != 16 {
+		t.Fatalf("unexpected IPs: %v", cidrs)
+	}
+
+	if _, _, err := parseRuleSetText([]byte("geosite:cn")); err == nil {
+		t.Fatal("expected unknown prefix to be rejected")
+	}
+}
+
+func TestRuleSetReload(t *testing.T) {
+	dir := t.TempDir()
+	adsPath := filepath.Join(dir, "ads.txt")
+	netsPath := filepath.Join(dir, "nets.json")
+	writeRuleSet(t, adsPath, "ads.example.com\n")
+	writeRuleSet(t, netsPath, `{"ip": ["10.0.0.0/8"]}`)
+
+	r := new(Router)
+	common.Must(r.Init(context.Background(), &Config{
+		RuleSet: []*RuleSet{
+			{Tag: "ads", Path: adsPath},
+			{Tag: "nets", Path: netsPath},
+		},
+		Rule: []*RoutingRule{
+			{
+				TargetTag:     &RoutingRule_Tag{Tag: "block"},
+				Domain:        []*Domain{{Type: Domain_Full, Value: "inline.example.net"}},
+				DomainRuleSet: []string{"ads"},
+			},
+			{
+				TargetTag: &RoutingRule_Tag{Tag: "lan"},
+				IpRuleSet: []string{"nets"},
+			},
+		},
+	}, nil, nil, nil))
+
+	route := func(dest net.Destination) string {
+		rt, err := r.PickRoute(targetContext(dest))
+		if err != nil {
+			return ""
+		}
+		return rt.GetOutboundTag()
+	}
+
+	for dest, want := range map[net.Destination]string{
+		net.TCPDestination(net.DomainAddress("x.ads.example.com"), 443):  "block",
+		net.TCPDestination(net.DomainAddress("inline.example.net"), 443): "block",
+		net.TCPDestination(net.DomainAddress("ads.example.org"), 443):    "",
+		net.TCPDestination(net.ParseAddress("10.1.2.3"), 443):            "lan",
+		net.TCPDestination(net.ParseAddress("192.168.1.1"), 443):         "",
+	} {
+		if got := route(dest); got != want {
+			t.Errorf("%v: got %q, want %q", dest, got, want)
+		}
+	}
+
+	// same size and modification time as before
+	writeRuleSet(t, adsPath, "ads.example.org\n")
+	writeRuleSet(t, netsPath, `{"ip": ["192.168.0.0/16"]}`)
+	for _, rs := range r.ruleSets {
+		common.Must(rs.reload())
+	}
+	for dest, want := range map[net.Destination]string{
+		net.TCPDestination(net.DomainAddress("x.ads.example.com"), 443):  "",
+		net.TCPDestination(net.DomainAddress("inline.example.net"), 443): "block",
+		net.TCPDestination(net.DomainAddress("ads.example.org"), 443):    "block",
+		net.TCPDestination(net.ParseAddress("10.1.2.3"), 443):            "",
+		net.TCPDestination(net.ParseAddress("192.168.1.1"), 443):         "lan",
+	} {
+		if got := route(dest); got != want {
+			t.Errorf("after reload %v: got %q, want %q", dest, got, want)
+		}
+	}
+
+	// a broken file keeps the last good list
+	writeRuleSet(t, netsPath, `{"ip": ["not an ip"]}`)
+	if err := r.ruleSets["nets"].reload(); err == nil {
+		t.Fatal("expected broken rule set to fail")
+	}
+	if got := route(net.TCPDestination(net.ParseAddress("192.168.1.1"), 443)); got != "lan" {
+		t.Fatalf("lost rule set after failed reload, got %q", got)
+	}
+
+	// rules added at runtime only see the rule sets of the startup config
+	common.Must(r.ReloadRules(&Config{Rule: []*RoutingRule{{TargetTag: &RoutingRule_Tag{Tag: "lan"}, IpRuleSet: []string{"nets"}}}}, true))
+	if err := r.ReloadRules(&Config{Rule: []*RoutingRule{{TargetTag: &RoutingRule_Tag{Tag: "x"}, IpRuleSet: []string{"other"}}}}, true); err == nil || !strings.Contains(err.Error(), "only defined at startup") {
+		t.Fatalf("expected error for unknown rule set, got %v", err)
+	}
+	if err := r.ReloadRules(&Config{RuleSet: []*RuleSet{{Tag: "other", Path: netsPath}}}, true); err == nil {
+		t.Fatal("expected error for a rule set added at runtime")
+	}
+}
+
+func TestRuleSetGeoData(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "custom.dat")
+	data, err := proto.Marshal(&GeoSiteList{Entry: []*GeoSite{
+		{CountryCode: "ADS", Domain: []*Domain{{Type: Domain_Domain, Value: "ads.example.com"}}},
+	}})
+	common.Must(err)
+	common.Must(os.WriteFile(path, data, 0o644))
+
+	rs, err := newRuleSet(context.Background(), &RuleSet{Tag: "ads", Path: path, Format: RuleSet_GEOSITE, Code: "ads"})
+	common.Must(err)
+	if !rs.load().domains.ApplyDomain("www.ads.example.com") {
+		t.Fatal("expected geosite list to match")
+	}
+
+	if _, err := newRuleSet(context.Background(), &RuleSet{Tag: "x", Path: path, Format: RuleSet_GEOSITE, Code: "missing"}); err == nil {
+		t.Fatal("expected missing list to fail")
+	}
+
+	r := new(Router)
+	err = r.Init(context.Background(), &Config{
+		Rule: []*RoutingRule{{TargetTag: &RoutingRule_Tag{Tag: "t"}, DomainRuleSet: []string{"unknown"}}},
+	}, nil, nil, nil)
+	if err == nil {
+		t.Fatal("expected unknown rule set to fail")
+	}
+}
@@ -0,0 +1,285 @@
+package router
+
+import (
+	"context"
+	"hash/fnv"
+	"math"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/xtls/xray-core/app/observatory"
+	"github.com/xtls/xray-core/common"
+	"github.com/xtls/xray-core/core"
+	"github.com/xtls/xray-core/features/extension"
+	"github.com/xtls/xray-core/features/routing"
+)
+
+const (
+	defaultHashLoadFactor = 1.25
+	defaultHashSticky     = 10 * time.Minute
+	// past this many keys new ones are hashed but not remembered
+	maxHashSessions = 1 << 16
+)
+
+// ConsistentHashStrategy keeps connections with the same key (source IP,
+// user, target domain) on the same outbound.
+//
+// Outbounds are ranked per key by rendezvous hashing, so a change in the
+// candidate set only moves the keys of the outbound that changed. A key
+// sticks to its outbound until it is idle for the sticky period or the
+// outbound stops being a healthy candidate; an outbound takes no new keys
+// once it holds load_factor times the average.
+type ConsistentHashStrategy struct {
+	keys        []StrategyConsistentHashConfig_Key
+	loadFactor  float64
+	sticky      time.Duration
+	ctx         context.Context
+	observatory extension.Observatory
+	now         func() time.Time
+
+	mu        sync.Mutex
+	sessions  map[string]*hashSession
+	load      map[string]int
+	lastSweep time.Time
+}
+
+type hashSession struct {
+	tag  string
+	seen time.Time
+}
+
+func NewConsistentHashStrategy(settings *StrategyConsistentHashConfig) *ConsistentHashStrategy {
+	s := &ConsistentHashStrategy{
+		keys:       settings.GetKeys(),
+		loadFactor: float64(settings.GetLoadFactor()),
+		sticky:     time.Duration(settings.GetStickySeconds()) * time.Second,
+		now:        time.Now,
+		sessions:   make(map[string]*hashSession),
+		load:       make(map[string]int),
+	}
+	if len(s.keys) == 0 {
+		s.keys = []StrategyConsistentHashConfig_Key{StrategyConsistentHashConfig_SOURCE_IP}
+	}
+	if s.loadFactor < 1 {
+		s.loadFactor = defaultHashLoadFactor
+	}
+	if s.sticky == 0 {
+		s.sticky = defaultHashSticky
+	}
+	return s
+}
+
+func (s *ConsistentHashStrategy) InjectContext(ctx context.Context) {
+	s.ctx = ctx
+	common.Must(core.OptionalFeatures(s.ctx, func(observatory extension.Observatory) error {
+		s.observatory = observatory
+		return nil
+	}))
+}
+
+func (s *ConsistentHashStrategy) GetPrincipleTarget(strings []string) []string {
+	return strings
+}
+
+// PickOutbound implements BalancingStrategy; without a routing context all
+// connections share the empty key.
+func (s *ConsistentHashStrategy) PickOutbound(candidates []string) string {
+	return s.pick("", s.alive(candidates))
+}
+
+// PickOutboundFor implements ContextualBalancingStrategy.
+func (s *ConsistentHashStrategy) PickOutboundFor(ctx routing.Context, candidates []string) string {
+	return s.pick(s.key(ctx), s.alive(candidates))
+}
+
+// PeekOutbound implements PeekingBalancingStrategy.
+func (s *ConsistentHashStrategy) PeekOutbound(ctx routing.Context, candidates []string) string {
+	key := ""
+	if ctx != nil {
+		key = s.key(ctx)
+	}
+	candidates = s.alive(candidates)
+	if len(candidates) == 0 {
+		return ""
+	}
+	now := s.now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	session, ok := s.sessions[key]
+	if ok && now.Sub(session.seen) < s.sticky && slices.Contains(candidates, session.tag) {
+		return session.tag
+	}
+	if !ok {
+		session = nil
+	}
+	return s.rankLocked(key, candidates, session)
+}
+
+func (s *ConsistentHashStrategy) key(ctx routing.Context) string {
+	var sb strings.Builder
+	for i, k := range s.keys {
+		if i > 0 {
+			sb.WriteByte('|')
+		}
+		switch k {
+		case StrategyConsistentHashConfig_SOURCE_IP:
+			if ips := ctx.GetSourceIPs(); len(ips) > 0 {
+				sb.WriteString(ips[0].String())
+			}
+		case StrategyConsistentHashConfig_USER:
+			sb.WriteString(ctx.GetUser())
+		case StrategyConsistentHashConfig_TARGET_DOMAIN:
+			if domain := ctx.GetTargetDomain(); domain != "" {
+				sb.WriteString(domain)
+			} else if ips := ctx.GetTargetIPs(); len(ips) > 0 {
+				sb.WriteString(ips[0].String())
+			}
+		}
+	}
+	return sb.String()
+}
+
+// alive drops candidates the observatory reports as dead; unknown ones are
+// considered alive.
+func (s *ConsistentHashStrategy) alive(candidates []string) []string {
+	if s.observatory == nil {
+		return candidates
+	}
+	report, err := s.observatory.GetObservation(s.ctx)
+	if err != nil {
+		return candidates
+	}
+	result, ok := report.(*observatory.ObservationResult)
+	if !ok {
+		return candidates
+	}
+	dead := make(map[string]bool)
+	for _, status := range result.Status {
+		if !status.Alive {
+			dead[status.OutboundTag] = true
+		}
+	}
+	alive := make([]string, 0, len(candidates))
+	for _, candidate := range candidates {
+		if !dead[candidate] {
+			alive = append(alive, candidate)
+		}
+	}
+	return alive
+}
+
+func (s *ConsistentHashStrategy) pick(key string, candidates []string) string {
+	if len(candidates) == 0 {
+		// goes to fallbackTag
+		return ""
+	}
+	now := s.now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sweep(now)
+	if session, ok := s.sessions[key]; ok {
+		if slices.Contains(candidates, session.tag) {
+			session.seen = now
+			return session.tag
+		}
+		s.forget(key, session)
+	}
+
+	tag := s.rankLocked(key, candidates, nil)
+	if len(s.sessions) < maxHashSessions {
+		s.sessions[key] = &hashSession{tag: tag, seen: now}
+		s.load[tag]++
+	}
+	return tag
+}
+
+// rankLocked returns the best ranked candidate for key with room for one
+// more key. The load of stale, a session about to be forgotten, is left out.
+func (s *ConsistentHashStrategy) rankLocked(key string, candidates []string, stale *hashSession) string {
+	load := func(tag string) int {
+		if stale != nil && stale.tag == tag {
+			return s.load[tag] - 1
+		}
+		return s.load[tag]
+	}
+	total := 0
+	for _, tag := range candidates {
+		total += load(tag)
+	}
+	capacity := int(math.Ceil(s.loadFactor * float64(total+1) / float64(len(candidates))))
+
+	ranked := rankByHash(key, candidates)
+	for _, t := range ranked {
+		if load(t) < capacity {
+			return t
+		}
+	}
+	return ranked[0]
+}
+
+// sweep forgets idle keys, at most a few times per sticky period.
+func (s *ConsistentHashStrategy) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < s.sticky/4 {
+		return
+	}
+	s.lastSweep = now
+	for key, session := range s.sessions {
+		if now.Sub(session.seen) >= s.sticky {
+			s.forget(key, session)
+		}
+	}
+}
+
+func (s *ConsistentHashStrategy) forget(key string, session *hashSession) {
+	delete(s.sessions, key)
+	if s.load[session.tag]--; s.load[session.tag] <= 0 {
+		delete(s.load, session.tag)
+	}
+}
+
+// rankByHash orders candidates by their rendezvous score for key, best first.
+func rankByHash(key string, candidates []string) []string {
+	type scored struct {
+		tag   string
+		score uint64
+	}
+	list := make([]scored, len(candidates))
+	for i, tag := range candidates {
+		h := fnv.New64a()
+		h.Write([]byte(key))
+		h.Write([]byte{0})
+		h.Write([]byte(tag))
+		list[i] = scored{tag, mix64(h.Sum64())}
+	}
+	slices.SortFunc(list, func(a, b scored) int {
+		switch {
+		case a.score > b.score:
+			return -1
+		case a.score < b.score:
+			return 1
+		}
+		return strings.Compare(a.tag, b.tag)
+	})
+	ranked := make([]string, len(list))
+	for i, s := range list {
+		ranked[i] = s.tag
+	}
+	return ranked
+}
+
+// mix64 is the splitmix64 finalizer; FNV alone spreads similar short keys
+// poorly.
+func mix64(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
@@ -0,0 +1,181 @@
+package router
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/xtls/xray-core/features/outbound"
+	"github.com/xtls/xray-core/features/routing"
+)
+
+type staticSelector struct {
+	outbound.Manager
+	tags []string
+}
+
+func (s staticSelector) Select([]string) []string { return s.tags }
+
+type userContext struct {
+	routing.Context
+	user string
+}
+
+func (c userContext) GetUser() string { return c.user }
+
+func TestBalancerPassesContextToStrategy(t *testing.T) {
+	b := &Balancer{
+		selectors: []string{"out-"},
+		strategy: NewConsistentHashStrategy(&StrategyConsistentHashConfig{
+			Keys: []StrategyConsistentHashConfig_Key{StrategyConsistentHashConfig_USER},
+		}),
+		ohm: staticSelector{tags: []string{"out-1", "out-2", "out-3"}},
+	}
+
+	used := make(map[string]bool)
+	for i := 0; i < 30; i++ {
+		ctx := userContext{user: fmt.Sprint("user", i)}
+		tag, err := b.PickOutboundFor(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if again, _ := b.PickOutboundFor(ctx); again != tag {
+			t.Fatalf("user%d moved from %s to %s", i, tag, again)
+		}
+		used[tag] = true
+	}
+	if len(used) < 2 {
+		t.Fatalf("expected users spread over outbounds, got %v", used)
+	}
+}
+
+func TestBalancerPeekLeavesConsistentHash(t *testing.T) {
+	s := NewConsistentHashStrategy(&StrategyConsistentHashConfig{
+		Keys: []StrategyConsistentHashConfig_Key{StrategyConsistentHashConfig_USER},
+	})
+	b := &Balancer{
+		selectors: []string{"out-"},
+		strategy:  s,
+		ohm:       staticSelector{tags: []string{"out-1", "out-2", "out-3"}},
+	}
+
+	ctx := userContext{user: "user"}
+	peeked, err := b.PeekOutboundFor(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again, _ := b.PeekOutboundFor(ctx); again != peeked {
+		t.Fatalf("peek moved from %s to %s", peeked, again)
+	}
+	if n := len(s.sessions); n != 0 {
+		t.Fatalf("peeking recorded %d sessions", n)
+	}
+	if tag, _ := b.PickOutboundFor(ctx); tag != peeked {
+		t.Fatalf("expected the peeked %s to be picked, got %s", peeked, tag)
+	}
+	if n := len(s.sessions); n != 1 {
+		t.Fatalf("expected one session after a pick, got %d", n)
+	}
+}
+
+func TestConsistentHashSticky(t *testing.T) {
+	s := NewConsistentHashStrategy(nil)
+	candidates := []string{"a", "b", "c"}
+
+	first := s.pick("10.0.0.1", candidates)
+	for i := 0; i < 10; i++ {
+		if tag := s.pick("10.0.0.1", candidates); tag != first {
+			t.Fatalf("key moved from %s to %s", first, tag)
+		}
+	}
+	if got := s.pick("", nil); got != "" {
+		t.Fatalf("expected empty tag without candidates, got %s", got)
+	}
+}
+
+func TestConsistentHashMinimalRemap(t *testing.T) {
+	candidates := []string{"a", "b", "c", "d"}
+	// without the load bound only the hash decides
+	before := NewConsistentHashStrategy(&StrategyConsistentHashConfig{LoadFactor: 1000})
+	after := NewConsistentHashStrategy(&StrategyConsistentHashConfig{LoadFactor: 1000})
+
+	moved := 0
+	for i := 0; i < 1000; i++ {
+		key := fmt.Sprint("user-", i)
+		was := before.pick(key, candidates)
+		now := after.pick(key, candidates[:3])
+		if was != "d" && now != was {
+			t.Fatalf("key %s moved from %s to %s although %s stayed", key, was, now, was)
+		}
+		if was == "d" {
+			moved++
+		}
+	}
+	if moved == 0 || moved > 400 {
+		t.Fatalf("expected about a quarter of keys on d, got %d", moved)
+	}
+}
+
+func TestConsistentHashDeadOutboundMovesOnlyItsKeys(t *testing.T) {
+	s := NewConsistentHashStrategy(nil)
+	candidates := []string{"a", "b", "c"}
+
+	assigned := make(map[string]string)
+	for i := 0; i < 300; i++ {
+		key := fmt.Sprint("10.0.", i/256, ".", i%256)
+		assigned[key] = s.pick(key, candidates)
+	}
+	// "b" fails a health check
+	for key, was := range assigned {
+		now := s.pick(key, []string{"a", "c"})
+		if was != "b" && now != was {
+			t.Fatalf("key %s moved from healthy %s to %s", key, was, now)
+		}
+		if now == "b" {
+			t.Fatalf("key %s stayed on dead outbound", key)
+		}
+	}
+}
+
+func TestConsistentHashBoundedLoad(t *testing.T) {
+	s := NewConsistentHashStrategy(&StrategyConsistentHashConfig{LoadFactor: 1.25})
+	candidates := []string{"a", "b", "c", "d"}
+
+	counts := make(map[string]int)
+	const keys = 400
+	for i := 0; i < keys; i++ {
+		counts[s.pick(fmt.Sprint("k", i), candidates)]++
+	}
+	limit := int(1.25*keys/4) + 1
+	for tag, n := range counts {
+		if n > limit {
+			t.Fatalf("%s holds %d keys, bound is %d: %v", tag, n, limit, counts)
+		}
+	}
+}
+
+func TestConsistentHashStickyExpires(t *testing.T) {
+	s := NewConsistentHashStrategy(&StrategyConsistentHashConfig{StickySeconds: 60})
+	now := time.Now()
+	s.now = func() time.Time { return now }
+
+	s.pick("k", []string{"a", "b"})
+	if len(s.sessions) != 1 {
+		t.Fatalf("expected one session, got %d", len(s.sessions))
+	}
+
+	now = now.Add(30 * time.Second)
+	s.pick("other", []string{"a", "b"})
+	if _, ok := s.sessions["k"]; !ok {
+		t.Fatal("session expired too early")
+	}
+
+	now = now.Add(61 * time.Second)
+	s.pick("other", []string{"a", "b"})
+	if _, ok := s.sessions["k"]; ok {
+		t.Fatal("expected idle session to expire")
+	}
+	if total := s.load["a"] + s.load["b"]; total != 1 {
+		t.Fatalf("expected load of one session, got %d", total)
+	}
+}
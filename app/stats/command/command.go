@@ -2,7 +2,10 @@ package command
 
 import (
 	"context"
+	"math"
 	"runtime"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/xtls/xray-core/app/stats"
@@ -134,6 +137,76 @@ func (s *statsServer) GetSysStats(ctx context.Context, request *SysStatsRequest)
 	return response, nil
 }
 
+// GetDNSStats gathers the dns>>>NAME>>>... counters of nameservers.
+func (s *statsServer) GetDNSStats(ctx context.Context, request *GetDNSStatsRequest) (*GetDNSStatsResponse, error) {
+	manager, ok := s.stats.(*stats.Manager)
+	if !ok {
+		return nil, errors.New("GetDNSStats only works its own stats.Manager.")
+	}
+
+	servers := make(map[string]*DNSServerStats)
+	manager.VisitCounters(func(name string, c feature_stats.Counter) bool {
+		parts := strings.Split(name, ">>>")
+		if len(parts) < 3 || parts[0] != "dns" || request.Name != "" && parts[1] != request.Name {
+			return true
+		}
+		var value int64
+		if request.Reset_ {
+			value = c.Set(0)
+		} else {
+			value = c.Value()
+		}
+
+		server := servers[parts[1]]
+		if server == nil {
+			server = &DNSServerStats{Name: parts[1]}
+			servers[parts[1]] = server
+		}
+		switch strings.Join(parts[2:], ">>>") {
+		case "queries":
+			server.Queries = value
+		case "errors":
+			server.Errors = value
+		case "cache_hits":
+			server.CacheHits = value
+		case "latency>>>sum_ms":
+			server.LatencySumMs = value
+		default:
+			if len(parts) == 4 && parts[2] == "latency" && strings.HasPrefix(parts[3], "le_") {
+				server.Latency = append(server.Latency, &DNSLatencyBucket{
+					Le:    strings.TrimPrefix(parts[3], "le_"),
+					Count: value,
+				})
+			}
+		}
+		return true
+	})
+
+	if request.Name != "" && len(servers) == 0 {
+		return nil, status.Error(codes.NotFound, request.Name+" not found.")
+	}
+
+	response := &GetDNSStatsResponse{}
+	for _, server := range servers {
+		sort.Slice(server.Latency, func(i, j int) bool {
+			return bucketBound(server.Latency[i].Le) < bucketBound(server.Latency[j].Le)
+		})
+		response.Server = append(response.Server, server)
+	}
+	sort.Slice(response.Server, func(i, j int) bool { return response.Server[i].Name < response.Server[j].Name })
+
+	return response, nil
+}
+
+// bucketBound orders latency buckets, "inf" last.
+func bucketBound(le string) time.Duration {
+	d, err := time.ParseDuration(le)
+	if err != nil {
+		return math.MaxInt64
+	}
+	return d
+}
+
 func (s *statsServer) mustEmbedUnimplementedStatsServiceServer() {}
 
 type service struct {
@@ -89,3 +89,52 @@ func TestQueryStats(t *testing.T) {
 		t.Error(r)
 	}
 }
+
+func TestGetDNSStats(t *testing.T) {
+	m, err := stats.NewManager(context.Background(), &stats.Config{})
+	common.Must(err)
+	for name, value := range map[string]int64{
+		"dns>>>a>>>queries":            3,
+		"dns>>>a>>>errors":             1,
+		"dns>>>a>>>cache_hits":         1,
+		"dns>>>a>>>latency>>>le_inf":   1,
+		"dns>>>a>>>latency>>>le_100ms": 1,
+		"dns>>>a>>>latency>>>le_25ms":  0,
+		"dns>>>a>>>latency>>>sum_ms":   3050,
+		"dns>>>b>>>queries":            2,
+		"rule>>>a>>>hits":              5,
+	} {
+		c, err := m.RegisterCounter(name)
+		common.Must(err)
+		c.Set(value)
+	}
+
+	s := NewStatsServer(m)
+	resp, err := s.GetDNSStats(context.Background(), &GetDNSStatsRequest{Name: "a", Reset_: true})
+	common.Must(err)
+	expected := &GetDNSStatsResponse{Server: []*DNSServerStats{{
+		Name:      "a",
+		Queries:   3,
+		Errors:    1,
+		CacheHits: 1,
+		Latency: []*DNSLatencyBucket{
+			{Le: "25ms"},
+			{Le: "100ms", Count: 1},
+			{Le: "inf", Count: 1},
+		},
+		LatencySumMs: 3050,
+	}}}
+	if r := cmp.Diff(resp, expected, cmpopts.IgnoreUnexported(GetDNSStatsResponse{}, DNSServerStats{}, DNSLatencyBucket{})); r != "" {
+		t.Error(r)
+	}
+
+	resp, err = s.GetDNSStats(context.Background(), &GetDNSStatsRequest{})
+	common.Must(err)
+	if len(resp.Server) != 2 || resp.Server[0].Queries != 0 || resp.Server[1].Queries != 2 {
+		t.Errorf("unexpected stats after reset: %v", resp.Server)
+	}
+
+	if _, err := s.GetDNSStats(context.Background(), &GetDNSStatsRequest{Name: "c"}); err == nil {
+		t.Error("expected an unknown nameserver to fail")
+	}
+}
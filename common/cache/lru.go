@@ -11,6 +11,7 @@ type Lru interface {
 	GetKeyFromValue(value interface{}) (key interface{}, ok bool)
 	PeekKeyFromValue(value interface{}) (key interface{}, ok bool) // Peek means check but NOT bring to top
 	Put(key, value interface{})
+	Range(f func(key, value interface{}) bool) // from the least recently used, until f returns false
 }
 
 type lru struct {
@@ -87,3 +88,14 @@ func (l *lru) Put(key, value interface{}) {
 	}
 	l.mu.Unlock()
 }
+
+func (l *lru) Range(f func(key, value interface{}) bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for element := l.doubleLinkedlist.Back(); element != nil; element = element.Prev() {
+		e := element.Value.(*lruElement)
+		if !f(e.key, e.value) {
+			return
+		}
+	}
+}
@@ -26,6 +26,7 @@ const (
 	fullHandlerKey            ctx.SessionKey = 10 // outbound gets full handler
 	mitmAlpn11Key             ctx.SessionKey = 11 // used by TLS dialer
 	mitmServerNameKey         ctx.SessionKey = 12 // used by TLS dialer
+	trackedDialKey            ctx.SessionKey = 13 // used by dispatcher failover to see the outbound connect
 )
 
 func ContextWithInbound(ctx context.Context, inbound *Inbound) context.Context {
@@ -127,6 +128,22 @@ func TrackedConnectionError(ctx context.Context, tracker TrackedRequestErrorFeed
 	return context.WithValue(ctx, trackedConnectionErrorKey, tracker)
 }
 
+type TrackedDialFeedback interface {
+	SubmitDial(err error)
+}
+
+// SubmitOutboundDialToOriginator tells the originator of ctx whether the
+// outbound connected.
+func SubmitOutboundDialToOriginator(ctx context.Context, err error) {
+	if tracker, ok := ctx.Value(trackedDialKey).(TrackedDialFeedback); ok {
+		tracker.SubmitDial(err)
+	}
+}
+
+func TrackedOutboundDial(ctx context.Context, tracker TrackedDialFeedback) context.Context {
+	return context.WithValue(ctx, trackedDialKey, tracker)
+}
+
 func ContextWithDispatcher(ctx context.Context, dispatcher routing.Dispatcher) context.Context {
 	return context.WithValue(ctx, dispatcherKey, dispatcher)
 }
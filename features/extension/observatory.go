@@ -2,6 +2,7 @@ package extension
 
 import (
 	"context"
+	"time"
 
 	"github.com/xtls/xray-core/features"
 	"google.golang.org/protobuf/proto"
@@ -13,6 +14,16 @@ type Observatory interface {
 	GetObservation(ctx context.Context) (proto.Message, error)
 }
 
+// HealthReporter is an Observatory that also learns from real connections.
+type HealthReporter interface {
+	// ReportFailure records that a connection through the outbound tag
+	// failed before any data came back.
+	ReportFailure(tag string, err error)
+	// ReportSuccess records that a connection through the outbound tag got
+	// its first data back after delay.
+	ReportSuccess(tag string, delay time.Duration)
+}
+
 func ObservatoryType() interface{} {
 	return (*Observatory)(nil)
 }
@@ -24,6 +24,14 @@ type HandlerSelector interface {
 	Select([]string) []string
 }
 
+// HandlerChangeNotifier is implemented by Managers that report tagged
+// handlers being added or removed at runtime.
+type HandlerChangeNotifier interface {
+	// OnHandlerChange registers f to be called after a tagged handler is
+	// added (added is true) or removed.
+	OnHandlerChange(f func(tag string, added bool))
+}
+
 // Manager is a feature that manages outbound.Handlers.
 //
 // xray:api:stable
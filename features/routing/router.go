@@ -1,7 +1,10 @@
 package routing
 
 import (
+	"time"
+
 	"github.com/xtls/xray-core/common"
+	"github.com/xtls/xray-core/common/net"
 	"github.com/xtls/xray-core/common/serial"
 	"github.com/xtls/xray-core/features"
 )
@@ -18,6 +21,103 @@ type Router interface {
 	RemoveRule(tag string) error
 }
 
+// RuleManager is a Router whose rules can be inspected and rearranged at
+// runtime. Configs are TypedMessages of the router's own config type.
+type RuleManager interface {
+	// ListRules returns the rules in matching order.
+	ListRules() []RuleInfo
+	// InsertRule inserts the rules of config before the rule at index;
+	// index equal to the number of rules appends.
+	InsertRule(config *serial.TypedMessage, index int) error
+	// ReplaceRules swaps the whole rule list for the rules of config.
+	ReplaceRules(config *serial.TypedMessage) error
+}
+
+// BalancerManager is a Router whose balancers can be changed at runtime.
+// Configs are TypedMessages of the router's balancer config type.
+type BalancerManager interface {
+	AddBalancer(config *serial.TypedMessage) error
+	// UpdateBalancer replaces the balancer with the tag of config.
+	UpdateBalancer(config *serial.TypedMessage) error
+	// RemoveBalancer fails while a rule still uses the balancer.
+	RemoveBalancer(tag string) error
+}
+
+// RouteTracer is a Router that can explain its decision for a context
+// without dispatching anything.
+type RouteTracer interface {
+	TraceRoute(ctx Context) (*RouteTrace, error)
+}
+
+// RouteTrace is the evaluation path of a routing decision.
+type RouteTrace struct {
+	DomainStrategy string
+	// Rules are the rules evaluated, in order; the last one matched if Route
+	// is set.
+	Rules []RuleTrace
+	// Resolved is set if the target domain was looked up for IP conditions.
+	Resolved    bool
+	ResolvedIPs []net.IP
+	ResolveErr  error
+	// Balancer is set if the matching rule points to a balancer.
+	Balancer *BalancerTrace
+	// Route is nil if no rule matched.
+	Route Route
+}
+
+// RuleTrace is the evaluation of one rule.
+type RuleTrace struct {
+	RuleInfo
+	// Conditions are evaluated in order up to the first one not matching.
+	Conditions []ConditionTrace
+	Matched    bool
+	// WithResolvedIPs is set for the second pass after resolving the domain.
+	WithResolvedIPs bool
+}
+
+// ConditionTrace is the result of one condition of a rule.
+type ConditionTrace struct {
+	Name    string
+	Matched bool
+}
+
+// BalancerTrace describes how a balancer chose the outbound.
+type BalancerTrace struct {
+	Tag         string
+	Candidates  []string
+	Override    string
+	FallbackTag string
+	Picked      string
+	Err         error
+}
+
+// FailoverRoute is a Route with outbounds to try when its outbound fails
+// before the client got any data back.
+type FailoverRoute interface {
+	// GetFailover returns nil if there is nothing to fail over to.
+	GetFailover() *Failover
+}
+
+// Failover is the failover plan of a route.
+type Failover struct {
+	// OutboundTags are tried in order after the outbound of the route.
+	OutboundTags []string
+	// Attempts in total, the first one included; 0 means one per outbound.
+	Attempts int
+	// Timeout is the time an attempt may take to connect; 0 means no limit.
+	Timeout time.Duration
+}
+
+// RuleInfo describes a routing rule.
+type RuleInfo struct {
+	Index       int
+	RuleTag     string
+	OutboundTag string
+	BalancerTag string
+	// Config is the rule as configured, nil if unknown.
+	Config *serial.TypedMessage
+}
+
 // Route is the routing result of Router feature.
 //
 // xray:api:stable
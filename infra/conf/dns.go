@@ -31,6 +31,7 @@ type NameServerConfig struct {
 	ServeExpiredTTL *uint32    `json:"serveExpiredTTL"`
 	FinalQuery      bool       `json:"finalQuery"`
 	UnexpectedIPs   StringList `json:"unexpectedIPs"`
+	SkipDNSSEC      bool       `json:"skipDnssec"`
 }
 
 // UnmarshalJSON implements encoding/json.Unmarshaler.UnmarshalJSON
@@ -57,6 +58,7 @@ func (c *NameServerConfig) UnmarshalJSON(data []byte) error {
 		ServeExpiredTTL *uint32    `json:"serveExpiredTTL"`
 		FinalQuery      bool       `json:"finalQuery"`
 		UnexpectedIPs   StringList `json:"unexpectedIPs"`
+		SkipDNSSEC      bool       `json:"skipDnssec"`
 	}
 	if err := json.Unmarshal(data, &advanced); err == nil {
 		c.Address = advanced.Address
@@ -74,6 +76,7 @@ func (c *NameServerConfig) UnmarshalJSON(data []byte) error {
 		c.ServeExpiredTTL = advanced.ServeExpiredTTL
 		c.FinalQuery = advanced.FinalQuery
 		c.UnexpectedIPs = advanced.UnexpectedIPs
+		c.SkipDNSSEC = advanced.SkipDNSSEC
 		return nil
 	}
 
@@ -184,6 +187,7 @@ func (c *NameServerConfig) Build() (*dns.NameServer, error) {
 		FinalQuery:        c.FinalQuery,
 		UnexpectedGeoip:   unexpectedGeoipList,
 		ActUnprior:        actUnprior,
+		SkipDnssec:        c.SkipDNSSEC,
 	}, nil
 }
 
@@ -208,6 +212,13 @@ type DNSConfig struct {
 	DisableFallbackIfMatch bool                `json:"disableFallbackIfMatch"`
 	EnableParallelQuery    bool                `json:"enableParallelQuery"`
 	UseSystemHosts         bool                `json:"useSystemHosts"`
+	DNSSEC                 *DNSSECConfig       `json:"dnssec"`
+}
+
+// DNSSECConfig is a JSON serializable object for dns.Config_DNSSEC.
+type DNSSECConfig struct {
+	// TrustAnchors are DS records, the root zone's KSK if empty.
+	TrustAnchors StringList `json:"trustAnchors"`
 }
 
 type HostAddress struct {
@@ -417,6 +428,12 @@ func (c *DNSConfig) Build() (*dns.Config, error) {
 		config.ClientIp = []byte(c.ClientIP.IP())
 	}
 
+	if c.DNSSEC != nil {
+		config.Dnssec = &dns.Config_DNSSEC{
+			TrustAnchor: c.DNSSEC.TrustAnchors,
+		}
+	}
+
 	// Build PolicyID
 	policyMap := map[string]uint32{}
 	nextPolicyID := uint32(1)
@@ -439,6 +456,13 @@ func (c *DNSConfig) Build() (*dns.Config, error) {
 			sb.WriteString("skip=0|")
 		}
 
+		// DNSSEC
+		if nsc.SkipDNSSEC {
+			sb.WriteString("dnssec=0|")
+		} else {
+			sb.WriteString("dnssec=1|")
+		}
+
 		// QueryStrategy
 		sb.WriteString("qs=")
 		sb.WriteString(strings.ToLower(strings.TrimSpace(nsc.QueryStrategy)))
@@ -113,5 +113,50 @@ func TestDNSConfigParsing(t *testing.T) {
 				DisableFallback: true,
 			},
 		},
+		{
+			Input: `{
+				"servers": [
+					"1.1.1.1",
+					{
+						"address": "localhost",
+						"skipDnssec": true
+					}
+				],
+				"dnssec": {
+					"trustAnchors": [". IN DS 20326 8 2 E06D44B80B8F1D39A95C0B0D7C65D08458E880409BBC683457104237C7F8EC8D"]
+				}
+			}`,
+			Parser: parserCreator(),
+			Output: &dns.Config{
+				NameServer: []*dns.NameServer{
+					{
+						Address: &net.Endpoint{
+							Address: &net.IPOrDomain{
+								Address: &net.IPOrDomain_Ip{
+									Ip: []byte{1, 1, 1, 1},
+								},
+							},
+							Network: net.Network_UDP,
+						},
+						PolicyID: 1,
+					},
+					{
+						Address: &net.Endpoint{
+							Address: &net.IPOrDomain{
+								Address: &net.IPOrDomain_Domain{
+									Domain: "localhost",
+								},
+							},
+							Network: net.Network_UDP,
+						},
+						SkipDnssec: true,
+						PolicyID:   2,
+					},
+				},
+				Dnssec: &dns.Config_DNSSEC{
+					TrustAnchor: []string{". IN DS 20326 8 2 E06D44B80B8F1D39A95C0B0D7C65D08458E880409BBC683457104237C7F8EC8D"},
+				},
+			},
+		},
 	})
 }
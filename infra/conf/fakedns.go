@@ -11,8 +11,19 @@ import (
 )
 
 type FakeDNSPoolElementConfig struct {
-	IPPool  string `json:"ipPool"`
-	LRUSize int64  `json:"poolSize"`
+	IPPool          string `json:"ipPool"`
+	LRUSize         int64  `json:"poolSize"`
+	PersistPath     string `json:"persistPath"`
+	PersistInterval int64  `json:"persistInterval"`
+}
+
+func (c *FakeDNSPoolElementConfig) Build() *fakedns.FakeDnsPool {
+	return &fakedns.FakeDnsPool{
+		IpPool:          c.IPPool,
+		LruSize:         c.LRUSize,
+		PersistPath:     c.PersistPath,
+		PersistInterval: c.PersistInterval,
+	}
 }
 
 type FakeDNSConfig struct {
@@ -51,16 +62,13 @@ func (f *FakeDNSConfig) Build() (*fakedns.FakeDnsPoolMulti, error) {
 	fakeDNSPool := fakedns.FakeDnsPoolMulti{}
 
 	if f.pool != nil {
-		fakeDNSPool.Pools = append(fakeDNSPool.Pools, &fakedns.FakeDnsPool{
-			IpPool:  f.pool.IPPool,
-			LruSize: f.pool.LRUSize,
-		})
+		fakeDNSPool.Pools = append(fakeDNSPool.Pools, f.pool.Build())
 		return &fakeDNSPool, nil
 	}
 
 	if f.pools != nil {
 		for _, v := range f.pools {
-			fakeDNSPool.Pools = append(fakeDNSPool.Pools, &fakedns.FakeDnsPool{IpPool: v.IPPool, LruSize: v.LRUSize})
+			fakeDNSPool.Pools = append(fakeDNSPool.Pools, v.Build())
 		}
 		return &fakeDNSPool, nil
 	}
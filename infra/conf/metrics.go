@@ -8,6 +8,9 @@ import (
 type MetricsConfig struct {
 	Tag    string `json:"tag"`
 	Listen string `json:"listen"`
+	// label cardinality caps of /metrics; 0 means no cap
+	MaxUserLabels   uint32 `json:"maxUserLabels"`
+	MaxDeviceLabels uint32 `json:"maxDeviceLabels"`
 }
 
 func (c *MetricsConfig) Build() (*metrics.Config, error) {
@@ -20,7 +23,9 @@ func (c *MetricsConfig) Build() (*metrics.Config, error) {
 	}
 
 	return &metrics.Config{
-		Tag:    c.Tag,
-		Listen: c.Listen,
+		Tag:             c.Tag,
+		Listen:          c.Listen,
+		MaxUserLabels:   c.MaxUserLabels,
+		MaxDeviceLabels: c.MaxDeviceLabels,
 	}, nil
 }
@@ -10,20 +10,36 @@ import (
 )
 
 type ObservatoryConfig struct {
-	SubjectSelector   []string          `json:"subjectSelector"`
-	ProbeURL          string            `json:"probeURL"`
-	ProbeInterval     duration.Duration `json:"probeInterval"`
-	EnableConcurrency bool              `json:"enableConcurrency"`
+	SubjectSelector   []string             `json:"subjectSelector"`
+	ProbeURL          string               `json:"probeURL"`
+	ProbeInterval     duration.Duration    `json:"probeInterval"`
+	EnableConcurrency bool                 `json:"enableConcurrency"`
+	Passive           *PassiveHealthConfig `json:"passive"`
 }
 
 func (o *ObservatoryConfig) Build() (proto.Message, error) {
-	return &observatory.Config{SubjectSelector: o.SubjectSelector, ProbeUrl: o.ProbeURL, ProbeInterval: int64(o.ProbeInterval), EnableConcurrency: o.EnableConcurrency}, nil
+	return &observatory.Config{SubjectSelector: o.SubjectSelector, ProbeUrl: o.ProbeURL, ProbeInterval: int64(o.ProbeInterval), EnableConcurrency: o.EnableConcurrency, Passive: o.Passive.Build()}, nil
+}
+
+// PassiveHealthConfig lets an observatory learn from real connections, e.g.
+// {"failureThreshold": 3, "skipProbe": true}.
+type PassiveHealthConfig struct {
+	FailureThreshold uint32 `json:"failureThreshold"`
+	SkipProbe        bool   `json:"skipProbe"`
+}
+
+func (c *PassiveHealthConfig) Build() *observatory.PassiveConfig {
+	if c == nil {
+		return nil
+	}
+	return &observatory.PassiveConfig{FailureThreshold: c.FailureThreshold, SkipProbe: c.SkipProbe}
 }
 
 type BurstObservatoryConfig struct {
 	SubjectSelector []string `json:"subjectSelector"`
 	// health check settings
 	HealthCheck *healthCheckSettings `json:"pingConfig,omitempty"`
+	Passive     *PassiveHealthConfig `json:"passive"`
 }
 
 func (b BurstObservatoryConfig) Build() (proto.Message, error) {
@@ -31,7 +47,7 @@ func (b BurstObservatoryConfig) Build() (proto.Message, error) {
 		return nil, errors.New("BurstObservatory requires a valid pingConfig")
 	}
 	if result, err := b.HealthCheck.Build(); err == nil {
-		return &burst.Config{SubjectSelector: b.SubjectSelector, PingConfig: result.(*burst.HealthPingConfig)}, nil
+		return &burst.Config{SubjectSelector: b.SubjectSelector, PingConfig: result.(*burst.HealthPingConfig), Passive: b.Passive.Build()}, nil
 	} else {
 		return nil, err
 	}
@@ -13,6 +13,288 @@ type RateLimitConfig struct {
 	KeyMode string `json:"keyMode"`
 	// optional; if omitted, keep the package default
 	GraceSeconds *uint32 `json:"graceSeconds"`
+	// per-uuid traffic quotas
+	Quotas []*RateLimitQuotaConfig `json:"quotas"`
+	// optional; persist limits/quotas across restarts
+	State *RateLimitStateConfig `json:"state"`
+	// optional; concurrent devices per user (uuid is ignored here)
+	MaxDevices *RateLimitMaxDevicesConfig `json:"maxDevices"`
+	// per-uuid overrides of maxDevices
+	UserMaxDevices []*RateLimitMaxDevicesConfig `json:"userMaxDevices"`
+	// time-of-day limit profiles per uuid or policy level
+	Schedules []*RateLimitScheduleConfig `json:"schedules"`
+	// optional; burst/initial credit/boost for all users (uuid is ignored here)
+	BucketShape *RateLimitShapeConfig `json:"bucketShape"`
+	// per-uuid overrides of bucketShape, field by field
+	UserBucketShapes []*RateLimitShapeConfig `json:"userBucketShapes"`
+	// optional; how devices without a pinned outbound get one
+	Chooser *RateLimitChooserConfig `json:"chooser"`
+}
+
+type RateLimitChooserConfig struct {
+	// "map" (default), "hash", "leastDevices" or "random"
+	Strategy  string   `json:"strategy"`
+	Outbounds []string `json:"outbounds"`
+	// weights for "random"; missing outbounds weigh 1
+	Weights map[string]uint32 `json:"weights"`
+	// uuid -> outbound tag; takes precedence over the strategy
+	Users map[string]string `json:"users"`
+	// skip outbounds the observatory reports as dead
+	HealthCheck bool `json:"healthCheck"`
+}
+
+func (c *RateLimitChooserConfig) Build() (ratelimit.ChooserConfig, error) {
+	cfg := ratelimit.ChooserConfig{
+		Outbounds:   c.Outbounds,
+		Weights:     c.Weights,
+		HealthCheck: c.HealthCheck,
+	}
+	switch strings.ToLower(strings.TrimSpace(c.Strategy)) {
+	case "", "map":
+		cfg.Strategy = ratelimit.ChooserMapOnly
+	case "hash":
+		cfg.Strategy = ratelimit.ChooserHash
+	case "leastdevices":
+		cfg.Strategy = ratelimit.ChooserLeastDevices
+	case "random":
+		cfg.Strategy = ratelimit.ChooserRandom
+	default:
+		return cfg, errors.New("unknown ratelimit chooser strategy: ", c.Strategy)
+	}
+	return cfg, nil
+}
+
+type RateLimitRateConfig struct {
+	DownBps uint64 `json:"downBps"`
+	UpBps   uint64 `json:"upBps"`
+}
+
+func (c *RateLimitRateConfig) Build() *ratelimit.RateBps {
+	if c == nil {
+		return nil
+	}
+	return &ratelimit.RateBps{Down: c.DownBps, Up: c.UpBps}
+}
+
+type RateLimitScheduleWindowConfig struct {
+	// "mon".."sun", "weekdays", "weekends"; empty means every day
+	Days []string `json:"days"`
+	// "HH:MM"; end <= start wraps past midnight, "24:00" is allowed as end
+	Start string `json:"start"`
+	End   string `json:"end"`
+	// omitted level is not touched by the window; zero rates mean unlimited
+	PerConn *RateLimitRateConfig `json:"perConn"`
+	Total   *RateLimitRateConfig `json:"total"`
+}
+
+var scheduleDays = map[string][]time.Weekday{
+	"sun":      {time.Sunday},
+	"mon":      {time.Monday},
+	"tue":      {time.Tuesday},
+	"wed":      {time.Wednesday},
+	"thu":      {time.Thursday},
+	"fri":      {time.Friday},
+	"sat":      {time.Saturday},
+	"weekdays": {time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday},
+	"weekends": {time.Saturday, time.Sunday},
+}
+
+func parseScheduleMinute(s string) (int, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		if s == "24:00" {
+			return 24 * 60, nil
+		}
+		return 0, errors.New("invalid time of day: ", s)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+func (c *RateLimitScheduleWindowConfig) Build() (ratelimit.ScheduleWindow, error) {
+	var w ratelimit.ScheduleWindow
+
+	for _, d := range c.Days {
+		days, ok := scheduleDays[strings.ToLower(strings.TrimSpace(d))]
+		if !ok {
+			return w, errors.New("unknown schedule day: ", d)
+		}
+		w.Days = append(w.Days, days...)
+	}
+
+	var err error
+	if w.StartMinute, err = parseScheduleMinute(c.Start); err != nil {
+		return w, err
+	}
+	if w.EndMinute, err = parseScheduleMinute(c.End); err != nil {
+		return w, err
+	}
+
+	w.PerConn = c.PerConn.Build()
+	w.Total = c.Total.Build()
+	return w, nil
+}
+
+type RateLimitScheduleConfig struct {
+	// either uuid or level
+	UUID  string  `json:"uuid"`
+	Level *uint32 `json:"level"`
+	// IANA name, "UTC", "Local" or an offset like "+03:00"; empty means Local
+	TimeZone string                           `json:"timeZone"`
+	Windows  []*RateLimitScheduleWindowConfig `json:"windows"`
+}
+
+func (c *RateLimitScheduleConfig) Build() (ratelimit.Schedule, error) {
+	sch := ratelimit.Schedule{TimeZone: c.TimeZone}
+	for _, wc := range c.Windows {
+		if wc == nil {
+			continue
+		}
+		w, err := wc.Build()
+		if err != nil {
+			return sch, err
+		}
+		sch.Windows = append(sch.Windows, w)
+	}
+	return sch, nil
+}
+
+type RateLimitBucketShapeConfig struct {
+	// fixed burst; 0 means burstMs of the rate
+	BurstBytes uint64 `json:"burstBytes"`
+	// 0 means 200ms (at least 32KB)
+	BurstMs uint32 `json:"burstMs"`
+	// initial credit of a new bucket; omitted means full burst
+	InitialBytes *uint64 `json:"initialBytes"`
+}
+
+func (c *RateLimitBucketShapeConfig) Build() *ratelimit.BucketShape {
+	if c == nil {
+		return nil
+	}
+	return &ratelimit.BucketShape{
+		BurstBytes:   c.BurstBytes,
+		BurstMs:      c.BurstMs,
+		InitialBytes: c.InitialBytes,
+	}
+}
+
+type RateLimitBoostConfig struct {
+	// full speed for the first bytes and/or seconds of a device session
+	Bytes   uint64 `json:"bytes"`
+	Seconds uint32 `json:"seconds"`
+}
+
+type RateLimitShapeConfig struct {
+	UUID    string                      `json:"uuid"`
+	PerConn *RateLimitBucketShapeConfig `json:"perConn"`
+	Total   *RateLimitBucketShapeConfig `json:"total"`
+	Boost   *RateLimitBoostConfig       `json:"boost"`
+}
+
+func (c *RateLimitShapeConfig) Build() ratelimit.LimitShape {
+	l := ratelimit.LimitShape{
+		PerConn: c.PerConn.Build(),
+		Total:   c.Total.Build(),
+	}
+	if c.Boost != nil {
+		l.Boost = &ratelimit.Boost{
+			Bytes:    c.Boost.Bytes,
+			Duration: time.Duration(c.Boost.Seconds) * time.Second,
+		}
+	}
+	return l
+}
+
+type RateLimitMaxDevicesConfig struct {
+	UUID string `json:"uuid"`
+	Max  uint32 `json:"max"`
+	// "reject" (default), "evict" или "log"
+	Policy string `json:"policy"`
+}
+
+func (c *RateLimitMaxDevicesConfig) Build() (ratelimit.MaxDevices, error) {
+	m := ratelimit.MaxDevices{Max: c.Max}
+
+	switch strings.ToLower(strings.TrimSpace(c.Policy)) {
+	case "", "reject":
+		m.Policy = ratelimit.DevicePolicyRejectNewest
+	case "evict":
+		m.Policy = ratelimit.DevicePolicyEvictOldest
+	case "log":
+		m.Policy = ratelimit.DevicePolicyLogOnly
+	default:
+		return m, errors.New("unknown ratelimit maxDevices policy: ", c.Policy)
+	}
+	return m, nil
+}
+
+type RateLimitStateConfig struct {
+	Path            string `json:"path"`
+	IntervalSeconds uint32 `json:"intervalSeconds"`
+}
+
+func (c *RateLimitStateConfig) Build() (*ratelimit.StateConfig, error) {
+	if c.Path == "" {
+		return nil, errors.New("ratelimit.state.path is empty")
+	}
+	return &ratelimit.StateConfig{
+		Path:            c.Path,
+		IntervalSeconds: c.IntervalSeconds,
+	}, nil
+}
+
+type RateLimitQuotaConfig struct {
+	UUID string `json:"uuid"`
+	// "absolute" (default), "daily" или "monthly"
+	Period string `json:"period"`
+	// "total" (default), "up" или "down"
+	Direction  string `json:"direction"`
+	LimitBytes uint64 `json:"limitBytes"`
+	// "block" (default), "close" или "penalty"
+	Action         string `json:"action"`
+	PenaltyDownBps uint64 `json:"penaltyDownBps"`
+	PenaltyUpBps   uint64 `json:"penaltyUpBps"`
+}
+
+func (c *RateLimitQuotaConfig) Build() (ratelimit.Quota, error) {
+	var q ratelimit.Quota
+
+	switch strings.ToLower(strings.TrimSpace(c.Period)) {
+	case "", "absolute":
+		q.Period = ratelimit.QuotaAbsolute
+	case "daily":
+		q.Period = ratelimit.QuotaDaily
+	case "monthly":
+		q.Period = ratelimit.QuotaMonthly
+	default:
+		return q, errors.New("unknown ratelimit quota period: ", c.Period)
+	}
+
+	switch strings.ToLower(strings.TrimSpace(c.Direction)) {
+	case "", "total":
+		q.Direction = ratelimit.QuotaTotal
+	case "up":
+		q.Direction = ratelimit.QuotaUp
+	case "down":
+		q.Direction = ratelimit.QuotaDown
+	default:
+		return q, errors.New("unknown ratelimit quota direction: ", c.Direction)
+	}
+
+	switch strings.ToLower(strings.TrimSpace(c.Action)) {
+	case "", "block":
+		q.Action = ratelimit.QuotaActionBlock
+	case "close":
+		q.Action = ratelimit.QuotaActionClose
+	case "penalty":
+		q.Action = ratelimit.QuotaActionPenalty
+	default:
+		return q, errors.New("unknown ratelimit quota action: ", c.Action)
+	}
+
+	q.LimitBytes = c.LimitBytes
+	q.Penalty = ratelimit.RateBps{Down: c.PenaltyDownBps, Up: c.PenaltyUpBps}
+	return q, nil
 }
 
 func (c *RateLimitConfig) Apply() error {
@@ -33,5 +315,85 @@ func (c *RateLimitConfig) Apply() error {
 		ratelimit.SetGrace(time.Duration(*c.GraceSeconds) * time.Second)
 	}
 
+	for _, qc := range c.Quotas {
+		if qc == nil || qc.UUID == "" {
+			return errors.New("ratelimit quota: uuid is empty")
+		}
+		q, err := qc.Build()
+		if err != nil {
+			return err
+		}
+		ratelimit.Quotas.Set(qc.UUID, q)
+	}
+
+	if c.MaxDevices != nil {
+		m, err := c.MaxDevices.Build()
+		if err != nil {
+			return err
+		}
+		ratelimit.MaxDevicesLimits.SetDefault(m)
+	}
+
+	for _, mc := range c.UserMaxDevices {
+		if mc == nil || mc.UUID == "" {
+			return errors.New("ratelimit userMaxDevices: uuid is empty")
+		}
+		m, err := mc.Build()
+		if err != nil {
+			return err
+		}
+		ratelimit.MaxDevicesLimits.SetUser(mc.UUID, m)
+	}
+
+	if c.BucketShape != nil {
+		ratelimit.Shapes.SetDefault(c.BucketShape.Build())
+	}
+
+	for _, sc := range c.UserBucketShapes {
+		if sc == nil || sc.UUID == "" {
+			return errors.New("ratelimit userBucketShapes: uuid is empty")
+		}
+		ratelimit.Shapes.SetUser(sc.UUID, sc.Build())
+	}
+
+	for _, sc := range c.Schedules {
+		if sc == nil {
+			continue
+		}
+		sch, err := sc.Build()
+		if err != nil {
+			return err
+		}
+		switch {
+		case sc.UUID != "" && sc.Level != nil:
+			return errors.New("ratelimit schedule: set either uuid or level, not both")
+		case sc.UUID != "":
+			err = ratelimit.Schedules.SetUser(sc.UUID, sch)
+		case sc.Level != nil:
+			err = ratelimit.Schedules.SetLevel(*sc.Level, sch)
+		default:
+			return errors.New("ratelimit schedule: uuid or level is required")
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	if c.Chooser != nil {
+		cfg, err := c.Chooser.Build()
+		if err != nil {
+			return err
+		}
+		if err := ratelimit.Egress.Configure(cfg); err != nil {
+			return err
+		}
+		for uuid, tag := range c.Chooser.Users {
+			if uuid == "" || tag == "" {
+				return errors.New("ratelimit chooser: uuid and outbound tag are required")
+			}
+			ratelimit.Egress.SetBinding(uuid, tag)
+		}
+	}
+
 	return nil
 }
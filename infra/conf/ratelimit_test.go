@@ -85,3 +85,199 @@ func TestRateLimitConfigApplyGraceOnlyKeepsExistingMode(t *testing.T) {
 		t.Fatalf("expected key mode device to stay unchanged, got %v", got)
 	}
 }
+
+func TestRateLimitConfigApplyQuotas(t *testing.T) {
+	t.Cleanup(func() { ratelimit.Quotas.Clear("quota-conf-user") })
+
+	cfg := RateLimitConfig{
+		Quotas: []*RateLimitQuotaConfig{{
+			UUID:           "quota-conf-user",
+			Period:         "monthly",
+			Direction:      "down",
+			LimitBytes:     1 << 30,
+			Action:         "penalty",
+			PenaltyDownBps: 1000000,
+		}},
+	}
+
+	if err := cfg.Apply(); err != nil {
+		t.Fatalf("Apply returned error: %v", err)
+	}
+
+	usage, ok := ratelimit.Quotas.Get("quota-conf-user")
+	if !ok {
+		t.Fatal("expected quota to be set")
+	}
+	q := usage.Quota
+	if q.Period != ratelimit.QuotaMonthly || q.Direction != ratelimit.QuotaDown ||
+		q.LimitBytes != 1<<30 || q.Action != ratelimit.QuotaActionPenalty || q.Penalty.Down != 1000000 {
+		t.Fatalf("unexpected quota: %+v", q)
+	}
+}
+
+func TestRateLimitConfigApplyQuotaUnknownAction(t *testing.T) {
+	cfg := RateLimitConfig{
+		Quotas: []*RateLimitQuotaConfig{{UUID: "quota-conf-bad", Action: "explode"}},
+	}
+	if err := cfg.Apply(); err == nil {
+		t.Fatal("expected error for unknown quota action")
+	}
+}
+
+func TestRateLimitStateConfigBuild(t *testing.T) {
+	sc, err := (&RateLimitStateConfig{Path: "/var/lib/xray/ratelimit.json", IntervalSeconds: 30}).Build()
+	if err != nil {
+		t.Fatalf("Build returned error: %v", err)
+	}
+	if sc.Path != "/var/lib/xray/ratelimit.json" || sc.IntervalSeconds != 30 {
+		t.Fatalf("unexpected state config: %+v", sc)
+	}
+
+	if _, err := (&RateLimitStateConfig{}).Build(); err == nil {
+		t.Fatal("expected error for empty path")
+	}
+}
+
+func TestRateLimitConfigApplyMaxDevices(t *testing.T) {
+	t.Cleanup(func() {
+		ratelimit.MaxDevicesLimits.ClearDefault()
+		ratelimit.MaxDevicesLimits.ClearUser("maxdev-conf-user")
+	})
+
+	cfg := RateLimitConfig{
+		MaxDevices: &RateLimitMaxDevicesConfig{Max: 3},
+		UserMaxDevices: []*RateLimitMaxDevicesConfig{
+			{UUID: "maxdev-conf-user", Max: 5, Policy: "evict"},
+		},
+	}
+	if err := cfg.Apply(); err != nil {
+		t.Fatalf("Apply returned error: %v", err)
+	}
+
+	if m, ok := ratelimit.MaxDevicesLimits.Get("someone-else"); !ok || m.Max != 3 || m.Policy != ratelimit.DevicePolicyRejectNewest {
+		t.Fatalf("unexpected default max devices: %+v, %v", m, ok)
+	}
+	if m, ok := ratelimit.MaxDevicesLimits.Get("maxdev-conf-user"); !ok || m.Max != 5 || m.Policy != ratelimit.DevicePolicyEvictOldest {
+		t.Fatalf("unexpected user max devices: %+v, %v", m, ok)
+	}
+
+	bad := RateLimitConfig{MaxDevices: &RateLimitMaxDevicesConfig{Max: 1, Policy: "kick"}}
+	if err := bad.Apply(); err == nil {
+		t.Fatal("expected error for unknown maxDevices policy")
+	}
+}
+
+func TestRateLimitScheduleConfigBuild(t *testing.T) {
+	sc := &RateLimitScheduleConfig{
+		UUID:     "schedule-conf-user",
+		TimeZone: "Europe/Moscow",
+		Windows: []*RateLimitScheduleWindowConfig{{
+			Days:    []string{"weekdays"},
+			Start:   "18:00",
+			End:     "23:30",
+			PerConn: &RateLimitRateConfig{DownBps: 5000000, UpBps: 1000000},
+		}, {
+			Start: "23:30",
+			End:   "24:00",
+			Total: &RateLimitRateConfig{},
+		}},
+	}
+
+	sch, err := sc.Build()
+	if err != nil {
+		t.Fatalf("Build returned error: %v", err)
+	}
+	if len(sch.Windows) != 2 {
+		t.Fatalf("unexpected windows: %+v", sch.Windows)
+	}
+	w := sch.Windows[0]
+	if len(w.Days) != 5 || w.StartMinute != 18*60 || w.EndMinute != 23*60+30 || w.PerConn == nil || w.PerConn.Down != 5000000 || w.Total != nil {
+		t.Fatalf("unexpected window: %+v", w)
+	}
+	if sch.Windows[1].EndMinute != 24*60 || sch.Windows[1].Total == nil {
+		t.Fatalf("unexpected window: %+v", sch.Windows[1])
+	}
+
+	bad := &RateLimitScheduleConfig{Windows: []*RateLimitScheduleWindowConfig{{Days: []string{"funday"}, Start: "00:00", End: "01:00"}}}
+	if _, err := bad.Build(); err == nil {
+		t.Fatal("expected error for unknown day")
+	}
+
+	cfg := RateLimitConfig{Schedules: []*RateLimitScheduleConfig{{TimeZone: "UTC", Windows: sc.Windows}}}
+	if err := cfg.Apply(); err == nil {
+		t.Fatal("expected error for schedule without uuid or level")
+	}
+}
+
+func TestRateLimitConfigApplyBucketShapes(t *testing.T) {
+	t.Cleanup(func() {
+		ratelimit.Shapes.ClearDefault()
+		ratelimit.Shapes.ClearUser("shape-conf-user")
+	})
+
+	initial := uint64(50 << 20)
+	cfg := RateLimitConfig{
+		BucketShape: &RateLimitShapeConfig{
+			PerConn: &RateLimitBucketShapeConfig{BurstMs: 1000},
+		},
+		UserBucketShapes: []*RateLimitShapeConfig{{
+			UUID:  "shape-conf-user",
+			Total: &RateLimitBucketShapeConfig{BurstBytes: 1 << 20, InitialBytes: &initial},
+			Boost: &RateLimitBoostConfig{Seconds: 30},
+		}},
+	}
+	if err := cfg.Apply(); err != nil {
+		t.Fatalf("Apply returned error: %v", err)
+	}
+
+	l := ratelimit.Shapes.Get("shape-conf-user")
+	if l.PerConn == nil || l.PerConn.BurstMs != 1000 {
+		t.Fatalf("expected per-conn shape from default, got %+v", l.PerConn)
+	}
+	if l.Total == nil || l.Total.BurstBytes != 1<<20 || l.Total.InitialBytes == nil || *l.Total.InitialBytes != initial {
+		t.Fatalf("unexpected total shape: %+v", l.Total)
+	}
+	if l.Boost == nil || l.Boost.Duration != 30*time.Second {
+		t.Fatalf("unexpected boost: %+v", l.Boost)
+	}
+
+	bad := RateLimitConfig{UserBucketShapes: []*RateLimitShapeConfig{{}}}
+	if err := bad.Apply(); err == nil {
+		t.Fatal("expected error for user bucket shape without uuid")
+	}
+}
+
+func TestRateLimitConfigApplyChooser(t *testing.T) {
+	t.Cleanup(func() {
+		ratelimit.Egress.Configure(ratelimit.ChooserConfig{})
+		ratelimit.Egress.ClearBinding("chooser-conf-user")
+	})
+
+	cfg := RateLimitConfig{
+		Chooser: &RateLimitChooserConfig{
+			Strategy:  "leastDevices",
+			Outbounds: []string{"a", "b"},
+			Users:     map[string]string{"chooser-conf-user": "c"},
+		},
+	}
+	if err := cfg.Apply(); err != nil {
+		t.Fatalf("Apply returned error: %v", err)
+	}
+
+	if c := ratelimit.Egress.Config(); c.Strategy != ratelimit.ChooserLeastDevices || len(c.Outbounds) != 2 {
+		t.Fatalf("unexpected chooser config: %+v", c)
+	}
+	if tag := ratelimit.Egress.Bindings()["chooser-conf-user"]; tag != "c" {
+		t.Fatalf("expected binding to c, got %q", tag)
+	}
+
+	for _, c := range []*RateLimitChooserConfig{
+		{Strategy: "roundRobin", Outbounds: []string{"a"}},
+		{Strategy: "hash"},
+	} {
+		bad := RateLimitConfig{Chooser: c}
+		if err := bad.Apply(); err == nil {
+			t.Fatalf("expected error for chooser %+v", c)
+		}
+	}
+}
@@ -5,12 +5,14 @@ import (
 	"runtime"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/xtls/xray-core/app/router"
 	"github.com/xtls/xray-core/common/errors"
 	"github.com/xtls/xray-core/common/net"
 	"github.com/xtls/xray-core/common/platform/filesystem"
 	"github.com/xtls/xray-core/common/serial"
+	"github.com/xtls/xray-core/infra/conf/cfgcommon/duration"
 	"google.golang.org/protobuf/proto"
 )
 
@@ -21,10 +23,11 @@ type StrategyConfig struct {
 }
 
 type BalancingRule struct {
-	Tag         string         `json:"tag"`
-	Selectors   StringList     `json:"selector"`
-	Strategy    StrategyConfig `json:"strategy"`
-	FallbackTag string         `json:"fallbackTag"`
+	Tag         string          `json:"tag"`
+	Selectors   StringList      `json:"selector"`
+	Strategy    StrategyConfig  `json:"strategy"`
+	FallbackTag string          `json:"fallbackTag"`
+	Failover    *FailoverConfig `json:"failover"`
 }
 
 // Build builds the balancing rule
@@ -40,7 +43,7 @@ func (r *BalancingRule) Build() (*router.BalancingRule, error) {
 	switch r.Strategy.Type {
 	case "":
 		r.Strategy.Type = strategyRandom
-	case strategyRandom, strategyLeastLoad, strategyLeastPing, strategyRoundRobin:
+	case strategyRandom, strategyLeastLoad, strategyLeastPing, strategyRoundRobin, strategyConsistentHash:
 	default:
 		return nil, errors.New("unknown balancing strategy: " + r.Strategy.Type)
 	}
@@ -61,12 +64,40 @@ func (r *BalancingRule) Build() (*router.BalancingRule, error) {
 		}
 	}
 
+	var failover *router.Failover
+	if r.Failover != nil {
+		if failover, err = r.Failover.Build(); err != nil {
+			return nil, err
+		}
+	}
+
 	return &router.BalancingRule{
 		Strategy:         r.Strategy.Type,
 		StrategySettings: serial.ToTypedMessage(ts),
 		FallbackTag:      r.FallbackTag,
 		OutboundSelector: r.Selectors,
 		Tag:              r.Tag,
+		Failover:         failover,
+	}, nil
+}
+
+// FailoverConfig lists the outbounds to retry a connection with when the
+// chosen one fails before the client got any data, e.g.
+// {"outboundTag": ["backup"], "attempts": 3, "timeout": "5s"}.
+type FailoverConfig struct {
+	OutboundTags StringList        `json:"outboundTag"`
+	Attempts     uint32            `json:"attempts"`
+	Timeout      duration.Duration `json:"timeout"`
+}
+
+func (c *FailoverConfig) Build() (*router.Failover, error) {
+	if c.Timeout < 0 {
+		return nil, errors.New("negative failover timeout")
+	}
+	return &router.Failover{
+		OutboundTag: c.OutboundTags,
+		Attempts:    c.Attempts,
+		Timeout:     uint32(time.Duration(c.Timeout).Milliseconds()),
 	}, nil
 }
 
@@ -74,6 +105,52 @@ type RouterConfig struct {
 	RuleList       []json.RawMessage `json:"rules"`
 	DomainStrategy *string           `json:"domainStrategy"`
 	Balancers      []*BalancingRule  `json:"balancers"`
+	// count hits and traffic of rules with a ruleTag in the stats manager
+	RuleStats bool             `json:"ruleStats"`
+	RuleSets  []*RuleSetConfig `json:"ruleSets"`
+}
+
+// RuleSetConfig is a list of domains and IPs in a file, used in rules as
+// "ruleset:tag" and reloaded when the file changes.
+type RuleSetConfig struct {
+	Tag            string `json:"tag"`
+	Path           string `json:"path"`
+	Format         string `json:"format"`
+	Code           string `json:"code"`
+	ReloadInterval uint32 `json:"reloadInterval"`
+}
+
+func (c *RuleSetConfig) Build() (*router.RuleSet, error) {
+	if c.Tag == "" {
+		return nil, errors.New("empty rule set tag")
+	}
+	if c.Path == "" {
+		return nil, errors.New("empty path of rule set ", c.Tag)
+	}
+	rs := &router.RuleSet{
+		Tag:            c.Tag,
+		Path:           c.Path,
+		Code:           c.Code,
+		ReloadInterval: c.ReloadInterval,
+	}
+	switch strings.ToLower(c.Format) {
+	case "":
+		rs.Format = router.RuleSet_AUTO
+	case "text":
+		rs.Format = router.RuleSet_TEXT
+	case "json":
+		rs.Format = router.RuleSet_JSON
+	case "geosite":
+		rs.Format = router.RuleSet_GEOSITE
+	case "geoip":
+		rs.Format = router.RuleSet_GEOIP
+	default:
+		return nil, errors.New("unknown format of rule set ", c.Tag, ": ", c.Format)
+	}
+	if (rs.Format == router.RuleSet_GEOSITE || rs.Format == router.RuleSet_GEOIP) && rs.Code == "" {
+		return nil, errors.New("rule set ", c.Tag, " needs the code of a list in ", c.Path)
+	}
+	return rs, nil
 }
 
 func (c *RouterConfig) getDomainStrategy() router.Config_DomainStrategy {
@@ -95,6 +172,7 @@ func (c *RouterConfig) getDomainStrategy() router.Config_DomainStrategy {
 func (c *RouterConfig) Build() (*router.Config, error) {
 	config := new(router.Config)
 	config.DomainStrategy = c.getDomainStrategy()
+	config.RuleStats = c.RuleStats
 
 	var rawRuleList []json.RawMessage
 	if c != nil {
@@ -109,6 +187,13 @@ func (c *RouterConfig) Build() (*router.Config, error) {
 
 		config.Rule = append(config.Rule, rule)
 	}
+	for _, rawRuleSet := range c.RuleSets {
+		rs, err := rawRuleSet.Build()
+		if err != nil {
+			return nil, err
+		}
+		config.RuleSet = append(config.RuleSet, rs)
+	}
 	for _, rawBalancer := range c.Balancers {
 		balancer, err := rawBalancer.Build()
 		if err != nil {
@@ -535,6 +620,8 @@ func parseFieldRule(msg json.RawMessage) (*router.RoutingRule, error) {
 		Attributes map[string]string `json:"attrs"`
 		LocalIP    *StringList       `json:"localIP"`
 		LocalPort  *PortList         `json:"localPort"`
+		Schedule   *ScheduleConfig   `json:"schedule"`
+		Failover   *FailoverConfig   `json:"failover"`
 	}
 	rawFieldRule := new(RawFieldRule)
 	err := json.Unmarshal(msg, rawFieldRule)
@@ -558,7 +645,9 @@ func parseFieldRule(msg json.RawMessage) (*router.RoutingRule, error) {
 	}
 
 	if rawFieldRule.Domain != nil {
-		for _, domain := range *rawFieldRule.Domain {
+		var domains StringList
+		domains, rule.DomainRuleSet = splitRuleSets(*rawFieldRule.Domain)
+		for _, domain := range domains {
 			rules, err := parseDomainRule(domain)
 			if err != nil {
 				return nil, errors.New("failed to parse domain rule: ", domain).Base(err)
@@ -568,7 +657,9 @@ func parseFieldRule(msg json.RawMessage) (*router.RoutingRule, error) {
 	}
 
 	if rawFieldRule.Domains != nil {
-		for _, domain := range *rawFieldRule.Domains {
+		domains, ruleSets := splitRuleSets(*rawFieldRule.Domains)
+		rule.DomainRuleSet = append(rule.DomainRuleSet, ruleSets...)
+		for _, domain := range domains {
 			rules, err := parseDomainRule(domain)
 			if err != nil {
 				return nil, errors.New("failed to parse domain rule: ", domain).Base(err)
@@ -578,7 +669,9 @@ func parseFieldRule(msg json.RawMessage) (*router.RoutingRule, error) {
 	}
 
 	if rawFieldRule.IP != nil {
-		geoipList, err := ToCidrList(*rawFieldRule.IP)
+		var ips StringList
+		ips, rule.IpRuleSet = splitRuleSets(*rawFieldRule.IP)
+		geoipList, err := ToCidrList(ips)
 		if err != nil {
 			return nil, err
 		}
@@ -598,7 +691,9 @@ func parseFieldRule(msg json.RawMessage) (*router.RoutingRule, error) {
 	}
 
 	if rawFieldRule.SourceIP != nil {
-		geoipList, err := ToCidrList(*rawFieldRule.SourceIP)
+		var ips StringList
+		ips, rule.SourceIpRuleSet = splitRuleSets(*rawFieldRule.SourceIP)
+		geoipList, err := ToCidrList(ips)
 		if err != nil {
 			return nil, err
 		}
@@ -647,9 +742,131 @@ func parseFieldRule(msg json.RawMessage) (*router.RoutingRule, error) {
 		rule.Attributes = rawFieldRule.Attributes
 	}
 
+	if rawFieldRule.Schedule != nil {
+		schedule, err := rawFieldRule.Schedule.Build()
+		if err != nil {
+			return nil, err
+		}
+		rule.Schedule = schedule
+	}
+
+	if rawFieldRule.Failover != nil {
+		failover, err := rawFieldRule.Failover.Build()
+		if err != nil {
+			return nil, err
+		}
+		rule.Failover = failover
+	}
+
 	return rule, nil
 }
 
+// ScheduleConfig is the "schedule" condition of a routing rule, e.g.
+// {"weekdays": ["mon-fri"], "time": ["22:00-06:00"], "timezone": "Europe/Berlin"}.
+type ScheduleConfig struct {
+	Weekdays *StringList `json:"weekdays"`
+	Time     *StringList `json:"time"`
+	Timezone string      `json:"timezone"`
+}
+
+var weekdayNames = map[string]uint32{
+	"sun": 0, "sunday": 0,
+	"mon": 1, "monday": 1,
+	"tue": 2, "tuesday": 2,
+	"wed": 3, "wednesday": 3,
+	"thu": 4, "thursday": 4,
+	"fri": 5, "friday": 5,
+	"sat": 6, "saturday": 6,
+}
+
+func parseWeekday(s string) (uint32, error) {
+	if day, found := weekdayNames[strings.ToLower(strings.TrimSpace(s))]; found {
+		return day, nil
+	}
+	return 0, errors.New("invalid weekday: ", s)
+}
+
+// parseDayMinute parses "HH:MM" into minutes since midnight; "24:00" is
+// allowed as the end of the day.
+func parseDayMinute(s string) (uint32, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(s))
+	if err != nil {
+		if strings.TrimSpace(s) == "24:00" {
+			return 24 * 60, nil
+		}
+		return 0, errors.New("invalid time of day: ", s).Base(err)
+	}
+	return uint32(t.Hour()*60 + t.Minute()), nil
+}
+
+func (c *ScheduleConfig) Build() (*router.Schedule, error) {
+	schedule := &router.Schedule{
+		Timezone: c.Timezone,
+	}
+	if c.Weekdays != nil {
+		for _, s := range *c.Weekdays {
+			// "mon-fri" wraps the week if needed, e.g. "fri-mon"
+			from, to, isRange := strings.Cut(s, "-")
+			first, err := parseWeekday(from)
+			if err != nil {
+				return nil, err
+			}
+			last := first
+			if isRange {
+				if last, err = parseWeekday(to); err != nil {
+					return nil, err
+				}
+			}
+			for day := first; ; day = (day + 1) % 7 {
+				schedule.Weekdays = append(schedule.Weekdays, day)
+				if day == last {
+					break
+				}
+			}
+		}
+	}
+	if c.Time != nil {
+		for _, s := range *c.Time {
+			from, to, found := strings.Cut(s, "-")
+			if !found {
+				return nil, errors.New("invalid time range: ", s, ", expected HH:MM-HH:MM")
+			}
+			start, err := parseDayMinute(from)
+			if err != nil {
+				return nil, err
+			}
+			end, err := parseDayMinute(to)
+			if err != nil {
+				return nil, err
+			}
+			if start == end || start >= 24*60 {
+				return nil, errors.New("invalid time range: ", s)
+			}
+			schedule.Ranges = append(schedule.Ranges, &router.Schedule_TimeRange{Start: start, End: end})
+		}
+	}
+	if c.Timezone != "" {
+		if _, err := time.LoadLocation(c.Timezone); err != nil {
+			return nil, errors.New("unknown time zone: ", c.Timezone).Base(err)
+		}
+	}
+	return schedule, nil
+}
+
+// splitRuleSets separates "ruleset:tag" references from other entries.
+func splitRuleSets(list StringList) (StringList, []string) {
+	var rest StringList
+	var ruleSets []string
+	for _, entry := range list {
+		if tag, found := strings.CutPrefix(entry, "ruleset:"); found {
+			ruleSets = append(ruleSets, tag)
+			continue
+		}
+		rest = append(rest, entry)
+	}
+	return rest, ruleSets
+}
+
 func ParseRule(msg json.RawMessage) (*router.RoutingRule, error) {
 	rawRule := new(RouterRule)
 	err := json.Unmarshal(msg, rawRule)
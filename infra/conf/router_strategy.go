@@ -6,6 +6,7 @@ import (
 
 	"github.com/xtls/xray-core/app/observatory/burst"
 	"github.com/xtls/xray-core/app/router"
+	"github.com/xtls/xray-core/common/errors"
 	"github.com/xtls/xray-core/infra/conf/cfgcommon/duration"
 )
 
@@ -14,6 +15,8 @@ const (
 	strategyLeastPing  string = "leastping"
 	strategyRoundRobin string = "roundrobin"
 	strategyLeastLoad  string = "leastload"
+	// consistentHash, lowercased like the others
+	strategyConsistentHash string = "consistenthash"
 )
 
 var (
@@ -22,6 +25,8 @@ var (
 		strategyLeastPing:  func() interface{} { return new(strategyEmptyConfig) },
 		strategyRoundRobin: func() interface{} { return new(strategyEmptyConfig) },
 		strategyLeastLoad:  func() interface{} { return new(strategyLeastLoadConfig) },
+
+		strategyConsistentHash: func() interface{} { return new(strategyConsistentHashConfig) },
 	}, "type", "settings")
 )
 
@@ -45,6 +50,15 @@ type strategyLeastLoadConfig struct {
 	Tolerance float64 `json:"tolerance,omitempty"`
 }
 
+type strategyConsistentHashConfig struct {
+	// "sourceIP", "user" and/or "domain"; default ["sourceIP"]
+	Keys []string `json:"keys,omitempty"`
+	// an outbound takes at most loadFactor times the average of keys; default 1.25
+	LoadFactor float64 `json:"loadFactor,omitempty"`
+	// how long a key stays on its outbound after the last connection; default 600
+	StickySeconds uint32 `json:"stickySeconds,omitempty"`
+}
+
 // healthCheckSettings holds settings for health Checker
 type healthCheckSettings struct {
 	Destination   string            `json:"destination"`
@@ -100,3 +114,27 @@ func (v *strategyLeastLoadConfig) Build() (proto.Message, error) {
 	}
 	return config, nil
 }
+
+// Build implements Buildable.
+func (v *strategyConsistentHashConfig) Build() (proto.Message, error) {
+	config := &router.StrategyConsistentHashConfig{
+		LoadFactor:    float32(v.LoadFactor),
+		StickySeconds: v.StickySeconds,
+	}
+	for _, k := range v.Keys {
+		switch strings.ToLower(k) {
+		case "sourceip", "source":
+			config.Keys = append(config.Keys, router.StrategyConsistentHashConfig_SOURCE_IP)
+		case "user", "email":
+			config.Keys = append(config.Keys, router.StrategyConsistentHashConfig_USER)
+		case "domain", "targetdomain":
+			config.Keys = append(config.Keys, router.StrategyConsistentHashConfig_TARGET_DOMAIN)
+		default:
+			return nil, errors.New("unknown consistentHash key: ", k)
+		}
+	}
+	if v.LoadFactor != 0 && v.LoadFactor < 1 {
+		return nil, errors.New("consistentHash loadFactor must be at least 1")
+	}
+	return config, nil
+}
@@ -109,13 +109,37 @@ func TestRouterConfig(t *testing.T) {
 					},{
 						"port": 123,
 						"outboundTag": "test"
+					},{
+						"schedule": {
+							"weekdays": ["fri-mon", "wed"],
+							"time": ["22:00-06:30", "12:00-24:00"],
+							"timezone": "UTC"
+						},
+						"outboundTag": "test"
+					},{
+						"domain": ["ruleset:ads"],
+						"ip": ["ruleset:nets", "10.0.0.0/8"],
+						"outboundTag": "test"
+					},{
+						"network": "tcp",
+						"outboundTag": "test",
+						"failover": {
+							"outboundTag": ["backup", "direct"],
+							"attempts": 2,
+							"timeout": "3s"
+						}
 					}
 				],
+				"ruleSets": [
+					{"tag": "ads", "path": "ads.txt", "reloadInterval": 60},
+					{"tag": "nets", "path": "lists.dat", "format": "geoip", "code": "private"}
+				],
 				"balancers": [
 					{
 						"tag": "b1",
 						"selector": ["test"],
-						"fallbackTag": "fall"
+						"fallbackTag": "fall",
+						"failover": {"outboundTag": "backup"}
 					},
 					{
 						"tag": "b2",
@@ -144,18 +168,35 @@ func TestRouterConfig(t *testing.T) {
 							}
 						},
 						"fallbackTag": "fall"
+					},
+					{
+						"tag": "b3",
+						"selector": ["test"],
+						"strategy": {
+							"type": "consistentHash",
+							"settings": {
+								"keys": ["sourceIP", "user"],
+								"loadFactor": 1.5,
+								"stickySeconds": 60
+							}
+						}
 					}
 				]
 			}`,
 			Parser: createParser(),
 			Output: &router.Config{
 				DomainStrategy: router.Config_AsIs,
+				RuleSet: []*router.RuleSet{
+					{Tag: "ads", Path: "ads.txt", ReloadInterval: 60},
+					{Tag: "nets", Path: "lists.dat", Format: router.RuleSet_GEOIP, Code: "private"},
+				},
 				BalancingRule: []*router.BalancingRule{
 					{
 						Tag:              "b1",
 						OutboundSelector: []string{"test"},
 						Strategy:         "random",
 						FallbackTag:      "fall",
+						Failover:         &router.Failover{OutboundTag: []string{"backup"}},
 					},
 					{
 						Tag:              "b2",
@@ -179,6 +220,19 @@ func TestRouterConfig(t *testing.T) {
 						}),
 						FallbackTag: "fall",
 					},
+					{
+						Tag:              "b3",
+						OutboundSelector: []string{"test"},
+						Strategy:         "consistenthash",
+						StrategySettings: serial.ToTypedMessage(&router.StrategyConsistentHashConfig{
+							Keys: []router.StrategyConsistentHashConfig_Key{
+								router.StrategyConsistentHashConfig_SOURCE_IP,
+								router.StrategyConsistentHashConfig_USER,
+							},
+							LoadFactor:    1.5,
+							StickySeconds: 60,
+						}),
+					},
 				},
 				Rule: []*router.RoutingRule{
 					{
@@ -237,6 +291,47 @@ func TestRouterConfig(t *testing.T) {
 							Tag: "test",
 						},
 					},
+					{
+						Schedule: &router.Schedule{
+							Weekdays: []uint32{5, 6, 0, 1, 3},
+							Ranges: []*router.Schedule_TimeRange{
+								{Start: 22 * 60, End: 6*60 + 30},
+								{Start: 12 * 60, End: 24 * 60},
+							},
+							Timezone: "UTC",
+						},
+						TargetTag: &router.RoutingRule_Tag{
+							Tag: "test",
+						},
+					},
+					{
+						DomainRuleSet: []string{"ads"},
+						IpRuleSet:     []string{"nets"},
+						Geoip: []*router.GeoIP{
+							{
+								Cidr: []*router.CIDR{
+									{
+										Ip:     []byte{10, 0, 0, 0},
+										Prefix: 8,
+									},
+								},
+							},
+						},
+						TargetTag: &router.RoutingRule_Tag{
+							Tag: "test",
+						},
+					},
+					{
+						Networks: []net.Network{net.Network_TCP},
+						TargetTag: &router.RoutingRule_Tag{
+							Tag: "test",
+						},
+						Failover: &router.Failover{
+							OutboundTag: []string{"backup", "direct"},
+							Attempts:    2,
+							Timeout:     3000,
+						},
+					},
 				},
 			},
 		},
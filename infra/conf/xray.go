@@ -539,6 +539,13 @@ func (c *Config) Build() (*core.Config, error) {
 		if err := rlConf.Apply(); err != nil {
 			return nil, errors.New("failed to apply ratelimit config").Base(err)
 		}
+		if rlConf.State != nil {
+			sc, err := rlConf.State.Build()
+			if err != nil {
+				return nil, errors.New("failed to build ratelimit state configuration").Base(err)
+			}
+			config.App = append(config.App, serial.ToTypedMessage(sc))
+		}
 	}
 
 	if c.Reverse != nil {
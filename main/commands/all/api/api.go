@@ -15,8 +15,15 @@ var CmdAPI = &base.Command{
 		cmdGetStats,
 		cmdQueryStats,
 		cmdSysStats,
+		cmdDNSStats,
+		cmdDNSAdd,
+		cmdDNSRemove,
+		cmdDNSCache,
+		cmdDNSResolve,
 		cmdBalancerInfo,
 		cmdBalancerOverride,
+		cmdAddBalancers,
+		cmdRemoveBalancers,
 		cmdAddInbounds,
 		cmdAddOutbounds,
 		cmdRemoveInbounds,
@@ -29,8 +36,13 @@ var CmdAPI = &base.Command{
 		cmdInboundUserCount,
 		cmdAddRules,
 		cmdRemoveRules,
+		cmdListRules,
+		cmdInsertRules,
+		cmdReplaceRules,
+		cmdRouteTrace,
 		cmdSourceIpBlock,
 		cmdOnlineStats,
 		cmdOnlineStatsIpList,
+		cmdRateLimit,
 	},
 }
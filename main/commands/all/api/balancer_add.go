@@ -0,0 +1,99 @@
+package api
+
+import (
+	"fmt"
+
+	routerService "github.com/xtls/xray-core/app/router/command"
+	cserial "github.com/xtls/xray-core/common/serial"
+	"github.com/xtls/xray-core/infra/conf/serial"
+	"github.com/xtls/xray-core/main/commands/base"
+)
+
+var cmdAddBalancers = &base.Command{
+	CustomFlags: true,
+	UsageLine:   "{{.Exec}} api adb [--server=127.0.0.1:8080] [-update] <c1.json> [c2.json]...",
+	Short:       "Add or update balancers",
+	Long: `
+Add the balancers found in "routing.balancers" of the given configs to
+Xray, or replace existing balancers with the same tags.
+
+> Ensure that the "RoutingService" is properly configured under "config.api.services" in the server configuration.
+
+Arguments:
+
+	-s, -server <server:port>
+		The API server address. Default 127.0.0.1:8080
+
+	-t, -timeout <seconds>
+		Timeout in seconds for calling API. Default 3
+
+	-u, -update
+		Replace existing balancers instead of adding new ones. Rules using
+		a balancer switch to the new one at once, an override is kept.
+
+Example:
+
+	{{.Exec}} {{.LongName}} --server=127.0.0.1:8080 c1.json c2.json
+	{{.Exec}} {{.LongName}} --server=127.0.0.1:8080 -update c1.json
+`,
+	Run: executeAddBalancers,
+}
+
+func executeAddBalancers(cmd *base.Command, args []string) {
+	var update bool
+	setSharedFlags(cmd)
+	cmd.Flag.BoolVar(&update, "u", false, "")
+	cmd.Flag.BoolVar(&update, "update", false, "")
+	cmd.Flag.Parse(args)
+
+	unnamedArgs := cmd.Flag.Args()
+	if len(unnamedArgs) == 0 {
+		fmt.Println("reading from stdin:")
+		unnamedArgs = []string{"stdin:"}
+	}
+
+	var configs []*cserial.TypedMessage
+	for _, arg := range unnamedArgs {
+		r, err := loadArg(arg)
+		if err != nil {
+			base.Fatalf("failed to load %s: %s", arg, err)
+		}
+		c, err := serial.DecodeJSONConfig(r)
+		if err != nil {
+			base.Fatalf("failed to decode %s: %s", arg, err)
+		}
+		if c.RouterConfig == nil {
+			continue
+		}
+		for _, b := range c.RouterConfig.Balancers {
+			br, err := b.Build()
+			if err != nil {
+				base.Fatalf("failed to build conf: %s", err)
+			}
+			configs = append(configs, cserial.ToTypedMessage(br))
+		}
+	}
+	if len(configs) == 0 {
+		base.Fatalf("no valid balancer found in config")
+	}
+
+	conn, ctx, close := dialAPIServer()
+	defer close()
+
+	client := routerService.NewRoutingServiceClient(conn)
+	for _, config := range configs {
+		if update {
+			resp, err := client.UpdateBalancer(ctx, &routerService.UpdateBalancerRequest{Config: config})
+			if err != nil {
+				base.Fatalf("failed to perform UpdateBalancer: %s", err)
+			}
+			showJSONResponse(resp)
+			continue
+		}
+		resp, err := client.AddBalancer(ctx, &routerService.AddBalancerRequest{Config: config})
+		if err != nil {
+			base.Fatalf("failed to perform AddBalancer: %s", err)
+		}
+		showJSONResponse(resp)
+	}
+}
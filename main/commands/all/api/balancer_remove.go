@@ -0,0 +1,53 @@
+package api
+
+import (
+	routerService "github.com/xtls/xray-core/app/router/command"
+	"github.com/xtls/xray-core/main/commands/base"
+)
+
+var cmdRemoveBalancers = &base.Command{
+	CustomFlags: true,
+	UsageLine:   "{{.Exec}} api rmb [--server=127.0.0.1:8080] <balancerTag>...",
+	Short:       "Remove balancers",
+	Long: `
+Remove balancers by tag from Xray. A balancer still used by a routing
+rule cannot be removed; remove or replace the rule first.
+
+> Ensure that the "RoutingService" is properly configured under "config.api.services" in the server configuration.
+
+Arguments:
+
+	-s, -server <server:port>
+		The API server address. Default 127.0.0.1:8080
+
+	-t, -timeout <seconds>
+		Timeout in seconds for calling API. Default 3
+
+Example:
+
+	{{.Exec}} {{.LongName}} --server=127.0.0.1:8080 balancer1 balancer2
+`,
+	Run: executeRemoveBalancers,
+}
+
+func executeRemoveBalancers(cmd *base.Command, args []string) {
+	setSharedFlags(cmd)
+	cmd.Flag.Parse(args)
+
+	tags := cmd.Flag.Args()
+	if len(tags) == 0 {
+		base.Fatalf("balancer tag not specified")
+	}
+
+	conn, ctx, close := dialAPIServer()
+	defer close()
+
+	client := routerService.NewRoutingServiceClient(conn)
+	for _, tag := range tags {
+		resp, err := client.RemoveBalancer(ctx, &routerService.RemoveBalancerRequest{BalancerTag: tag})
+		if err != nil {
+			base.Fatalf("failed to perform RemoveBalancer: %s", err)
+		}
+		showJSONResponse(resp)
+	}
+}
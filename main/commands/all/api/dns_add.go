@@ -0,0 +1,81 @@
+package api
+
+import (
+	"fmt"
+
+	dnsService "github.com/xtls/xray-core/app/dns/command"
+	"github.com/xtls/xray-core/infra/conf/serial"
+	"github.com/xtls/xray-core/main/commands/base"
+)
+
+var cmdDNSAdd = &base.Command{
+	CustomFlags: true,
+	UsageLine:   "{{.Exec}} api dns-add [--server=127.0.0.1:8080] <c1.json> [c2.json]...",
+	Short:       "Add DNS hosts and nameservers",
+	Long: `
+Add the hosts and nameservers in the "dns" object of the configs to Xray.
+They are added after the existing ones. Options a nameserver doesn't set
+are taken from the running config.
+
+Arguments:
+
+	-s, -server <server:port>
+		The API server address. Default 127.0.0.1:8080
+
+	-t, -timeout <seconds>
+		Timeout in seconds for calling API. Default 3
+
+Example:
+
+	{{.Exec}} {{.LongName}} --server=127.0.0.1:8080 c1.json c2.json
+`,
+	Run: executeDNSAdd,
+}
+
+func executeDNSAdd(cmd *base.Command, args []string) {
+	setSharedFlags(cmd)
+	cmd.Flag.Parse(args)
+
+	unnamedArgs := cmd.Flag.Args()
+	if len(unnamedArgs) == 0 {
+		fmt.Println("reading from stdin:")
+		unnamedArgs = []string{"stdin:"}
+	}
+	conn, ctx, close := dialAPIServer()
+	defer close()
+
+	client := dnsService.NewDNSServiceClient(conn)
+
+	for _, arg := range unnamedArgs {
+		r, err := loadArg(arg)
+		if err != nil {
+			base.Fatalf("failed to load %s: %s", arg, err)
+		}
+		conf, err := serial.DecodeJSONConfig(r)
+		if err != nil {
+			base.Fatalf("failed to decode %s: %s", arg, err)
+		}
+		if conf.DNSConfig == nil {
+			base.Fatalf("no dns config found in %s", arg)
+		}
+		config, err := conf.DNSConfig.Build()
+		if err != nil {
+			base.Fatalf("failed to build conf: %s", err)
+		}
+
+		if len(config.StaticHosts) > 0 {
+			resp, err := client.AddHosts(ctx, &dnsService.AddHostsRequest{Hosts: config.StaticHosts})
+			if err != nil {
+				base.Fatalf("failed to perform AddHosts: %s", err)
+			}
+			showJSONResponse(resp)
+		}
+		if len(config.NameServer) > 0 {
+			resp, err := client.AddNameServer(ctx, &dnsService.AddNameServerRequest{NameServer: config.NameServer})
+			if err != nil {
+				base.Fatalf("failed to perform AddNameServer: %s", err)
+			}
+			showJSONResponse(resp)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package api
+
+import (
+	dnsService "github.com/xtls/xray-core/app/dns/command"
+	"github.com/xtls/xray-core/main/commands/base"
+)
+
+var cmdDNSCache = &base.Command{
+	CustomFlags: true,
+	UsageLine:   "{{.Exec}} api dns-cache [--server=127.0.0.1:8080] [-ns ''] [-domain ''] [-flush]",
+	Short:       "List or flush the DNS cache",
+	Long: `
+List the answers in the DNS cache of Xray, or remove them with -flush.
+
+Arguments:
+
+	-s, -server <server:port>
+		The API server address. Default 127.0.0.1:8080
+
+	-t, -timeout <seconds>
+		Timeout in seconds for calling API. Default 3
+
+	-ns <name>
+		Only the cache of the nameserver with this tag, or server name if
+		it has none. Default all nameservers
+
+	-domain <domain>
+		Only the answers for this domain. Default all domains
+
+	-flush
+		Remove the answers instead of listing them. Default false
+
+Example:
+
+	{{.Exec}} {{.LongName}} --server=127.0.0.1:8080 -domain example.com -flush
+`,
+	Run: executeDNSCache,
+}
+
+func executeDNSCache(cmd *base.Command, args []string) {
+	setSharedFlags(cmd)
+	ns := cmd.Flag.String("ns", "", "")
+	domain := cmd.Flag.String("domain", "", "")
+	flush := cmd.Flag.Bool("flush", false, "")
+	cmd.Flag.Parse(args)
+
+	conn, ctx, close := dialAPIServer()
+	defer close()
+
+	client := dnsService.NewDNSServiceClient(conn)
+	if *flush {
+		resp, err := client.FlushCache(ctx, &dnsService.FlushCacheRequest{Server: *ns, Domain: *domain})
+		if err != nil {
+			base.Fatalf("failed to flush dns cache: %s", err)
+		}
+		showJSONResponse(resp)
+		return
+	}
+	resp, err := client.ListCache(ctx, &dnsService.ListCacheRequest{Server: *ns, Domain: *domain})
+	if err != nil {
+		base.Fatalf("failed to list dns cache: %s", err)
+	}
+	showJSONResponse(resp)
+}
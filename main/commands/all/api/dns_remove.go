@@ -0,0 +1,86 @@
+package api
+
+import (
+	"encoding/json"
+
+	dnsService "github.com/xtls/xray-core/app/dns/command"
+	"github.com/xtls/xray-core/infra/conf"
+	"github.com/xtls/xray-core/main/commands/base"
+)
+
+var cmdDNSRemove = &base.Command{
+	CustomFlags: true,
+	UsageLine:   "{{.Exec}} api dns-remove [--server=127.0.0.1:8080] [-hosts] <name>...",
+	Short:       "Remove DNS nameservers or hosts",
+	Long: `
+Remove DNS nameservers by tag, or by server name for those without one, from
+Xray. With -hosts, remove the hosts of domains instead.
+
+Arguments:
+
+	-s, -server <server:port>
+		The API server address. Default 127.0.0.1:8080
+
+	-t, -timeout <seconds>
+		Timeout in seconds for calling API. Default 3
+
+	-hosts
+		The names are domains of "hosts", as in the config, like
+		"domain:example.com". Default false
+
+Example:
+
+	{{.Exec}} {{.LongName}} --server=127.0.0.1:8080 "UDP:8.8.8.8:53" dns-tag
+	{{.Exec}} {{.LongName}} --server=127.0.0.1:8080 -hosts "domain:example.com"
+`,
+	Run: executeDNSRemove,
+}
+
+func executeDNSRemove(cmd *base.Command, args []string) {
+	var hosts bool
+	setSharedFlags(cmd)
+	cmd.Flag.BoolVar(&hosts, "hosts", false, "")
+	cmd.Flag.Parse(args)
+
+	names := cmd.Flag.Args()
+	if len(names) == 0 {
+		base.Fatalf("no name to remove")
+	}
+	conn, ctx, close := dialAPIServer()
+	defer close()
+
+	client := dnsService.NewDNSServiceClient(conn)
+
+	if hosts {
+		// any address builds the mappings, only their domains count
+		m := make(map[string]string, len(names))
+		for _, name := range names {
+			m[name] = "127.0.0.1"
+		}
+		data, err := json.Marshal(m)
+		if err != nil {
+			base.Fatalf("failed to encode hosts: %s", err)
+		}
+		var wrapper conf.HostsWrapper
+		if err := json.Unmarshal(data, &wrapper); err != nil {
+			base.Fatalf("failed to decode hosts: %s", err)
+		}
+		mappings, err := wrapper.Build()
+		if err != nil {
+			base.Fatalf("failed to build hosts: %s", err)
+		}
+		resp, err := client.RemoveHosts(ctx, &dnsService.RemoveHostsRequest{Hosts: mappings})
+		if err != nil {
+			base.Fatalf("failed to perform RemoveHosts: %s", err)
+		}
+		showJSONResponse(resp)
+		return
+	}
+	for _, name := range names {
+		resp, err := client.RemoveNameServer(ctx, &dnsService.RemoveNameServerRequest{Name: name})
+		if err != nil {
+			base.Fatalf("failed to perform RemoveNameServer: %s", err)
+		}
+		showJSONResponse(resp)
+	}
+}
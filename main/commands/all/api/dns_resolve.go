@@ -0,0 +1,65 @@
+package api
+
+import (
+	"strings"
+
+	"github.com/xtls/xray-core/app/dns"
+	dnsService "github.com/xtls/xray-core/app/dns/command"
+	"github.com/xtls/xray-core/main/commands/base"
+)
+
+var cmdDNSResolve = &base.Command{
+	CustomFlags: true,
+	UsageLine:   "{{.Exec}} api dns-resolve [--server=127.0.0.1:8080] [-strategy UseIP] <domain>",
+	Short:       "Resolve a domain through the DNS of Xray",
+	Long: `
+Resolve a domain through the DNS of Xray, like a connection would, and show
+the nameservers it goes to.
+
+Arguments:
+
+	-s, -server <server:port>
+		The API server address. Default 127.0.0.1:8080
+
+	-t, -timeout <seconds>
+		Timeout in seconds for calling API. Default 3
+
+	-strategy <UseIP|UseIPv4|UseIPv6>
+		The address families to query. Default UseIP
+
+Example:
+
+	{{.Exec}} {{.LongName}} --server=127.0.0.1:8080 -strategy UseIPv4 example.com
+`,
+	Run: executeDNSResolve,
+}
+
+func executeDNSResolve(cmd *base.Command, args []string) {
+	setSharedFlags(cmd)
+	strategy := cmd.Flag.String("strategy", "UseIP", "")
+	cmd.Flag.Parse(args)
+
+	if cmd.Flag.NArg() != 1 {
+		base.Fatalf("expected exactly one domain")
+	}
+	r := &dnsService.ResolveRequest{Domain: cmd.Flag.Arg(0)}
+	switch strings.ToLower(*strategy) {
+	case "useip":
+	case "useipv4", "useip4":
+		r.QueryStrategy = dns.QueryStrategy_USE_IP4
+	case "useipv6", "useip6":
+		r.QueryStrategy = dns.QueryStrategy_USE_IP6
+	default:
+		base.Fatalf("unknown strategy %s", *strategy)
+	}
+
+	conn, ctx, close := dialAPIServer()
+	defer close()
+
+	client := dnsService.NewDNSServiceClient(conn)
+	resp, err := client.Resolve(ctx, r)
+	if err != nil {
+		base.Fatalf("failed to resolve: %s", err)
+	}
+	showJSONResponse(resp)
+}
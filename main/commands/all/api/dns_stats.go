@@ -0,0 +1,57 @@
+package api
+
+import (
+	statsService "github.com/xtls/xray-core/app/stats/command"
+	"github.com/xtls/xray-core/main/commands/base"
+)
+
+var cmdDNSStats = &base.Command{
+	CustomFlags: true,
+	UsageLine:   "{{.Exec}} api dns-stats [--server=127.0.0.1:8080] [-name '']",
+	Short:       "Retrieve DNS statistics",
+	Long: `
+Retrieve the query statistics of the DNS nameservers: queries, errors,
+cache hits and the latency of queries sent. Needs "stats" in the config.
+
+Arguments:
+
+	-s, -server <server:port>
+		The API server address. Default 127.0.0.1:8080
+
+	-t, -timeout <seconds>
+		Timeout in seconds for calling API. Default 3
+
+	-name
+		Tag of the nameserver, or its server name if it has no tag.
+		Default all nameservers
+
+	-reset
+		Reset the counters after fetching their values. Default false
+
+Example:
+
+	{{.Exec}} {{.LongName}} --server=127.0.0.1:8080 -name "UDP:8.8.8.8:53"
+`,
+	Run: executeDNSStats,
+}
+
+func executeDNSStats(cmd *base.Command, args []string) {
+	setSharedFlags(cmd)
+	name := cmd.Flag.String("name", "", "")
+	reset := cmd.Flag.Bool("reset", false, "")
+	cmd.Flag.Parse(args)
+
+	conn, ctx, close := dialAPIServer()
+	defer close()
+
+	client := statsService.NewStatsServiceClient(conn)
+	r := &statsService.GetDNSStatsRequest{
+		Name:   *name,
+		Reset_: *reset,
+	}
+	resp, err := client.GetDNSStats(ctx, r)
+	if err != nil {
+		base.Fatalf("failed to get dns stats: %s", err)
+	}
+	showJSONResponse(resp)
+}
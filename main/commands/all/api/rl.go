@@ -0,0 +1,149 @@
+package api
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	ratelimitpb "github.com/xtls/xray-core/app/ratelimit/api"
+	"github.com/xtls/xray-core/main/commands/base"
+)
+
+var cmdRateLimit = &base.Command{
+	UsageLine: "{{.Exec}} api rl",
+	Short:     "Manage rate limits via RateLimitService",
+	Long: `{{.Exec}} {{.LongName}} calls the RateLimitService of an Xray process.
+
+> Ensure that "RateLimitService" is enabled under "config.api.services" in the server configuration.
+
+Rates accept plain bits per second or a K/M/G suffix (decimal), e.g. 512K, 10M, 1G.
+`,
+	Commands: []*base.Command{
+		cmdRLSetDefault,
+		cmdRLClearDefault,
+		cmdRLSetDevice,
+		cmdRLClearDevice,
+		cmdRLSetTotal,
+		cmdRLClearTotal,
+		cmdRLDevices,
+		cmdRLUserStats,
+		cmdRLSnapshot,
+		cmdRLKeyMode,
+		cmdRLGrace,
+		cmdRLClearEgress,
+		cmdRLQuota,
+		cmdRLMaxDevices,
+		cmdRLSchedule,
+		cmdRLShape,
+		cmdRLEgress,
+		cmdRLEvents,
+	},
+}
+
+// parseBps parses "10M", "512k", "1G" or a plain number of bits per second.
+func parseBps(s string) (uint64, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, nil
+	}
+	mult := uint64(1)
+	switch s[len(s)-1] {
+	case 'k', 'K':
+		mult = 1000
+	case 'm', 'M':
+		mult = 1000 * 1000
+	case 'g', 'G':
+		mult = 1000 * 1000 * 1000
+	}
+	if mult != 1 {
+		s = s[:len(s)-1]
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil || v < 0 {
+		return 0, fmt.Errorf("invalid rate: %q", s)
+	}
+	return uint64(v * float64(mult)), nil
+}
+
+func mustParseBps(name, s string) uint64 {
+	v, err := parseBps(s)
+	if err != nil {
+		base.Fatalf("-%s: %s", name, err)
+	}
+	return v
+}
+
+func formatBps(v uint64) string {
+	switch {
+	case v == 0:
+		return "unlimited"
+	case v >= 1000*1000*1000:
+		return strconv.FormatFloat(float64(v)/1e9, 'f', -1, 64) + "G"
+	case v >= 1000*1000:
+		return strconv.FormatFloat(float64(v)/1e6, 'f', -1, 64) + "M"
+	case v >= 1000:
+		return strconv.FormatFloat(float64(v)/1e3, 'f', -1, 64) + "K"
+	}
+	return strconv.FormatUint(v, 10)
+}
+
+func formatBytes(v uint64) string {
+	const unit = 1024
+	if v < unit {
+		return fmt.Sprintf("%dB", v)
+	}
+	div, exp := uint64(unit), 0
+	for n := v / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f%ciB", float64(v)/float64(div), "KMGTPE"[exp])
+}
+
+func formatUnix(v uint64) string {
+	if v == 0 {
+		return "-"
+	}
+	return time.Unix(int64(v), 0).Format("2006-01-02 15:04:05")
+}
+
+func showDevices(devices []*ratelimitpb.DeviceInfo) {
+	titles := []string{"UUID", "Source IP", "Conn ID", "Refs", "Started", "Last Seen", "Rx", "Tx"}
+	widths := make([]int, len(titles))
+	rows := make([][]string, 0, len(devices))
+	for i, t := range titles {
+		widths[i] = len(t)
+	}
+	for _, d := range devices {
+		row := []string{
+			d.Uuid,
+			d.SrcIp,
+			strconv.FormatUint(d.ConnId, 10),
+			strconv.FormatUint(uint64(d.RefCount), 10),
+			formatUnix(d.StartedAtUnix),
+			formatUnix(d.LastSeenUnix),
+			formatBytes(d.RxBytes),
+			formatBytes(d.TxBytes),
+		}
+		for i, v := range row {
+			if len(v) > widths[i] {
+				widths[i] = len(v)
+			}
+		}
+		rows = append(rows, row)
+	}
+
+	formats := make([]string, len(titles))
+	for i, w := range widths {
+		formats[i] = fmt.Sprintf("%%-%ds ", w)
+	}
+
+	sb := new(strings.Builder)
+	writeRow(sb, 0, 0, titles, formats)
+	for i, row := range rows {
+		writeRow(sb, 0, i+1, row, formats)
+	}
+	os.Stdout.WriteString(sb.String())
+}
@@ -0,0 +1,89 @@
+package api
+
+import (
+	"fmt"
+
+	ratelimitpb "github.com/xtls/xray-core/app/ratelimit/api"
+	"github.com/xtls/xray-core/main/commands/base"
+)
+
+var cmdRLClearDefault = &base.Command{
+	CustomFlags: true,
+	UsageLine:   "{{.Exec}} api rl cleardefault [--server=127.0.0.1:8080] [-all] [-overrides] <uuid>...",
+	Short:       "Clear users' default limits",
+	Long: `
+Clear the default per-device limit of the given users.
+
+Arguments:
+
+	-s, -server <server:port>
+		The API server address. Default 127.0.0.1:8080
+
+	-t, -timeout <seconds>
+		Timeout in seconds for calling API. Default 3
+
+	-overrides
+		Also clear per-device overrides and total limits of these users.
+
+	-all
+		Clear every limit on the server. No uuid is needed.
+
+Example:
+
+	{{.Exec}} {{.LongName}} --server=127.0.0.1:8080 "user1@example" "user2@example"
+	{{.Exec}} {{.LongName}} --server=127.0.0.1:8080 -overrides "user1@example"
+	{{.Exec}} {{.LongName}} --server=127.0.0.1:8080 -all
+`,
+	Run: executeRLClearDefault,
+}
+
+func executeRLClearDefault(cmd *base.Command, args []string) {
+	var all, overrides bool
+	setSharedFlags(cmd)
+	cmd.Flag.BoolVar(&all, "all", false, "")
+	cmd.Flag.BoolVar(&overrides, "overrides", false, "")
+	cmd.Flag.Parse(args)
+	uuids := cmd.Flag.Args()
+
+	if !all && len(uuids) == 0 {
+		base.Fatalf("uuid not specified")
+	}
+
+	conn, ctx, close := dialAPIServer()
+	defer close()
+
+	client := ratelimitpb.NewRateLimitServiceClient(conn)
+
+	switch {
+	case all:
+		resp, err := client.ClearAllRateLimits(ctx, &ratelimitpb.ClearAllRateLimitsRequest{})
+		if err != nil {
+			base.Fatalf("failed to clear rate limits: %s", err)
+		}
+		if apiJSON {
+			showJSONResponse(resp)
+			return
+		}
+		fmt.Printf("cleared %d default(s), %d override(s)\n", resp.ClearedDefaults, resp.ClearedOverrides)
+	case overrides:
+		resp, err := client.ClearUserRateLimits(ctx, &ratelimitpb.ClearUserRateLimitsRequest{Uuids: uuids})
+		if err != nil {
+			base.Fatalf("failed to clear user rate limits: %s", err)
+		}
+		if apiJSON {
+			showJSONResponse(resp)
+			return
+		}
+		fmt.Printf("cleared %d default(s), %d override(s)\n", resp.ClearedDefaults, resp.ClearedOverrides)
+	default:
+		for _, uuid := range uuids {
+			resp, err := client.ClearUserDefaultPerConnLimit(ctx, &ratelimitpb.ClearUserDefaultPerConnLimitRequest{Uuid: uuid})
+			if err != nil {
+				base.Fatalf("failed to clear default limit of %s: %s", uuid, err)
+			}
+			if apiJSON {
+				showJSONResponse(resp)
+			}
+		}
+	}
+}
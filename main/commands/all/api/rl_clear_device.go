@@ -0,0 +1,52 @@
+package api
+
+import (
+	ratelimitpb "github.com/xtls/xray-core/app/ratelimit/api"
+	"github.com/xtls/xray-core/main/commands/base"
+)
+
+var cmdRLClearDevice = &base.Command{
+	CustomFlags: true,
+	UsageLine:   "{{.Exec}} api rl cleardevice [--server=127.0.0.1:8080] <device_key>...",
+	Short:       "Clear device limits",
+	Long: `
+Remove the per-device override of the given devices. They fall back to
+their user's default limit.
+
+Arguments:
+
+	-s, -server <server:port>
+		The API server address. Default 127.0.0.1:8080
+
+	-t, -timeout <seconds>
+		Timeout in seconds for calling API. Default 3
+
+Example:
+
+	{{.Exec}} {{.LongName}} --server=127.0.0.1:8080 "user@example|203.0.113.5"
+`,
+	Run: executeRLClearDevice,
+}
+
+func executeRLClearDevice(cmd *base.Command, args []string) {
+	setSharedFlags(cmd)
+	cmd.Flag.Parse(args)
+	keys := cmd.Flag.Args()
+	if len(keys) == 0 {
+		base.Fatalf("device key not specified")
+	}
+
+	conn, ctx, close := dialAPIServer()
+	defer close()
+
+	client := ratelimitpb.NewRateLimitServiceClient(conn)
+	for _, key := range keys {
+		resp, err := client.ClearDeviceLimit(ctx, &ratelimitpb.ClearDeviceLimitRequest{DeviceKey: key})
+		if err != nil {
+			base.Fatalf("failed to clear limit of %s: %s", key, err)
+		}
+		if apiJSON {
+			showJSONResponse(resp)
+		}
+	}
+}
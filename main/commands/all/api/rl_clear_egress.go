@@ -0,0 +1,56 @@
+package api
+
+import (
+	"fmt"
+
+	ratelimitpb "github.com/xtls/xray-core/app/ratelimit/api"
+	"github.com/xtls/xray-core/main/commands/base"
+)
+
+var cmdRLClearEgress = &base.Command{
+	CustomFlags: true,
+	UsageLine:   "{{.Exec}} api rl clearegress [--server=127.0.0.1:8080] <uuid>...",
+	Short:       "Clear users' sticky egress bindings",
+	Long: `
+Forget the outbound each device of the given users is pinned to. The next
+connection of each device picks an outbound again.
+
+Arguments:
+
+	-s, -server <server:port>
+		The API server address. Default 127.0.0.1:8080
+
+	-t, -timeout <seconds>
+		Timeout in seconds for calling API. Default 3
+
+Example:
+
+	{{.Exec}} {{.LongName}} --server=127.0.0.1:8080 "user@example"
+`,
+	Run: executeRLClearEgress,
+}
+
+func executeRLClearEgress(cmd *base.Command, args []string) {
+	setSharedFlags(cmd)
+	cmd.Flag.Parse(args)
+	uuids := cmd.Flag.Args()
+	if len(uuids) == 0 {
+		base.Fatalf("uuid not specified")
+	}
+
+	conn, ctx, close := dialAPIServer()
+	defer close()
+
+	client := ratelimitpb.NewRateLimitServiceClient(conn)
+	for _, uuid := range uuids {
+		resp, err := client.ClearUserEgressCache(ctx, &ratelimitpb.ClearUserEgressCacheRequest{Uuid: uuid})
+		if err != nil {
+			base.Fatalf("failed to clear egress cache of %s: %s", uuid, err)
+		}
+		if apiJSON {
+			showJSONResponse(resp)
+			continue
+		}
+		fmt.Printf("%s: cleared %d binding(s)\n", uuid, resp.Cleared)
+	}
+}
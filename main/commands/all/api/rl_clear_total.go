@@ -0,0 +1,92 @@
+package api
+
+import (
+	"fmt"
+
+	ratelimitpb "github.com/xtls/xray-core/app/ratelimit/api"
+	"github.com/xtls/xray-core/main/commands/base"
+)
+
+var cmdRLClearTotal = &base.Command{
+	CustomFlags: true,
+	UsageLine:   "{{.Exec}} api rl cleartotal [--server=127.0.0.1:8080] [-uuid <uuid> | -inbound <tag> | -global]",
+	Short:       "Clear an aggregate limit",
+	Long: `
+Remove an aggregate limit set by "rl settotal".
+
+Arguments:
+
+	-s, -server <server:port>
+		The API server address. Default 127.0.0.1:8080
+
+	-t, -timeout <seconds>
+		Timeout in seconds for calling API. Default 3
+
+	-uuid
+		Clear the user's aggregate limit.
+
+	-inbound
+		Clear the inbound's aggregate limit.
+
+	-global
+		Clear the server-wide limit.
+
+Example:
+
+	{{.Exec}} {{.LongName}} --server=127.0.0.1:8080 -uuid "user@example"
+`,
+	Run: executeRLClearTotal,
+}
+
+func executeRLClearTotal(cmd *base.Command, args []string) {
+	var uuid, inbound string
+	var global bool
+	setSharedFlags(cmd)
+	cmd.Flag.StringVar(&uuid, "uuid", "", "")
+	cmd.Flag.StringVar(&inbound, "inbound", "", "")
+	cmd.Flag.BoolVar(&global, "global", false, "")
+	cmd.Flag.Parse(args)
+
+	conn, ctx, close := dialAPIServer()
+	defer close()
+
+	client := ratelimitpb.NewRateLimitServiceClient(conn)
+	var cleared bool
+	switch {
+	case uuid != "":
+		resp, err := client.ClearUserTotalLimit(ctx, &ratelimitpb.ClearUserTotalLimitRequest{Uuid: uuid})
+		if err != nil {
+			base.Fatalf("failed to clear user total limit: %s", err)
+		}
+		if apiJSON {
+			showJSONResponse(resp)
+			return
+		}
+		cleared = resp.Cleared
+	case inbound != "":
+		resp, err := client.ClearInboundTotalLimit(ctx, &ratelimitpb.ClearInboundTotalLimitRequest{InboundTag: inbound})
+		if err != nil {
+			base.Fatalf("failed to clear inbound total limit: %s", err)
+		}
+		if apiJSON {
+			showJSONResponse(resp)
+			return
+		}
+		cleared = resp.Cleared
+	case global:
+		resp, err := client.ClearGlobalTotalLimit(ctx, &ratelimitpb.ClearGlobalTotalLimitRequest{})
+		if err != nil {
+			base.Fatalf("failed to clear global total limit: %s", err)
+		}
+		if apiJSON {
+			showJSONResponse(resp)
+			return
+		}
+		cleared = resp.Cleared
+	default:
+		base.Fatalf("one of -uuid, -inbound or -global must be specified")
+	}
+	if !cleared {
+		fmt.Println("no such limit")
+	}
+}
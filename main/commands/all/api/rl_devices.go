@@ -0,0 +1,70 @@
+package api
+
+import (
+	ratelimitpb "github.com/xtls/xray-core/app/ratelimit/api"
+	"github.com/xtls/xray-core/main/commands/base"
+)
+
+var cmdRLDevices = &base.Command{
+	CustomFlags: true,
+	UsageLine:   "{{.Exec}} api rl devices [--server=127.0.0.1:8080] [-json] [-uuid <uuid>]",
+	Short:       "List devices",
+	Long: `
+List the devices known to the rate limiter, including devices in their
+grace period. Traffic counters are not reset.
+
+Arguments:
+
+	-s, -server <server:port>
+		The API server address. Default 127.0.0.1:8080
+
+	-t, -timeout <seconds>
+		Timeout in seconds for calling API. Default 3
+
+	-json
+		Print the raw JSON response.
+
+	-uuid
+		Only list devices of this user. Default: all devices.
+
+Example:
+
+	{{.Exec}} {{.LongName}} --server=127.0.0.1:8080 -uuid "user@example"
+`,
+	Run: executeRLDevices,
+}
+
+func executeRLDevices(cmd *base.Command, args []string) {
+	var uuid string
+	setSharedFlags(cmd)
+	cmd.Flag.StringVar(&uuid, "uuid", "", "")
+	cmd.Flag.Parse(args)
+
+	conn, ctx, close := dialAPIServer()
+	defer close()
+
+	client := ratelimitpb.NewRateLimitServiceClient(conn)
+	var devices []*ratelimitpb.DeviceInfo
+	if uuid != "" {
+		resp, err := client.ListUserDevices(ctx, &ratelimitpb.ListUserDevicesRequest{Uuid: uuid})
+		if err != nil {
+			base.Fatalf("failed to list user devices: %s", err)
+		}
+		if apiJSON {
+			showJSONResponse(resp)
+			return
+		}
+		devices = resp.Devices
+	} else {
+		resp, err := client.PeekActiveDevicesSnapshot(ctx, &ratelimitpb.GetActiveDevicesSnapshotRequest{})
+		if err != nil {
+			base.Fatalf("failed to list devices: %s", err)
+		}
+		if apiJSON {
+			showJSONResponse(resp)
+			return
+		}
+		devices = resp.Devices
+	}
+	showDevices(devices)
+}
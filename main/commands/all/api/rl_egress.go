@@ -0,0 +1,122 @@
+package api
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	ratelimitpb "github.com/xtls/xray-core/app/ratelimit/api"
+	"github.com/xtls/xray-core/main/commands/base"
+)
+
+var cmdRLEgress = &base.Command{
+	CustomFlags: true,
+	UsageLine:   "{{.Exec}} api rl egress [--server=127.0.0.1:8080] [-json] [-set <tag> uuid | -clear uuid]",
+	Short:       "Get or manage egress bindings of users",
+	Long: `
+List the egress chooser configuration and the uuid -> outbound bindings,
+or pin/unpin a user to an outbound. Changing a binding drops the egress
+already chosen for devices of the user.
+
+Arguments:
+
+	-s, -server <server:port>
+		The API server address. Default 127.0.0.1:8080
+
+	-t, -timeout <seconds>
+		Timeout in seconds for calling API. Default 3
+
+	-json
+		Print the raw JSON response.
+
+	-set <tag>
+		Bind the user to the outbound.
+
+	-clear
+		Remove the binding of the user.
+
+Example:
+
+	{{.Exec}} {{.LongName}} --server=127.0.0.1:8080
+	{{.Exec}} {{.LongName}} --server=127.0.0.1:8080 -set proxy-de "user@example"
+	{{.Exec}} {{.LongName}} --server=127.0.0.1:8080 -clear "user@example"
+`,
+	Run: executeRLEgress,
+}
+
+func executeRLEgress(cmd *base.Command, args []string) {
+	var (
+		setTag string
+		clear  bool
+	)
+	setSharedFlags(cmd)
+	cmd.Flag.StringVar(&setTag, "set", "", "")
+	cmd.Flag.BoolVar(&clear, "clear", false, "")
+	cmd.Flag.Parse(args)
+
+	uuid := cmd.Flag.Arg(0)
+	if (setTag != "" || clear) && uuid == "" {
+		base.Fatalf("uuid not specified")
+	}
+	if setTag != "" && clear {
+		base.Fatalf("-set and -clear are mutually exclusive")
+	}
+
+	conn, ctx, close := dialAPIServer()
+	defer close()
+
+	client := ratelimitpb.NewRateLimitServiceClient(conn)
+	switch {
+	case setTag != "":
+		resp, err := client.SetEgressBinding(ctx, &ratelimitpb.SetEgressBindingRequest{Uuid: uuid, OutboundTag: setTag})
+		if err != nil {
+			base.Fatalf("failed to set egress binding: %s", err)
+		}
+		if apiJSON {
+			showJSONResponse(resp)
+		}
+		return
+	case clear:
+		resp, err := client.ClearEgressBinding(ctx, &ratelimitpb.ClearEgressBindingRequest{Uuid: uuid})
+		if err != nil {
+			base.Fatalf("failed to clear egress binding: %s", err)
+		}
+		if apiJSON {
+			showJSONResponse(resp)
+		} else if !resp.Cleared {
+			fmt.Println("no binding")
+		}
+		return
+	}
+
+	resp, err := client.ListEgressBindings(ctx, &ratelimitpb.ListEgressBindingsRequest{})
+	if err != nil {
+		base.Fatalf("failed to list egress bindings: %s", err)
+	}
+	if apiJSON {
+		showJSONResponse(resp)
+		return
+	}
+
+	fmt.Printf("strategy: %s\n", strings.ToLower(resp.Strategy.String()))
+	if len(resp.Outbounds) > 0 {
+		outbounds := make([]string, 0, len(resp.Outbounds))
+		for _, tag := range resp.Outbounds {
+			if w, ok := resp.Weights[tag]; ok {
+				tag = fmt.Sprintf("%s(weight %d)", tag, w)
+			}
+			outbounds = append(outbounds, tag)
+		}
+		fmt.Printf("outbounds: %s\n", strings.Join(outbounds, ", "))
+	}
+	fmt.Printf("health check: %v\n", resp.HealthCheck)
+
+	bindings := resp.Bindings
+	sort.Slice(bindings, func(i, j int) bool { return bindings[i].Uuid < bindings[j].Uuid })
+	for _, b := range bindings {
+		if uuid != "" && b.Uuid != uuid {
+			continue
+		}
+		fmt.Printf("%s -> %s\n", b.Uuid, b.OutboundTag)
+	}
+}
@@ -0,0 +1,104 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	ratelimitpb "github.com/xtls/xray-core/app/ratelimit/api"
+	"github.com/xtls/xray-core/main/commands/base"
+)
+
+var cmdRLEvents = &base.Command{
+	CustomFlags: true,
+	UsageLine:   "{{.Exec}} api rl events [--server=127.0.0.1:8080] [-json] [uuid]",
+	Short:       "Follow device events",
+	Long: `
+Print device events as they happen: new devices, grace, resume, removal,
+eviction and changes of limits or egress bindings. Runs until interrupted.
+The -timeout flag only applies to connecting.
+
+Arguments:
+
+	-s, -server <server:port>
+		The API server address. Default 127.0.0.1:8080
+
+	-t, -timeout <seconds>
+		Timeout in seconds for connecting to the API. Default 3
+
+	-json
+		Print each event as JSON.
+
+Example:
+
+	{{.Exec}} {{.LongName}} --server=127.0.0.1:8080
+	{{.Exec}} {{.LongName}} --server=127.0.0.1:8080 "user@example"
+`,
+	Run: executeRLEvents,
+}
+
+func executeRLEvents(cmd *base.Command, args []string) {
+	setSharedFlags(cmd)
+	cmd.Flag.Parse(args)
+
+	conn, _, close := dialAPIServer()
+	defer close()
+
+	client := ratelimitpb.NewRateLimitServiceClient(conn)
+	stream, err := client.SubscribeDeviceEvents(context.Background(), &ratelimitpb.SubscribeDeviceEventsRequest{
+		Uuid: cmd.Flag.Arg(0),
+	})
+	if err != nil {
+		base.Fatalf("failed to subscribe to device events: %s", err)
+	}
+
+	for {
+		ev, err := stream.Recv()
+		if err == io.EOF {
+			return
+		}
+		if err != nil {
+			base.Fatalf("device event stream failed: %s", err)
+		}
+		if apiJSON {
+			showJSONResponse(ev)
+			continue
+		}
+		fmt.Println(formatDeviceEvent(ev))
+	}
+}
+
+func formatDeviceEvent(ev *ratelimitpb.DeviceEvent) string {
+	var sb strings.Builder
+	sb.WriteString(time.UnixMilli(int64(ev.TimeUnixMs)).Format("2006-01-02 15:04:05.000"))
+	sb.WriteString(" ")
+	sb.WriteString(strings.ToLower(ev.Type.String()))
+	sb.WriteString(" ")
+	if ev.DeviceKey != "" {
+		sb.WriteString(ev.DeviceKey)
+	} else {
+		sb.WriteString(ev.Uuid)
+	}
+	if ev.ConnId != 0 {
+		fmt.Fprintf(&sb, " conn=%d", ev.ConnId)
+	}
+
+	switch ev.Type {
+	case ratelimitpb.DeviceEvent_LIMIT_CHANGED:
+		fmt.Fprintf(&sb, " scope=%s", strings.ToLower(ev.LimitScope.String()))
+		if ev.LimitCleared {
+			sb.WriteString(" cleared")
+		} else {
+			fmt.Fprintf(&sb, " down=%s up=%s", formatBps(ev.DownBps), formatBps(ev.UpBps))
+		}
+	case ratelimitpb.DeviceEvent_EGRESS_CHANGED, ratelimitpb.DeviceEvent_STARTED:
+		if ev.EgressTag != "" {
+			fmt.Fprintf(&sb, " egress=%s", ev.EgressTag)
+		} else if ev.Type == ratelimitpb.DeviceEvent_EGRESS_CHANGED {
+			sb.WriteString(" egress cleared")
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,68 @@
+package api
+
+import (
+	"fmt"
+	"strconv"
+
+	ratelimitpb "github.com/xtls/xray-core/app/ratelimit/api"
+	"github.com/xtls/xray-core/main/commands/base"
+)
+
+var cmdRLGrace = &base.Command{
+	CustomFlags: true,
+	UsageLine:   "{{.Exec}} api rl grace [--server=127.0.0.1:8080] [seconds]",
+	Short:       "Get or set the device grace period",
+	Long: `
+Get or set how long a device is kept after its last connection closes,
+so that a quick reconnect keeps its limits and egress binding.
+Without an argument, the current grace period is printed.
+
+Arguments:
+
+	-s, -server <server:port>
+		The API server address. Default 127.0.0.1:8080
+
+	-t, -timeout <seconds>
+		Timeout in seconds for calling API. Default 3
+
+Example:
+
+	{{.Exec}} {{.LongName}} --server=127.0.0.1:8080
+	{{.Exec}} {{.LongName}} --server=127.0.0.1:8080 30
+`,
+	Run: executeRLGrace,
+}
+
+func executeRLGrace(cmd *base.Command, args []string) {
+	setSharedFlags(cmd)
+	cmd.Flag.Parse(args)
+
+	conn, ctx, close := dialAPIServer()
+	defer close()
+
+	client := ratelimitpb.NewRateLimitServiceClient(conn)
+	if cmd.Flag.NArg() == 0 {
+		resp, err := client.GetGrace(ctx, &ratelimitpb.GetGraceRequest{})
+		if err != nil {
+			base.Fatalf("failed to get grace: %s", err)
+		}
+		if apiJSON {
+			showJSONResponse(resp)
+			return
+		}
+		fmt.Printf("%ds\n", resp.Seconds)
+		return
+	}
+
+	seconds, err := strconv.ParseUint(cmd.Flag.Arg(0), 10, 32)
+	if err != nil {
+		base.Fatalf("invalid grace seconds: %s", cmd.Flag.Arg(0))
+	}
+	resp, err := client.SetGrace(ctx, &ratelimitpb.SetGraceRequest{Seconds: uint32(seconds)})
+	if err != nil {
+		base.Fatalf("failed to set grace: %s", err)
+	}
+	if apiJSON {
+		showJSONResponse(resp)
+	}
+}
@@ -0,0 +1,73 @@
+package api
+
+import (
+	"fmt"
+	"strings"
+
+	ratelimitpb "github.com/xtls/xray-core/app/ratelimit/api"
+	"github.com/xtls/xray-core/main/commands/base"
+)
+
+var cmdRLKeyMode = &base.Command{
+	CustomFlags: true,
+	UsageLine:   "{{.Exec}} api rl keymode [--server=127.0.0.1:8080] [uuid|device]",
+	Short:       "Get or set the device key mode",
+	Long: `
+Get or set how devices are keyed: "uuid" treats all connections of a user
+as one device, "device" keys them by uuid and source IP.
+Without an argument, the current mode is printed.
+
+Arguments:
+
+	-s, -server <server:port>
+		The API server address. Default 127.0.0.1:8080
+
+	-t, -timeout <seconds>
+		Timeout in seconds for calling API. Default 3
+
+Example:
+
+	{{.Exec}} {{.LongName}} --server=127.0.0.1:8080
+	{{.Exec}} {{.LongName}} --server=127.0.0.1:8080 device
+`,
+	Run: executeRLKeyMode,
+}
+
+func executeRLKeyMode(cmd *base.Command, args []string) {
+	setSharedFlags(cmd)
+	cmd.Flag.Parse(args)
+
+	conn, ctx, close := dialAPIServer()
+	defer close()
+
+	client := ratelimitpb.NewRateLimitServiceClient(conn)
+	if cmd.Flag.NArg() == 0 {
+		resp, err := client.GetKeyMode(ctx, &ratelimitpb.GetKeyModeRequest{})
+		if err != nil {
+			base.Fatalf("failed to get key mode: %s", err)
+		}
+		if apiJSON {
+			showJSONResponse(resp)
+			return
+		}
+		fmt.Println(strings.ToLower(resp.Mode.String()))
+		return
+	}
+
+	var mode ratelimitpb.SetKeyModeRequest_Mode
+	switch strings.ToLower(cmd.Flag.Arg(0)) {
+	case "uuid":
+		mode = ratelimitpb.SetKeyModeRequest_UUID
+	case "device":
+		mode = ratelimitpb.SetKeyModeRequest_DEVICE
+	default:
+		base.Fatalf("unknown key mode: %s", cmd.Flag.Arg(0))
+	}
+	resp, err := client.SetKeyMode(ctx, &ratelimitpb.SetKeyModeRequest{Mode: mode})
+	if err != nil {
+		base.Fatalf("failed to set key mode: %s", err)
+	}
+	if apiJSON {
+		showJSONResponse(resp)
+	}
+}
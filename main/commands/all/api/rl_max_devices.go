@@ -0,0 +1,121 @@
+package api
+
+import (
+	"fmt"
+	"strings"
+
+	ratelimitpb "github.com/xtls/xray-core/app/ratelimit/api"
+	"github.com/xtls/xray-core/main/commands/base"
+)
+
+var cmdRLMaxDevices = &base.Command{
+	CustomFlags: true,
+	UsageLine:   "{{.Exec}} api rl maxdevices [--server=127.0.0.1:8080] [-json] [-set <n> [-policy <policy>] | -clear] [uuid]",
+	Short:       "Get or manage the concurrent device limit",
+	Long: `
+Show the concurrent device limit of a user together with its counters,
+or set or clear it. Without a uuid the global limit is used, which applies
+to every user without a limit of their own.
+
+Arguments:
+
+	-s, -server <server:port>
+		The API server address. Default 127.0.0.1:8080
+
+	-t, -timeout <seconds>
+		Timeout in seconds for calling API. Default 3
+
+	-json
+		Print the raw JSON response.
+
+	-set <n>
+		Allow at most n devices at once. 0 means unlimited.
+
+	-policy <reject_newest|evict_oldest|log_only>
+		What happens to a device over the limit. Default reject_newest
+
+	-clear
+		Remove the limit.
+
+Example:
+
+	{{.Exec}} {{.LongName}} --server=127.0.0.1:8080 -set 3
+	{{.Exec}} {{.LongName}} --server=127.0.0.1:8080 -set 5 -policy evict_oldest "user@example"
+	{{.Exec}} {{.LongName}} --server=127.0.0.1:8080 "user@example"
+`,
+	Run: executeRLMaxDevices,
+}
+
+func executeRLMaxDevices(cmd *base.Command, args []string) {
+	var (
+		set    int
+		clear  bool
+		policy string
+	)
+	setSharedFlags(cmd)
+	cmd.Flag.IntVar(&set, "set", -1, "")
+	cmd.Flag.BoolVar(&clear, "clear", false, "")
+	cmd.Flag.StringVar(&policy, "policy", "reject_newest", "")
+	cmd.Flag.Parse(args)
+	uuid := cmd.Flag.Arg(0)
+
+	conn, ctx, close := dialAPIServer()
+	defer close()
+
+	client := ratelimitpb.NewRateLimitServiceClient(conn)
+	switch {
+	case set >= 0:
+		p, ok := ratelimitpb.MaxDevices_Policy_value[strings.ToUpper(policy)]
+		if !ok {
+			base.Fatalf("unknown max devices policy: %s", policy)
+		}
+		resp, err := client.SetMaxDevices(ctx, &ratelimitpb.SetMaxDevicesRequest{
+			Uuid: uuid,
+			Limit: &ratelimitpb.MaxDevices{
+				Max:    uint32(set),
+				Policy: ratelimitpb.MaxDevices_Policy(p),
+			},
+		})
+		if err != nil {
+			base.Fatalf("failed to set max devices: %s", err)
+		}
+		if apiJSON {
+			showJSONResponse(resp)
+		}
+	case clear:
+		resp, err := client.ClearMaxDevices(ctx, &ratelimitpb.ClearMaxDevicesRequest{Uuid: uuid})
+		if err != nil {
+			base.Fatalf("failed to clear max devices: %s", err)
+		}
+		if apiJSON {
+			showJSONResponse(resp)
+		} else if !resp.Cleared {
+			fmt.Println("no limit")
+		}
+	default:
+		resp, err := client.GetMaxDevices(ctx, &ratelimitpb.GetMaxDevicesRequest{Uuid: uuid})
+		if err != nil {
+			base.Fatalf("failed to get max devices: %s", err)
+		}
+		if apiJSON {
+			showJSONResponse(resp)
+			return
+		}
+		if !resp.Found {
+			fmt.Println("Limit:      none")
+		} else {
+			scope := "global"
+			if resp.PerUser {
+				scope = "user"
+			}
+			fmt.Printf("Limit:      %d (%s, %s)\n", resp.Limit.Max, strings.ToLower(resp.Limit.Policy.String()), scope)
+		}
+		if uuid != "" {
+			fmt.Printf("UUID:       %s\n", resp.Uuid)
+			fmt.Printf("Active:     %d\n", resp.ActiveDevices)
+			fmt.Printf("Rejected:   %d\n", resp.Rejected)
+			fmt.Printf("Evicted:    %d\n", resp.Evicted)
+			fmt.Printf("Exceeded:   %d\n", resp.Exceeded)
+		}
+	}
+}
@@ -0,0 +1,159 @@
+package api
+
+import (
+	"fmt"
+	"strings"
+
+	ratelimitpb "github.com/xtls/xray-core/app/ratelimit/api"
+	"github.com/xtls/xray-core/main/commands/base"
+)
+
+var cmdRLQuota = &base.Command{
+	CustomFlags: true,
+	UsageLine:   "{{.Exec}} api rl quota [--server=127.0.0.1:8080] [-json] [-set | -clear | -reset] <uuid>",
+	Short:       "Get or manage a user's traffic quota",
+	Long: `
+Show a user's traffic quota and usage, or set, clear or reset it.
+
+Arguments:
+
+	-s, -server <server:port>
+		The API server address. Default 127.0.0.1:8080
+
+	-t, -timeout <seconds>
+		Timeout in seconds for calling API. Default 3
+
+	-json
+		Print the raw JSON response.
+
+	-set
+		Set the quota described by the flags below.
+
+	-clear
+		Remove the quota.
+
+	-reset
+		Reset the usage of the current period, keeping the quota.
+
+	-limit <bytes>
+		Quota size in bytes (K/M/G suffix allowed, decimal).
+
+	-period <absolute|daily|monthly>
+		When usage resets. Default absolute
+
+	-direction <total|up|down>
+		Which traffic counts. Default total
+
+	-action <block|close|penalty>
+		What happens when the quota is exceeded. Default block
+
+	-penalty-down, -penalty-up
+		Rate applied with the "penalty" action, bits per second.
+
+Example:
+
+	{{.Exec}} {{.LongName}} --server=127.0.0.1:8080 "user@example"
+	{{.Exec}} {{.LongName}} --server=127.0.0.1:8080 -set -limit 100G -period monthly -action penalty -penalty-down 1M "user@example"
+	{{.Exec}} {{.LongName}} --server=127.0.0.1:8080 -reset "user@example"
+`,
+	Run: executeRLQuota,
+}
+
+func executeRLQuota(cmd *base.Command, args []string) {
+	var (
+		set, clear, reset                bool
+		limit, period, direction, action string
+		penaltyDown, penaltyUp           string
+	)
+	setSharedFlags(cmd)
+	cmd.Flag.BoolVar(&set, "set", false, "")
+	cmd.Flag.BoolVar(&clear, "clear", false, "")
+	cmd.Flag.BoolVar(&reset, "reset", false, "")
+	cmd.Flag.StringVar(&limit, "limit", "", "")
+	cmd.Flag.StringVar(&period, "period", "absolute", "")
+	cmd.Flag.StringVar(&direction, "direction", "total", "")
+	cmd.Flag.StringVar(&action, "action", "block", "")
+	cmd.Flag.StringVar(&penaltyDown, "penalty-down", "", "")
+	cmd.Flag.StringVar(&penaltyUp, "penalty-up", "", "")
+	cmd.Flag.Parse(args)
+	if cmd.Flag.NArg() < 1 {
+		base.Fatalf("uuid not specified")
+	}
+	uuid := cmd.Flag.Arg(0)
+
+	conn, ctx, close := dialAPIServer()
+	defer close()
+
+	client := ratelimitpb.NewRateLimitServiceClient(conn)
+	switch {
+	case set:
+		p, ok := ratelimitpb.Quota_Period_value[strings.ToUpper(period)]
+		if !ok {
+			base.Fatalf("unknown quota period: %s", period)
+		}
+		d, ok := ratelimitpb.Quota_Direction_value[strings.ToUpper(direction)]
+		if !ok {
+			base.Fatalf("unknown quota direction: %s", direction)
+		}
+		a, ok := ratelimitpb.Quota_Action_value[strings.ToUpper(action)]
+		if !ok {
+			base.Fatalf("unknown quota action: %s", action)
+		}
+		resp, err := client.SetUserQuota(ctx, &ratelimitpb.SetUserQuotaRequest{
+			Uuid: uuid,
+			Quota: &ratelimitpb.Quota{
+				Period:         ratelimitpb.Quota_Period(p),
+				Direction:      ratelimitpb.Quota_Direction(d),
+				LimitBytes:     mustParseBps("limit", limit),
+				Action:         ratelimitpb.Quota_Action(a),
+				PenaltyDownBps: mustParseBps("penalty-down", penaltyDown),
+				PenaltyUpBps:   mustParseBps("penalty-up", penaltyUp),
+			},
+		})
+		if err != nil {
+			base.Fatalf("failed to set quota: %s", err)
+		}
+		if apiJSON {
+			showJSONResponse(resp)
+		}
+	case clear:
+		resp, err := client.ClearUserQuota(ctx, &ratelimitpb.ClearUserQuotaRequest{Uuid: uuid})
+		if err != nil {
+			base.Fatalf("failed to clear quota: %s", err)
+		}
+		if apiJSON {
+			showJSONResponse(resp)
+		} else if !resp.Cleared {
+			fmt.Println("no quota")
+		}
+	case reset:
+		resp, err := client.ResetUserQuotaUsage(ctx, &ratelimitpb.ResetUserQuotaUsageRequest{Uuid: uuid})
+		if err != nil {
+			base.Fatalf("failed to reset quota usage: %s", err)
+		}
+		if apiJSON {
+			showJSONResponse(resp)
+		} else if !resp.Found {
+			fmt.Println("no quota")
+		}
+	default:
+		resp, err := client.GetUserQuota(ctx, &ratelimitpb.GetUserQuotaRequest{Uuid: uuid})
+		if err != nil {
+			base.Fatalf("failed to get quota: %s", err)
+		}
+		if apiJSON {
+			showJSONResponse(resp)
+			return
+		}
+		q := resp.Quota
+		fmt.Printf("UUID:       %s\n", resp.Uuid)
+		fmt.Printf("Quota:      %s %s, %s\n", formatBytes(q.LimitBytes), strings.ToLower(q.Direction.String()), strings.ToLower(q.Period.String()))
+		fmt.Printf("Action:     %s\n", strings.ToLower(q.Action.String()))
+		if q.Action == ratelimitpb.Quota_PENALTY {
+			fmt.Printf("Penalty:    down %s / up %s\n", formatBps(q.PenaltyDownBps), formatBps(q.PenaltyUpBps))
+		}
+		fmt.Printf("Used:       up %s / down %s\n", formatBytes(resp.UpBytes), formatBytes(resp.DownBytes))
+		fmt.Printf("Since:      %s\n", formatUnix(resp.PeriodStartUnix))
+		fmt.Printf("Exceeded:   %v\n", resp.Exceeded)
+	}
+}
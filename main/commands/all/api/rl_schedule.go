@@ -0,0 +1,217 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/xtls/xray-core/app/ratelimit"
+	ratelimitpb "github.com/xtls/xray-core/app/ratelimit/api"
+	"github.com/xtls/xray-core/infra/conf"
+	"github.com/xtls/xray-core/infra/conf/serial"
+	"github.com/xtls/xray-core/main/commands/base"
+)
+
+var cmdRLSchedule = &base.Command{
+	CustomFlags: true,
+	UsageLine:   "{{.Exec}} api rl schedule [--server=127.0.0.1:8080] [-json] [-clear] [-level <n> | uuid] | -set <c1.json> [c2.json]...",
+	Short:       "Get or manage time-of-day limit schedules",
+	Long: `
+Show or clear the limit schedule of a user or a policy level, or set
+schedules from the "ratelimit.schedules" section of config files.
+
+Arguments:
+
+	-s, -server <server:port>
+		The API server address. Default 127.0.0.1:8080
+
+	-t, -timeout <seconds>
+		Timeout in seconds for calling API. Default 3
+
+	-json
+		Print the raw JSON response.
+
+	-level <n>
+		Use the schedule of a policy level instead of a uuid.
+
+	-clear
+		Remove the schedule.
+
+	-set
+		Apply every schedule found in the given config files.
+
+Example:
+
+	{{.Exec}} {{.LongName}} --server=127.0.0.1:8080 "user@example"
+	{{.Exec}} {{.LongName}} --server=127.0.0.1:8080 -level 1 -clear
+	{{.Exec}} {{.LongName}} --server=127.0.0.1:8080 -set schedules.json
+`,
+	Run: executeRLSchedule,
+}
+
+func executeRLSchedule(cmd *base.Command, args []string) {
+	var (
+		set, clear bool
+		level      int
+	)
+	setSharedFlags(cmd)
+	cmd.Flag.BoolVar(&set, "set", false, "")
+	cmd.Flag.BoolVar(&clear, "clear", false, "")
+	cmd.Flag.IntVar(&level, "level", -1, "")
+	cmd.Flag.Parse(args)
+
+	if set {
+		setRLSchedules(cmd.Flag.Args())
+		return
+	}
+
+	uuid := cmd.Flag.Arg(0)
+	if uuid == "" && level < 0 {
+		base.Fatalf("uuid or -level not specified")
+	}
+
+	conn, ctx, close := dialAPIServer()
+	defer close()
+
+	client := ratelimitpb.NewRateLimitServiceClient(conn)
+	if clear {
+		resp, err := client.ClearSchedule(ctx, &ratelimitpb.ClearScheduleRequest{Uuid: uuid, Level: uint32(max(level, 0))})
+		if err != nil {
+			base.Fatalf("failed to clear schedule: %s", err)
+		}
+		if apiJSON {
+			showJSONResponse(resp)
+		} else if !resp.Cleared {
+			fmt.Println("no schedule")
+		}
+		return
+	}
+
+	resp, err := client.GetSchedule(ctx, &ratelimitpb.GetScheduleRequest{Uuid: uuid, Level: uint32(max(level, 0))})
+	if err != nil {
+		base.Fatalf("failed to get schedule: %s", err)
+	}
+	if apiJSON {
+		showJSONResponse(resp)
+		return
+	}
+	if !resp.Found {
+		fmt.Println("no schedule")
+		return
+	}
+	showSchedule(resp.Schedule, int(resp.ActiveWindow))
+}
+
+func setRLSchedules(files []string) {
+	if len(files) == 0 {
+		files = []string{"stdin:"}
+	}
+
+	var reqs []*ratelimitpb.SetScheduleRequest
+	for _, arg := range files {
+		r, err := loadArg(arg)
+		if err != nil {
+			base.Fatalf("failed to load %s: %s", arg, err)
+		}
+		c, err := serial.DecodeJSONConfig(r)
+		if err != nil {
+			base.Fatalf("failed to decode %s: %s", arg, err)
+		}
+		if c.RateLimit == nil {
+			continue
+		}
+		var rl conf.RateLimitConfig
+		if err := json.Unmarshal(*c.RateLimit, &rl); err != nil {
+			base.Fatalf("failed to decode ratelimit in %s: %s", arg, err)
+		}
+		for _, sc := range rl.Schedules {
+			sch, err := sc.Build()
+			if err != nil {
+				base.Fatalf("invalid schedule in %s: %s", arg, err)
+			}
+			req := &ratelimitpb.SetScheduleRequest{Uuid: sc.UUID, Schedule: scheduleToRLPB(sch)}
+			if sc.Level != nil {
+				req.Level = *sc.Level
+			} else if sc.UUID == "" {
+				base.Fatalf("schedule in %s has neither uuid nor level", arg)
+			}
+			reqs = append(reqs, req)
+		}
+	}
+	if len(reqs) == 0 {
+		base.Fatalf("no schedule found")
+	}
+
+	conn, ctx, close := dialAPIServer()
+	defer close()
+
+	client := ratelimitpb.NewRateLimitServiceClient(conn)
+	for _, req := range reqs {
+		target := req.Uuid
+		if target == "" {
+			target = fmt.Sprintf("level %d", req.Level)
+		}
+		if _, err := client.SetSchedule(ctx, req); err != nil {
+			base.Fatalf("failed to set schedule for %s: %s", target, err)
+		}
+		fmt.Println("schedule set for", target)
+	}
+}
+
+func scheduleToRLPB(sch ratelimit.Schedule) *ratelimitpb.Schedule {
+	out := &ratelimitpb.Schedule{TimeZone: sch.TimeZone}
+	for _, w := range sch.Windows {
+		win := &ratelimitpb.ScheduleWindow{
+			StartMinute: uint32(w.StartMinute),
+			EndMinute:   uint32(w.EndMinute),
+		}
+		if w.PerConn != nil {
+			win.PerConn = &ratelimitpb.Rate{DownBps: w.PerConn.Down, UpBps: w.PerConn.Up}
+		}
+		if w.Total != nil {
+			win.Total = &ratelimitpb.Rate{DownBps: w.Total.Down, UpBps: w.Total.Up}
+		}
+		for _, d := range w.Days {
+			win.Days = append(win.Days, uint32(d))
+		}
+		out.Windows = append(out.Windows, win)
+	}
+	return out
+}
+
+func showSchedule(sch *ratelimitpb.Schedule, active int) {
+	tz := sch.GetTimeZone()
+	if tz == "" {
+		tz = "Local"
+	}
+	fmt.Println("time zone:", tz)
+
+	for i, w := range sch.GetWindows() {
+		days := "every day"
+		if len(w.Days) > 0 {
+			names := make([]string, 0, len(w.Days))
+			for _, d := range w.Days {
+				names = append(names, time.Weekday(d).String()[:3])
+			}
+			days = strings.Join(names, ",")
+		}
+
+		marker := " "
+		if i == active {
+			marker = "*"
+		}
+		fmt.Printf("%s %d: %s %s-%s", marker, i, days, formatScheduleMinute(w.StartMinute), formatScheduleMinute(w.EndMinute))
+		if w.PerConn != nil {
+			fmt.Printf(" per-conn down=%s up=%s", formatBps(w.PerConn.DownBps), formatBps(w.PerConn.UpBps))
+		}
+		if w.Total != nil {
+			fmt.Printf(" total down=%s up=%s", formatBps(w.Total.DownBps), formatBps(w.Total.UpBps))
+		}
+		fmt.Println()
+	}
+}
+
+func formatScheduleMinute(m uint32) string {
+	return fmt.Sprintf("%02d:%02d", m/60, m%60)
+}
@@ -0,0 +1,64 @@
+package api
+
+import (
+	ratelimitpb "github.com/xtls/xray-core/app/ratelimit/api"
+	"github.com/xtls/xray-core/main/commands/base"
+)
+
+var cmdRLSetDefault = &base.Command{
+	CustomFlags: true,
+	UsageLine:   "{{.Exec}} api rl setdefault [--server=127.0.0.1:8080] -uuid <uuid> [-down 10M] [-up 10M]",
+	Short:       "Set a user's default per-device limit",
+	Long: `
+Set the default rate limit applied to every device of a user.
+A rate of 0 means unlimited in that direction.
+
+Arguments:
+
+	-s, -server <server:port>
+		The API server address. Default 127.0.0.1:8080
+
+	-t, -timeout <seconds>
+		Timeout in seconds for calling API. Default 3
+
+	-uuid
+		The user's UUID (email).
+
+	-down, -up
+		Downlink/uplink rate, bits per second (K/M/G suffix allowed).
+
+Example:
+
+	{{.Exec}} {{.LongName}} --server=127.0.0.1:8080 -uuid "user@example" -down 10M -up 2M
+`,
+	Run: executeRLSetDefault,
+}
+
+func executeRLSetDefault(cmd *base.Command, args []string) {
+	var uuid, down, up string
+	setSharedFlags(cmd)
+	cmd.Flag.StringVar(&uuid, "uuid", "", "")
+	cmd.Flag.StringVar(&down, "down", "", "")
+	cmd.Flag.StringVar(&up, "up", "", "")
+	cmd.Flag.Parse(args)
+
+	if uuid == "" {
+		base.Fatalf("uuid not specified")
+	}
+
+	conn, ctx, close := dialAPIServer()
+	defer close()
+
+	client := ratelimitpb.NewRateLimitServiceClient(conn)
+	resp, err := client.SetUserDefaultPerConnLimit(ctx, &ratelimitpb.SetUserDefaultPerConnLimitRequest{
+		Uuid:    uuid,
+		DownBps: mustParseBps("down", down),
+		UpBps:   mustParseBps("up", up),
+	})
+	if err != nil {
+		base.Fatalf("failed to set user default limit: %s", err)
+	}
+	if apiJSON {
+		showJSONResponse(resp)
+	}
+}
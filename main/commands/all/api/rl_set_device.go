@@ -0,0 +1,64 @@
+package api
+
+import (
+	ratelimitpb "github.com/xtls/xray-core/app/ratelimit/api"
+	"github.com/xtls/xray-core/main/commands/base"
+)
+
+var cmdRLSetDevice = &base.Command{
+	CustomFlags: true,
+	UsageLine:   "{{.Exec}} api rl setdevice [--server=127.0.0.1:8080] -key <device_key> [-down 10M] [-up 10M]",
+	Short:       "Set a limit for one device",
+	Long: `
+Override the rate limit of a single device. The device key is the uuid
+in "uuid" key mode, or "uuid|srcIP" in "device" key mode; see "rl devices".
+
+Arguments:
+
+	-s, -server <server:port>
+		The API server address. Default 127.0.0.1:8080
+
+	-t, -timeout <seconds>
+		Timeout in seconds for calling API. Default 3
+
+	-key
+		The device key.
+
+	-down, -up
+		Downlink/uplink rate, bits per second (K/M/G suffix allowed).
+
+Example:
+
+	{{.Exec}} {{.LongName}} --server=127.0.0.1:8080 -key "user@example|203.0.113.5" -down 5M -up 1M
+`,
+	Run: executeRLSetDevice,
+}
+
+func executeRLSetDevice(cmd *base.Command, args []string) {
+	var key, down, up string
+	setSharedFlags(cmd)
+	cmd.Flag.StringVar(&key, "key", "", "")
+	cmd.Flag.StringVar(&down, "down", "", "")
+	cmd.Flag.StringVar(&up, "up", "", "")
+	cmd.Flag.Parse(args)
+
+	if key == "" {
+		base.Fatalf("device key not specified")
+	}
+
+	conn, ctx, close := dialAPIServer()
+	defer close()
+
+	client := ratelimitpb.NewRateLimitServiceClient(conn)
+	resp, err := client.SetDeviceLimit(ctx, &ratelimitpb.SetDeviceLimitRequest{
+		DeviceKey: key,
+		DownBps:   mustParseBps("down", down),
+		UpBps:     mustParseBps("up", up),
+	})
+	if err != nil {
+		base.Fatalf("failed to set device limit: %s", err)
+	}
+	if apiJSON {
+		showJSONResponse(resp)
+	}
+}
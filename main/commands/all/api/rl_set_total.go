@@ -0,0 +1,108 @@
+package api
+
+import (
+	"fmt"
+
+	ratelimitpb "github.com/xtls/xray-core/app/ratelimit/api"
+	"github.com/xtls/xray-core/main/commands/base"
+)
+
+var cmdRLSetTotal = &base.Command{
+	CustomFlags: true,
+	UsageLine:   "{{.Exec}} api rl settotal [--server=127.0.0.1:8080] [-uuid <uuid> | -inbound <tag> | -global] [-down 100M] [-up 100M]",
+	Short:       "Set an aggregate limit for a user, inbound or the server",
+	Long: `
+Set an aggregate rate limit shared by all devices of a user, all
+connections of an inbound, or the whole server. Devices draw from the
+shared limit, so an idle device's share goes to the active ones.
+
+Arguments:
+
+	-s, -server <server:port>
+		The API server address. Default 127.0.0.1:8080
+
+	-t, -timeout <seconds>
+		Timeout in seconds for calling API. Default 3
+
+	-uuid
+		Limit all devices of this user.
+
+	-inbound
+		Limit all connections of this inbound tag.
+
+	-global
+		Limit the whole server.
+
+	-down, -up
+		Downlink/uplink rate, bits per second (K/M/G suffix allowed).
+
+Example:
+
+	{{.Exec}} {{.LongName}} --server=127.0.0.1:8080 -uuid "user@example" -down 50M -up 10M
+	{{.Exec}} {{.LongName}} --server=127.0.0.1:8080 -inbound "vless-in" -down 500M
+	{{.Exec}} {{.LongName}} --server=127.0.0.1:8080 -global -down 1G -up 1G
+`,
+	Run: executeRLSetTotal,
+}
+
+func executeRLSetTotal(cmd *base.Command, args []string) {
+	var uuid, inbound, down, up string
+	var global bool
+	setSharedFlags(cmd)
+	cmd.Flag.StringVar(&uuid, "uuid", "", "")
+	cmd.Flag.StringVar(&inbound, "inbound", "", "")
+	cmd.Flag.BoolVar(&global, "global", false, "")
+	cmd.Flag.StringVar(&down, "down", "", "")
+	cmd.Flag.StringVar(&up, "up", "", "")
+	cmd.Flag.Parse(args)
+
+	downBps := mustParseBps("down", down)
+	upBps := mustParseBps("up", up)
+
+	conn, ctx, close := dialAPIServer()
+	defer close()
+
+	client := ratelimitpb.NewRateLimitServiceClient(conn)
+	switch {
+	case uuid != "":
+		resp, err := client.SetUserTotalLimit(ctx, &ratelimitpb.SetUserTotalLimitRequest{
+			Uuid:    uuid,
+			DownBps: downBps,
+			UpBps:   upBps,
+		})
+		if err != nil {
+			base.Fatalf("failed to set user total limit: %s", err)
+		}
+		if apiJSON {
+			showJSONResponse(resp)
+			return
+		}
+		fmt.Printf("%d active device(s) share down %s / up %s\n",
+			resp.DeviceCount, formatBps(resp.TotalDownBps), formatBps(resp.TotalUpBps))
+	case inbound != "":
+		resp, err := client.SetInboundTotalLimit(ctx, &ratelimitpb.SetInboundTotalLimitRequest{
+			InboundTag: inbound,
+			DownBps:    downBps,
+			UpBps:      upBps,
+		})
+		if err != nil {
+			base.Fatalf("failed to set inbound total limit: %s", err)
+		}
+		if apiJSON {
+			showJSONResponse(resp)
+		}
+	case global:
+		resp, err := client.SetGlobalTotalLimit(ctx, &ratelimitpb.SetGlobalTotalLimitRequest{
+			DownBps: downBps,
+			UpBps:   upBps,
+		})
+		if err != nil {
+			base.Fatalf("failed to set global total limit: %s", err)
+		}
+		if apiJSON {
+			showJSONResponse(resp)
+		}
+	default:
+		base.Fatalf("one of -uuid, -inbound or -global must be specified")
+	}
+}
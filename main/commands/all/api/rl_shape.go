@@ -0,0 +1,223 @@
+package api
+
+import (
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+
+	ratelimitpb "github.com/xtls/xray-core/app/ratelimit/api"
+	"github.com/xtls/xray-core/main/commands/base"
+)
+
+var cmdRLShape = &base.Command{
+	CustomFlags: true,
+	UsageLine:   "{{.Exec}} api rl shape [--server=127.0.0.1:8080] [-json] [-set <shape flags> | -clear] [uuid]",
+	Short:       "Get or manage bucket burst, initial credit and boost",
+	Long: `
+Show, set or clear the shape of the rate limit buckets of a user: the
+burst size, the initial credit of a new bucket and the boost at the start
+of a device session. Without a uuid the global shape is used; fields set
+for a uuid override it one by one. -set replaces the whole entry.
+
+Sizes accept plain bytes or a K/M/G suffix (binary), e.g. 64K, 50M.
+
+Arguments:
+
+	-s, -server <server:port>
+		The API server address. Default 127.0.0.1:8080
+
+	-t, -timeout <seconds>
+		Timeout in seconds for calling API. Default 3
+
+	-json
+		Print the raw JSON response.
+
+	-set
+		Replace the shape with the one given by the flags below.
+
+	-conn-burst <size>, -conn-burst-ms <ms>, -conn-initial <size>
+		Per-device bucket: fixed burst, burst in ms of the rate
+		(default 200ms, at least 32K), initial credit (default full burst).
+
+	-total-burst <size>, -total-burst-ms <ms>, -total-initial <size>
+		The same for the bucket of the user total limit.
+
+	-boost-bytes <size>, -boost-seconds <n>
+		Skip the device and user limits for the first bytes and/or
+		seconds of a device session.
+
+	-clear
+		Remove the shape.
+
+Example:
+
+	{{.Exec}} {{.LongName}} --server=127.0.0.1:8080 -set -conn-burst-ms 1000
+	{{.Exec}} {{.LongName}} --server=127.0.0.1:8080 -set -total-initial 50M -boost-seconds 10 "user@example"
+	{{.Exec}} {{.LongName}} --server=127.0.0.1:8080 "user@example"
+`,
+	Run: executeRLShape,
+}
+
+func executeRLShape(cmd *base.Command, args []string) {
+	var (
+		set, clear bool
+
+		connBurst, connInitial    string
+		totalBurst, totalInitial  string
+		connBurstMs, totalBurstMs uint
+		boostBytes                string
+		boostSeconds              uint
+	)
+	setSharedFlags(cmd)
+	cmd.Flag.BoolVar(&set, "set", false, "")
+	cmd.Flag.BoolVar(&clear, "clear", false, "")
+	cmd.Flag.StringVar(&connBurst, "conn-burst", "", "")
+	cmd.Flag.UintVar(&connBurstMs, "conn-burst-ms", 0, "")
+	cmd.Flag.StringVar(&connInitial, "conn-initial", "", "")
+	cmd.Flag.StringVar(&totalBurst, "total-burst", "", "")
+	cmd.Flag.UintVar(&totalBurstMs, "total-burst-ms", 0, "")
+	cmd.Flag.StringVar(&totalInitial, "total-initial", "", "")
+	cmd.Flag.StringVar(&boostBytes, "boost-bytes", "", "")
+	cmd.Flag.UintVar(&boostSeconds, "boost-seconds", 0, "")
+	cmd.Flag.Parse(args)
+	uuid := cmd.Flag.Arg(0)
+
+	visited := make(map[string]bool)
+	cmd.Flag.Visit(func(f *flag.Flag) { visited[f.Name] = true })
+
+	conn, ctx, close := dialAPIServer()
+	defer close()
+
+	client := ratelimitpb.NewRateLimitServiceClient(conn)
+	switch {
+	case set:
+		shape := &ratelimitpb.LimitShape{}
+		if visited["conn-burst"] || visited["conn-burst-ms"] || visited["conn-initial"] {
+			shape.PerConn = &ratelimitpb.BucketShape{
+				BurstBytes: mustParseBytes("conn-burst", connBurst),
+				BurstMs:    uint32(connBurstMs),
+			}
+			if visited["conn-initial"] {
+				v := mustParseBytes("conn-initial", connInitial)
+				shape.PerConn.InitialBytes = &v
+			}
+		}
+		if visited["total-burst"] || visited["total-burst-ms"] || visited["total-initial"] {
+			shape.Total = &ratelimitpb.BucketShape{
+				BurstBytes: mustParseBytes("total-burst", totalBurst),
+				BurstMs:    uint32(totalBurstMs),
+			}
+			if visited["total-initial"] {
+				v := mustParseBytes("total-initial", totalInitial)
+				shape.Total.InitialBytes = &v
+			}
+		}
+		if visited["boost-bytes"] || visited["boost-seconds"] {
+			shape.Boost = &ratelimitpb.Boost{
+				Bytes:   mustParseBytes("boost-bytes", boostBytes),
+				Seconds: uint32(boostSeconds),
+			}
+		}
+
+		resp, err := client.SetBucketShape(ctx, &ratelimitpb.SetBucketShapeRequest{Uuid: uuid, Shape: shape})
+		if err != nil {
+			base.Fatalf("failed to set bucket shape: %s", err)
+		}
+		if apiJSON {
+			showJSONResponse(resp)
+		}
+	case clear:
+		resp, err := client.ClearBucketShape(ctx, &ratelimitpb.ClearBucketShapeRequest{Uuid: uuid})
+		if err != nil {
+			base.Fatalf("failed to clear bucket shape: %s", err)
+		}
+		if apiJSON {
+			showJSONResponse(resp)
+		} else if !resp.Cleared {
+			fmt.Println("no shape")
+		}
+	default:
+		resp, err := client.GetBucketShape(ctx, &ratelimitpb.GetBucketShapeRequest{Uuid: uuid})
+		if err != nil {
+			base.Fatalf("failed to get bucket shape: %s", err)
+		}
+		if apiJSON {
+			showJSONResponse(resp)
+			return
+		}
+		if uuid != "" && !resp.Found {
+			fmt.Println("no shape for user, effective:")
+		}
+		l := resp.Effective
+		fmt.Printf("Per-conn:   %s\n", formatBucketShape(l.GetPerConn()))
+		fmt.Printf("Total:      %s\n", formatBucketShape(l.GetTotal()))
+		fmt.Printf("Boost:      %s\n", formatBoost(l.GetBoost()))
+	}
+}
+
+// parseBytes parses "50M", "64k", "1G" or a plain number of bytes.
+func parseBytes(s string) (uint64, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, nil
+	}
+	mult := uint64(1)
+	switch s[len(s)-1] {
+	case 'k', 'K':
+		mult = 1 << 10
+	case 'm', 'M':
+		mult = 1 << 20
+	case 'g', 'G':
+		mult = 1 << 30
+	}
+	if mult != 1 {
+		s = s[:len(s)-1]
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil || v < 0 {
+		return 0, fmt.Errorf("invalid size: %q", s)
+	}
+	return uint64(v * float64(mult)), nil
+}
+
+func mustParseBytes(name, s string) uint64 {
+	v, err := parseBytes(s)
+	if err != nil {
+		base.Fatalf("-%s: %s", name, err)
+	}
+	return v
+}
+
+func formatBucketShape(s *ratelimitpb.BucketShape) string {
+	if s == nil {
+		return "default"
+	}
+	var parts []string
+	switch {
+	case s.BurstBytes > 0:
+		parts = append(parts, "burst="+formatBytes(s.BurstBytes))
+	case s.BurstMs > 0:
+		parts = append(parts, fmt.Sprintf("burst=%dms", s.BurstMs))
+	default:
+		parts = append(parts, "burst=default")
+	}
+	if s.InitialBytes != nil {
+		parts = append(parts, "initial="+formatBytes(*s.InitialBytes))
+	}
+	return strings.Join(parts, " ")
+}
+
+func formatBoost(b *ratelimitpb.Boost) string {
+	if b == nil || (b.Bytes == 0 && b.Seconds == 0) {
+		return "none"
+	}
+	var parts []string
+	if b.Bytes > 0 {
+		parts = append(parts, "first "+formatBytes(b.Bytes))
+	}
+	if b.Seconds > 0 {
+		parts = append(parts, fmt.Sprintf("first %ds", b.Seconds))
+	}
+	return strings.Join(parts, " or ")
+}
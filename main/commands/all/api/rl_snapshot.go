@@ -0,0 +1,64 @@
+package api
+
+import (
+	ratelimitpb "github.com/xtls/xray-core/app/ratelimit/api"
+	"github.com/xtls/xray-core/main/commands/base"
+)
+
+var cmdRLSnapshot = &base.Command{
+	CustomFlags: true,
+	UsageLine:   "{{.Exec}} api rl snapshot [--server=127.0.0.1:8080] [-json] [-reset]",
+	Short:       "Take a snapshot of active devices",
+	Long: `
+Take a snapshot of all active devices and their traffic counters.
+
+Arguments:
+
+	-s, -server <server:port>
+		The API server address. Default 127.0.0.1:8080
+
+	-t, -timeout <seconds>
+		Timeout in seconds for calling API. Default 3
+
+	-json
+		Print the raw JSON response.
+
+	-reset
+		Reset the traffic counters after reading them, as a billing agent
+		would. Default false
+
+Example:
+
+	{{.Exec}} {{.LongName}} --server=127.0.0.1:8080 -json -reset
+`,
+	Run: executeRLSnapshot,
+}
+
+func executeRLSnapshot(cmd *base.Command, args []string) {
+	var reset bool
+	setSharedFlags(cmd)
+	cmd.Flag.BoolVar(&reset, "reset", false, "")
+	cmd.Flag.Parse(args)
+
+	conn, ctx, close := dialAPIServer()
+	defer close()
+
+	client := ratelimitpb.NewRateLimitServiceClient(conn)
+	var (
+		resp *ratelimitpb.GetActiveDevicesSnapshotResponse
+		err  error
+	)
+	if reset {
+		resp, err = client.GetActiveDevicesSnapshot(ctx, &ratelimitpb.GetActiveDevicesSnapshotRequest{})
+	} else {
+		resp, err = client.PeekActiveDevicesSnapshot(ctx, &ratelimitpb.GetActiveDevicesSnapshotRequest{})
+	}
+	if err != nil {
+		base.Fatalf("failed to get devices snapshot: %s", err)
+	}
+	if apiJSON {
+		showJSONResponse(resp)
+		return
+	}
+	showDevices(resp.Devices)
+}
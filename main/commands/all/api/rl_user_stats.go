@@ -0,0 +1,61 @@
+package api
+
+import (
+	"fmt"
+
+	ratelimitpb "github.com/xtls/xray-core/app/ratelimit/api"
+	"github.com/xtls/xray-core/main/commands/base"
+)
+
+var cmdRLUserStats = &base.Command{
+	CustomFlags: true,
+	UsageLine:   "{{.Exec}} api rl userstats [--server=127.0.0.1:8080] [-json] <uuid>",
+	Short:       "Retrieve a user's device and traffic summary",
+	Long: `
+Retrieve the number of devices and the traffic of a user, summed over
+all of the user's devices.
+
+Arguments:
+
+	-s, -server <server:port>
+		The API server address. Default 127.0.0.1:8080
+
+	-t, -timeout <seconds>
+		Timeout in seconds for calling API. Default 3
+
+	-json
+		Print the raw JSON response.
+
+Example:
+
+	{{.Exec}} {{.LongName}} --server=127.0.0.1:8080 "user@example"
+`,
+	Run: executeRLUserStats,
+}
+
+func executeRLUserStats(cmd *base.Command, args []string) {
+	setSharedFlags(cmd)
+	cmd.Flag.Parse(args)
+	if cmd.Flag.NArg() < 1 {
+		base.Fatalf("uuid not specified")
+	}
+
+	conn, ctx, close := dialAPIServer()
+	defer close()
+
+	client := ratelimitpb.NewRateLimitServiceClient(conn)
+	resp, err := client.GetUserStats(ctx, &ratelimitpb.GetUserStatsRequest{Uuid: cmd.Flag.Arg(0)})
+	if err != nil {
+		base.Fatalf("failed to get user stats: %s", err)
+	}
+	if apiJSON {
+		showJSONResponse(resp)
+		return
+	}
+	fmt.Printf("UUID:       %s\n", resp.Uuid)
+	fmt.Printf("Devices:    %d\n", resp.DeviceCount)
+	fmt.Printf("Rx:         %s\n", formatBytes(resp.RxBytesTotal))
+	fmt.Printf("Tx:         %s\n", formatBytes(resp.TxBytesTotal))
+	fmt.Printf("Started:    %s\n", formatUnix(resp.StartedAtUnixMin))
+	fmt.Printf("Last seen:  %s\n", formatUnix(resp.LastSeenUnixMax))
+}
@@ -0,0 +1,124 @@
+package api
+
+import (
+	"strings"
+
+	routerService "github.com/xtls/xray-core/app/router/command"
+	"github.com/xtls/xray-core/common/net"
+	"github.com/xtls/xray-core/main/commands/base"
+)
+
+var cmdRouteTrace = &base.Command{
+	CustomFlags: true,
+	UsageLine:   "{{.Exec}} api route-trace [--server=127.0.0.1:8080] [-domain d] [-ip ip] [-port n] ...",
+	Short:       "Explain the routing decision for a connection",
+	Long: `
+Evaluate the routing rules for a made-up connection without dispatching it,
+and show every rule and condition evaluated, whether the domain was resolved
+because of domainStrategy, and the balancer candidates considered.
+
+Balancers only report what they would pick: a trace doesn't advance
+roundRobin or add consistentHash sessions.
+
+Arguments:
+
+	-s, -server <server:port>
+		The API server address. Default 127.0.0.1:8080
+
+	-t, -timeout <seconds>
+		Timeout in seconds for calling API. Default 3
+
+	-domain <domain>
+		Target domain.
+
+	-ip <ip>
+		Target IP.
+
+	-port <n>
+		Target port. Default 443
+
+	-network <tcp|udp>
+		Network of the connection. Default tcp
+
+	-source <ip>
+		Source IP.
+
+	-user <email>
+		User of the connection.
+
+	-inbound <tag>
+		Inbound tag.
+
+	-protocol <name>
+		Sniffed protocol, e.g. http, tls, quic, bittorrent.
+
+Example:
+
+	{{.Exec}} {{.LongName}} --server=127.0.0.1:8080 -domain www.example.com
+	{{.Exec}} {{.LongName}} --server=127.0.0.1:8080 -ip 1.1.1.1 -port 53 -network udp -inbound socks
+`,
+	Run: executeRouteTrace,
+}
+
+func executeRouteTrace(cmd *base.Command, args []string) {
+	var (
+		domain, ip, network, source, user, inbound, protocol string
+		port                                                 uint
+	)
+	setSharedFlags(cmd)
+	cmd.Flag.StringVar(&domain, "domain", "", "")
+	cmd.Flag.StringVar(&ip, "ip", "", "")
+	cmd.Flag.UintVar(&port, "port", 443, "")
+	cmd.Flag.StringVar(&network, "network", "tcp", "")
+	cmd.Flag.StringVar(&source, "source", "", "")
+	cmd.Flag.StringVar(&user, "user", "", "")
+	cmd.Flag.StringVar(&inbound, "inbound", "", "")
+	cmd.Flag.StringVar(&protocol, "protocol", "", "")
+	cmd.Flag.Parse(args)
+
+	if domain == "" && ip == "" {
+		base.Fatalf("-domain or -ip is required")
+	}
+	if port > 65535 {
+		base.Fatalf("invalid port: %d", port)
+	}
+	rc := &routerService.RoutingContext{
+		TargetDomain: domain,
+		TargetPort:   uint32(port),
+		User:         user,
+		InboundTag:   inbound,
+		Protocol:     protocol,
+	}
+	switch strings.ToLower(network) {
+	case "tcp":
+		rc.Network = net.Network_TCP
+	case "udp":
+		rc.Network = net.Network_UDP
+	default:
+		base.Fatalf("invalid network: %s", network)
+	}
+	if ip != "" {
+		addr := net.ParseAddress(ip)
+		if !addr.Family().IsIP() {
+			base.Fatalf("invalid IP: %s", ip)
+		}
+		rc.TargetIPs = [][]byte{addr.IP()}
+	}
+	if source != "" {
+		addr := net.ParseAddress(source)
+		if !addr.Family().IsIP() {
+			base.Fatalf("invalid source IP: %s", source)
+		}
+		rc.SourceIPs = [][]byte{addr.IP()}
+	}
+
+	conn, ctx, close := dialAPIServer()
+	defer close()
+
+	client := routerService.NewRoutingServiceClient(conn)
+	resp, err := client.TraceRoute(ctx, &routerService.TraceRouteRequest{RoutingContext: rc})
+	if err != nil {
+		base.Fatalf("failed to perform TraceRoute: %s", err)
+	}
+	showJSONResponse(resp)
+}
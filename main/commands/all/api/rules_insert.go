@@ -0,0 +1,93 @@
+package api
+
+import (
+	"fmt"
+
+	"github.com/xtls/xray-core/app/router"
+	routerService "github.com/xtls/xray-core/app/router/command"
+	cserial "github.com/xtls/xray-core/common/serial"
+	"github.com/xtls/xray-core/infra/conf/serial"
+	"github.com/xtls/xray-core/main/commands/base"
+)
+
+var cmdInsertRules = &base.Command{
+	CustomFlags: true,
+	UsageLine:   "{{.Exec}} api insrules [--server=127.0.0.1:8080] -index n <c1.json> [c2.json]...",
+	Short:       "Insert routing rules at a position",
+	Long: `
+Insert the routing rules of the given configs before the rule at
+position n, keeping their order. Balancers in the configs are added.
+
+Arguments:
+
+	-s, -server <server:port>
+		The API server address. Default 127.0.0.1:8080
+
+	-t, -timeout <seconds>
+		Timeout in seconds for calling API. Default 3
+
+	-index <n>
+		Position (0-based) to insert at; the number of rules appends. Default 0
+
+Example:
+
+	{{.Exec}} {{.LongName}} --server=127.0.0.1:8080 -index 2 c1.json c2.json
+`,
+	Run: executeInsertRules,
+}
+
+func executeInsertRules(cmd *base.Command, args []string) {
+	var index int
+	setSharedFlags(cmd)
+	cmd.Flag.IntVar(&index, "index", 0, "")
+	cmd.Flag.Parse(args)
+
+	config := loadRouterConfigs(cmd.Flag.Args())
+
+	conn, ctx, close := dialAPIServer()
+	defer close()
+
+	client := routerService.NewRoutingServiceClient(conn)
+	resp, err := client.InsertRule(ctx, &routerService.InsertRuleRequest{
+		Config: cserial.ToTypedMessage(config),
+		Index:  int32(index),
+	})
+	if err != nil {
+		base.Fatalf("failed to perform InsertRule: %s", err)
+	}
+	showJSONResponse(resp)
+}
+
+// loadRouterConfigs merges the routing rules and balancers of config files
+// into one router config.
+func loadRouterConfigs(args []string) *router.Config {
+	if len(args) == 0 {
+		fmt.Println("reading from stdin:")
+		args = []string{"stdin:"}
+	}
+
+	merged := &router.Config{}
+	for _, arg := range args {
+		r, err := loadArg(arg)
+		if err != nil {
+			base.Fatalf("failed to load %s: %s", arg, err)
+		}
+		c, err := serial.DecodeJSONConfig(r)
+		if err != nil {
+			base.Fatalf("failed to decode %s: %s", arg, err)
+		}
+		if c.RouterConfig == nil {
+			continue
+		}
+		config, err := c.RouterConfig.Build()
+		if err != nil {
+			base.Fatalf("failed to build conf: %s", err)
+		}
+		merged.Rule = append(merged.Rule, config.Rule...)
+		merged.BalancingRule = append(merged.BalancingRule, config.BalancingRule...)
+	}
+	if len(merged.Rule) == 0 {
+		base.Fatalf("no valid rule found in config")
+	}
+	return merged
+}
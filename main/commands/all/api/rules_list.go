@@ -0,0 +1,71 @@
+package api
+
+import (
+	routerService "github.com/xtls/xray-core/app/router/command"
+	"github.com/xtls/xray-core/main/commands/base"
+)
+
+var cmdListRules = &base.Command{
+	CustomFlags: true,
+	UsageLine:   "{{.Exec}} api lsrules [--server=127.0.0.1:8080] [-index n | ruleTag...]",
+	Short:       "List routing rules",
+	Long: `
+List routing rules of Xray in matching order. With ruleTags or -index,
+show those rules including their conditions.
+
+Arguments:
+
+	-s, -server <server:port>
+		The API server address. Default 127.0.0.1:8080
+
+	-t, -timeout <seconds>
+		Timeout in seconds for calling API. Default 3
+
+	-index <n>
+		Show the rule at position n (0-based). Useful for rules without ruleTag.
+
+Example:
+
+	{{.Exec}} {{.LongName}} --server=127.0.0.1:8080
+	{{.Exec}} {{.LongName}} --server=127.0.0.1:8080 ruleTag1 ruleTag2
+	{{.Exec}} {{.LongName}} --server=127.0.0.1:8080 -index 0
+`,
+	Run: executeListRules,
+}
+
+func executeListRules(cmd *base.Command, args []string) {
+	var index int
+	setSharedFlags(cmd)
+	cmd.Flag.IntVar(&index, "index", -1, "")
+	cmd.Flag.Parse(args)
+	ruleTags := cmd.Flag.Args()
+
+	conn, ctx, close := dialAPIServer()
+	defer close()
+
+	client := routerService.NewRoutingServiceClient(conn)
+
+	if index >= 0 {
+		resp, err := client.GetRule(ctx, &routerService.GetRuleRequest{Index: int32(index)})
+		if err != nil {
+			base.Fatalf("failed to perform GetRule: %s", err)
+		}
+		showJSONResponse(resp)
+		return
+	}
+	if len(ruleTags) == 0 {
+		resp, err := client.ListRules(ctx, &routerService.ListRulesRequest{})
+		if err != nil {
+			base.Fatalf("failed to perform ListRules: %s", err)
+		}
+		showJSONResponse(resp)
+		return
+	}
+	for _, tag := range ruleTags {
+		resp, err := client.GetRule(ctx, &routerService.GetRuleRequest{RuleTag: tag})
+		if err != nil {
+			base.Fatalf("failed to perform GetRule: %s", err)
+		}
+		showJSONResponse(resp)
+	}
+}
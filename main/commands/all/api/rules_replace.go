@@ -0,0 +1,51 @@
+package api
+
+import (
+	routerService "github.com/xtls/xray-core/app/router/command"
+	cserial "github.com/xtls/xray-core/common/serial"
+	"github.com/xtls/xray-core/main/commands/base"
+)
+
+var cmdReplaceRules = &base.Command{
+	CustomFlags: true,
+	UsageLine:   "{{.Exec}} api replrules [--server=127.0.0.1:8080] <c1.json> [c2.json]...",
+	Short:       "Replace all routing rules at once",
+	Long: `
+Replace the whole list of routing rules with the rules of the given
+configs in one step: connections are routed either by the old or by
+the new list, never by a mix. Existing balancers are kept, balancers
+in the configs are added.
+
+Arguments:
+
+	-s, -server <server:port>
+		The API server address. Default 127.0.0.1:8080
+
+	-t, -timeout <seconds>
+		Timeout in seconds for calling API. Default 3
+
+Example:
+
+	{{.Exec}} {{.LongName}} --server=127.0.0.1:8080 c1.json c2.json
+`,
+	Run: executeReplaceRules,
+}
+
+func executeReplaceRules(cmd *base.Command, args []string) {
+	setSharedFlags(cmd)
+	cmd.Flag.Parse(args)
+
+	config := loadRouterConfigs(cmd.Flag.Args())
+
+	conn, ctx, close := dialAPIServer()
+	defer close()
+
+	client := routerService.NewRoutingServiceClient(conn)
+	resp, err := client.ReplaceRules(ctx, &routerService.ReplaceRulesRequest{
+		Config: cserial.ToTypedMessage(config),
+	})
+	if err != nil {
+		base.Fatalf("failed to perform ReplaceRules: %s", err)
+	}
+	showJSONResponse(resp)
+}